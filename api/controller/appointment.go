@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -26,20 +29,53 @@ func NewAppointmentHandler(service services.AppointmentService) *AppointmentHand
 
 type CreateAppointmentRequest struct {
 	ClientID     int       `json:"client_id"`
-	EmployeeID   int       `json:"employee_id"`
+	EmployeeID   int       `json:"employee_id,omitempty"` // any free employee is assigned when omitted
+	LocationID   *int      `json:"location_id,omitempty"` // limits the assigned employee to the branch
 	ServiceID    int       `json:"service_id"`
 	StartTime    time.Time `json:"start_time"`
 	ReminderTime *int      `json:"reminder_time,omitempty"`
+	// HoldToken books the slot held during checkout, other fields may be omitted then
+	HoldToken string `json:"hold_token,omitempty"`
+}
+
+type HoldSlotRequest struct {
+	ClientID   int       `json:"client_id"`
+	EmployeeID int       `json:"employee_id,omitempty"` // any free employee is assigned when omitted
+	LocationID *int      `json:"location_id,omitempty"` // limits the assigned employee to the branch
+	ServiceID  int       `json:"service_id"`
+	StartTime  time.Time `json:"start_time"`
 }
 
 type UpdateAppointmentRequest struct {
 	StartTime    time.Time `json:"start_time"`
+	EmployeeID   *int      `json:"employee_id,omitempty"` // keeps the current employee when omitted
+	ReminderTime *int      `json:"reminder_time,omitempty"`
+}
+
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type CreateSeriesRequest struct {
+	ClientID     int       `json:"client_id"`
+	EmployeeID   int       `json:"employee_id"`
+	ServiceID    int       `json:"service_id"`
+	StartTime    time.Time `json:"start_time"`
+	RRule        string    `json:"rrule"`
 	ReminderTime *int      `json:"reminder_time,omitempty"`
+	// AllOrNothing books nothing when any occurrence is unavailable
+	AllOrNothing bool `json:"all_or_nothing"`
+}
+
+type RescheduleFollowingRequest struct {
+	StartTime    time.Time `json:"start_time"`
+	AllOrNothing bool      `json:"all_or_nothing"`
 }
 
 type GetAvailableSlotsQuery struct {
 	EmployeeID int       `json:"employee_id"`
 	ServiceID  int       `json:"service_id"`
+	LocationID int       `json:"location_id"`
 	Date       time.Time `json:"date"`
 }
 
@@ -78,20 +114,145 @@ func (h *AppointmentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		BusinessID:   businessID,
 		ClientID:     req.ClientID,
 		EmployeeID:   req.EmployeeID,
+		LocationID:   req.LocationID,
 		ServiceID:    req.ServiceID,
 		StartTime:    req.StartTime,
 		ReminderTime: req.ReminderTime,
 	}
+	if req.HoldToken != "" {
+		appointment.HoldToken = &req.HoldToken
+	}
 
-	if err := h.appointmentService.
-		Create(r.Context(), appointment); err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+	if err := h.appointmentService.Create(r.Context(), appointment); err != nil {
+		appointmentError(w, err)
 		return
 	}
 
 	response.JSON(w, http.StatusCreated, appointment)
 }
 
+// Hold reserves a slot for a few minutes while the client completes the booking
+func (h *AppointmentHandler) Hold(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	var req HoldSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.StartTime.IsZero() {
+		response.Error(w, http.StatusBadRequest, "start time is required")
+		return
+	}
+
+	// If client ID is not provided, use the authenticated user's ID
+	if req.ClientID == 0 {
+		userID, ok := middleware.GetUserID(r.Context())
+		if !ok {
+			response.Error(w, http.StatusBadRequest, "client ID is required")
+			return
+		}
+		req.ClientID = userID
+	} else {
+		// If client ID is provided, only admins can hold slots for other users
+		userRole, _ := middleware.GetRole(r.Context())
+		if userRole != entity.RoleAdmin {
+			response.Error(w, http.StatusForbidden, "unauthorized to hold slots for other users")
+			return
+		}
+	}
+
+	hold, err := h.appointmentService.Hold(r.Context(), &entity.Appointment{
+		BusinessID: businessID,
+		ClientID:   req.ClientID,
+		EmployeeID: req.EmployeeID,
+		LocationID: req.LocationID,
+		ServiceID:  req.ServiceID,
+		StartTime:  req.StartTime,
+	})
+	if err != nil {
+		appointmentError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, hold)
+}
+
+// ReleaseHold frees a held slot, the hold token in the URL identifies it
+func (h *AppointmentHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "holdToken")
+	if token == "" {
+		response.Error(w, http.StatusBadRequest, "invalid hold token")
+		return
+	}
+
+	if err := h.appointmentService.ReleaseHold(r.Context(), token); err != nil {
+		appointmentError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "released"})
+}
+
+func (h *AppointmentHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	var req CreateSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.RRule == "" {
+		response.Error(w, http.StatusBadRequest, "rrule is required")
+		return
+	}
+
+	// If client ID is not provided, use the authenticated user's ID
+	if req.ClientID == 0 {
+		userID, ok := middleware.GetUserID(r.Context())
+		if !ok {
+			response.Error(w, http.StatusBadRequest, "client ID is required")
+			return
+		}
+		req.ClientID = userID
+	} else {
+		// If client ID is provided, only admins can create appointments for other users
+		userRole, _ := middleware.GetRole(r.Context())
+		if userRole != entity.RoleAdmin {
+			response.Error(w, http.StatusForbidden, "unauthorized to create appointments for other users")
+			return
+		}
+	}
+
+	series := &entity.AppointmentSeries{
+		BusinessID:   businessID,
+		ClientID:     req.ClientID,
+		EmployeeID:   req.EmployeeID,
+		ServiceID:    req.ServiceID,
+		RRule:        req.RRule,
+		StartTime:    req.StartTime,
+		ReminderTime: req.ReminderTime,
+	}
+
+	result, err := h.appointmentService.CreateSeries(r.Context(), series, req.AllOrNothing)
+	if err != nil {
+		seriesError(w, result, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, result)
+}
+
 func (h *AppointmentHandler) Get(w http.ResponseWriter, r *http.Request) {
 	appointmentID, err := strconv.Atoi(chi.URLParam(r, "appointmentID"))
 	if err != nil {
@@ -133,6 +294,11 @@ func (h *AppointmentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.StartTime.IsZero() {
+		response.Error(w, http.StatusBadRequest, "start time is required")
+		return
+	}
+
 	// Get existing appointment
 	existing, err := h.appointmentService.Get(r.Context(), appointmentID)
 	if err != nil {
@@ -150,15 +316,21 @@ func (h *AppointmentHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update only allowed fields
-	existing.StartTime = req.StartTime
-	existing.ReminderTime = req.ReminderTime
+	appointment := &entity.Appointment{
+		ID:           appointmentID,
+		StartTime:    req.StartTime,
+		ReminderTime: req.ReminderTime,
+	}
+	if req.EmployeeID != nil {
+		appointment.EmployeeID = *req.EmployeeID
+	}
 
-	if err := h.appointmentService.Update(r.Context(), existing); err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+	if err := h.appointmentService.Update(r.Context(), appointment); err != nil {
+		appointmentError(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, existing)
+	response.JSON(w, http.StatusOK, appointment)
 }
 
 func (h *AppointmentHandler) Cancel(w http.ResponseWriter, r *http.Request) {
@@ -184,12 +356,139 @@ func (h *AppointmentHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.appointmentService.Cancel(r.Context(), appointmentID); err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+	// The request body is optional, DELETE requests usually come without one
+	var req CancelAppointmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.appointmentService.Cancel(r.Context(), appointmentID, req.Reason); err != nil {
+		appointmentError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": entity.AppointmentStatusCancelled})
+}
+
+// CancelFollowing cancels the appointment and the following appointments of its series
+func (h *AppointmentHandler) CancelFollowing(w http.ResponseWriter, r *http.Request) {
+	appointmentID, err := strconv.Atoi(chi.URLParam(r, "appointmentID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid appointment ID")
+		return
+	}
+
+	existing, err := h.appointmentService.Get(r.Context(), appointmentID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get appointment")
+		return
+	}
+
+	// Verify access rights
+	userRole, _ := middleware.GetRole(r.Context())
+	userID, _ := middleware.GetUserID(r.Context())
+
+	if userRole != entity.RoleAdmin && existing.ClientID != userID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return
+	}
+
+	var req CancelAppointmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ids, err := h.appointmentService.CancelFollowing(r.Context(), appointmentID, req.Reason)
+	if err != nil {
+		appointmentError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string][]int{"cancelled": ids})
+}
+
+// RescheduleFollowing moves the appointment and the following appointments of its series
+func (h *AppointmentHandler) RescheduleFollowing(w http.ResponseWriter, r *http.Request) {
+	appointmentID, err := strconv.Atoi(chi.URLParam(r, "appointmentID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid appointment ID")
 		return
 	}
 
-	response.JSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
+	var req RescheduleFollowingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.StartTime.IsZero() {
+		response.Error(w, http.StatusBadRequest, "start time is required")
+		return
+	}
+
+	existing, err := h.appointmentService.Get(r.Context(), appointmentID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get appointment")
+		return
+	}
+
+	// Verify access rights
+	userRole, _ := middleware.GetRole(r.Context())
+	userID, _ := middleware.GetUserID(r.Context())
+
+	if userRole != entity.RoleAdmin && existing.ClientID != userID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return
+	}
+
+	result, err := h.appointmentService.RescheduleFollowing(r.Context(), appointmentID, req.StartTime, req.AllOrNothing)
+	if err != nil {
+		seriesError(w, result, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *AppointmentHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	h.finish(w, r, entity.AppointmentStatusCompleted, h.appointmentService.Complete)
+}
+
+func (h *AppointmentHandler) MarkNoShow(w http.ResponseWriter, r *http.Request) {
+	h.finish(w, r, entity.AppointmentStatusNoShow, h.appointmentService.MarkNoShow)
+}
+
+// finish records the outcome of an appointment. Only the business staff can do this.
+func (h *AppointmentHandler) finish(w http.ResponseWriter, r *http.Request, status string, transition func(ctx context.Context, id int) error) {
+	appointmentID, err := strconv.Atoi(chi.URLParam(r, "appointmentID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid appointment ID")
+		return
+	}
+
+	existing, err := h.appointmentService.Get(r.Context(), appointmentID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get appointment")
+		return
+	}
+
+	// Verify access rights
+	userRole, _ := middleware.GetRole(r.Context())
+	businessID, _ := middleware.GetBusinessID(r.Context())
+
+	if (userRole != entity.RoleAdmin && userRole != entity.RoleEmployee) || existing.BusinessID != businessID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return
+	}
+
+	if err := transition(r.Context(), appointmentID); err != nil {
+		appointmentError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": status})
 }
 
 func (h *AppointmentHandler) ListByBusiness(w http.ResponseWriter, r *http.Request) {
@@ -300,6 +599,12 @@ func (h *AppointmentHandler) GetAvailableSlots(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	locationID, err := parseLocationQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	dateStr := r.URL.Query().Get("date")
 	if dateStr == "" {
 		response.Error(w, http.StatusBadRequest, "date is required")
@@ -312,15 +617,183 @@ func (h *AppointmentHandler) GetAvailableSlots(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	slots, err := h.appointmentService.GetAvailableSlots(r.Context(), employeeID, serviceID, date)
+	slots, err := h.appointmentService.GetAvailableSlots(r.Context(), employeeID, serviceID, locationID, date)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		slotsError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, slots)
+}
+
+func (h *AppointmentHandler) GetServiceSlots(w http.ResponseWriter, r *http.Request) {
+	serviceID, err := strconv.Atoi(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid service ID")
+		return
+	}
+
+	locationID, err := parseLocationQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dateStr := r.URL.Query().Get("date")
+	if dateStr == "" {
+		response.Error(w, http.StatusBadRequest, "date is required")
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid date format")
+		return
+	}
+
+	slots, err := h.appointmentService.GetServiceSlots(r.Context(), serviceID, locationID, date)
+	if err != nil {
+		slotsError(w, err)
 		return
 	}
 
 	response.JSON(w, http.StatusOK, slots)
 }
 
+func (h *AppointmentHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	serviceID, employeeID, err := parseSlotQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	locationID, err := parseLocationQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	startDate, endDate, err := parseDateRangeQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	days, err := h.appointmentService.GetAvailability(r.Context(), employeeID, serviceID, locationID, startDate, endDate)
+	if err != nil {
+		slotsError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, days)
+}
+
+func (h *AppointmentHandler) GetNextAvailableSlot(w http.ResponseWriter, r *http.Request) {
+	serviceID, employeeID, err := parseSlotQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	locationID, err := parseLocationQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Search from today in the timezone of the branch or of the business unless a start date is given
+	var from time.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid from format")
+			return
+		}
+	}
+
+	slot, err := h.appointmentService.GetNextAvailableSlot(r.Context(), employeeID, serviceID, locationID, from)
+	if err != nil {
+		if errors.Is(err, services.ErrNoAvailableSlots) {
+			response.ErrorWithCode(w, http.StatusNotFound, err.Error(), "no_available_slots")
+			return
+		}
+		slotsError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, slot)
+}
+
+// appointmentError writes appointment service errors, giving conflicts a distinct status and code
+func appointmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrSlotUnavailable):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "slot_unavailable")
+	case errors.Is(err, services.ErrSessionFull):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "session_full")
+	case errors.Is(err, services.ErrAlreadyBooked):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "already_booked")
+	case errors.Is(err, services.ErrHoldExpired):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "hold_expired")
+	case errors.Is(err, services.ErrInvalidStatusTransition):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "invalid_status_transition")
+	case errors.Is(err, services.ErrInvalidLocation):
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_location")
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// seriesError writes series errors, reporting the unavailable occurrences with the conflict
+func seriesError(w http.ResponseWriter, result *services.SeriesResult, err error) {
+	if errors.Is(err, services.ErrSeriesUnavailable) {
+		response.ErrorWithData(w, http.StatusConflict, err.Error(), "series_unavailable", result)
+		return
+	}
+	appointmentError(w, err)
+}
+
+// parseSlotQuery reads the required service ID and the optional employee ID from query parameters
+func parseSlotQuery(r *http.Request) (int, int, error) {
+	serviceID, err := strconv.Atoi(r.URL.Query().Get("service_id"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid service ID")
+	}
+
+	var employeeID int
+	if employeeIDStr := r.URL.Query().Get("employee_id"); employeeIDStr != "" {
+		employeeID, err = strconv.Atoi(employeeIDStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid employee ID")
+		}
+	}
+
+	return serviceID, employeeID, nil
+}
+
+// parseLocationQuery returns the optional location filter of the slot queries, 0 when it is not given
+func parseLocationQuery(r *http.Request) (int, error) {
+	locationIDStr := r.URL.Query().Get("location_id")
+	if locationIDStr == "" {
+		return 0, nil
+	}
+
+	locationID, err := strconv.Atoi(locationIDStr)
+	if err != nil || locationID <= 0 {
+		return 0, fmt.Errorf("invalid location ID")
+	}
+	return locationID, nil
+}
+
+// slotsError writes slot query errors, a location the slots cannot be filtered by is a bad request
+func slotsError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrInvalidLocation) {
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_location")
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, err.Error())
+}
+
 // Helper function to parse date range from query parameters
 func parseDateRangeQuery(r *http.Request) (time.Time, time.Time, error) {
 	startDate := r.URL.Query().Get("start_date")
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/vadimpk/ppc-project/controller/middleware"
@@ -23,11 +24,13 @@ func NewBusinessHandler(service services.BusinessService) *BusinessHandler {
 }
 
 type CreateBusinessRequest struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Timezone string `json:"timezone,omitempty"` // IANA name, defaults to UTC
 }
 
 type UpdateBusinessRequest struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Timezone string `json:"timezone,omitempty"` // IANA name, keeps the current one when empty
 }
 
 type UpdateBusinessAppearanceRequest struct {
@@ -43,7 +46,8 @@ func (h *BusinessHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	business := &entity.Business{
-		Name: req.Name,
+		Name:     req.Name,
+		Timezone: req.Timezone,
 	}
 
 	if err := h.businessService.Create(r.Context(), business); err != nil {
@@ -91,8 +95,9 @@ func (h *BusinessHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	business := &entity.Business{
-		ID:   businessID,
-		Name: req.Name,
+		ID:       businessID,
+		Name:     req.Name,
+		Timezone: req.Timezone,
 	}
 
 	if err := h.businessService.Update(r.Context(), business); err != nil {
@@ -136,20 +141,23 @@ type SearchBusinessResponse struct {
 	Services   []entity.BusinessService `json:"services"`
 }
 
+// SearchBusinessAndServices finds businesses and services by name. The location query limits them to
+// branches whose name or address contains it, e.g. a city or a street, and may be used without a search.
 func (h *BusinessHandler) SearchBusinessAndServices(w http.ResponseWriter, r *http.Request) {
 	search := r.URL.Query().Get("search")
-	if search == "" {
+	location := strings.TrimSpace(r.URL.Query().Get("location"))
+	if search == "" && location == "" {
 		response.Error(w, http.StatusBadRequest, "search query is required")
 		return
 	}
 
-	businesses, err := h.businessService.ListBySearch(r.Context(), search)
+	businesses, err := h.businessService.ListBySearch(r.Context(), search, location)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to search businesses")
 		return
 	}
 
-	businessServices, err := h.businessService.ListServicesBySearch(r.Context(), search)
+	businessServices, err := h.businessService.ListServicesBySearch(r.Context(), search, location)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to search services")
 		return
@@ -160,3 +168,23 @@ func (h *BusinessHandler) SearchBusinessAndServices(w http.ResponseWriter, r *ht
 		Services:   businessServices,
 	})
 }
+
+// authorizeBusinessAdmin returns the business ID from the URL and verifies the user is an admin of that business.
+// The error response is written when false is returned.
+func authorizeBusinessAdmin(w http.ResponseWriter, r *http.Request) (int, bool) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return 0, false
+	}
+
+	// Settings of a business, such as webhooks and opening hours, are only managed by its own admins
+	userRole, _ := middleware.GetRole(r.Context())
+	userBusinessID, _ := middleware.GetBusinessID(r.Context())
+	if userRole != entity.RoleAdmin || userBusinessID != businessID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return 0, false
+	}
+
+	return businessID, true
+}
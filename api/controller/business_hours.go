@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+type BusinessHoursHandler struct {
+	businessHoursService services.BusinessHoursService
+}
+
+func NewBusinessHoursHandler(service services.BusinessHoursService) *BusinessHoursHandler {
+	return &BusinessHoursHandler{
+		businessHoursService: service,
+	}
+}
+
+type BusinessHoursRequest struct {
+	DayOfWeek int       `json:"day_of_week"`
+	OpenTime  time.Time `json:"open_time"`
+	CloseTime time.Time `json:"close_time"`
+}
+
+type SetBusinessHoursRequest struct {
+	Hours []BusinessHoursRequest `json:"hours"`
+}
+
+type CreateClosureRequest struct {
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	IsYearly  bool      `json:"is_yearly"`
+}
+
+type ImportClosuresResponse struct {
+	Created int `json:"created"`
+}
+
+func (h *BusinessHoursHandler) GetHours(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	hours, err := h.businessHoursService.GetHours(r.Context(), businessID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get business hours")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, hours)
+}
+
+// SetHours replaces the opening hours of the whole week
+func (h *BusinessHoursHandler) SetHours(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req SetBusinessHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hours := make([]entity.BusinessHours, len(req.Hours))
+	for i, h := range req.Hours {
+		hours[i] = entity.BusinessHours{
+			DayOfWeek: h.DayOfWeek,
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		}
+	}
+
+	if err := h.businessHoursService.SetHours(r.Context(), businessID, hours); err != nil {
+		businessHoursError(w, err, "failed to set business hours")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, hours)
+}
+
+func (h *BusinessHoursHandler) ListClosures(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	// Parse date range from query parameters
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+
+	if startDate == "" || endDate == "" {
+		response.Error(w, http.StatusBadRequest, "start_date and end_date are required")
+		return
+	}
+
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid start_date format")
+		return
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid end_date format")
+		return
+	}
+
+	closures, err := h.businessHoursService.ListClosures(r.Context(), businessID, start, end)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, closures)
+}
+
+func (h *BusinessHoursHandler) CreateClosure(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateClosureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	closure := &entity.BusinessClosure{
+		BusinessID: businessID,
+		Name:       req.Name,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		IsYearly:   req.IsYearly,
+	}
+	if err := h.businessHoursService.CreateClosure(r.Context(), closure); err != nil {
+		businessHoursError(w, err, "failed to create closure")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, closure)
+}
+
+// ImportClosures imports the holidays of an .ics file sent as the request body
+func (h *BusinessHoursHandler) ImportClosures(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCalendarUploadSize))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read calendar file")
+		return
+	}
+
+	created, err := h.businessHoursService.ImportClosures(r.Context(), businessID, data)
+	if err != nil {
+		businessHoursError(w, err, "failed to import closures")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ImportClosuresResponse{Created: created})
+}
+
+func (h *BusinessHoursHandler) DeleteClosure(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	closureID, err := strconv.Atoi(chi.URLParam(r, "closureID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid closure ID")
+		return
+	}
+
+	closure, err := h.businessHoursService.GetClosure(r.Context(), closureID)
+	if err != nil || closure.BusinessID != businessID {
+		response.Error(w, http.StatusNotFound, "closure not found")
+		return
+	}
+
+	if err := h.businessHoursService.DeleteClosure(r.Context(), closure.ID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to delete closure")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func businessHoursError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidBusinessHours):
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_business_hours")
+	case errors.Is(err, services.ErrInvalidClosure):
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_closure")
+	case errors.Is(err, services.ErrInvalidCalendar):
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_calendar")
+	default:
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -27,6 +28,7 @@ type CreateServiceRequest struct {
 	Description *string `json:"description,omitempty"`
 	Duration    int     `json:"duration"` // in minutes
 	Price       int     `json:"price"`    // in cents
+	ServiceBookingSettings
 }
 
 type UpdateServiceRequest struct {
@@ -35,6 +37,43 @@ type UpdateServiceRequest struct {
 	Duration    int     `json:"duration"` // in minutes
 	Price       int     `json:"price"`    // in cents
 	IsActive    bool    `json:"is_active"`
+	ServiceBookingSettings
+}
+
+// ServiceBookingSettings are optional, settings that are omitted keep their current value
+type ServiceBookingSettings struct {
+	SlotInterval   *int `json:"slot_interval,omitempty"` // in minutes
+	BufferBefore   *int `json:"buffer_before,omitempty"` // in minutes
+	BufferAfter    *int `json:"buffer_after,omitempty"`  // in minutes
+	MinNotice      *int `json:"min_notice,omitempty"`    // in minutes
+	MaxAdvanceDays *int `json:"max_advance_days,omitempty"`
+	Capacity       *int `json:"capacity,omitempty"` // clients per session, above 1 for group classes
+	// ResourceTypes the service is booked with, one free resource of any of them is needed. Empty needs none.
+	ResourceTypes *[]string `json:"resource_types,omitempty"`
+}
+
+func (b ServiceBookingSettings) apply(service *entity.BusinessService) {
+	if b.SlotInterval != nil {
+		service.SlotInterval = *b.SlotInterval
+	}
+	if b.BufferBefore != nil {
+		service.BufferBefore = *b.BufferBefore
+	}
+	if b.BufferAfter != nil {
+		service.BufferAfter = *b.BufferAfter
+	}
+	if b.MinNotice != nil {
+		service.MinNotice = *b.MinNotice
+	}
+	if b.MaxAdvanceDays != nil {
+		service.MaxAdvanceDays = *b.MaxAdvanceDays
+	}
+	if b.Capacity != nil {
+		service.Capacity = *b.Capacity
+	}
+	if b.ResourceTypes != nil {
+		service.ResourceTypes = *b.ResourceTypes
+	}
 }
 
 func (h *BusinessServiceHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -65,7 +104,9 @@ func (h *BusinessServiceHandler) Create(w http.ResponseWriter, r *http.Request)
 		Duration:    req.Duration,
 		Price:       req.Price,
 		IsActive:    true,
+		MinNotice:   entity.DefaultMinNotice,
 	}
+	req.ServiceBookingSettings.apply(service)
 
 	if err := h.serviceService.Create(r.Context(), service); err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to create service")
@@ -115,15 +156,30 @@ func (h *BusinessServiceHandler) Update(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Keep the booking settings that are not part of the request
+	existing, err := h.serviceService.Get(r.Context(), serviceID)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "service not found")
+		return
+	}
+
 	service := &entity.BusinessService{
-		ID:          serviceID,
-		BusinessID:  businessID,
-		Name:        req.Name,
-		Description: req.Description,
-		Duration:    req.Duration,
-		Price:       req.Price,
-		IsActive:    req.IsActive,
+		ID:             serviceID,
+		BusinessID:     businessID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Duration:       req.Duration,
+		Price:          req.Price,
+		IsActive:       req.IsActive,
+		SlotInterval:   existing.SlotInterval,
+		BufferBefore:   existing.BufferBefore,
+		BufferAfter:    existing.BufferAfter,
+		MinNotice:      existing.MinNotice,
+		MaxAdvanceDays: existing.MaxAdvanceDays,
+		Capacity:       existing.Capacity,
+		ResourceTypes:  existing.ResourceTypes,
 	}
+	req.ServiceBookingSettings.apply(service)
 
 	if err := h.serviceService.Update(r.Context(), service); err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to update service")
@@ -164,24 +220,37 @@ func (h *BusinessServiceHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check query parameter for active-only filter
+	// Check query parameters for the branch and active-only filters
+	locationID, err := parseLocationQuery(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	activeOnly := r.URL.Query().Get("active") == "true"
 
-	var services []entity.BusinessService
+	var businessServices []entity.BusinessService
 	var listErr error
 
-	if activeOnly {
-		services, listErr = h.serviceService.ListActive(r.Context(), businessID)
-	} else {
-		services, listErr = h.serviceService.List(r.Context(), businessID)
+	switch {
+	case locationID != 0:
+		// Only active services are offered at a branch
+		businessServices, listErr = h.serviceService.ListByLocation(r.Context(), businessID, locationID)
+	case activeOnly:
+		businessServices, listErr = h.serviceService.ListActive(r.Context(), businessID)
+	default:
+		businessServices, listErr = h.serviceService.List(r.Context(), businessID)
 	}
 
 	if listErr != nil {
+		if errors.Is(listErr, services.ErrInvalidLocation) {
+			response.ErrorWithCode(w, http.StatusBadRequest, listErr.Error(), "invalid_location")
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to list services")
 		return
 	}
 
-	response.JSON(w, http.StatusOK, services)
+	response.JSON(w, http.StatusOK, businessServices)
 }
 
 func (h *BusinessServiceHandler) ListEmployees(w http.ResponseWriter, r *http.Request) {
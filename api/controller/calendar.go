@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/middleware"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	calendarService       services.CalendarService
+	calendarImportService services.CalendarImportService
+	employeeService       services.EmployeeService
+	appointmentService    services.AppointmentService
+}
+
+func NewCalendarHandler(
+	calendarService services.CalendarService,
+	calendarImportService services.CalendarImportService,
+	employeeService services.EmployeeService,
+	appointmentService services.AppointmentService,
+) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService:       calendarService,
+		calendarImportService: calendarImportService,
+		employeeService:       employeeService,
+		appointmentService:    appointmentService,
+	}
+}
+
+// CalendarFeedResponse holds the subscription URL of a feed. The URL contains the token and
+// should be kept private, anyone with it can read the calendar until the feed is revoked.
+type CalendarFeedResponse struct {
+	*entity.CalendarFeed
+	URL string `json:"url"`
+}
+
+// Feed serves the calendar of the feed token. It requires no authentication,
+// so calendar applications can subscribe to it.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	calendar, err := h.calendarService.Feed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "calendar feed not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar)
+}
+
+func (h *CalendarHandler) CreateEmployeeFeed(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	feed, err := h.calendarService.CreateEmployeeFeed(r.Context(), employeeID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to create calendar feed")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, CalendarFeedResponse{CalendarFeed: feed, URL: feedURL(r, feed.Token)})
+}
+
+func (h *CalendarHandler) RevokeEmployeeFeed(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarService.RevokeEmployeeFeed(r.Context(), employeeID); err != nil {
+		response.Error(w, http.StatusNotFound, "calendar feed not found")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+func (h *CalendarHandler) CreateClientFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authorizeUser(w, r)
+	if !ok {
+		return
+	}
+
+	feed, err := h.calendarService.CreateClientFeed(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, CalendarFeedResponse{CalendarFeed: feed, URL: feedURL(r, feed.Token)})
+}
+
+func (h *CalendarHandler) RevokeClientFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authorizeUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarService.RevokeClientFeed(r.Context(), userID); err != nil {
+		response.Error(w, http.StatusNotFound, "calendar feed not found")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+// DownloadAppointment serves a single appointment as an .ics file to add to a calendar
+func (h *CalendarHandler) DownloadAppointment(w http.ResponseWriter, r *http.Request) {
+	appointmentID, err := strconv.Atoi(chi.URLParam(r, "appointmentID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid appointment ID")
+		return
+	}
+
+	appointment, err := h.appointmentService.Get(r.Context(), appointmentID)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "appointment not found")
+		return
+	}
+
+	// Verify access rights
+	userID, _ := middleware.GetUserID(r.Context())
+	userRole, _ := middleware.GetRole(r.Context())
+	businessID, _ := middleware.GetBusinessID(r.Context())
+
+	if userRole != entity.RoleAdmin &&
+		appointment.BusinessID != businessID &&
+		appointment.ClientID != userID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return
+	}
+
+	calendar, err := h.calendarService.AppointmentCalendar(r.Context(), appointmentID)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="appointment-%d.ics"`, appointmentID))
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar)
+}
+
+// authorizeUser returns the user ID from the URL and verifies it is the authenticated user.
+// The error response is written when false is returned.
+func authorizeUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user ID")
+		return 0, false
+	}
+
+	actualUserID, _ := middleware.GetUserID(r.Context())
+	if actualUserID != userID {
+		response.Error(w, http.StatusForbidden, "forbidden")
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// feedURL returns the absolute subscription URL of the feed token on the host of the request
+func feedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/api/v1/calendar/%s.ics", scheme, r.Host, token)
+}
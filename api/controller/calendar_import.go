@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+// maxCalendarUploadSize limits uploaded calendar files
+const maxCalendarUploadSize = 5 << 20
+
+type SubscribeCalendarRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (h *CalendarHandler) ListImports(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	imports, err := h.calendarImportService.List(r.Context(), employeeID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list calendar imports")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, imports)
+}
+
+// Subscribe imports the calendar at a URL, it is imported again periodically
+func (h *CalendarHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	var req SubscribeCalendarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	calendarImport, err := h.calendarImportService.Subscribe(r.Context(), employeeID, req.Name, req.URL)
+	if err != nil {
+		calendarImportError(w, err, "failed to import calendar")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, calendarImport)
+}
+
+// UploadImport imports an .ics file. The file is either the "file" field of a multipart form, named by
+// the "name" field or the file name, or the request body, named by the "name" query parameter.
+func (h *CalendarHandler) UploadImport(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCalendarUploadSize)
+
+	var (
+		name = r.URL.Query().Get("name")
+		data []byte
+		err  error
+	)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "calendar file is required")
+			return
+		}
+		defer file.Close()
+
+		if formName := r.FormValue("name"); formName != "" {
+			name = formName
+		}
+		if name == "" {
+			name = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+		}
+		data, err = io.ReadAll(file)
+	} else {
+		data, err = io.ReadAll(r.Body)
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read calendar file")
+		return
+	}
+
+	calendarImport, err := h.calendarImportService.ImportFile(r.Context(), employeeID, name, data)
+	if err != nil {
+		calendarImportError(w, err, "failed to import calendar")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, calendarImport)
+}
+
+// RefreshImport imports a subscribed calendar again right away
+func (h *CalendarHandler) RefreshImport(w http.ResponseWriter, r *http.Request) {
+	calendarImport, ok := h.getAuthorizedImport(w, r)
+	if !ok {
+		return
+	}
+
+	calendarImport, err := h.calendarImportService.Refresh(r.Context(), calendarImport.ID)
+	if err != nil {
+		calendarImportError(w, err, "failed to refresh calendar")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, calendarImport)
+}
+
+// DeleteImport removes the calendar together with its busy time
+func (h *CalendarHandler) DeleteImport(w http.ResponseWriter, r *http.Request) {
+	calendarImport, ok := h.getAuthorizedImport(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarImportService.Delete(r.Context(), calendarImport.ID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to delete calendar import")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// getAuthorizedImport returns the import from the URL when it belongs to the employee from the URL
+// and the user may manage that employee. The error response is written when false is returned.
+func (h *CalendarHandler) getAuthorizedImport(w http.ResponseWriter, r *http.Request) (*entity.CalendarImport, bool) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return nil, false
+	}
+
+	importID, err := strconv.Atoi(chi.URLParam(r, "importID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid calendar import ID")
+		return nil, false
+	}
+
+	calendarImport, err := h.calendarImportService.Get(r.Context(), importID)
+	if err != nil || calendarImport.EmployeeID != employeeID {
+		response.Error(w, http.StatusNotFound, "calendar import not found")
+		return nil, false
+	}
+
+	return calendarImport, true
+}
+
+func calendarImportError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCalendar):
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_calendar")
+	case errors.Is(err, services.ErrCalendarUnavailable):
+		response.ErrorWithCode(w, http.StatusBadGateway, err.Error(), "calendar_unavailable")
+	default:
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -25,11 +26,13 @@ func NewEmployeeHandler(service services.EmployeeService) *EmployeeHandler {
 type CreateEmployeeRequest struct {
 	UserID         int     `json:"user_id"`
 	Specialization *string `json:"specialization,omitempty"`
+	LocationID     *int    `json:"location_id,omitempty"`
 }
 
 type UpdateEmployeeRequest struct {
 	Specialization *string `json:"specialization,omitempty"`
 	IsActive       bool    `json:"is_active"`
+	LocationID     *int    `json:"location_id,omitempty"` // keeps the current branch when omitted, 0 removes it
 }
 
 type AssignServicesRequest struct {
@@ -62,10 +65,11 @@ func (h *EmployeeHandler) Create(w http.ResponseWriter, r *http.Request) {
 		UserID:         req.UserID,
 		Specialization: req.Specialization,
 		IsActive:       true,
+		LocationID:     req.LocationID,
 	}
 
 	if err := h.employeeService.Create(r.Context(), employee); err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to create employee")
+		employeeError(w, err, "failed to create employee")
 		return
 	}
 
@@ -121,10 +125,11 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request) {
 		ID:             employeeID,
 		Specialization: req.Specialization,
 		IsActive:       req.IsActive,
+		LocationID:     req.LocationID,
 	}
 
 	if err := h.employeeService.Update(r.Context(), employee); err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to update employee")
+		employeeError(w, err, "failed to update employee")
 		return
 	}
 
@@ -229,3 +234,40 @@ func (h *EmployeeHandler) RemoveServices(w http.ResponseWriter, r *http.Request)
 
 	response.JSON(w, http.StatusOK, map[string]string{"status": "services removed"})
 }
+
+// authorizeEmployee returns the employee ID from the URL and verifies the user is that employee
+// or an admin of its business. The error response is written when false is returned.
+func authorizeEmployee(w http.ResponseWriter, r *http.Request, employeeService services.EmployeeService) (int, bool) {
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid employee ID")
+		return 0, false
+	}
+
+	employee, err := employeeService.Get(r.Context(), employeeID)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "employee not found")
+		return 0, false
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	userRole, _ := middleware.GetRole(r.Context())
+	businessID, _ := middleware.GetBusinessID(r.Context())
+
+	isAdmin := userRole == entity.RoleAdmin && employee.BusinessID == businessID
+	if !isAdmin && employee.UserID != userID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return 0, false
+	}
+
+	return employeeID, true
+}
+
+// employeeError writes employee service errors, a branch of another business is a bad request
+func employeeError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrInvalidLocation) {
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_location")
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, message)
+}
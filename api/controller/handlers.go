@@ -10,8 +10,15 @@ type Handlers struct {
 	User        *UserHandler
 	Employee    *EmployeeHandler
 	Service     *BusinessServiceHandler
+	Resource    *ResourceHandler
+	Location    *LocationHandler
 	Schedule    *ScheduleHandler
+	Hours       *BusinessHoursHandler
+	TimeOff     *TimeOffHandler
 	Appointment *AppointmentHandler
+	Waitlist    *WaitlistHandler
+	Webhook     *WebhookHandler
+	Calendar    *CalendarHandler
 }
 
 func NewHandlers(services *services.Services, tokenManager *auth.TokenManager) *Handlers {
@@ -20,7 +27,14 @@ func NewHandlers(services *services.Services, tokenManager *auth.TokenManager) *
 		User:        NewUserHandler(services.User, services.Employee, tokenManager),
 		Employee:    NewEmployeeHandler(services.Employee),
 		Service:     NewBusinessServiceHandler(services.Service),
+		Resource:    NewResourceHandler(services.Resource),
+		Location:    NewLocationHandler(services.Location),
 		Schedule:    NewScheduleHandler(services.Schedule),
+		Hours:       NewBusinessHoursHandler(services.BusinessHours),
+		TimeOff:     NewTimeOffHandler(services.TimeOff, services.Employee),
 		Appointment: NewAppointmentHandler(services.Appointment),
+		Waitlist:    NewWaitlistHandler(services.Waitlist),
+		Webhook:     NewWebhookHandler(services.Webhook),
+		Calendar:    NewCalendarHandler(services.Calendar, services.CalendarImport, services.Employee, services.Appointment),
 	}
 }
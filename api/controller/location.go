@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+type LocationHandler struct {
+	locationService services.LocationService
+}
+
+func NewLocationHandler(locationService services.LocationService) *LocationHandler {
+	return &LocationHandler{
+		locationService: locationService,
+	}
+}
+
+type CreateLocationRequest struct {
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	Timezone  string   `json:"timezone,omitempty"` // the business timezone is used when omitted
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+type UpdateLocationRequest struct {
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	Timezone  string   `json:"timezone"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	IsActive  bool     `json:"is_active"`
+}
+
+// List returns the branches of the business, clients choose where to book from them
+func (h *LocationHandler) List(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	locations, err := h.locationService.List(r.Context(), businessID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list locations")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, locations)
+}
+
+func (h *LocationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	location := &entity.Location{
+		BusinessID: businessID,
+		Name:       req.Name,
+		Address:    req.Address,
+		Timezone:   req.Timezone,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+	}
+	if err := h.locationService.Create(r.Context(), location); err != nil {
+		locationError(w, err, "failed to create location")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, location)
+}
+
+func (h *LocationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	location, ok := h.getBusinessLocation(w, r, businessID)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, location)
+}
+
+func (h *LocationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	location, ok := h.getBusinessLocation(w, r, businessID)
+	if !ok {
+		return
+	}
+
+	var req UpdateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	location.Name = req.Name
+	location.Address = req.Address
+	location.Timezone = req.Timezone
+	location.Latitude = req.Latitude
+	location.Longitude = req.Longitude
+	location.IsActive = req.IsActive
+	if err := h.locationService.Update(r.Context(), location); err != nil {
+		locationError(w, err, "failed to update location")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, location)
+}
+
+// Delete deactivates the location, its employees and appointments keep it
+func (h *LocationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	location, ok := h.getBusinessLocation(w, r, businessID)
+	if !ok {
+		return
+	}
+
+	if err := h.locationService.Delete(r.Context(), location.ID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to delete location")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// getBusinessLocation loads the location from the URL and verifies it belongs to the business.
+// The error response is written when false is returned.
+func (h *LocationHandler) getBusinessLocation(w http.ResponseWriter, r *http.Request, businessID int) (*entity.Location, bool) {
+	locationID, err := strconv.Atoi(chi.URLParam(r, "locationID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return nil, false
+	}
+
+	location, err := h.locationService.Get(r.Context(), locationID)
+	if err != nil || location.BusinessID != businessID {
+		response.Error(w, http.StatusNotFound, "location not found")
+		return nil, false
+	}
+
+	return location, true
+}
+
+func locationError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrInvalidLocation) {
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_location")
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, message)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+type ResourceHandler struct {
+	resourceService services.ResourceService
+}
+
+func NewResourceHandler(resourceService services.ResourceService) *ResourceHandler {
+	return &ResourceHandler{
+		resourceService: resourceService,
+	}
+}
+
+type CreateResourceRequest struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	LocationID *int   `json:"location_id,omitempty"` // the resource is shared by all branches when omitted
+}
+
+type UpdateResourceRequest struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	IsActive   bool   `json:"is_active"`
+	LocationID *int   `json:"location_id,omitempty"`
+}
+
+func (h *ResourceHandler) List(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	resources, err := h.resourceService.List(r.Context(), businessID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list resources")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, resources)
+}
+
+func (h *ResourceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resource := &entity.Resource{
+		BusinessID: businessID,
+		Name:       req.Name,
+		Type:       req.Type,
+		LocationID: req.LocationID,
+	}
+	if err := h.resourceService.Create(r.Context(), resource); err != nil {
+		resourceError(w, err, "failed to create resource")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, resource)
+}
+
+func (h *ResourceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	resource, ok := h.getAuthorizedResource(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, resource)
+}
+
+func (h *ResourceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	resource, ok := h.getAuthorizedResource(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resource.Name = req.Name
+	resource.Type = req.Type
+	resource.IsActive = req.IsActive
+	resource.LocationID = req.LocationID
+	if err := h.resourceService.Update(r.Context(), resource); err != nil {
+		resourceError(w, err, "failed to update resource")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, resource)
+}
+
+// Delete deactivates the resource, appointments already booked with it keep it
+func (h *ResourceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	resource, ok := h.getAuthorizedResource(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.resourceService.Delete(r.Context(), resource.ID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to delete resource")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// getAuthorizedResource loads the resource from the URL and verifies it belongs to the business of the admin.
+// The error response is written when false is returned.
+func (h *ResourceHandler) getAuthorizedResource(w http.ResponseWriter, r *http.Request) (*entity.Resource, bool) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	resourceID, err := strconv.Atoi(chi.URLParam(r, "resourceID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid resource ID")
+		return nil, false
+	}
+
+	resource, err := h.resourceService.Get(r.Context(), resourceID)
+	if err != nil || resource.BusinessID != businessID {
+		response.Error(w, http.StatusNotFound, "resource not found")
+		return nil, false
+	}
+
+	return resource, true
+}
+
+func resourceError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrInvalidResource) {
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_resource")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidLocation) {
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_location")
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, message)
+}
@@ -61,3 +61,20 @@ func ErrorWithCode(w http.ResponseWriter, statusCode int, message string, code s
 
 	json.NewEncoder(w).Encode(response)
 }
+
+// ErrorWithData sends an error response with an error code and data describing the failure
+func ErrorWithData(w http.ResponseWriter, statusCode int, message string, code string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := Response{
+		Success: false,
+		Data:    data,
+		Error: &ErrorInfo{
+			Message: message,
+			Code:    code,
+		},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
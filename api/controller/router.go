@@ -23,6 +23,9 @@ func NewRouter(h *Handlers, authMiddleware, corsMiddleware func(http.Handler) ht
 		r.Group(func(r chi.Router) {
 			r.Post("/auth/login", h.User.Login)
 			r.Post("/auth/register", h.User.RegisterBusiness)
+
+			// Calendar feeds are authenticated by the token in their URL
+			r.Get("/calendar/{token}.ics", h.Calendar.Feed)
 		})
 
 		// Routes requiring authentication
@@ -40,16 +43,57 @@ func NewRouter(h *Handlers, authMiddleware, corsMiddleware func(http.Handler) ht
 					r.Put("/", h.Business.Update)
 					r.Patch("/appearance", h.Business.UpdateAppearance)
 
+					// Opening hours and closures, changed by admins only
+					r.Get("/hours", h.Hours.GetHours)
+					r.Put("/hours", h.Hours.SetHours)
+					r.Route("/closures", func(r chi.Router) {
+						r.Get("/", h.Hours.ListClosures)
+						r.Post("/", h.Hours.CreateClosure)
+						r.Post("/import", h.Hours.ImportClosures)
+						r.Delete("/{closureID}", h.Hours.DeleteClosure)
+					})
+
+					// Overrides applied to several employees at once
+					r.Post("/schedule/overrides", h.Schedule.ApplyOverride)
+
+					// Time off requests, decided by admins
+					r.Route("/time-off", func(r chi.Router) {
+						r.Get("/", h.TimeOff.ListByBusiness)
+						r.Get("/{requestID}", h.TimeOff.Get)
+						r.Get("/{requestID}/conflicts", h.TimeOff.Conflicts)
+						r.Post("/{requestID}/approve", h.TimeOff.Approve)
+						r.Post("/{requestID}/reject", h.TimeOff.Reject)
+					})
+
 					// Service routes
 					r.Route("/services", func(r chi.Router) {
 						r.Get("/", h.Service.List)
 						r.Post("/", h.Service.Create)
 						r.Get("/{serviceID}", h.Service.Get)
 						r.Get("/{serviceID}/employees", h.Service.ListEmployees)
+						r.Get("/{serviceID}/slots", h.Appointment.GetServiceSlots)
 						r.Put("/{serviceID}", h.Service.Update)
 						r.Delete("/{serviceID}", h.Service.Delete)
 					})
 
+					// Branches, listed for everyone and managed by admins
+					r.Route("/locations", func(r chi.Router) {
+						r.Get("/", h.Location.List)
+						r.Post("/", h.Location.Create)
+						r.Get("/{locationID}", h.Location.Get)
+						r.Put("/{locationID}", h.Location.Update)
+						r.Delete("/{locationID}", h.Location.Delete)
+					})
+
+					// Rooms, chairs and equipment services are booked with, admin only
+					r.Route("/resources", func(r chi.Router) {
+						r.Get("/", h.Resource.List)
+						r.Post("/", h.Resource.Create)
+						r.Get("/{resourceID}", h.Resource.Get)
+						r.Put("/{resourceID}", h.Resource.Update)
+						r.Delete("/{resourceID}", h.Resource.Delete)
+					})
+
 					// Employee routes
 					r.Route("/employees", func(r chi.Router) {
 						r.Get("/", h.Employee.List)
@@ -64,11 +108,33 @@ func NewRouter(h *Handlers, authMiddleware, corsMiddleware func(http.Handler) ht
 							r.Post("/services", h.Employee.AssignServices)
 							r.Delete("/services", h.Employee.RemoveServices)
 
+							// Employee availability
+							r.Get("/availability", h.Schedule.CheckAvailability)
+
+							// Employee calendar feed
+							r.Post("/calendar-feed", h.Calendar.CreateEmployeeFeed)
+							r.Delete("/calendar-feed", h.Calendar.RevokeEmployeeFeed)
+
+							// External calendars imported as busy time
+							r.Route("/calendar-imports", func(r chi.Router) {
+								r.Get("/", h.Calendar.ListImports)
+								r.Post("/", h.Calendar.Subscribe)
+								r.Post("/upload", h.Calendar.UploadImport)
+								r.Post("/{importID}/refresh", h.Calendar.RefreshImport)
+								r.Delete("/{importID}", h.Calendar.DeleteImport)
+							})
+
+							// Time off requested by the employee
+							r.Get("/time-off", h.TimeOff.ListByEmployee)
+							r.Post("/time-off", h.TimeOff.Create)
+
 							// Employee schedule
 							r.Route("/schedule", func(r chi.Router) {
 								// Templates
 								r.Get("/templates", h.Schedule.ListTemplates)
 								r.Post("/templates", h.Schedule.CreateTemplate)
+								r.Put("/templates", h.Schedule.ReplaceTemplates)
+								r.Post("/copy", h.Schedule.CopyTemplates)
 								r.Put("/templates/{templateID}", h.Schedule.UpdateTemplate)
 								r.Delete("/templates/{templateID}", h.Schedule.DeleteTemplate)
 
@@ -86,12 +152,52 @@ func NewRouter(h *Handlers, authMiddleware, corsMiddleware func(http.Handler) ht
 						r.Get("/", h.Appointment.ListByBusiness)
 						r.Get("/employee/{employeeID}", h.Appointment.ListByEmployee)
 						r.Post("/", h.Appointment.Create)
+						r.Post("/series", h.Appointment.CreateSeries)
+						r.Post("/holds", h.Appointment.Hold)
+						r.Delete("/holds/{holdToken}", h.Appointment.ReleaseHold)
 						r.Get("/slots", h.Appointment.GetAvailableSlots)
+						r.Get("/availability", h.Appointment.GetAvailability)
+						r.Get("/next-available", h.Appointment.GetNextAvailableSlot)
 
 						r.Route("/{appointmentID}", func(r chi.Router) {
 							r.Get("/", h.Appointment.Get)
 							r.Put("/", h.Appointment.Update)
 							r.Delete("/", h.Appointment.Cancel)
+							r.Get("/calendar.ics", h.Calendar.DownloadAppointment)
+
+							// Status transitions
+							r.Post("/cancel", h.Appointment.Cancel)
+							r.Post("/complete", h.Appointment.Complete)
+							r.Post("/no-show", h.Appointment.MarkNoShow)
+							r.Post("/cancel-following", h.Appointment.CancelFollowing)
+							r.Post("/reschedule-following", h.Appointment.RescheduleFollowing)
+						})
+					})
+
+					// Waitlist routes
+					r.Route("/waitlist", func(r chi.Router) {
+						r.Post("/", h.Waitlist.Join)
+
+						r.Route("/{entryID}", func(r chi.Router) {
+							r.Get("/", h.Waitlist.Get)
+							r.Delete("/", h.Waitlist.Leave)
+							r.Post("/accept", h.Waitlist.Accept)
+							r.Post("/decline", h.Waitlist.Decline)
+						})
+					})
+
+					// Webhook routes, admin only
+					r.Route("/webhooks", func(r chi.Router) {
+						r.Get("/", h.Webhook.List)
+						r.Post("/", h.Webhook.Create)
+
+						r.Route("/{webhookID}", func(r chi.Router) {
+							r.Get("/", h.Webhook.Get)
+							r.Put("/", h.Webhook.Update)
+							r.Delete("/", h.Webhook.Delete)
+							r.Post("/test", h.Webhook.Test)
+							r.Get("/deliveries", h.Webhook.ListDeliveries)
+							r.Post("/deliveries/{deliveryID}/replay", h.Webhook.Replay)
 						})
 					})
 				})
@@ -103,6 +209,9 @@ func NewRouter(h *Handlers, authMiddleware, corsMiddleware func(http.Handler) ht
 					r.Get("/", h.User.Get)
 					r.Put("/", h.User.Update)
 					r.Get("/appointments", h.Appointment.ListByClient)
+					r.Get("/waitlist", h.Waitlist.ListByClient)
+					r.Post("/calendar-feed", h.Calendar.CreateClientFeed)
+					r.Delete("/calendar-feed", h.Calendar.RevokeClientFeed)
 				})
 			})
 		})
@@ -23,17 +23,30 @@ func NewScheduleHandler(service services.ScheduleService) *ScheduleHandler {
 	}
 }
 
+// CreateTemplateRequest holds a weekly schedule block. Blocks for the next schedule are planned with valid_from,
+// the current blocks are ended with valid_until. Rotating blocks apply in rotation_week of every
+// rotation_weeks long cycle starting on rotation_anchor.
 type CreateTemplateRequest struct {
-	DayOfWeek int       `json:"day_of_week"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	IsBreak   bool      `json:"is_break"`
+	DayOfWeek      int        `json:"day_of_week"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        time.Time  `json:"end_time"`
+	IsBreak        bool       `json:"is_break"`
+	ValidFrom      *time.Time `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+	RotationWeeks  int        `json:"rotation_weeks,omitempty"`
+	RotationWeek   int        `json:"rotation_week,omitempty"`
+	RotationAnchor *time.Time `json:"rotation_anchor,omitempty"`
 }
 
 type UpdateTemplateRequest struct {
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	IsBreak   bool      `json:"is_break"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        time.Time  `json:"end_time"`
+	IsBreak        bool       `json:"is_break"`
+	ValidFrom      *time.Time `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+	RotationWeeks  int        `json:"rotation_weeks,omitempty"`
+	RotationWeek   int        `json:"rotation_week,omitempty"`
+	RotationAnchor *time.Time `json:"rotation_anchor,omitempty"`
 }
 
 type CreateOverrideRequest struct {
@@ -77,6 +90,12 @@ func (h *ScheduleHandler) CreateTemplate(w http.ResponseWriter, r *http.Request)
 		StartTime:  req.StartTime,
 		EndTime:    req.EndTime,
 		IsBreak:    req.IsBreak,
+
+		ValidFrom:      req.ValidFrom,
+		ValidUntil:     req.ValidUntil,
+		RotationWeeks:  req.RotationWeeks,
+		RotationWeek:   req.RotationWeek,
+		RotationAnchor: req.RotationAnchor,
 	}
 
 	if err := h.scheduleService.CreateTemplate(r.Context(), template); err != nil {
@@ -119,6 +138,12 @@ func (h *ScheduleHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request)
 		StartTime:  req.StartTime,
 		EndTime:    req.EndTime,
 		IsBreak:    req.IsBreak,
+
+		ValidFrom:      req.ValidFrom,
+		ValidUntil:     req.ValidUntil,
+		RotationWeeks:  req.RotationWeeks,
+		RotationWeek:   req.RotationWeek,
+		RotationAnchor: req.RotationAnchor,
 	}
 
 	if err := h.scheduleService.UpdateTemplate(r.Context(), template); err != nil {
@@ -311,6 +336,47 @@ func (h *ScheduleHandler) DeleteOverride(w http.ResponseWriter, r *http.Request)
 	response.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
+func (h *ScheduleHandler) CheckAvailability(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid employee ID")
+		return
+	}
+
+	startStr := r.URL.Query().Get("start")
+	endStr := r.URL.Query().Get("end")
+
+	if startStr == "" || endStr == "" {
+		response.Error(w, http.StatusBadRequest, "start and end are required")
+		return
+	}
+
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid start format")
+		return
+	}
+
+	end, err := time.Parse(time.RFC3339, endStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid end format")
+		return
+	}
+
+	if !end.After(start) {
+		response.Error(w, http.StatusBadRequest, "end must be after start")
+		return
+	}
+
+	availability, err := h.scheduleService.IsAvailable(r.Context(), employeeID, start, end)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, availability)
+}
+
 // Additional handler for getting the combined schedule
 //func (h *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
 //	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeID"))
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+// ReplaceTemplatesRequest holds the whole weekly schedule of an employee. With from set the schedule
+// is replaced from that date on and the current schedule keeps applying before it.
+type ReplaceTemplatesRequest struct {
+	Templates []CreateTemplateRequest `json:"templates"`
+	From      *time.Time              `json:"from,omitempty"`
+}
+
+type CopyTemplatesRequest struct {
+	EmployeeIDs []int      `json:"employee_ids"`
+	From        *time.Time `json:"from,omitempty"`
+}
+
+// ApplyOverrideRequest holds an override applied to every employee on every date from start_date to end_date
+type ApplyOverrideRequest struct {
+	EmployeeIDs  []int      `json:"employee_ids"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      time.Time  `json:"end_date"`
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	IsWorkingDay bool       `json:"is_working_day"`
+	IsBreak      bool       `json:"is_break"`
+}
+
+// ReplaceTemplates replaces the weekly schedule of the employee in one transaction
+func (h *ScheduleHandler) ReplaceTemplates(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid employee ID")
+		return
+	}
+
+	var req ReplaceTemplatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	templates := make([]entity.ScheduleTemplate, len(req.Templates))
+	for i, t := range req.Templates {
+		templates[i] = entity.ScheduleTemplate{
+			DayOfWeek: t.DayOfWeek,
+			StartTime: t.StartTime,
+			EndTime:   t.EndTime,
+			IsBreak:   t.IsBreak,
+
+			ValidFrom:      t.ValidFrom,
+			ValidUntil:     t.ValidUntil,
+			RotationWeeks:  t.RotationWeeks,
+			RotationWeek:   t.RotationWeek,
+			RotationAnchor: t.RotationAnchor,
+		}
+	}
+
+	result, err := h.scheduleService.ReplaceTemplates(r.Context(), businessID, employeeID, templates, req.From)
+	if err != nil {
+		bulkScheduleError(w, result, err, "failed to replace templates")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+// CopyTemplates copies the weekly schedule of the employee to other employees
+func (h *ScheduleHandler) CopyTemplates(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid employee ID")
+		return
+	}
+
+	var req CopyTemplatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.scheduleService.CopyTemplates(r.Context(), businessID, employeeID, req.EmployeeIDs, req.From)
+	if err != nil {
+		bulkScheduleError(w, result, err, "failed to copy templates")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+// ApplyOverride creates the same override for several employees and dates, such as closing for inventory
+func (h *ScheduleHandler) ApplyOverride(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req ApplyOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	override := entity.ScheduleOverride{
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		IsWorkingDay: req.IsWorkingDay,
+		IsBreak:      req.IsBreak,
+	}
+
+	result, err := h.scheduleService.ApplyOverride(r.Context(), businessID, req.EmployeeIDs, req.StartDate, req.EndDate, override)
+	if err != nil {
+		bulkScheduleError(w, result, err, "failed to apply override")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, result)
+}
+
+// bulkScheduleError writes bulk schedule errors, reporting every problem found with an invalid change
+func bulkScheduleError(w http.ResponseWriter, result *services.BulkScheduleResult, err error, message string) {
+	if errors.Is(err, services.ErrInvalidBulkSchedule) {
+		response.ErrorWithData(w, http.StatusBadRequest, err.Error(), "invalid_schedule", result)
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, message)
+}
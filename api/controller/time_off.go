@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/middleware"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+type TimeOffHandler struct {
+	timeOffService  services.TimeOffService
+	employeeService services.EmployeeService
+}
+
+func NewTimeOffHandler(timeOffService services.TimeOffService, employeeService services.EmployeeService) *TimeOffHandler {
+	return &TimeOffHandler{
+		timeOffService:  timeOffService,
+		employeeService: employeeService,
+	}
+}
+
+type CreateTimeOffRequest struct {
+	StartDate time.Time  `json:"start_date"`
+	EndDate   time.Time  `json:"end_date"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Reason    *string    `json:"reason,omitempty"`
+}
+
+type DecideTimeOffRequest struct {
+	Note string `json:"note"`
+}
+
+// ListByEmployee returns the time off requests of the employee, for the employee or an admin
+func (h *TimeOffHandler) ListByEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	requests, err := h.timeOffService.ListByEmployee(r.Context(), employeeID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list time off requests")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, requests)
+}
+
+// Create submits a time off request of the employee for an admin to decide
+func (h *TimeOffHandler) Create(w http.ResponseWriter, r *http.Request) {
+	employeeID, ok := authorizeEmployee(w, r, h.employeeService)
+	if !ok {
+		return
+	}
+
+	var req CreateTimeOffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	request := &entity.TimeOffRequest{
+		EmployeeID: employeeID,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Reason:     req.Reason,
+	}
+	if err := h.timeOffService.Request(r.Context(), request); err != nil {
+		timeOffError(w, err, "failed to create time off request")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, request)
+}
+
+// ListByBusiness returns the time off requests of the business, filtered by the "status" query parameter
+func (h *TimeOffHandler) ListByBusiness(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := h.timeOffService.ListByBusiness(r.Context(), businessID, r.URL.Query().Get("status"))
+	if err != nil {
+		timeOffError(w, err, "failed to list time off requests")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, requests)
+}
+
+func (h *TimeOffHandler) Get(w http.ResponseWriter, r *http.Request) {
+	request, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, request)
+}
+
+// Approve creates non-working overrides for the time off and returns the scheduled appointments during it
+func (h *TimeOffHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	request, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+
+	note, ok := decodeDecisionNote(w, r)
+	if !ok {
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+	decision, err := h.timeOffService.Approve(r.Context(), request.ID, adminID, note)
+	if err != nil {
+		timeOffError(w, err, "failed to approve time off request")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, decision)
+}
+
+func (h *TimeOffHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	request, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+
+	note, ok := decodeDecisionNote(w, r)
+	if !ok {
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(r.Context())
+	request, err := h.timeOffService.Reject(r.Context(), request.ID, adminID, note)
+	if err != nil {
+		timeOffError(w, err, "failed to reject time off request")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, request)
+}
+
+// Conflicts returns the scheduled appointments to reassign or cancel for the time off
+func (h *TimeOffHandler) Conflicts(w http.ResponseWriter, r *http.Request) {
+	request, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+
+	conflicts, err := h.timeOffService.Conflicts(r.Context(), request.ID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get conflicting appointments")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, conflicts)
+}
+
+// getRequest returns the time off request from the URL and verifies the user is an admin of its business.
+// The error response is written when false is returned.
+func (h *TimeOffHandler) getRequest(w http.ResponseWriter, r *http.Request) (*entity.TimeOffRequest, bool) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	requestID, err := strconv.Atoi(chi.URLParam(r, "requestID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid time off request ID")
+		return nil, false
+	}
+
+	request, err := h.timeOffService.Get(r.Context(), requestID)
+	if err != nil || request.BusinessID != businessID {
+		response.Error(w, http.StatusNotFound, "time off request not found")
+		return nil, false
+	}
+
+	return request, true
+}
+
+// decodeDecisionNote reads the optional note of a decision, the request body may be empty
+func decodeDecisionNote(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req DecideTimeOffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return "", false
+	}
+
+	return req.Note, true
+}
+
+func timeOffError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTimeOff):
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_time_off")
+	case errors.Is(err, services.ErrTimeOffDecided):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "time_off_decided")
+	default:
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/middleware"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+type WaitlistHandler struct {
+	waitlistService services.WaitlistService
+}
+
+func NewWaitlistHandler(service services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: service,
+	}
+}
+
+type JoinWaitlistRequest struct {
+	ClientID   int  `json:"client_id"`
+	ServiceID  int  `json:"service_id"`
+	EmployeeID *int `json:"employee_id,omitempty"` // any employee providing the service when omitted
+	// Date is a whole day (YYYY-MM-DD) in the timezone of the employee's branch or business, used instead of the window
+	Date        string    `json:"date,omitempty"`
+	WindowStart time.Time `json:"window_start,omitempty"`
+	WindowEnd   time.Time `json:"window_end,omitempty"`
+	// AutoBook books a freed slot right away instead of holding it until the offer is accepted
+	AutoBook bool `json:"auto_book"`
+}
+
+func (h *WaitlistHandler) Join(w http.ResponseWriter, r *http.Request) {
+	businessID, err := strconv.Atoi(chi.URLParam(r, "businessID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid business ID")
+		return
+	}
+
+	var req JoinWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// If client ID is not provided, use the authenticated user's ID
+	if req.ClientID == 0 {
+		userID, ok := middleware.GetUserID(r.Context())
+		if !ok {
+			response.Error(w, http.StatusBadRequest, "client ID is required")
+			return
+		}
+		req.ClientID = userID
+	} else {
+		// If client ID is provided, only admins can add other users to the waitlist
+		userRole, _ := middleware.GetRole(r.Context())
+		if userRole != entity.RoleAdmin {
+			response.Error(w, http.StatusForbidden, "unauthorized to join the waitlist for other users")
+			return
+		}
+	}
+
+	entry := &entity.WaitlistEntry{
+		BusinessID:  businessID,
+		ClientID:    req.ClientID,
+		ServiceID:   req.ServiceID,
+		EmployeeID:  req.EmployeeID,
+		WindowStart: req.WindowStart,
+		WindowEnd:   req.WindowEnd,
+		AutoBook:    req.AutoBook,
+	}
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid date format")
+			return
+		}
+		entry.WindowStart = date
+		entry.WindowEnd = time.Time{}
+	}
+	if entry.WindowStart.IsZero() {
+		response.Error(w, http.StatusBadRequest, "date or window start is required")
+		return
+	}
+
+	if err := h.waitlistService.Join(r.Context(), entry); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, entry)
+}
+
+func (h *WaitlistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.getAuthorizedEntry(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entry)
+}
+
+func (h *WaitlistHandler) ListByClient(w http.ResponseWriter, r *http.Request) {
+	clientID, err := strconv.Atoi(chi.URLParam(r, "userID")) // from /users/{userID}/waitlist
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	// Verify access rights
+	userID, _ := middleware.GetUserID(r.Context())
+	userRole, _ := middleware.GetRole(r.Context())
+
+	if userRole != entity.RoleAdmin && clientID != userID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return
+	}
+
+	entries, err := h.waitlistService.ListByClient(r.Context(), clientID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list waitlist entries")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entries)
+}
+
+func (h *WaitlistHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.getAuthorizedEntry(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.waitlistService.Leave(r.Context(), entry.ID); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": entity.WaitlistStatusCancelled})
+}
+
+// Accept books the slot held for the entry
+func (h *WaitlistHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.getAuthorizedEntry(w, r)
+	if !ok {
+		return
+	}
+
+	appointment, err := h.waitlistService.AcceptOffer(r.Context(), entry.ID)
+	if err != nil {
+		waitlistError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, appointment)
+}
+
+// Decline gives the slot held for the entry to the next client on the waitlist
+func (h *WaitlistHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.getAuthorizedEntry(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.waitlistService.DeclineOffer(r.Context(), entry.ID); err != nil {
+		waitlistError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": entity.WaitlistStatusCancelled})
+}
+
+// getAuthorizedEntry loads the entry from the URL and verifies the user is its client or an admin.
+// The error response is written when false is returned.
+func (h *WaitlistHandler) getAuthorizedEntry(w http.ResponseWriter, r *http.Request) (*entity.WaitlistEntry, bool) {
+	entryID, err := strconv.Atoi(chi.URLParam(r, "entryID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid waitlist entry ID")
+		return nil, false
+	}
+
+	entry, err := h.waitlistService.Get(r.Context(), entryID)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "waitlist entry not found")
+		return nil, false
+	}
+
+	// Verify access rights
+	userID, _ := middleware.GetUserID(r.Context())
+	userRole, _ := middleware.GetRole(r.Context())
+
+	if userRole != entity.RoleAdmin && entry.ClientID != userID {
+		response.Error(w, http.StatusForbidden, "unauthorized")
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func waitlistError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrNoWaitlistOffer):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "no_offer")
+	case errors.Is(err, services.ErrOfferExpired):
+		response.ErrorWithCode(w, http.StatusConflict, err.Error(), "offer_expired")
+	default:
+		appointmentError(w, err)
+	}
+}
@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vadimpk/ppc-project/controller/response"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+type UpdateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+}
+
+// CreateWebhookResponse is the only response including the signing secret of the webhook
+type CreateWebhookResponse struct {
+	*entity.Webhook
+	Secret string `json:"secret"`
+}
+
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.List(r.Context(), businessID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list webhooks")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, webhooks)
+}
+
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	webhook := &entity.Webhook{
+		BusinessID: businessID,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+	}
+	if err := h.webhookService.Create(r.Context(), webhook); err != nil {
+		webhookError(w, err, "failed to create webhook")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, CreateWebhookResponse{Webhook: webhook, Secret: webhook.Secret})
+}
+
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.getAuthorizedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.getAuthorizedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	webhook.URL = req.URL
+	webhook.EventTypes = req.EventTypes
+	webhook.IsActive = req.IsActive
+	if err := h.webhookService.Update(r.Context(), webhook); err != nil {
+		webhookError(w, err, "failed to update webhook")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.getAuthorizedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), webhook.ID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to delete webhook")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// Test sends a test delivery right away. The delivery is returned whether the endpoint accepted it or not.
+func (h *WebhookHandler) Test(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.getAuthorizedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Test(r.Context(), webhook.ID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to send test delivery")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.getAuthorizedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), webhook.ID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list webhook deliveries")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, deliveries)
+}
+
+// Replay sends a delivery again right away, including deliveries that already succeeded or failed for good
+func (h *WebhookHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.getAuthorizedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.Atoi(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(r.Context(), webhook.ID, deliveryID)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "webhook delivery not found")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, delivery)
+}
+
+// getAuthorizedWebhook loads the webhook from the URL and verifies it belongs to the business of the admin.
+// The error response is written when false is returned.
+func (h *WebhookHandler) getAuthorizedWebhook(w http.ResponseWriter, r *http.Request) (*entity.Webhook, bool) {
+	businessID, ok := authorizeBusinessAdmin(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	webhookID, err := strconv.Atoi(chi.URLParam(r, "webhookID"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid webhook ID")
+		return nil, false
+	}
+
+	webhook, err := h.webhookService.Get(r.Context(), webhookID)
+	if err != nil || webhook.BusinessID != businessID {
+		response.Error(w, http.StatusNotFound, "webhook not found")
+		return nil, false
+	}
+
+	return webhook, true
+}
+
+func webhookError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		response.ErrorWithCode(w, http.StatusBadRequest, err.Error(), "invalid_webhook")
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, message)
+}
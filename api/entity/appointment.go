@@ -11,17 +11,53 @@ type Appointment struct {
 	StartTime    time.Time `json:"start_time" db:"start_time"`
 	EndTime      time.Time `json:"end_time" db:"end_time"`
 	Status       string    `json:"status" db:"status"`
-	ReminderTime *int      `json:"reminder_time" db:"reminder_time"`
+	ReminderTime *int      `json:"reminder_time" db:"reminder_time"` // minutes before the start
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 
+	CancellationReason *string    `json:"cancellation_reason" db:"cancellation_reason"`
+	SeriesID           *int       `json:"series_id" db:"series_id"`
+	SessionID          *int       `json:"session_id" db:"session_id"`
+	HoldExpiresAt      *time.Time `json:"hold_expires_at,omitempty" db:"hold_expires_at"` // held appointments keep the interval until then
+	HoldToken          *string    `json:"-" db:"hold_token"`                              // confirms a checkout hold, only shown to its client
+	ReminderSentAt     *time.Time `json:"reminder_sent_at,omitempty" db:"reminder_sent_at"`
+	ResourceID         *int       `json:"resource_id" db:"resource_id"` // the resource booked for services that need one
+	LocationID         *int       `json:"location_id" db:"location_id"` // the branch of the employee
+
 	Client   *User            `json:"client"`
 	Employee *User            `json:"employee"`
 	Service  *BusinessService `json:"service"`
 }
 
+// AppointmentSeries is a recurring booking. Its occurrences are stored as appointments linked by SeriesID.
+type AppointmentSeries struct {
+	ID           int       `json:"id" db:"id"`
+	BusinessID   int       `json:"business_id" db:"business_id"`
+	ClientID     int       `json:"client_id" db:"client_id"`
+	EmployeeID   int       `json:"employee_id" db:"employee_id"`
+	ServiceID    int       `json:"service_id" db:"service_id"`
+	RRule        string    `json:"rrule" db:"rrule"`
+	StartTime    time.Time `json:"start_time" db:"start_time"`
+	ReminderTime *int      `json:"reminder_time" db:"reminder_time"` // minutes before the start
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ClassSession is a session of a group service. Every seat booked in it is an appointment linked by SessionID.
+type ClassSession struct {
+	ID          int       `json:"id" db:"id"`
+	BusinessID  int       `json:"business_id" db:"business_id"`
+	EmployeeID  int       `json:"employee_id" db:"employee_id"`
+	ServiceID   int       `json:"service_id" db:"service_id"`
+	StartTime   time.Time `json:"start_time" db:"start_time"`
+	EndTime     time.Time `json:"end_time" db:"end_time"`
+	Capacity    int       `json:"capacity" db:"capacity"`
+	BookedSeats int       `json:"booked_seats" db:"booked_seats"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
 const (
 	AppointmentStatusScheduled = "scheduled"
 	AppointmentStatusCompleted = "completed"
 	AppointmentStatusCancelled = "cancelled"
 	AppointmentStatusNoShow    = "no_show"
+	AppointmentStatusHeld      = "held"
 )
@@ -7,9 +7,13 @@ type Business struct {
 	Name        string                 `json:"name" db:"name"`
 	LogoURL     *string                `json:"logo_url" db:"logo_url"`
 	ColorScheme map[string]interface{} `json:"color_scheme" db:"color_scheme"`
+	Timezone    string                 `json:"timezone" db:"timezone"` // IANA name, e.g. Europe/Kyiv
 	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
 }
 
+// DefaultMinNotice is the minimum notice in minutes for services created without one
+const DefaultMinNotice = 15
+
 type BusinessService struct {
 	ID          int       `json:"id" db:"id"`
 	BusinessID  int       `json:"business_id" db:"business_id"`
@@ -19,6 +23,20 @@ type BusinessService struct {
 	Price       int       `json:"price" db:"price"`
 	IsActive    bool      `json:"is_active" db:"is_active"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	// Booking settings, all in minutes unless stated otherwise
+	SlotInterval   int `json:"slot_interval" db:"slot_interval"` // 0 steps slots by the duration
+	BufferBefore   int `json:"buffer_before" db:"buffer_before"`
+	BufferAfter    int `json:"buffer_after" db:"buffer_after"`
+	MinNotice      int `json:"min_notice" db:"min_notice"`
+	MaxAdvanceDays int `json:"max_advance_days" db:"max_advance_days"` // 0 is unlimited
+
+	// Capacity is the number of clients served at once, group classes have more than one seat
+	Capacity int `json:"capacity" db:"capacity"`
+
+	// ResourceTypes are the types of resources the service can be booked with, one free resource
+	// of any of them is assigned to every appointment. Services without them need no resource.
+	ResourceTypes []string `json:"resource_types" db:"-"`
 }
 
 type Employee struct {
@@ -27,6 +45,7 @@ type Employee struct {
 	UserID         int       `json:"user_id" db:"user_id"`
 	Specialization *string   `json:"specialization" db:"specialization"`
 	IsActive       bool      `json:"is_active" db:"is_active"`
+	LocationID     *int      `json:"location_id" db:"location_id"` // the branch the employee works at, nil for the whole business
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 	User           *User     `json:"user" db:"user"`
 }
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// CalendarFeed is the token of the read-only iCalendar feed of an employee or a client.
+// Exactly one of EmployeeID and ClientID is set.
+type CalendarFeed struct {
+	ID         int       `json:"id" db:"id"`
+	Token      string    `json:"token" db:"token"`
+	EmployeeID *int      `json:"employee_id,omitempty" db:"employee_id"`
+	ClientID   *int      `json:"client_id,omitempty" db:"client_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// CalendarImport is an external calendar of an employee. The employee is not available during its events.
+// Imports with a SourceURL are fetched periodically, other imports are replaced by uploading the calendar again.
+type CalendarImport struct {
+	ID           int        `json:"id" db:"id"`
+	EmployeeID   int        `json:"employee_id" db:"employee_id"`
+	Name         string     `json:"name" db:"name"`
+	SourceURL    *string    `json:"source_url,omitempty" db:"source_url"`
+	BusyCount    int        `json:"busy_count" db:"busy_count"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	// LastError describes why the last import failed, or the events it skipped
+	LastError *string   `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// BusyTime is an occurrence of an event of an imported calendar
+type BusyTime struct {
+	ID         int       `json:"id" db:"id"`
+	ImportID   int       `json:"import_id" db:"import_id"`
+	EmployeeID int       `json:"employee_id" db:"employee_id"`
+	StartTime  time.Time `json:"start_time" db:"start_time"`
+	EndTime    time.Time `json:"end_time" db:"end_time"`
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Appointment events, also used as notification and template names
+const (
+	EventAppointmentCreated     = "appointment.created"
+	EventAppointmentRescheduled = "appointment.rescheduled"
+	EventAppointmentCancelled   = "appointment.cancelled"
+	EventAppointmentCompleted   = "appointment.completed"
+	EventAppointmentNoShow      = "appointment.no_show"
+	EventAppointmentReminder    = "appointment.reminder"
+	EventAppointmentOffered     = "appointment.offered" // a freed slot is held for a waitlisted client
+)
+
+const (
+	EventEmployeeCreated = "employee.created"
+	EventEmployeeUpdated = "employee.updated"
+	EventServiceCreated  = "service.created"
+	EventServiceUpdated  = "service.updated"
+	EventServiceDeleted  = "service.deleted"
+	EventScheduleUpdated = "schedule.updated"
+)
+
+// Aggregates of domain events
+const (
+	AggregateAppointment = "appointment"
+	AggregateEmployee    = "employee"
+	AggregateService     = "service"
+	AggregateSchedule    = "schedule" // identified by the employee of the schedule
+)
+
+// DomainEvent is a change recorded in the outbox in the transaction of the change.
+// The relay publishes pending events to every consumer at least once.
+type DomainEvent struct {
+	ID            int             `json:"id" db:"id"`
+	BusinessID    int             `json:"business_id" db:"business_id"`
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id" db:"aggregate_id"`
+	EventType     string          `json:"event_type" db:"event_type"`
+	Payload       json.RawMessage `json:"payload" db:"payload"` // the aggregate after the change
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	DeliveredTo   []string        `json:"delivered_to" db:"delivered_to"` // consumers that already handled the event
+	LastError     *string         `json:"last_error" db:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at" db:"published_at"`
+}
+
+const (
+	DomainEventStatusPending   = "pending"
+	DomainEventStatusPublished = "published"
+	DomainEventStatusDead      = "dead" // retries exhausted
+)
+
+// ScheduleChange is the payload of schedule events
+type ScheduleChange struct {
+	EmployeeID int               `json:"employee_id"`
+	Action     string            `json:"action"` // created, updated or deleted
+	Template   *ScheduleTemplate `json:"template,omitempty"`
+	Override   *ScheduleOverride `json:"override,omitempty"`
+}
+
+const (
+	ScheduleActionCreated = "created"
+	ScheduleActionUpdated = "updated"
+	ScheduleActionDeleted = "deleted"
+)
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Location is a branch of a business. Its employees work in its timezone and
+// its appointments take place at its address.
+type Location struct {
+	ID         int       `json:"id" db:"id"`
+	BusinessID int       `json:"business_id" db:"business_id"`
+	Name       string    `json:"name" db:"name"`
+	Address    string    `json:"address" db:"address"`
+	Timezone   string    `json:"timezone" db:"timezone"` // IANA name, e.g. Europe/Kyiv
+	Latitude   *float64  `json:"latitude" db:"latitude"`
+	Longitude  *float64  `json:"longitude" db:"longitude"`
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
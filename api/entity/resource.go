@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Resource is a room, chair or piece of equipment shared by the employees of a business or of one of its branches.
+// Services with resource types are only booked while a resource of one of their types is free.
+type Resource struct {
+	ID         int       `json:"id" db:"id"`
+	BusinessID int       `json:"business_id" db:"business_id"`
+	Name       string    `json:"name" db:"name"`
+	Type       string    `json:"type" db:"resource_type"` // e.g. room, chair, laser
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	LocationID *int      `json:"location_id" db:"location_id"` // nil shares the resource between all branches
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
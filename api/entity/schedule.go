@@ -10,6 +10,15 @@ type ScheduleTemplate struct {
 	EndTime    time.Time `json:"end_time" db:"end_time"`
 	IsBreak    bool      `json:"is_break" db:"is_break"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+
+	// ValidFrom and ValidUntil are the first and last dates the template applies on, nil for open ends
+	ValidFrom  *time.Time `json:"valid_from" db:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until" db:"valid_until"`
+	// RotationWeeks is the length of the cycle of a rotating template, 1 for templates applying every week.
+	// A rotating template applies in week RotationWeek of every cycle, the first cycle starts on RotationAnchor.
+	RotationWeeks  int        `json:"rotation_weeks" db:"rotation_weeks"`
+	RotationWeek   int        `json:"rotation_week" db:"rotation_week"`
+	RotationAnchor *time.Time `json:"rotation_anchor" db:"rotation_anchor"`
 }
 
 type ScheduleOverride struct {
@@ -22,3 +31,52 @@ type ScheduleOverride struct {
 	IsBreak      bool       `json:"is_break" db:"is_break"`
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 }
+
+// BusinessHours is an opening block of a business on a weekday, in the business timezone.
+// A business without opening hours is open whenever its employees work.
+type BusinessHours struct {
+	ID         int       `json:"id" db:"id"`
+	BusinessID int       `json:"business_id" db:"business_id"`
+	DayOfWeek  int       `json:"day_of_week" db:"day_of_week"`
+	OpenTime   time.Time `json:"open_time" db:"open_time"`
+	CloseTime  time.Time `json:"close_time" db:"close_time"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// BusinessClosure closes a business for the whole days from StartDate to EndDate inclusive.
+// Yearly closures repeat on the same dates every year from StartDate on.
+type BusinessClosure struct {
+	ID         int       `json:"id" db:"id"`
+	BusinessID int       `json:"business_id" db:"business_id"`
+	Name       string    `json:"name" db:"name"`
+	StartDate  time.Time `json:"start_date" db:"start_date"`
+	EndDate    time.Time `json:"end_date" db:"end_date"`
+	IsYearly   bool      `json:"is_yearly" db:"is_yearly"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// TimeOffRequest is time off asked for by an employee. Approving it creates non-working overrides
+// for its dates, scheduled appointments during it are left for the admin to reassign or cancel.
+type TimeOffRequest struct {
+	ID         int       `json:"id" db:"id"`
+	BusinessID int       `json:"business_id" db:"business_id"`
+	EmployeeID int       `json:"employee_id" db:"employee_id"`
+	StartDate  time.Time `json:"start_date" db:"start_date"`
+	EndDate    time.Time `json:"end_date" db:"end_date"`
+	// StartTime and EndTime limit the time off to part of a single day, whole days are taken off when nil
+	StartTime *time.Time `json:"start_time" db:"start_time"`
+	EndTime   *time.Time `json:"end_time" db:"end_time"`
+	Reason    *string    `json:"reason" db:"reason"`
+	Status    string     `json:"status" db:"status"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	DecidedBy    *int       `json:"decided_by" db:"decided_by"` // the admin who approved or rejected the request
+	DecidedAt    *time.Time `json:"decided_at" db:"decided_at"`
+	DecisionNote *string    `json:"decision_note" db:"decision_note"`
+}
+
+const (
+	TimeOffStatusPending  = "pending"
+	TimeOffStatusApproved = "approved"
+	TimeOffStatusRejected = "rejected"
+)
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// WaitlistEntry is a client waiting for a slot of a service within a time window.
+// When an appointment matching the entry is cancelled, the freed slot is offered to the entry.
+type WaitlistEntry struct {
+	ID          int       `json:"id" db:"id"`
+	BusinessID  int       `json:"business_id" db:"business_id"`
+	ClientID    int       `json:"client_id" db:"client_id"`
+	ServiceID   int       `json:"service_id" db:"service_id"`
+	EmployeeID  *int      `json:"employee_id" db:"employee_id"` // any employee providing the service when nil
+	WindowStart time.Time `json:"window_start" db:"window_start"`
+	WindowEnd   time.Time `json:"window_end" db:"window_end"`
+	AutoBook    bool      `json:"auto_book" db:"auto_book"` // book a freed slot right away instead of offering it
+	Status      string    `json:"status" db:"status"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	// AppointmentID is the held appointment while offered and the booked one afterwards
+	AppointmentID  *int       `json:"appointment_id" db:"appointment_id"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at" db:"offer_expires_at"`
+}
+
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusOffered   = "offered"
+	WaitlistStatusBooked    = "booked"
+	WaitlistStatusExpired   = "expired"
+	WaitlistStatusCancelled = "cancelled"
+)
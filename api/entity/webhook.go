@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEventTest is the event type of test deliveries sent on request
+const WebhookEventTest = "webhook.test"
+
+// WebhookEventTypes are the domain events a webhook can subscribe to
+var WebhookEventTypes = []string{
+	EventAppointmentCreated,
+	EventAppointmentRescheduled,
+	EventAppointmentCancelled,
+	EventAppointmentCompleted,
+	EventAppointmentNoShow,
+	EventEmployeeCreated,
+	EventEmployeeUpdated,
+	EventServiceCreated,
+	EventServiceUpdated,
+	EventServiceDeleted,
+	EventScheduleUpdated,
+}
+
+// Webhook is an endpoint of a business receiving the events it subscribed to
+type Webhook struct {
+	ID         int       `json:"id" db:"id"`
+	BusinessID int       `json:"business_id" db:"business_id"`
+	URL        string    `json:"url" db:"url"`
+	Secret     string    `json:"-" db:"secret"` // signs deliveries, only returned when the webhook is created
+	EventTypes []string  `json:"event_types" db:"event_types"`
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookDelivery is a delivery of an event to a webhook, retried with exponential backoff until it succeeds
+type WebhookDelivery struct {
+	ID             int             `json:"id" db:"id"`
+	WebhookID      int             `json:"webhook_id" db:"webhook_id"`
+	EventID        *int            `json:"event_id" db:"event_id"` // nil for test deliveries
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"` // the request body
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"response_status" db:"response_status"`
+	LastError      *string         `json:"last_error" db:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at" db:"delivered_at"`
+}
+
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	WebhookDeliveryStatusFailed    = "failed" // retries exhausted
+)
@@ -7,12 +7,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
+	// Embedded timezone database for business timezones on hosts without one
+	_ "time/tzdata"
 
 	"github.com/vadimpk/ppc-project/controller"
 	"github.com/vadimpk/ppc-project/controller/middleware"
+	"github.com/vadimpk/ppc-project/notifications"
 	"github.com/vadimpk/ppc-project/pkg/auth"
+	"github.com/vadimpk/ppc-project/pkg/webhook"
 	"github.com/vadimpk/ppc-project/repository"
 	"github.com/vadimpk/ppc-project/services"
 )
@@ -20,6 +26,16 @@ import (
 const (
 	defaultPort            = "8080"
 	defaultShutdownTimeout = 10 * time.Second
+	// sweepInterval is how often expired holds and waitlist offers are released
+	sweepInterval = time.Minute
+	// reminderInterval is how often due appointment reminders are sent
+	reminderInterval = time.Minute
+	// relayInterval is how often pending domain events are published
+	relayInterval = 5 * time.Second
+	// webhookInterval is how often due webhook deliveries are sent
+	webhookInterval = 10 * time.Second
+	// calendarSyncInterval is how often subscribed external calendars are imported again
+	calendarSyncInterval = time.Hour
 )
 
 func main() {
@@ -48,8 +64,15 @@ func main() {
 		log.Fatalf("Failed to initialize token manager: %v", err)
 	}
 
+	// Initialize notifications
+	notifier, err := newNotifier()
+	if err != nil {
+		log.Fatalf("Failed to initialize notifications: %v", err)
+	}
+
 	// Initialize services
-	srvcs := services.NewServices(repositories)
+	webhookSender := webhook.NewSender(nil)
+	srvcs := services.NewServices(repositories, notifier, webhookSender)
 
 	// Initialize handlers and middleware
 	handlers := controller.NewHandlers(srvcs, tokenManager)
@@ -82,6 +105,26 @@ func main() {
 		}
 	}()
 
+	// Background workers run until shutdown
+	sweeper := services.NewSweeper(srvcs.Appointment, srvcs.Waitlist, sweepInterval)
+	reminders := services.NewReminderDispatcher(repositories, notifier, nil, reminderInterval)
+	relay, analyticsFile, err := newEventRelay(repositories, notifier)
+	if err != nil {
+		log.Fatalf("Failed to initialize event relay: %v", err)
+	}
+	webhooks := services.NewWebhookDispatcher(repositories, webhookSender, nil, webhookInterval)
+	calendars := services.NewCalendarImportSyncer(srvcs.CalendarImport, calendarSyncInterval)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	for _, run := range []func(context.Context){sweeper.Run, reminders.Run, relay.Run, webhooks.Run, calendars.Run} {
+		workers.Add(1)
+		go func(run func(context.Context)) {
+			defer workers.Done()
+			run(workerCtx)
+		}(run)
+	}
+
 	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	// Kill (no param) default send syscall.SIGTERM
@@ -100,9 +143,88 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop background workers
+	stopWorkers()
+	workers.Wait()
+
+	// The relay no longer writes analytics events
+	if analyticsFile != nil {
+		if err := analyticsFile.Sync(); err != nil {
+			log.Printf("Failed to sync analytics events file: %v", err)
+		}
+		if err := analyticsFile.Close(); err != nil {
+			log.Printf("Failed to close analytics events file: %v", err)
+		}
+	}
+
 	log.Println("Server exited properly")
 }
 
+// newNotifier configures the notification providers from the environment. Emails are written to stdout
+// when SMTP_HOST is not set, SMS are not sent when SMS_GATEWAY_URL is not set.
+func newNotifier() (*notifications.Service, error) {
+	templates, err := notifications.DefaultTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+
+	var email notifications.Notifier = notifications.NewLogNotifier(os.Stdout)
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := 0
+		if p := os.Getenv("SMTP_PORT"); p != "" {
+			if port, err = strconv.Atoi(p); err != nil {
+				return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+			}
+		}
+
+		email, err = notifications.NewSMTPNotifier(notifications.SMTPConfig{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var sms notifications.Notifier
+	if url := os.Getenv("SMS_GATEWAY_URL"); url != "" {
+		sms, err = notifications.NewSMSGatewayNotifier(notifications.SMSGatewayConfig{
+			URL:    url,
+			APIKey: os.Getenv("SMS_GATEWAY_API_KEY"),
+			From:   os.Getenv("SMS_FROM"),
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return notifications.NewService(email, sms, templates), nil
+}
+
+// newEventRelay registers the consumers of domain events. Events are written as JSON lines to
+// ANALYTICS_EVENTS_FILE when it is set, the opened file is returned to be closed once the relay stops.
+func newEventRelay(repos *repository.Repositories, notifier services.Notifier) (*services.EventRelay, *os.File, error) {
+	consumers := []services.EventConsumer{
+		services.NewNotificationConsumer(repos, notifier),
+		services.NewWebhookConsumer(repos),
+	}
+
+	var analyticsFile *os.File
+	if path := os.Getenv("ANALYTICS_EVENTS_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open analytics events file: %w", err)
+		}
+		analyticsFile = f
+		consumers = append(consumers, services.NewAnalyticsConsumer(f))
+	}
+
+	return services.NewEventRelay(repos, consumers, nil, relayInterval), analyticsFile, nil
+}
+
 // Debug helper - remove in production
 func init() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
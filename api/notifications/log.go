@@ -0,0 +1,39 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes messages to a writer instead of delivering them, for development
+type LogNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{w: w}
+}
+
+// NewFileNotifier appends messages to the file, creating it when missing
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notification log: %w", err)
+	}
+
+	return NewLogNotifier(f), nil
+}
+
+func (n *LogNotifier) Send(_ context.Context, message Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "%s [%s] to=%s subject=%q\n%s\n\n",
+		time.Now().Format(time.RFC3339), message.Channel, message.To, message.Subject, message.Text)
+	return err
+}
@@ -0,0 +1,24 @@
+// Package notifications renders appointment messages from templates and delivers them
+// by email and SMS through pluggable providers.
+package notifications
+
+import "context"
+
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+// Message is a rendered message for a single recipient. HTML is only used by email.
+type Message struct {
+	Channel string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Notifier delivers messages through a provider
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
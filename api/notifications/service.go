@@ -0,0 +1,142 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+)
+
+// Service renders the messages of appointment events and sends them to the client and the employee.
+// Clients get an email and an SMS when their contacts and the providers allow it, employees get an email.
+type Service struct {
+	email     Notifier
+	sms       Notifier
+	templates *Templates
+}
+
+// NewService creates the notification service. A nil provider disables its channel.
+func NewService(email, sms Notifier, templates *Templates) *Service {
+	return &Service{
+		email:     email,
+		sms:       sms,
+		templates: templates,
+	}
+}
+
+// Notify sends the messages of the event. The appointment must carry its client, employee and service details,
+// its times are shown in loc, the timezone of its branch or business, or in UTC when nil.
+// Messages that went out are not sent again when a failed event is retried, so an error is returned only when
+// no message could be sent and the failures of single recipients and channels are logged otherwise.
+func (s *Service) Notify(ctx context.Context, event string, appointment *entity.Appointment, business *entity.Business, loc *time.Location) error {
+	if !s.templates.Has(event) {
+		return nil
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+	if business == nil {
+		business = &entity.Business{}
+	}
+
+	data := TemplateData{
+		Event:       event,
+		Business:    business,
+		Appointment: appointment,
+		Client:      userOrEmpty(appointment.Client),
+		Employee:    userOrEmpty(appointment.Employee),
+		Service:     appointment.Service,
+		StartTime:   appointment.StartTime.In(loc),
+		EndTime:     appointment.EndTime.In(loc),
+	}
+	if data.Service == nil {
+		data.Service = &entity.BusinessService{}
+	}
+	if appointment.HoldExpiresAt != nil {
+		data.HoldExpiresAt = appointment.HoldExpiresAt.In(loc)
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+	record := func(delivered bool, err error) {
+		if delivered {
+			sent++
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	client := data
+	client.RecipientName = data.Client.FullName
+	if data.Client.Email != nil {
+		record(s.sendEmail(ctx, event, *data.Client.Email, client))
+	}
+	if data.Client.Phone != nil {
+		record(s.sendSMS(ctx, event, *data.Client.Phone, client))
+	}
+
+	staff := data
+	staff.ForStaff = true
+	staff.RecipientName = data.Employee.FullName
+	if data.Employee.Email != nil {
+		record(s.sendEmail(ctx, event, *data.Employee.Email, staff))
+	}
+
+	if sent == 0 {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		log.Printf("Failed to notify about appointment %d: %v", appointment.ID, err)
+	}
+	return nil
+}
+
+// sendEmail emails the message of the event and reports whether it was sent
+func (s *Service) sendEmail(ctx context.Context, event, to string, data TemplateData) (bool, error) {
+	if s.email == nil || to == "" {
+		return false, nil
+	}
+
+	message, err := s.templates.RenderEmail(event, data)
+	if err != nil {
+		return false, err
+	}
+	message.To = to
+
+	if err := s.email.Send(ctx, message); err != nil {
+		return false, fmt.Errorf("failed to email %s: %w", event, err)
+	}
+	return true, nil
+}
+
+// sendSMS texts the message of the event and reports whether it was sent
+func (s *Service) sendSMS(ctx context.Context, event, to string, data TemplateData) (bool, error) {
+	if s.sms == nil || to == "" {
+		return false, nil
+	}
+
+	message, ok, err := s.templates.RenderSMS(event, data)
+	if err != nil || !ok {
+		return false, err
+	}
+	message.To = to
+
+	if err := s.sms.Send(ctx, message); err != nil {
+		return false, fmt.Errorf("failed to text %s: %w", event, err)
+	}
+	return true, nil
+}
+
+func userOrEmpty(user *entity.User) *entity.User {
+	if user == nil {
+		return &entity.User{}
+	}
+	return user
+}
@@ -0,0 +1,196 @@
+package notifications_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/notifications"
+)
+
+// inMemoryNotifier records the messages it was asked to send, failing for the recipients in failFor
+type inMemoryNotifier struct {
+	mu       sync.Mutex
+	messages []notifications.Message
+	failFor  map[string]bool
+}
+
+func (n *inMemoryNotifier) Send(_ context.Context, message notifications.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.failFor[message.To] {
+		return fmt.Errorf("provider unavailable")
+	}
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func (n *inMemoryNotifier) recipients() []string {
+	var to []string
+	for _, message := range n.messages {
+		to = append(to, message.To)
+	}
+	return to
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func testAppointment() *entity.Appointment {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	return &entity.Appointment{
+		ID:         1,
+		BusinessID: 1,
+		StartTime:  start,
+		EndTime:    start.Add(30 * time.Minute),
+		Status:     entity.AppointmentStatusScheduled,
+		Client:     &entity.User{FullName: "Olena Client", Email: stringPtr("client@example.com"), Phone: stringPtr("+380501234567")},
+		Employee:   &entity.User{FullName: "Ivan Barber", Email: stringPtr("ivan@example.com")},
+		Service:    &entity.BusinessService{Name: "Haircut", Duration: 30},
+	}
+}
+
+func TestService_Notify(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err   error
+		email []string
+		sms   []string
+	}
+
+	business := &entity.Business{ID: 1, Name: "Sharp Cuts", Timezone: "Europe/Kyiv"}
+	kyiv, err := time.LoadLocation(business.Timezone)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	testCases := []struct {
+		name        string
+		event       string
+		appointment func() *entity.Appointment
+		failFor     map[string]bool
+		withoutSMS  bool
+		expected    expected
+	}{
+		{
+			name:        "positive: client emailed and texted, employee emailed",
+			event:       entity.EventAppointmentCreated,
+			appointment: testAppointment,
+			expected: expected{
+				email: []string{"client@example.com", "ivan@example.com"},
+				sms:   []string{"+380501234567"},
+			},
+		},
+		{
+			name:  "positive: missing contacts skipped",
+			event: entity.EventAppointmentCancelled,
+			appointment: func() *entity.Appointment {
+				a := testAppointment()
+				a.Client.Email = nil
+				a.Employee.Email = nil
+				return a
+			},
+			expected: expected{
+				sms: []string{"+380501234567"},
+			},
+		},
+		{
+			name:        "positive: sms provider not configured",
+			event:       entity.EventAppointmentReminder,
+			appointment: testAppointment,
+			withoutSMS:  true,
+			expected: expected{
+				email: []string{"client@example.com", "ivan@example.com"},
+			},
+		},
+		{
+			name:        "positive: event without templates ignored",
+			event:       "appointment.unknown",
+			appointment: testAppointment,
+		},
+		{
+			name:        "positive: failed recipient does not stop the others or fail the event",
+			event:       entity.EventAppointmentRescheduled,
+			appointment: testAppointment,
+			failFor:     map[string]bool{"client@example.com": true},
+			expected: expected{
+				email: []string{"ivan@example.com"},
+				sms:   []string{"+380501234567"},
+			},
+		},
+		{
+			name:        "negative: nothing sent",
+			event:       entity.EventAppointmentRescheduled,
+			appointment: testAppointment,
+			failFor:     map[string]bool{"client@example.com": true, "ivan@example.com": true},
+			withoutSMS:  true,
+			expected: expected{
+				err: fmt.Errorf("failed to email %[1]s: %[2]w\nfailed to email %[1]s: %[2]w", entity.EventAppointmentRescheduled, fmt.Errorf("provider unavailable")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			templates, err := notifications.DefaultTemplates()
+			require.NoError(t, err)
+
+			email := &inMemoryNotifier{failFor: tc.failFor}
+			sms := &inMemoryNotifier{}
+			var smsProvider notifications.Notifier = sms
+			if tc.withoutSMS {
+				smsProvider = nil
+			}
+			service := notifications.NewService(email, smsProvider, templates)
+
+			// Execute
+			err = service.Notify(ctx, tc.event, tc.appointment(), business, kyiv)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected.email, email.recipients())
+			assert.Equal(t, tc.expected.sms, sms.recipients())
+		})
+	}
+}
+
+func TestService_NotifyUsesLocationTimezone(t *testing.T) {
+	t.Parallel()
+
+	templates, err := notifications.DefaultTemplates()
+	require.NoError(t, err)
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	email := &inMemoryNotifier{}
+	service := notifications.NewService(email, nil, templates)
+
+	// 08:00 UTC is 10:00 at the branch in Kyiv, the business itself is in UTC
+	err = service.Notify(context.Background(), entity.EventAppointmentCreated, testAppointment(), &entity.Business{Name: "Sharp Cuts", Timezone: "UTC"}, kyiv)
+	require.NoError(t, err)
+
+	require.Len(t, email.messages, 2)
+	client, staff := email.messages[0], email.messages[1]
+	assert.Equal(t, notifications.ChannelEmail, client.Channel)
+	assert.Equal(t, "Your Haircut booking is confirmed", client.Subject)
+	assert.Contains(t, client.Text, "Hello Olena Client")
+	assert.Contains(t, client.Text, "Monday, 10 March 2025 10:00 - 10:30")
+	assert.Contains(t, client.HTML, "Sharp Cuts")
+	assert.Equal(t, "New booking: Haircut with Olena Client", staff.Subject)
+	assert.Contains(t, staff.Text, "Hello Ivan Barber")
+}
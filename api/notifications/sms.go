@@ -0,0 +1,79 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultSMSTimeout = 10 * time.Second
+
+type SMSGatewayConfig struct {
+	URL    string
+	APIKey string // sent as a bearer token when set
+	From   string
+}
+
+// SMSGatewayNotifier sends messages as SMS through an HTTP gateway. The message is posted as JSON:
+// {"from": "...", "to": "...", "text": "..."}, any 2xx response is a success.
+type SMSGatewayNotifier struct {
+	config SMSGatewayConfig
+	client *http.Client
+}
+
+// NewSMSGatewayNotifier creates the gateway adapter, a default client with a timeout is used when client is nil
+func NewSMSGatewayNotifier(config SMSGatewayConfig, client *http.Client) (*SMSGatewayNotifier, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("sms gateway url is required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: defaultSMSTimeout}
+	}
+
+	return &SMSGatewayNotifier{
+		config: config,
+		client: client,
+	}, nil
+}
+
+type smsRequest struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+func (n *SMSGatewayNotifier) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(smsRequest{
+		From: n.config.From,
+		To:   message.To,
+		Text: message.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode sms: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.APIKey)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sms gateway responded with %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
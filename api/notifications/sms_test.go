@@ -0,0 +1,71 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/notifications"
+)
+
+func TestSMSGatewayNotifier_Send(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		status int
+		err    string
+	}{
+		{
+			name:   "positive: message posted to the gateway",
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "negative: gateway rejects the message",
+			status: http.StatusBadRequest,
+			err:    "sms gateway responded with 400: invalid number",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received map[string]string
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				_ = json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(tc.status)
+				if tc.status >= 300 {
+					_, _ = w.Write([]byte("invalid number\n"))
+				}
+			}))
+			defer server.Close()
+
+			notifier, err := notifications.NewSMSGatewayNotifier(notifications.SMSGatewayConfig{
+				URL:    server.URL,
+				APIKey: "secret",
+				From:   "SharpCuts",
+			}, server.Client())
+			require.NoError(t, err)
+
+			// Execute
+			err = notifier.Send(context.Background(), notifications.Message{Channel: notifications.ChannelSMS, To: "+380501234567", Text: "See you at 10:00"})
+
+			// Assert
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, "Bearer secret", authorization)
+			assert.Equal(t, map[string]string{"from": "SharpCuts", "to": "+380501234567", "text": "See you at 10:00"}, received)
+		})
+	}
+}
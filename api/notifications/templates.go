@@ -0,0 +1,153 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// Templates holds the message templates of every event. Each event has a text file, <event>.txt.tmpl,
+// defining the "subject", "text" and optional "sms" templates, and an optional HTML file, <event>.html.tmpl,
+// defining "html". Events without templates are not notified.
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// TemplateData is passed to the templates. Times are in the timezone of the appointment's branch or business.
+type TemplateData struct {
+	Event string
+	// ForStaff is set for the message sent to the employee, the client gets the other one
+	ForStaff      bool
+	RecipientName string
+
+	Business    *entity.Business
+	Appointment *entity.Appointment
+	Client      *entity.User
+	Employee    *entity.User
+	Service     *entity.BusinessService
+	StartTime   time.Time
+	EndTime     time.Time
+	// HoldExpiresAt is the end of the hold of a held appointment, zero otherwise
+	HoldExpiresAt time.Time
+}
+
+// DefaultTemplates returns the templates shipped with the package
+func DefaultTemplates() (*Templates, error) {
+	sub, err := fs.Sub(defaultTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplates(sub)
+}
+
+// ParseTemplates reads the templates of every event from the root of fsys
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	t := &Templates{
+		text: map[string]*texttemplate.Template{},
+		html: map[string]*htmltemplate.Template{},
+	}
+
+	textFiles, err := fs.Glob(fsys, "*.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range textFiles {
+		event := strings.TrimSuffix(name, ".txt.tmpl")
+		tmpl, err := texttemplate.ParseFS(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if tmpl.Lookup("subject") == nil || tmpl.Lookup("text") == nil {
+			return nil, fmt.Errorf("%s must define subject and text", name)
+		}
+		t.text[event] = tmpl
+	}
+
+	htmlFiles, err := fs.Glob(fsys, "*.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range htmlFiles {
+		event := strings.TrimSuffix(name, ".html.tmpl")
+		if _, ok := t.text[event]; !ok {
+			return nil, fmt.Errorf("%s has no text template", name)
+		}
+		tmpl, err := htmltemplate.ParseFS(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if tmpl.Lookup("html") == nil {
+			return nil, fmt.Errorf("%s must define html", name)
+		}
+		t.html[event] = tmpl
+	}
+
+	return t, nil
+}
+
+// Has reports whether the event has templates
+func (t *Templates) Has(event string) bool {
+	_, ok := t.text[event]
+	return ok
+}
+
+// RenderEmail renders the subject, text and HTML body of the event's email
+func (t *Templates) RenderEmail(event string, data TemplateData) (Message, error) {
+	tmpl, ok := t.text[event]
+	if !ok {
+		return Message{}, fmt.Errorf("no templates for event %s", event)
+	}
+
+	message := Message{Channel: ChannelEmail}
+	var err error
+	if message.Subject, err = executeText(tmpl, "subject", data); err != nil {
+		return Message{}, err
+	}
+	message.Subject = strings.Join(strings.Fields(message.Subject), " ")
+	if message.Text, err = executeText(tmpl, "text", data); err != nil {
+		return Message{}, err
+	}
+
+	if html, ok := t.html[event]; ok {
+		var b bytes.Buffer
+		if err := html.ExecuteTemplate(&b, "html", data); err != nil {
+			return Message{}, fmt.Errorf("failed to render %s html: %w", event, err)
+		}
+		message.HTML = b.String()
+	}
+
+	return message, nil
+}
+
+// RenderSMS renders the event's SMS text, ok is false when the event has no SMS template
+func (t *Templates) RenderSMS(event string, data TemplateData) (Message, bool, error) {
+	tmpl, found := t.text[event]
+	if !found || tmpl.Lookup("sms") == nil {
+		return Message{}, false, nil
+	}
+
+	text, err := executeText(tmpl, "sms", data)
+	if err != nil {
+		return Message{}, false, err
+	}
+	return Message{Channel: ChannelSMS, Text: text}, true, nil
+}
+
+func executeText(tmpl *texttemplate.Template, name string, data TemplateData) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
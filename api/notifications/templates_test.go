@@ -0,0 +1,110 @@
+package notifications_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/notifications"
+)
+
+func TestDefaultTemplates(t *testing.T) {
+	t.Parallel()
+
+	templates, err := notifications.DefaultTemplates()
+	require.NoError(t, err)
+
+	appointment := testAppointment()
+	appointment.CancellationReason = stringPtr("client is ill")
+	expiresAt := appointment.StartTime.Add(-15 * time.Minute)
+	appointment.HoldExpiresAt = &expiresAt
+
+	events := []string{
+		entity.EventAppointmentCreated,
+		entity.EventAppointmentRescheduled,
+		entity.EventAppointmentCancelled,
+		entity.EventAppointmentReminder,
+		entity.EventAppointmentOffered,
+	}
+	for _, event := range events {
+		for _, forStaff := range []bool{false, true} {
+			data := notifications.TemplateData{
+				Event:         event,
+				ForStaff:      forStaff,
+				RecipientName: "Olena Client",
+				Business:      &entity.Business{Name: "Sharp Cuts"},
+				Appointment:   appointment,
+				Client:        appointment.Client,
+				Employee:      appointment.Employee,
+				Service:       appointment.Service,
+				StartTime:     appointment.StartTime,
+				EndTime:       appointment.EndTime,
+				HoldExpiresAt: expiresAt,
+			}
+
+			require.True(t, templates.Has(event), event)
+
+			message, err := templates.RenderEmail(event, data)
+			require.NoError(t, err, event)
+			assert.NotEmpty(t, message.Subject, event)
+			assert.NotContains(t, message.Subject, "\n", event)
+			assert.Contains(t, message.Text, "Haircut", event)
+
+			sms, ok, err := templates.RenderSMS(event, data)
+			require.NoError(t, err, event)
+			if ok {
+				assert.Equal(t, notifications.ChannelSMS, sms.Channel)
+				assert.Contains(t, sms.Text, "Sharp Cuts", event)
+			}
+		}
+	}
+}
+
+func TestParseTemplates(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		files fstest.MapFS
+		err   string
+	}{
+		{
+			name: "positive: text only",
+			files: fstest.MapFS{
+				"appointment.created.txt.tmpl": {Data: []byte(`{{define "subject"}}Booked{{end}}{{define "text"}}Hi{{end}}`)},
+			},
+		},
+		{
+			name: "negative: missing subject",
+			files: fstest.MapFS{
+				"appointment.created.txt.tmpl": {Data: []byte(`{{define "text"}}Hi{{end}}`)},
+			},
+			err: "appointment.created.txt.tmpl must define subject and text",
+		},
+		{
+			name: "negative: html without text",
+			files: fstest.MapFS{
+				"appointment.created.html.tmpl": {Data: []byte(`{{define "html"}}<p>Hi</p>{{end}}`)},
+			},
+			err: "appointment.created.html.tmpl has no text template",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := notifications.ParseTemplates(tc.files)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
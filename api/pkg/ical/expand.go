@@ -0,0 +1,114 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vadimpk/ppc-project/pkg/rrule"
+)
+
+// Occurrence is a single occurrence of an event. Events that do not recur have one occurrence.
+type Occurrence struct {
+	// Event is the event the occurrence belongs to, the replacing event for modified occurrences
+	Event *Event
+	Start time.Time
+	End   time.Time
+}
+
+// Expand returns the occurrences of the events that overlap [from, to), sorted by start.
+//
+// Recurring events are expanded with their RRULE without the occurrences in their EXDATEs.
+// An event with a RECURRENCE-ID replaces the occurrence of the recurring event with the same UID
+// that starts at that time, including cancelling it when the replacing event is cancelled.
+// Occurrences keep the local clock time of the event across DST changes.
+//
+// Events whose rule cannot be expanded are left out. Their errors are joined into the returned
+// error, which is returned together with the occurrences of the other events.
+func Expand(events []Event, from, to time.Time) ([]Occurrence, error) {
+	// Modified occurrences by UID and original start
+	replaced := make(map[string]map[int64]bool)
+	for _, event := range events {
+		if event.RecurrenceID.IsZero() {
+			continue
+		}
+		if replaced[event.UID] == nil {
+			replaced[event.UID] = make(map[int64]bool)
+		}
+		replaced[event.UID][event.RecurrenceID.Unix()] = true
+	}
+
+	var (
+		occurrences []Occurrence
+		errs        []error
+	)
+	for i := range events {
+		event := &events[i]
+
+		if event.RRule == "" || !event.RecurrenceID.IsZero() {
+			if overlaps(event.Start, event.End, from, to) {
+				occurrences = append(occurrences, Occurrence{Event: event, Start: event.Start, End: event.End})
+			}
+			continue
+		}
+
+		rule, err := rrule.Parse(event.RRule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("event %q: %w", event.UID, err))
+			continue
+		}
+
+		excluded := make(map[int64]bool, len(event.ExDates))
+		for _, exdate := range event.ExDates {
+			excluded[exdate.Unix()] = true
+		}
+
+		// Occurrences starting before from may still overlap it, the duration is at most
+		// a day longer in local time around DST changes
+		lookback := event.End.Sub(event.Start) + 24*time.Hour
+		for _, start := range rule.Between(event.Start, from.Add(-lookback), to) {
+			if excluded[start.Unix()] || replaced[event.UID][start.Unix()] {
+				continue
+			}
+
+			end := event.occurrenceEnd(start)
+			if overlaps(start, end, from, to) {
+				occurrences = append(occurrences, Occurrence{Event: event, Start: start, End: end})
+			}
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+
+	return occurrences, errors.Join(errs...)
+}
+
+// occurrenceEnd returns the end of the occurrence starting at start. All-day events keep their
+// number of days and other events keep their duration.
+func (e *Event) occurrenceEnd(start time.Time) time.Time {
+	if e.AllDay {
+		days := int(dateOf(e.End).Sub(dateOf(e.Start)).Hours() / 24)
+		return start.AddDate(0, 0, days)
+	}
+	return start.Add(e.End.Sub(e.Start))
+}
+
+// dateOf returns the calendar date of t in UTC, so differences between dates are whole days
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// overlaps reports whether [start, end) overlaps [from, to). Events without a duration
+// overlap the period they start in.
+func overlaps(start, end, from, to time.Time) bool {
+	if !start.Before(to) {
+		return false
+	}
+	if end.Equal(start) {
+		return !start.Before(from)
+	}
+	return end.After(from)
+}
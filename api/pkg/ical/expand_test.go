@@ -0,0 +1,118 @@
+package ical_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/pkg/ical"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2025, month, day, hour, 0, 0, 0, kyiv)
+	}
+	from, to := at(3, 1, 0), at(4, 1, 0)
+
+	testCases := []struct {
+		name     string
+		events   []ical.Event
+		expected [][2]time.Time
+		err      string
+	}{
+		{
+			name: "positive: single events overlapping the period",
+			events: []ical.Event{
+				{UID: "before", Start: at(2, 27, 9), End: at(2, 27, 10)},
+				{UID: "across start", Start: at(2, 28, 23), End: at(3, 1, 1)},
+				{UID: "inside", Start: at(3, 10, 9), End: at(3, 10, 10)},
+				{UID: "after", Start: at(4, 1, 0), End: at(4, 1, 1)},
+			},
+			expected: [][2]time.Time{
+				{at(2, 28, 23), at(3, 1, 1)},
+				{at(3, 10, 9), at(3, 10, 10)},
+			},
+		},
+		{
+			name: "positive: weekly event keeps its local time across DST",
+			events: []ical.Event{
+				{UID: "weekly", Start: at(3, 17, 9), End: at(3, 17, 10), RRule: "FREQ=WEEKLY"},
+			},
+			expected: [][2]time.Time{
+				{at(3, 17, 9), at(3, 17, 10)},
+				{at(3, 24, 9), at(3, 24, 10)},
+				{at(3, 31, 9), at(3, 31, 10)},
+			},
+		},
+		{
+			name: "positive: excluded, moved and cancelled occurrences",
+			events: []ical.Event{
+				{
+					UID:     "daily",
+					Start:   at(3, 3, 9),
+					End:     at(3, 3, 10),
+					RRule:   "FREQ=DAILY;COUNT=5",
+					ExDates: []time.Time{at(3, 4, 9).UTC()},
+				},
+				{UID: "daily", RecurrenceID: at(3, 5, 9), Start: at(3, 5, 14), End: at(3, 5, 15)},
+				{UID: "daily", RecurrenceID: at(3, 6, 9), Start: at(3, 6, 9), End: at(3, 6, 10), Status: ical.StatusCancelled},
+			},
+			expected: [][2]time.Time{
+				{at(3, 3, 9), at(3, 3, 10)},
+				{at(3, 5, 14), at(3, 5, 15)},
+				// The cancelled occurrence is returned with its event, so callers can skip it
+				{at(3, 6, 9), at(3, 6, 10)},
+				{at(3, 7, 9), at(3, 7, 10)},
+			},
+		},
+		{
+			name: "positive: recurring all-day event started before the period",
+			events: []ical.Event{
+				{UID: "monthly", Start: at(1, 31, 0), End: at(2, 1, 0), AllDay: true, RRule: "FREQ=MONTHLY"},
+				{UID: "two days", Start: at(2, 28, 0), End: at(3, 2, 0), AllDay: true, RRule: "FREQ=YEARLY"},
+			},
+			expected: [][2]time.Time{
+				{at(2, 28, 0), at(3, 2, 0)},
+				{at(3, 31, 0), at(4, 1, 0)},
+			},
+		},
+		{
+			name: "negative: unsupported rules are skipped and reported",
+			events: []ical.Event{
+				{UID: "monthly by weekday", Start: at(3, 3, 9), End: at(3, 3, 10), RRule: "FREQ=MONTHLY;BYDAY=1MO"},
+				{UID: "single", Start: at(3, 10, 9), End: at(3, 10, 10)},
+			},
+			expected: [][2]time.Time{
+				{at(3, 10, 9), at(3, 10, 10)},
+			},
+			err: `event "monthly by weekday": unsupported weekday "1MO"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			occurrences, err := ical.Expand(tc.events, from, to)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			actual := make([][2]time.Time, len(occurrences))
+			for i, o := range occurrences {
+				actual[i] = [2]time.Time{o.Start, o.End}
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
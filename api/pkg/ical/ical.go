@@ -0,0 +1,278 @@
+// Package ical reads and writes iCalendar (RFC 5545) calendars of events.
+//
+// Event times are written as local times of their location with a VTIMEZONE describing
+// the location, so calendar clients show them in the right zone across DST changes.
+// Times in UTC are written in UTC form.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+
+	TranspOpaque      = "OPAQUE"
+	TranspTransparent = "TRANSPARENT"
+
+	// maxLineLength is the limit of content lines in octets, longer lines are folded
+	maxLineLength = 75
+
+	localFormat = "20060102T150405"
+	utcFormat   = "20060102T150405Z"
+	dateFormat  = "20060102"
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string // identifies the product that created the calendar, e.g. "-//Company//Product//EN"
+	Name   string // shown by clients as the name of subscribed calendars, optional
+	Events []Event
+}
+
+// Event is a VEVENT component
+type Event struct {
+	// UID identifies the event across updates of the calendar and must not change
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	// Transp is TranspTransparent for events that do not block time, empty or TranspOpaque otherwise
+	Transp  string
+	Created time.Time // optional
+	Stamp   time.Time // when the calendar object was created
+
+	// AllDay events start and end at midnight and are written as dates
+	AllDay bool
+	// RRule is the recurrence rule of recurring events, see package rrule
+	RRule string
+	// ExDates are the start times of the occurrences excluded from the recurrence
+	ExDates []time.Time
+	// RecurrenceID is the original start time of the occurrence this event replaces,
+	// zero for events that are not a modified occurrence of a recurring event
+	RecurrenceID time.Time
+}
+
+// Encode writes the calendar
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{}
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", c.ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	for _, tz := range c.timezones() {
+		e.timezone(tz.loc, tz.from, tz.to)
+	}
+
+	for _, event := range c.Events {
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", event.UID)
+		e.line("DTSTAMP", event.Stamp.UTC().Format(utcFormat))
+		e.eventTime("DTSTART", event.Start, event.AllDay)
+		e.eventTime("DTEND", event.End, event.AllDay)
+		if !event.RecurrenceID.IsZero() {
+			e.eventTime("RECURRENCE-ID", event.RecurrenceID, event.AllDay)
+		}
+		if event.RRule != "" {
+			e.line("RRULE", event.RRule)
+		}
+		for _, exdate := range event.ExDates {
+			e.eventTime("EXDATE", exdate, event.AllDay)
+		}
+		if !event.Created.IsZero() {
+			e.line("CREATED", event.Created.UTC().Format(utcFormat))
+		}
+		e.line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			e.line("DESCRIPTION", escape(event.Description))
+		}
+		if event.Location != "" {
+			e.line("LOCATION", escape(event.Location))
+		}
+		if event.Status != "" {
+			e.line("STATUS", event.Status)
+		}
+		if event.Transp != "" {
+			e.line("TRANSP", event.Transp)
+		}
+		e.line("END", "VEVENT")
+	}
+
+	e.line("END", "VCALENDAR")
+
+	_, err := w.Write(e.buf.Bytes())
+	return err
+}
+
+// Marshal returns the encoded calendar
+func (c *Calendar) Marshal() []byte {
+	var buf bytes.Buffer
+	// Writing to a buffer does not fail
+	_ = c.Encode(&buf)
+	return buf.Bytes()
+}
+
+type timezoneRange struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// timezones returns the locations of the events other than UTC with the period their events cover
+func (c *Calendar) timezones() []timezoneRange {
+	ranges := make(map[string]*timezoneRange)
+	for _, event := range c.Events {
+		if event.AllDay {
+			continue
+		}
+		for _, t := range []time.Time{event.Start, event.End} {
+			loc := t.Location()
+			if loc == time.UTC {
+				continue
+			}
+
+			r, ok := ranges[loc.String()]
+			if !ok {
+				ranges[loc.String()] = &timezoneRange{loc: loc, from: t, to: t}
+				continue
+			}
+			if t.Before(r.from) {
+				r.from = t
+			}
+			if t.After(r.to) {
+				r.to = t
+			}
+		}
+	}
+
+	result := make([]timezoneRange, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].loc.String() < result[j].loc.String()
+	})
+	return result
+}
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it into lines of at most maxLineLength octets
+func (e *encoder) line(name, value string) {
+	line := name + ":" + value
+	limit := maxLineLength
+	for len(line) > limit {
+		// Never split a UTF-8 sequence
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		e.buf.WriteString(line[:cut])
+		e.buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = maxLineLength - 1
+	}
+	e.buf.WriteString(line)
+	e.buf.WriteString("\r\n")
+}
+
+// eventTime writes a date property for all-day events and a date-time property otherwise
+func (e *encoder) eventTime(name string, t time.Time, allDay bool) {
+	if allDay {
+		e.line(name+";VALUE=DATE", t.Format(dateFormat))
+		return
+	}
+	e.time(name, t)
+}
+
+// time writes a date-time property in the location of t
+func (e *encoder) time(name string, t time.Time) {
+	if t.Location() == time.UTC {
+		e.line(name, t.Format(utcFormat))
+		return
+	}
+	e.line(name+";TZID="+t.Location().String(), t.Format(localFormat))
+}
+
+// timezone writes a VTIMEZONE with the observances of the location in effect between from and to
+func (e *encoder) timezone(loc *time.Location, from, to time.Time) {
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", loc.String())
+
+	start, end := from.In(loc).ZoneBounds()
+	if start.IsZero() {
+		// The zone has been in effect forever
+		name, offset := from.In(loc).Zone()
+		e.observance(from.In(loc).IsDST(), time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), name, offset, offset)
+	} else {
+		e.transition(loc, start)
+	}
+	for !end.IsZero() && !end.After(to) {
+		e.transition(loc, end)
+		_, end = end.In(loc).ZoneBounds()
+	}
+
+	e.line("END", "VTIMEZONE")
+}
+
+// transition writes the observance starting at the instant the location changes its offset
+func (e *encoder) transition(loc *time.Location, at time.Time) {
+	_, offsetFrom := at.Add(-time.Second).In(loc).Zone()
+	after := at.In(loc)
+	name, offsetTo := after.Zone()
+
+	// The onset is the local time before the transition
+	onset := at.In(time.FixedZone("", offsetFrom))
+	e.observance(after.IsDST(), onset, name, offsetFrom, offsetTo)
+}
+
+func (e *encoder) observance(dst bool, onset time.Time, name string, offsetFrom, offsetTo int) {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+
+	e.line("BEGIN", kind)
+	e.line("DTSTART", onset.Format(localFormat))
+	e.line("TZOFFSETFROM", formatOffset(offsetFrom))
+	e.line("TZOFFSETTO", formatOffset(offsetTo))
+	e.line("TZNAME", escape(name))
+	e.line("END", kind)
+}
+
+// formatOffset formats an offset in seconds east of UTC as +HHMM
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// escape escapes a text value
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
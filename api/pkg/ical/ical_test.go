@@ -0,0 +1,142 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/pkg/ical"
+)
+
+func TestCalendar_Marshal(t *testing.T) {
+	t.Parallel()
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	stamp := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	calendar := &ical.Calendar{
+		ProdID: "-//PPC//Bookings//EN",
+		Name:   "Anna, Salon",
+		Events: []ical.Event{
+			{
+				UID:     "appointment-1@ppc",
+				Start:   time.Date(2025, 3, 28, 10, 0, 0, 0, kyiv),
+				End:     time.Date(2025, 3, 28, 11, 0, 0, 0, kyiv),
+				Summary: "Haircut; wash",
+				Status:  ical.StatusConfirmed,
+				Stamp:   stamp,
+			},
+			{
+				UID:         "appointment-2@ppc",
+				Start:       time.Date(2025, 4, 2, 10, 0, 0, 0, kyiv),
+				End:         time.Date(2025, 4, 2, 11, 0, 0, 0, kyiv),
+				Summary:     "Coloring",
+				Description: "Cancelled:\nclient is ill",
+				Status:      ical.StatusCancelled,
+				Stamp:       stamp,
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//PPC//Bookings//EN",
+		"CALSCALE:GREGORIAN",
+		`X-WR-CALNAME:Anna\, Salon`,
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Kyiv",
+		"BEGIN:STANDARD",
+		"DTSTART:20241027T040000",
+		"TZOFFSETFROM:+0300",
+		"TZOFFSETTO:+0200",
+		"TZNAME:EET",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20250330T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0300",
+		"TZNAME:EEST",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:appointment-1@ppc",
+		"DTSTAMP:20250301T120000Z",
+		"DTSTART;TZID=Europe/Kyiv:20250328T100000",
+		"DTEND;TZID=Europe/Kyiv:20250328T110000",
+		`SUMMARY:Haircut\; wash`,
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:appointment-2@ppc",
+		"DTSTAMP:20250301T120000Z",
+		"DTSTART;TZID=Europe/Kyiv:20250402T100000",
+		"DTEND;TZID=Europe/Kyiv:20250402T110000",
+		"SUMMARY:Coloring",
+		`DESCRIPTION:Cancelled:\nclient is ill`,
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	assert.Equal(t, expected, string(calendar.Marshal()))
+}
+
+func TestCalendar_MarshalUTC(t *testing.T) {
+	t.Parallel()
+
+	calendar := &ical.Calendar{
+		ProdID: "-//PPC//Bookings//EN",
+		Events: []ical.Event{{
+			UID:     "appointment-1@ppc",
+			Start:   time.Date(2025, 3, 28, 10, 0, 0, 0, time.UTC),
+			End:     time.Date(2025, 3, 28, 11, 0, 0, 0, time.UTC),
+			Summary: "Haircut",
+			Stamp:   time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	output := string(calendar.Marshal())
+
+	assert.NotContains(t, output, "VTIMEZONE")
+	assert.Contains(t, output, "\r\nDTSTART:20250328T100000Z\r\n")
+	assert.Contains(t, output, "\r\nDTEND:20250328T110000Z\r\n")
+}
+
+func TestCalendar_MarshalFoldsLongLines(t *testing.T) {
+	t.Parallel()
+
+	calendar := &ical.Calendar{
+		ProdID: "-//PPC//Bookings//EN",
+		Events: []ical.Event{{
+			UID:         "appointment-1@ppc",
+			Start:       time.Date(2025, 3, 28, 10, 0, 0, 0, time.UTC),
+			End:         time.Date(2025, 3, 28, 11, 0, 0, 0, time.UTC),
+			Summary:     "Haircut",
+			Description: strings.Repeat("Стрижка ", 20),
+			Stamp:       time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	output := string(calendar.Marshal())
+
+	lines := strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n")
+	var description strings.Builder
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75, "line %d is too long", i)
+		if strings.HasPrefix(line, "DESCRIPTION:") {
+			description.WriteString(line)
+			for _, next := range lines[i+1:] {
+				if !strings.HasPrefix(next, " ") {
+					break
+				}
+				description.WriteString(next[1:])
+			}
+		}
+	}
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("Стрижка ", 20), description.String())
+}
@@ -0,0 +1,381 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// property is a parsed content line
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// component is a parsed BEGIN/END block with its properties and nested components
+type component struct {
+	Name       string
+	Properties []property
+	Children   []*component
+}
+
+func (c *component) property(name string) (property, bool) {
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+func (c *component) value(name string) string {
+	p, _ := c.property(name)
+	return p.Value
+}
+
+// Parse reads the events of a calendar.
+//
+// Times with a TZID are placed in that location. Unknown TZIDs, such as the Windows zone names
+// of some clients, fall back to the standard offset of the VTIMEZONE of the calendar.
+// Floating times and dates of all-day events have no zone and are placed in loc.
+// Events without DTEND end after their DURATION, all-day events last a day by default.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	root, err := parseComponents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendar *component
+	for _, c := range root.Children {
+		if c.Name == "VCALENDAR" {
+			calendar = c
+			break
+		}
+	}
+	if calendar == nil {
+		return nil, fmt.Errorf("no VCALENDAR found")
+	}
+
+	p := &parser{loc: loc, zones: make(map[string]*time.Location)}
+	for _, c := range calendar.Children {
+		if c.Name == "VTIMEZONE" {
+			p.addTimezone(c)
+		}
+	}
+
+	var events []Event
+	for _, c := range calendar.Children {
+		if c.Name != "VEVENT" {
+			continue
+		}
+
+		event, err := p.event(c)
+		if err != nil {
+			if uid := c.value("UID"); uid != "" {
+				return nil, fmt.Errorf("invalid event %q: %w", uid, err)
+			}
+			return nil, fmt.Errorf("invalid event: %w", err)
+		}
+		events = append(events, *event)
+	}
+
+	return events, nil
+}
+
+// parseComponents unfolds the content lines and builds the tree of components under an unnamed root
+func parseComponents(r io.Reader) (*component, error) {
+	root := &component{}
+	stack := []*component{root}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		// Folded lines continue with a single space or tab
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	for i, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		current := stack[len(stack)-1]
+		switch prop.Name {
+		case "BEGIN":
+			child := &component{Name: strings.ToUpper(prop.Value)}
+			current.Children = append(current.Children, child)
+			stack = append(stack, child)
+		case "END":
+			if len(stack) == 1 || current.Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			current.Properties = append(current.Properties, prop)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("%s is not closed", stack[len(stack)-1].Name)
+	}
+
+	return root, nil
+}
+
+// parseProperty parses a content line of the form NAME;PARAM=VALUE;PARAM="VALUE":VALUE
+func parseProperty(line string) (property, error) {
+	nameEnd := strings.IndexAny(line, ";:")
+	if nameEnd <= 0 {
+		return property{}, fmt.Errorf("invalid content line %q", line)
+	}
+	prop := property{Name: strings.ToUpper(line[:nameEnd]), Params: make(map[string]string)}
+
+	rest := line[nameEnd:]
+	for rest[0] == ';' {
+		rest = rest[1:]
+		name, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return property{}, fmt.Errorf("invalid parameter in %q", line)
+		}
+		rest = value
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return property{}, fmt.Errorf("unterminated parameter value in %q", line)
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return property{}, fmt.Errorf("invalid content line %q", line)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		prop.Params[strings.ToUpper(name)] = value
+
+		if rest == "" {
+			return property{}, fmt.Errorf("invalid content line %q", line)
+		}
+	}
+	if rest[0] != ':' {
+		return property{}, fmt.Errorf("invalid content line %q", line)
+	}
+	prop.Value = rest[1:]
+
+	return prop, nil
+}
+
+type parser struct {
+	// loc is the location of floating times and dates
+	loc *time.Location
+	// zones are the fallback locations of the VTIMEZONEs by TZID
+	zones map[string]*time.Location
+}
+
+// addTimezone registers a fixed zone with the current standard offset of the VTIMEZONE,
+// used for TZIDs that are not IANA names
+func (p *parser) addTimezone(c *component) {
+	tzid := c.value("TZID")
+	if tzid == "" {
+		return
+	}
+
+	// Observances are not ordered, the standard one with the latest onset is the current one.
+	// Zones without a standard observance use their latest observance of any kind.
+	var current *component
+	for _, observance := range c.Children {
+		if observance.Name != "STANDARD" && observance.Name != "DAYLIGHT" {
+			continue
+		}
+		switch {
+		case current == nil:
+			current = observance
+		case observance.Name == "STANDARD" && current.Name != "STANDARD":
+			current = observance
+		case observance.Name == current.Name && observance.value("DTSTART") > current.value("DTSTART"):
+			current = observance
+		}
+	}
+	if current == nil {
+		return
+	}
+
+	offset, err := parseOffset(current.value("TZOFFSETTO"))
+	if err != nil {
+		return
+	}
+	p.zones[tzid] = time.FixedZone(tzid, offset)
+}
+
+func (p *parser) location(tzid string) *time.Location {
+	if tzid == "" {
+		return p.loc
+	}
+	if loc, err := time.LoadLocation(tzid); err == nil && tzid != "Local" {
+		return loc
+	}
+	if loc, ok := p.zones[tzid]; ok {
+		return loc
+	}
+	return p.loc
+}
+
+func (p *parser) event(c *component) (*Event, error) {
+	event := &Event{
+		UID:         c.value("UID"),
+		Summary:     unescape(c.value("SUMMARY")),
+		Description: unescape(c.value("DESCRIPTION")),
+		Location:    unescape(c.value("LOCATION")),
+		Status:      strings.ToUpper(c.value("STATUS")),
+		Transp:      strings.ToUpper(c.value("TRANSP")),
+		RRule:       c.value("RRULE"),
+	}
+
+	start, ok := c.property("DTSTART")
+	if !ok {
+		return nil, fmt.Errorf("DTSTART is required")
+	}
+	var err error
+	event.Start, event.AllDay, err = p.time(start, start.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+
+	if end, ok := c.property("DTEND"); ok {
+		event.End, _, err = p.time(end, end.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DTEND: %w", err)
+		}
+	} else if duration, ok := c.property("DURATION"); ok {
+		event.End, err = addDuration(event.Start, duration.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DURATION: %w", err)
+		}
+	} else if event.AllDay {
+		event.End = event.Start.AddDate(0, 0, 1)
+	} else {
+		event.End = event.Start
+	}
+	if event.End.Before(event.Start) {
+		return nil, fmt.Errorf("event ends before it starts")
+	}
+
+	if id, ok := c.property("RECURRENCE-ID"); ok {
+		event.RecurrenceID, _, err = p.time(id, id.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RECURRENCE-ID: %w", err)
+		}
+	}
+
+	for _, prop := range c.Properties {
+		if prop.Name != "EXDATE" {
+			continue
+		}
+		for _, value := range strings.Split(prop.Value, ",") {
+			exdate, _, err := p.time(prop, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid EXDATE: %w", err)
+			}
+			event.ExDates = append(event.ExDates, exdate)
+		}
+	}
+
+	if created := c.value("CREATED"); created != "" {
+		event.Created, _ = time.Parse(utcFormat, created)
+	}
+	if stamp := c.value("DTSTAMP"); stamp != "" {
+		event.Stamp, _ = time.Parse(utcFormat, stamp)
+	}
+
+	return event, nil
+}
+
+// time parses a date or date-time value of the property, reporting whether it is a date
+func (p *parser) time(prop property, value string) (time.Time, bool, error) {
+	if prop.Params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, p.loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcFormat, value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation(localFormat, value, p.location(prop.Params["TZID"]))
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// addDuration adds an RFC 5545 duration such as "PT1H30M" or "P1D" to t.
+// Days and weeks are calendar days, so they keep the clock time across DST changes.
+func addDuration(t time.Time, value string) (time.Time, error) {
+	match := durationPattern.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return time.Time{}, fmt.Errorf("invalid duration %q", value)
+	}
+
+	parts := make([]int, 5)
+	for i, s := range match[2:] {
+		if s != "" {
+			parts[i], _ = strconv.Atoi(s)
+		}
+	}
+	sign := 1
+	if match[1] == "-" {
+		sign = -1
+	}
+
+	days := sign * (parts[0]*7 + parts[1])
+	clock := time.Duration(sign) * (time.Duration(parts[2])*time.Hour + time.Duration(parts[3])*time.Minute + time.Duration(parts[4])*time.Second)
+	return t.AddDate(0, 0, days).Add(clock), nil
+}
+
+// parseOffset parses a UTC offset such as "+0200" or "-053000" into seconds east of UTC
+func parseOffset(value string) (int, error) {
+	if len(value) != 5 && len(value) != 7 || (value[0] != '+' && value[0] != '-') {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+
+	digits := value[1:]
+	if len(digits) == 4 {
+		digits += "00"
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+
+	offset := n/10000*3600 + n/100%100*60 + n%100
+	if value[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+// unescape reverses escape
+func unescape(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
@@ -0,0 +1,232 @@
+package ical_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/pkg/ical"
+)
+
+func calendarOf(lines ...string) string {
+	lines = append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Test//EN"}, lines...)
+	return strings.Join(append(lines, "END:VCALENDAR"), "\r\n") + "\r\n"
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+	// Default location of floating times and dates
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		calendar string
+		expected []ical.Event
+		err      error
+	}{
+		{
+			name: "positive: times in a named zone, UTC and floating",
+			calendar: calendarOf(
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTART;TZID=Europe/Kyiv:20250310T090000",
+				"DTEND;TZID=Europe/Kyiv:20250310T100000",
+				"SUMMARY:Dentist\\, checkup",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:2",
+				"DTSTART:20250310T120000Z",
+				"DTEND:20250310T123000Z",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:3",
+				"DTSTART:20250310T150000",
+				"DURATION:PT1H30M",
+				"END:VEVENT",
+			),
+			expected: []ical.Event{
+				{
+					UID:     "1",
+					Start:   time.Date(2025, 3, 10, 9, 0, 0, 0, kyiv),
+					End:     time.Date(2025, 3, 10, 10, 0, 0, 0, kyiv),
+					Summary: "Dentist, checkup",
+				},
+				{
+					UID:   "2",
+					Start: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+					End:   time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC),
+				},
+				{
+					UID:   "3",
+					Start: time.Date(2025, 3, 10, 15, 0, 0, 0, warsaw),
+					End:   time.Date(2025, 3, 10, 16, 30, 0, 0, warsaw),
+				},
+			},
+		},
+		{
+			name: "positive: unknown zone falls back to the standard offset of its VTIMEZONE",
+			calendar: calendarOf(
+				"BEGIN:VTIMEZONE",
+				"TZID:FLE Standard Time",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:16010325T030000",
+				"TZOFFSETFROM:+0200",
+				"TZOFFSETTO:+0300",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:16011028T040000",
+				"TZOFFSETFROM:+0300",
+				"TZOFFSETTO:+0200",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+				"BEGIN:VEVENT",
+				"UID:1",
+				`DTSTART;TZID="FLE Standard Time":20250110T090000`,
+				`DTEND;TZID="FLE Standard Time":20250110T100000`,
+				"END:VEVENT",
+			),
+			expected: []ical.Event{
+				{
+					UID:   "1",
+					Start: time.Date(2025, 1, 10, 7, 0, 0, 0, time.UTC),
+					End:   time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "positive: all-day events, recurrence and folded lines",
+			calendar: calendarOf(
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTART;VALUE=DATE:20250310",
+				"RRULE:FREQ=WEEKLY;",
+				" COUNT=3",
+				"EXDATE;VALUE=DATE:20250317,20250324",
+				"TRANSP:TRANSPARENT",
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"DESCRIPTION:Reminder",
+				"END:VALARM",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:1",
+				"RECURRENCE-ID;VALUE=DATE:20250317",
+				"DTSTART;VALUE=DATE:20250318",
+				"DTEND;VALUE=DATE:20250320",
+				"STATUS:cancelled",
+				"END:VEVENT",
+			),
+			expected: []ical.Event{
+				{
+					UID:     "1",
+					Start:   time.Date(2025, 3, 10, 0, 0, 0, 0, warsaw),
+					End:     time.Date(2025, 3, 11, 0, 0, 0, 0, warsaw),
+					Transp:  ical.TranspTransparent,
+					AllDay:  true,
+					RRule:   "FREQ=WEEKLY;COUNT=3",
+					ExDates: []time.Time{time.Date(2025, 3, 17, 0, 0, 0, 0, warsaw), time.Date(2025, 3, 24, 0, 0, 0, 0, warsaw)},
+				},
+				{
+					UID:          "1",
+					Start:        time.Date(2025, 3, 18, 0, 0, 0, 0, warsaw),
+					End:          time.Date(2025, 3, 20, 0, 0, 0, 0, warsaw),
+					Status:       ical.StatusCancelled,
+					AllDay:       true,
+					RecurrenceID: time.Date(2025, 3, 17, 0, 0, 0, 0, warsaw),
+				},
+			},
+		},
+		{
+			name:     "negative: not a calendar",
+			calendar: "BEGIN:VEVENT\r\nEND:VEVENT\r\n",
+			err:      fmt.Errorf("no VCALENDAR found"),
+		},
+		{
+			name:     "negative: component not closed",
+			calendar: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+			err:      fmt.Errorf("line 3: unexpected END:VCALENDAR"),
+		},
+		{
+			name:     "negative: event without a start",
+			calendar: calendarOf("BEGIN:VEVENT", "UID:1", "END:VEVENT"),
+			err:      fmt.Errorf(`invalid event "1": DTSTART is required`),
+		},
+		{
+			name: "negative: event ending before its start",
+			calendar: calendarOf(
+				"BEGIN:VEVENT",
+				"DTSTART:20250310T100000Z",
+				"DTEND:20250310T090000Z",
+				"END:VEVENT",
+			),
+			err: fmt.Errorf("invalid event: event ends before it starts"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			events, err := ical.Parse(strings.NewReader(tc.calendar), warsaw)
+
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, len(tc.expected))
+			for i, expected := range tc.expected {
+				actual := events[i]
+				assert.Equal(t, expected.UID, actual.UID)
+				assert.True(t, expected.Start.Equal(actual.Start), "start %s, got %s", expected.Start, actual.Start)
+				assert.True(t, expected.End.Equal(actual.End), "end %s, got %s", expected.End, actual.End)
+				assert.Equal(t, expected.Summary, actual.Summary)
+				assert.Equal(t, expected.Status, actual.Status)
+				assert.Equal(t, expected.Transp, actual.Transp)
+				assert.Equal(t, expected.AllDay, actual.AllDay)
+				assert.Equal(t, expected.RRule, actual.RRule)
+				assert.Equal(t, expected.ExDates, actual.ExDates)
+				assert.True(t, expected.RecurrenceID.Equal(actual.RecurrenceID))
+			}
+		})
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	calendar := &ical.Calendar{
+		ProdID: "-//PPC//Bookings//EN",
+		Events: []ical.Event{
+			{
+				UID:         "appointment-1@ppc",
+				Start:       time.Date(2025, 3, 28, 10, 0, 0, 0, kyiv),
+				End:         time.Date(2025, 3, 28, 11, 0, 0, 0, kyiv),
+				Summary:     "Haircut; wash, " + strings.Repeat("long ", 20),
+				Description: "Line one\nline two",
+				Status:      ical.StatusConfirmed,
+				Stamp:       time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	events, err := ical.Parse(strings.NewReader(string(calendar.Marshal())), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, calendar.Events[0].Summary, events[0].Summary)
+	assert.Equal(t, calendar.Events[0].Description, events[0].Description)
+	assert.Equal(t, "Europe/Kyiv", events[0].Start.Location().String())
+	assert.True(t, calendar.Events[0].Start.Equal(events[0].Start))
+	assert.True(t, calendar.Events[0].Stamp.Equal(events[0].Stamp))
+}
@@ -0,0 +1,65 @@
+// Package netguard restricts the addresses that requests made on behalf of users may connect to,
+// so user supplied URLs cannot reach the loopback, private or cloud metadata addresses of the server's network.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// maxRedirects is the number of redirects followed, like the default of net/http
+const maxRedirects = 10
+
+// ErrAddressNotAllowed is returned when a request would connect to an address the policy rejects
+var ErrAddressNotAllowed = errors.New("address is not allowed")
+
+// AddressPolicy reports whether connections to the address are allowed
+type AddressPolicy func(addr netip.Addr) bool
+
+// PublicAddresses allows public unicast addresses only
+func PublicAddresses(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not routed on the internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewClient creates a client connecting only to the addresses the policy allows. The address is checked right
+// before connecting, so redirects and hosts resolving to another address than when validated are checked as well.
+// Redirects to hosts given as addresses the policy rejects are refused before they are followed.
+// Proxies are not used, they would connect on behalf of the client.
+func NewClient(policy AddressPolicy, timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !policy(addrPort.Addr()) {
+				return ErrAddressNotAllowed
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil && !policy(addr) {
+				return ErrAddressNotAllowed
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package netguard_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/pkg/netguard"
+)
+
+func TestPublicAddresses(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		addr    string
+		allowed bool
+	}{
+		{addr: "93.184.216.34", allowed: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", allowed: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "0.0.0.0"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "100.64.0.1"},
+		{addr: "169.254.169.254"},
+		{addr: "fd00::1"},
+		{addr: "fe80::1"},
+		{addr: "224.0.0.1"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.allowed, netguard.PublicAddresses(netip.MustParseAddr(tc.addr)), tc.addr)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback, which the public policy refuses before connecting
+	_, err := netguard.NewClient(netguard.PublicAddresses, time.Second).Get(server.URL)
+	assert.True(t, errors.Is(err, netguard.ErrAddressNotAllowed), err)
+	assert.Zero(t, hits.Load())
+
+	client := netguard.NewClient(func(addr netip.Addr) bool {
+		return addr.IsLoopback()
+	}, time.Second)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Redirects to refused addresses are not followed
+	_, err = client.Get(server.URL + "/redirect")
+	assert.True(t, errors.Is(err, netguard.ErrAddressNotAllowed), err)
+}
@@ -0,0 +1,282 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used for appointment series:
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY and WKST.
+package rrule
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the iteration of rules without COUNT or UNTIL
+const maxPeriods = 10000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	// Count is the number of occurrences, 0 when not limited by count
+	Count int
+	// Until is the last moment an occurrence may start at, zero when not limited by date
+	Until time.Time
+	// ByDay lists the weekdays of weekly rules, the weekday of the start is used when empty
+	ByDay []time.Weekday
+	// WeekStart is the first day of the weeks counted by weekly intervals, Monday by default
+	WeekStart time.Weekday
+
+	// untilDate is set when UNTIL is a date, which includes every occurrence on that local date
+	untilDate bool
+}
+
+// Parse parses a recurrence rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// An "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			switch freq := Frequency(strings.ToUpper(value)); freq {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = freq
+			default:
+				return nil, fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid interval %q", value)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid count %q", value)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, isDate, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+			rule.untilDate = isDate
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(value), ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("unsupported weekday %q", day)
+				}
+				// Repeated weekdays would produce the same occurrence twice
+				if !slices.Contains(rule.ByDay, weekday) {
+					rule.ByDay = append(rule.ByDay, weekday)
+				}
+			}
+		case "WKST":
+			weekday, ok := weekdays[strings.ToUpper(value)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported weekday %q", value)
+			}
+			rule.WeekStart = weekday
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("frequency is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("count and until cannot be used together")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, fmt.Errorf("weekdays are only supported for weekly rules")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	// Floating times have no zone of their own and are read as UTC
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid until %q", value)
+}
+
+// String formats the rule in its canonical form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.untilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, weekday := range r.ByDay {
+			days[i] = strings.ToUpper(weekday.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.WeekStart.String()[:2]))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns up to limit start times of the rule, beginning with the first one at or after start.
+// Occurrences keep the clock time of start in its location, so they do not shift across DST changes.
+func (r *Rule) Occurrences(start time.Time, limit int) []time.Time {
+	var result []time.Time
+	r.iterate(start, func(t time.Time) bool {
+		if len(result) == limit {
+			return false
+		}
+		result = append(result, t)
+		return true
+	})
+	return result
+}
+
+// Between returns the start times of the rule that fall within [from, to)
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	var result []time.Time
+	r.iterate(start, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return true
+	})
+	return result
+}
+
+// iterate calls yield for every occurrence in order until the rule ends or yield returns false
+func (r *Rule) iterate(start time.Time, yield func(time.Time) bool) {
+	emitted := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range r.periodCandidates(start, period*r.Interval) {
+			if t.Before(start) {
+				continue
+			}
+			if r.afterUntil(t) {
+				return
+			}
+			if !yield(t) {
+				return
+			}
+			emitted++
+			if r.Count > 0 && emitted == r.Count {
+				return
+			}
+		}
+	}
+}
+
+// periodCandidates returns the candidate start times of the period offset by the given number of frequency units
+func (r *Rule) periodCandidates(start time.Time, offset int) []time.Time {
+	hour, minute, second := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, start.Nanosecond(), start.Location())
+	}
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{at(start.Year(), start.Month(), start.Day()+offset)}
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+
+		weekStart := start.Day() - r.weekOffset(start.Weekday()) + 7*offset
+		candidates := make([]time.Time, 0, len(days))
+		for _, weekday := range days {
+			candidates = append(candidates, at(start.Year(), start.Month(), weekStart+r.weekOffset(weekday)))
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].Before(candidates[j])
+		})
+		return candidates
+	case Monthly:
+		// Months without the day of the start are skipped
+		first := time.Date(start.Year(), start.Month()+time.Month(offset), 1, 0, 0, 0, 0, start.Location())
+		if start.Day() > daysIn(first.Year(), first.Month()) {
+			return nil
+		}
+		return []time.Time{at(first.Year(), first.Month(), start.Day())}
+	case Yearly:
+		year := start.Year() + offset
+		if start.Day() > daysIn(year, start.Month()) {
+			return nil
+		}
+		return []time.Time{at(year, start.Month(), start.Day())}
+	}
+	return nil
+}
+
+func (r *Rule) afterUntil(t time.Time) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+	if r.untilDate {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).After(r.Until)
+	}
+	return t.After(r.Until)
+}
+
+// weekOffset returns the number of days from the start of the week to the weekday
+func (r *Rule) weekOffset(weekday time.Weekday) int {
+	return (int(weekday) - int(r.WeekStart) + 7) % 7
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
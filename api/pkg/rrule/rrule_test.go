@@ -0,0 +1,227 @@
+package rrule_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/pkg/rrule"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		rule     string
+		expected string
+		err      error
+	}{
+		{
+			name:     "positive: weekly with interval and weekdays",
+			rule:     "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10",
+			expected: "FREQ=WEEKLY;INTERVAL=2;COUNT=10;BYDAY=MO,TH",
+		},
+		{
+			name:     "positive: daily until a date",
+			rule:     "FREQ=DAILY;UNTIL=20250131",
+			expected: "FREQ=DAILY;UNTIL=20250131",
+		},
+		{
+			name:     "positive: monthly until a time",
+			rule:     "freq=monthly;until=20250131T100000Z",
+			expected: "FREQ=MONTHLY;UNTIL=20250131T100000Z",
+		},
+		{
+			name:     "positive: week start other than Monday",
+			rule:     "FREQ=WEEKLY;WKST=SU;BYDAY=SU,TU",
+			expected: "FREQ=WEEKLY;BYDAY=SU,TU;WKST=SU",
+		},
+		{
+			name:     "positive: repeated weekdays are kept once",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,TH,MO",
+			expected: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+		{
+			name:     "positive: Monday week start is the default",
+			rule:     "FREQ=WEEKLY;WKST=MO",
+			expected: "FREQ=WEEKLY",
+		},
+		{
+			name: "negative: missing frequency",
+			rule: "COUNT=3",
+			err:  fmt.Errorf("frequency is required"),
+		},
+		{
+			name: "negative: unsupported frequency",
+			rule: "FREQ=HOURLY",
+			err:  fmt.Errorf("unsupported frequency %q", "HOURLY"),
+		},
+		{
+			name: "negative: count and until together",
+			rule: "FREQ=DAILY;COUNT=3;UNTIL=20250131",
+			err:  fmt.Errorf("count and until cannot be used together"),
+		},
+		{
+			name: "negative: invalid interval",
+			rule: "FREQ=DAILY;INTERVAL=0",
+			err:  fmt.Errorf("invalid interval %q", "0"),
+		},
+		{
+			name: "negative: weekdays on a monthly rule",
+			rule: "FREQ=MONTHLY;BYDAY=MO",
+			err:  fmt.Errorf("weekdays are only supported for weekly rules"),
+		},
+		{
+			name: "negative: unsupported part",
+			rule: "FREQ=WEEKLY;BYSETPOS=1",
+			err:  fmt.Errorf("unsupported rule part %q", "BYSETPOS"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := rrule.Parse(tc.rule)
+
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, rule.String())
+			}
+		})
+	}
+}
+
+func TestRule_Occurrences(t *testing.T) {
+	t.Parallel()
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	// Wednesday
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 10, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		limit    int
+		expected []time.Time
+	}{
+		{
+			name:  "positive: weekly on the weekday of the start",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			start: start,
+			limit: 10,
+			expected: []time.Time{
+				date(2025, 1, 1), date(2025, 1, 8), date(2025, 1, 15),
+			},
+		},
+		{
+			name:  "positive: biweekly on two weekdays skips days before the start",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=4",
+			start: start,
+			limit: 10,
+			expected: []time.Time{
+				date(2025, 1, 2), date(2025, 1, 13), date(2025, 1, 16), date(2025, 1, 27),
+			},
+		},
+		{
+			name:  "positive: repeated weekdays occur once a week",
+			rule:  "FREQ=WEEKLY;BYDAY=TH,TH;COUNT=2",
+			start: start,
+			limit: 10,
+			expected: []time.Time{
+				date(2025, 1, 2), date(2025, 1, 9),
+			},
+		},
+		{
+			name:  "positive: biweekly with weeks starting on Sunday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,TU;WKST=SU;COUNT=3",
+			start: start,
+			limit: 10,
+			expected: []time.Time{
+				date(2025, 1, 12), date(2025, 1, 14), date(2025, 1, 26),
+			},
+		},
+		{
+			name:  "positive: until date is inclusive",
+			rule:  "FREQ=DAILY;INTERVAL=3;UNTIL=20250107",
+			start: start,
+			limit: 10,
+			expected: []time.Time{
+				date(2025, 1, 1), date(2025, 1, 4), date(2025, 1, 7),
+			},
+		},
+		{
+			name:  "positive: monthly skips months without the day",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: date(2025, 1, 31),
+			limit: 10,
+			expected: []time.Time{
+				date(2025, 1, 31), date(2025, 3, 31), date(2025, 5, 31),
+			},
+		},
+		{
+			name:  "positive: limited rule without an end",
+			rule:  "FREQ=YEARLY",
+			start: start,
+			limit: 2,
+			expected: []time.Time{
+				date(2025, 1, 1), date(2026, 1, 1),
+			},
+		},
+		{
+			name:  "positive: local clock time kept across DST",
+			rule:  "FREQ=WEEKLY;COUNT=2",
+			start: time.Date(2025, 3, 24, 9, 0, 0, 0, kyiv),
+			limit: 10,
+			expected: []time.Time{
+				time.Date(2025, 3, 24, 7, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 31, 6, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := rrule.Parse(tc.rule)
+			require.NoError(t, err)
+
+			occurrences := rule.Occurrences(tc.start, tc.limit)
+
+			utc := make([]time.Time, len(occurrences))
+			for i, o := range occurrences {
+				utc[i] = o.UTC()
+			}
+			assert.Equal(t, tc.expected, utc)
+		})
+	}
+}
+
+func TestRule_Between(t *testing.T) {
+	t.Parallel()
+
+	rule, err := rrule.Parse("FREQ=DAILY")
+	require.NoError(t, err)
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	occurrences := rule.Between(start, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC),
+	}, occurrences)
+}
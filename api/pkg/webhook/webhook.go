@@ -0,0 +1,124 @@
+// Package webhook signs and sends webhook deliveries.
+//
+// Every delivery is a JSON POST carrying the signature of the body in the X-Webhook-Signature header:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret,
+// where timestamp is the Unix time in the X-Webhook-Timestamp header. Receivers recompute the
+// signature and reject old timestamps to prevent replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vadimpk/ppc-project/pkg/netguard"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+
+	signaturePrefix = "sha256="
+	// maxErrorBody bounds the part of an error response kept in the delivery log
+	maxErrorBody = 512
+)
+
+// Sign returns the signature header value of the body sent at the timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a received delivery.
+// Timestamps further than tolerance from now are rejected, a zero tolerance disables the check.
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp")
+	}
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return fmt.Errorf("timestamp outside of tolerance")
+		}
+	}
+
+	signature := header.Get(HeaderSignature)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return fmt.Errorf("invalid signature")
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Request is a single delivery attempt
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID int
+	EventType  string
+	Body       []byte
+}
+
+// Sender posts signed deliveries
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a sender using the client. When nil, a client with a 10 second timeout is used
+// that only connects to public addresses, so webhook URLs cannot reach the server's network.
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = netguard.NewClient(netguard.PublicAddresses, 10*time.Second)
+	}
+
+	return &Sender{client: client}
+}
+
+// Send posts the request signed at now and returns the response status code.
+// Responses other than 2xx are returned as errors along with their status code,
+// the status code is 0 when no response was received.
+func (s *Sender) Send(ctx context.Context, req Request, now time.Time) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := now.Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "ppc-webhooks/1.0")
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+	httpReq.Header.Set(HeaderEvent, req.EventType)
+	httpReq.Header.Set(HeaderDelivery, strconv.Itoa(req.DeliveryID))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,161 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/pkg/netguard"
+	"github.com/vadimpk/ppc-project/pkg/webhook"
+)
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686",
+		webhook.Sign("secret", 1700000000, []byte(`{"a":1}`)),
+	)
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"a":1}`)
+	header := func(timestamp, signature string) http.Header {
+		h := http.Header{}
+		h.Set(webhook.HeaderTimestamp, timestamp)
+		h.Set(webhook.HeaderSignature, signature)
+		return h
+	}
+	valid := webhook.Sign("secret", now.Unix(), body)
+
+	testCases := []struct {
+		name   string
+		secret string
+		header http.Header
+		body   []byte
+		err    string
+	}{
+		{
+			name:   "positive: valid signature",
+			secret: "secret",
+			header: header("1700000000", valid),
+			body:   body,
+		},
+		{
+			name:   "negative: wrong secret",
+			secret: "other",
+			header: header("1700000000", valid),
+			body:   body,
+			err:    "signature mismatch",
+		},
+		{
+			name:   "negative: modified body",
+			secret: "secret",
+			header: header("1700000000", valid),
+			body:   []byte(`{"a":2}`),
+			err:    "signature mismatch",
+		},
+		{
+			name:   "negative: old timestamp",
+			secret: "secret",
+			header: header("1699999000", webhook.Sign("secret", 1699999000, body)),
+			body:   body,
+			err:    "timestamp outside of tolerance",
+		},
+		{
+			name:   "negative: missing signature",
+			secret: "secret",
+			header: header("1700000000", ""),
+			body:   body,
+			err:    "invalid signature",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := webhook.Verify(tc.secret, tc.header, tc.body, now, 5*time.Minute)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	var received http.Header
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		receivedBody, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := webhook.NewSender(server.Client())
+	req := webhook.Request{
+		URL:        server.URL,
+		Secret:     "secret",
+		DeliveryID: 7,
+		EventType:  "appointment.created",
+		Body:       []byte(`{"id":1}`),
+	}
+
+	status, err := sender.Send(context.Background(), req, now)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, "application/json", received.Get("Content-Type"))
+	assert.Equal(t, "appointment.created", received.Get(webhook.HeaderEvent))
+	assert.Equal(t, "7", received.Get(webhook.HeaderDelivery))
+	assert.NoError(t, webhook.Verify("secret", received, receivedBody, now, time.Minute))
+
+	req.URL = server.URL + "/fail"
+	status, err = sender.Send(context.Background(), req, now)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.EqualError(t, err, "endpoint responded with 500: boom")
+}
+
+func TestSender_SendRefusesLoopback(t *testing.T) {
+	t.Parallel()
+
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The default client only connects to public addresses
+	status, err := webhook.NewSender(nil).Send(context.Background(), webhook.Request{
+		URL:       server.URL,
+		Secret:    "secret",
+		EventType: "appointment.created",
+		Body:      []byte(`{"id":1}`),
+	}, time.Now())
+
+	assert.True(t, errors.Is(err, netguard.ErrAddressNotAllowed), err)
+	assert.Zero(t, status)
+	assert.False(t, hit)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,15 +12,62 @@ import (
 	"github.com/vadimpk/ppc-project/repository/db/sqlc"
 )
 
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name AppointmentRepository --output ./mocks
 type AppointmentRepository interface {
 	Create(ctx context.Context, appointment *entity.Appointment) error
 	Get(ctx context.Context, id int) (*entity.Appointment, error)
 	Update(ctx context.Context, appointment *entity.Appointment) error
-	Delete(ctx context.Context, id int) error
+	Cancel(ctx context.Context, id int, reason string) error
+	UpdateStatus(ctx context.Context, id int, currentStatus, newStatus string) error
 	ListByBusiness(ctx context.Context, businessID int, startTime, endTime time.Time) ([]entity.Appointment, error)
 	ListByEmployee(ctx context.Context, employeeID int, startTime, endTime time.Time) ([]entity.Appointment, error)
 	ListByClient(ctx context.Context, clientID int, startTime, endTime time.Time) ([]entity.Appointment, error)
-	IsEmployeeAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time) (bool, error)
+	// IsEmployeeAvailable ignores the appointment with excludeID, so a rescheduled appointment does not conflict with itself
+	IsEmployeeAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time, excludeID int) (bool, error)
+	// IsEmployeeAvailableForSession ignores the bookings of the class session of the service starting at sessionStart,
+	// so joining a session does not conflict with its other seats
+	IsEmployeeAvailableForSession(ctx context.Context, employeeID, serviceID int, sessionStart, startTime, endTime time.Time) (bool, error)
+	// IsResourceAvailable ignores the appointment with excludeID, so a rescheduled appointment does not conflict with itself
+	IsResourceAvailable(ctx context.Context, resourceID int, startTime, endTime time.Time, excludeID int) (bool, error)
+	// ListByResources returns the scheduled and held appointments of the resources overlapping the period
+	ListByResources(ctx context.Context, resourceIDs []int, startTime, endTime time.Time) ([]entity.Appointment, error)
+	// CreateInSession books a seat in the class session of the appointment's employee, service and start time,
+	// creating the session with the given capacity when it does not exist yet. ErrNoCapacity is returned when the session is full.
+	CreateInSession(ctx context.Context, appointment *entity.Appointment, capacity int) error
+
+	// CreateSeries stores the series and its appointments in one transaction
+	CreateSeries(ctx context.Context, series *entity.AppointmentSeries, appointments []*entity.Appointment) error
+	// ListBySeries returns the appointments of the series starting at or after from
+	ListBySeries(ctx context.Context, seriesID int, from time.Time) ([]entity.Appointment, error)
+	// CancelSeries cancels the scheduled appointments of the series starting at or after from and returns their IDs
+	CancelSeries(ctx context.Context, seriesID int, from time.Time, reason string) ([]int, error)
+	// UpdateMany updates the appointments in one transaction. The appointments may take each other's times,
+	// overlaps are checked once every one is updated.
+	UpdateMany(ctx context.Context, appointments []*entity.Appointment) error
+	// IsEmployeeAvailableForSeries ignores the appointments of the series starting at or after from,
+	// so the occurrences moved together do not conflict with their old times
+	IsEmployeeAvailableForSeries(ctx context.Context, employeeID, seriesID int, from, startTime, endTime time.Time) (bool, error)
+	// IsResourceAvailableForSeries ignores the appointments of the series starting at or after from,
+	// so the occurrences moved together do not conflict with their old times
+	IsResourceAvailableForSeries(ctx context.Context, resourceID, seriesID int, from, startTime, endTime time.Time) (bool, error)
+
+	// GetByHoldToken returns the held appointment of a checkout hold
+	GetByHoldToken(ctx context.Context, token string) (*entity.Appointment, error)
+	// ConfirmHold turns an unexpired hold into a scheduled appointment.
+	// ErrNotFound is returned when the hold has expired or was already confirmed.
+	ConfirmHold(ctx context.Context, token string, reminderTime *int) (*entity.Appointment, error)
+	// DeleteHold releases a hold before it expires
+	DeleteHold(ctx context.Context, token string) error
+	// DeleteExpiredHolds removes the checkout holds that expired at or before now and returns their number
+	DeleteExpiredHolds(ctx context.Context, now time.Time) (int, error)
+
+	// ClaimDueReminders claims up to limit scheduled appointments whose reminder is due at now and not sent yet,
+	// and returns their IDs. Claimed reminders are not returned again until the lease passes.
+	ClaimDueReminders(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]int, error)
+	// MarkReminderSent records the reminder as sent, so it is never claimed again
+	MarkReminderSent(ctx context.Context, id int, sentAt time.Time) error
+	// ReleaseReminderClaim makes an unsent reminder available to be claimed again
+	ReleaseReminderClaim(ctx context.Context, id int) error
 }
 
 type appointmentRepository struct {
@@ -33,28 +81,259 @@ func NewAppointmentRepository(db *DB) AppointmentRepository {
 }
 
 func (r *appointmentRepository) Create(ctx context.Context, appointment *entity.Appointment) error {
-	var reminderTime pgtype.Int4
-	if appointment.ReminderTime != nil {
-		reminderTime = pgtype.Int4{Int32: int32(*appointment.ReminderTime), Valid: true}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockEmployeeBookings(ctx, q, appointment.EmployeeID); err != nil {
+			return err
+		}
+
+		dbAppointment, err := q.CreateAppointment(ctx, createAppointmentParams(appointment))
+		if err != nil {
+			return fmt.Errorf("failed to create appointment: %w", r.db.HandleBasicErrors(err))
+		}
+		if err := checkEmployeeOverlap(ctx, q, dbAppointment.ID); err != nil {
+			return err
+		}
+
+		appointment.ID = int(dbAppointment.ID)
+		appointment.CreatedAt = dbAppointment.CreatedAt.Time
+		return recordCreatedAppointment(ctx, q, dbAppointment)
+	})
+}
+
+func (r *appointmentRepository) CreateInSession(ctx context.Context, appointment *entity.Appointment, capacity int) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockEmployeeBookings(ctx, q, appointment.EmployeeID); err != nil {
+			return err
+		}
+
+		session, err := q.GetOrCreateClassSession(ctx, sqlc.GetOrCreateClassSessionParams{
+			BusinessID: pgtype.Int4{Int32: int32(appointment.BusinessID), Valid: true},
+			EmployeeID: pgtype.Int4{Int32: int32(appointment.EmployeeID), Valid: true},
+			ServiceID:  pgtype.Int4{Int32: int32(appointment.ServiceID), Valid: true},
+			StartTime:  pgtype.Timestamptz{Time: appointment.StartTime, Valid: true},
+			EndTime:    pgtype.Timestamptz{Time: appointment.EndTime, Valid: true},
+			Capacity:   int32(capacity),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get class session: %w", r.db.HandleBasicErrors(err))
+		}
+
+		// The seat is taken with a conditional update, so concurrent bookings cannot overfill the session
+		if _, err := q.ReserveClassSeat(ctx, session.ID); err != nil {
+			if err = r.db.HandleBasicErrors(err); errors.Is(err, ErrNotFound) {
+				return ErrNoCapacity
+			}
+			return fmt.Errorf("failed to reserve class seat: %w", err)
+		}
+
+		sessionID := int(session.ID)
+		appointment.SessionID = &sessionID
+
+		dbAppointment, err := q.CreateAppointment(ctx, createAppointmentParams(appointment))
+		if err != nil {
+			appointment.SessionID = nil
+			return fmt.Errorf("failed to create appointment: %w", r.db.HandleBasicErrors(err))
+		}
+		if err := checkEmployeeOverlap(ctx, q, dbAppointment.ID); err != nil {
+			appointment.SessionID = nil
+			return err
+		}
+
+		appointment.ID = int(dbAppointment.ID)
+		appointment.CreatedAt = dbAppointment.CreatedAt.Time
+		return recordCreatedAppointment(ctx, q, dbAppointment)
+	})
+}
+
+func (r *appointmentRepository) CreateSeries(ctx context.Context, series *entity.AppointmentSeries, appointments []*entity.Appointment) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockEmployeeBookings(ctx, q, series.EmployeeID); err != nil {
+			return err
+		}
+
+		dbSeries, err := q.CreateAppointmentSeries(ctx, sqlc.CreateAppointmentSeriesParams{
+			BusinessID:   pgtype.Int4{Int32: int32(series.BusinessID), Valid: true},
+			ClientID:     pgtype.Int4{Int32: int32(series.ClientID), Valid: true},
+			EmployeeID:   pgtype.Int4{Int32: int32(series.EmployeeID), Valid: true},
+			ServiceID:    pgtype.Int4{Int32: int32(series.ServiceID), Valid: true},
+			Rrule:        series.RRule,
+			StartTime:    pgtype.Timestamptz{Time: series.StartTime, Valid: true},
+			ReminderTime: optionalInt4(series.ReminderTime),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create appointment series: %w", r.db.HandleBasicErrors(err))
+		}
+
+		series.ID = int(dbSeries.ID)
+		series.CreatedAt = dbSeries.CreatedAt.Time
+
+		for _, appointment := range appointments {
+			appointment.SeriesID = &series.ID
+
+			dbAppointment, err := q.CreateAppointment(ctx, createAppointmentParams(appointment))
+			if err != nil {
+				return fmt.Errorf("failed to create appointment: %w", r.db.HandleBasicErrors(err))
+			}
+			if err := checkEmployeeOverlap(ctx, q, dbAppointment.ID); err != nil {
+				return err
+			}
+
+			appointment.ID = int(dbAppointment.ID)
+			appointment.CreatedAt = dbAppointment.CreatedAt.Time
+			if err := recordCreatedAppointment(ctx, q, dbAppointment); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *appointmentRepository) ListBySeries(ctx context.Context, seriesID int, from time.Time) ([]entity.Appointment, error) {
+	dbAppointments, err := r.db.SQLC.ListSeriesAppointments(ctx, sqlc.ListSeriesAppointmentsParams{
+		SeriesID:  pgtype.Int4{Int32: int32(seriesID), Valid: true},
+		StartTime: pgtype.Timestamptz{Time: from, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series appointments: %w", err)
+	}
+
+	appointments := make([]entity.Appointment, len(dbAppointments))
+	for i, a := range dbAppointments {
+		appointments[i] = *convertDBAppointmentToEntity(sqlc.GetAppointmentRow(a))
+	}
+
+	return appointments, nil
+}
+
+func (r *appointmentRepository) CancelSeries(ctx context.Context, seriesID int, from time.Time, reason string) ([]int, error) {
+	var cancellationReason pgtype.Text
+	if reason != "" {
+		cancellationReason = r.db.ValidText(reason)
+	}
+
+	var ids []int
+	err := r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbAppointments, err := q.CancelSeriesAppointments(ctx, sqlc.CancelSeriesAppointmentsParams{
+			CancellationReason: cancellationReason,
+			SeriesID:           pgtype.Int4{Int32: int32(seriesID), Valid: true},
+			StartTime:          pgtype.Timestamptz{Time: from, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel series appointments: %w", err)
+		}
+
+		ids = make([]int, len(dbAppointments))
+		for i, a := range dbAppointments {
+			ids[i] = int(a.ID)
+			if err := releaseSeat(ctx, q, a); err != nil {
+				return err
+			}
+			if err := recordAppointmentEvent(ctx, q, entity.EventAppointmentCancelled, a.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *appointmentRepository) UpdateMany(ctx context.Context, appointments []*entity.Appointment) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.DeferAppointmentOverlapConstraints(ctx); err != nil {
+			return fmt.Errorf("failed to defer overlap constraints: %w", err)
+		}
+
+		for _, appointment := range appointments {
+			if err := lockEmployeeBookings(ctx, q, appointment.EmployeeID); err != nil {
+				return err
+			}
+
+			dbAppointment, err := q.UpdateAppointment(ctx, updateAppointmentParams(appointment))
+			if err != nil {
+				return fmt.Errorf("failed to update appointment: %w", r.db.HandleBasicErrors(err))
+			}
+
+			appointment.CreatedAt = dbAppointment.CreatedAt.Time
+			if err := recordAppointmentEvent(ctx, q, entity.EventAppointmentRescheduled, dbAppointment.ID); err != nil {
+				return err
+			}
+		}
+
+		// Every appointment is at its new time now
+		for _, appointment := range appointments {
+			if err := checkEmployeeOverlap(ctx, q, int32(appointment.ID)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func createAppointmentParams(appointment *entity.Appointment) sqlc.CreateAppointmentParams {
+	return sqlc.CreateAppointmentParams{
+		BusinessID:    pgtype.Int4{Int32: int32(appointment.BusinessID), Valid: true},
+		ClientID:      pgtype.Int4{Int32: int32(appointment.ClientID), Valid: true},
+		EmployeeID:    pgtype.Int4{Int32: int32(appointment.EmployeeID), Valid: true},
+		ServiceID:     pgtype.Int4{Int32: int32(appointment.ServiceID), Valid: true},
+		StartTime:     pgtype.Timestamptz{Time: appointment.StartTime, Valid: true},
+		EndTime:       pgtype.Timestamptz{Time: appointment.EndTime, Valid: true},
+		Status:        pgtype.Text{String: appointment.Status, Valid: true},
+		ReminderTime:  optionalInt4(appointment.ReminderTime),
+		SeriesID:      optionalInt4(appointment.SeriesID),
+		SessionID:     optionalInt4(appointment.SessionID),
+		HoldExpiresAt: optionalTimestamptz(appointment.HoldExpiresAt),
+		HoldToken:     optionalText(appointment.HoldToken),
+		ResourceID:    optionalInt4(appointment.ResourceID),
+		LocationID:    optionalInt4(appointment.LocationID),
 	}
+}
 
-	dbAppointment, err := r.db.SQLC.CreateAppointment(ctx, sqlc.CreateAppointmentParams{
-		BusinessID:   pgtype.Int4{Int32: int32(appointment.BusinessID), Valid: true},
-		ClientID:     pgtype.Int4{Int32: int32(appointment.ClientID), Valid: true},
+func updateAppointmentParams(appointment *entity.Appointment) sqlc.UpdateAppointmentParams {
+	return sqlc.UpdateAppointmentParams{
+		ID:           int32(appointment.ID),
 		EmployeeID:   pgtype.Int4{Int32: int32(appointment.EmployeeID), Valid: true},
-		ServiceID:    pgtype.Int4{Int32: int32(appointment.ServiceID), Valid: true},
 		StartTime:    pgtype.Timestamptz{Time: appointment.StartTime, Valid: true},
 		EndTime:      pgtype.Timestamptz{Time: appointment.EndTime, Valid: true},
 		Status:       pgtype.Text{String: appointment.Status, Valid: true},
-		ReminderTime: reminderTime,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create appointment: %w", err)
+		ReminderTime: optionalInt4(appointment.ReminderTime),
+		ResourceID:   optionalInt4(appointment.ResourceID),
+		LocationID:   optionalInt4(appointment.LocationID),
 	}
+}
 
-	appointment.ID = int(dbAppointment.ID)
-	appointment.CreatedAt = dbAppointment.CreatedAt.Time
-	return nil
+func optionalInt4(v *int) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
+
+func optionalTimestamptz(v *time.Time) pgtype.Timestamptz {
+	if v == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *v, Valid: true}
+}
+
+func optionalDate(v *time.Time) pgtype.Date {
+	if v == nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{Time: *v, Valid: true}
+}
+
+func optionalText(v *string) pgtype.Text {
+	if v == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *v, Valid: true}
 }
 
 func (r *appointmentRepository) Get(ctx context.Context, id int) (*entity.Appointment, error) {
@@ -70,40 +349,204 @@ func (r *appointmentRepository) Get(ctx context.Context, id int) (*entity.Appoin
 }
 
 func (r *appointmentRepository) Update(ctx context.Context, appointment *entity.Appointment) error {
-	var reminderTime pgtype.Int4
-	if appointment.ReminderTime != nil {
-		reminderTime = pgtype.Int4{Int32: int32(*appointment.ReminderTime), Valid: true}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockEmployeeBookings(ctx, q, appointment.EmployeeID); err != nil {
+			return err
+		}
+
+		dbAppointment, err := q.UpdateAppointment(ctx, updateAppointmentParams(appointment))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to update appointment: %w", r.db.HandleBasicErrors(err))
+		}
+		if err := checkEmployeeOverlap(ctx, q, dbAppointment.ID); err != nil {
+			return err
+		}
+
+		appointment.CreatedAt = dbAppointment.CreatedAt.Time
+		return recordAppointmentEvent(ctx, q, entity.EventAppointmentRescheduled, dbAppointment.ID)
+	})
+}
+
+// lockEmployeeBookings makes concurrent bookings of the employee wait for the transaction to finish.
+// The exclusion constraint only compares the raw times of scheduled appointments,
+// so the buffers and holds are checked under this lock with checkEmployeeOverlap.
+func lockEmployeeBookings(ctx context.Context, q *sqlc.Queries, employeeID int) error {
+	if err := q.LockEmployeeBookings(ctx, int32(employeeID)); err != nil {
+		return fmt.Errorf("failed to lock employee bookings: %w", err)
 	}
+	return nil
+}
 
-	dbAppointment, err := r.db.SQLC.UpdateAppointment(ctx, sqlc.UpdateAppointmentParams{
-		ID:           int32(appointment.ID),
-		StartTime:    pgtype.Timestamptz{Time: appointment.StartTime, Valid: true},
-		EndTime:      pgtype.Timestamptz{Time: appointment.EndTime, Valid: true},
-		Status:       pgtype.Text{String: appointment.Status, Valid: true},
-		ReminderTime: reminderTime,
+// checkEmployeeOverlap returns ErrConflict when the stored appointment overlaps another booking of its employee
+func checkEmployeeOverlap(ctx context.Context, q *sqlc.Queries, id int32) error {
+	overlaps, err := q.HasEmployeeOverlap(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check employee bookings: %w", err)
+	}
+	if overlaps {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (r *appointmentRepository) Cancel(ctx context.Context, id int, reason string) error {
+	var cancellationReason pgtype.Text
+	if reason != "" {
+		cancellationReason = r.db.ValidText(reason)
+	}
+
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbAppointment, err := q.CancelAppointment(ctx, sqlc.CancelAppointmentParams{
+			ID:                 int32(id),
+			CancellationReason: cancellationReason,
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		if err := releaseSeat(ctx, q, dbAppointment); err != nil {
+			return err
+		}
+		return recordAppointmentEvent(ctx, q, entity.EventAppointmentCancelled, dbAppointment.ID)
 	})
+}
+
+func (r *appointmentRepository) GetByHoldToken(ctx context.Context, token string) (*entity.Appointment, error) {
+	dbAppointment, err := r.db.SQLC.GetAppointmentByHoldToken(ctx, r.db.ValidText(token))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return ErrNotFound
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBAppointmentToEntity(dbAppointment), nil
+}
+
+func (r *appointmentRepository) ConfirmHold(ctx context.Context, token string, reminderTime *int) (*entity.Appointment, error) {
+	var id int32
+	err := r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbAppointment, err := q.ConfirmAppointmentHold(ctx, sqlc.ConfirmAppointmentHoldParams{
+			HoldToken:    r.db.ValidText(token),
+			ReminderTime: optionalInt4(reminderTime),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to confirm hold: %w", r.db.HandleBasicErrors(err))
 		}
-		return fmt.Errorf("failed to update appointment: %w", err)
+
+		id = dbAppointment.ID
+		return recordAppointmentEvent(ctx, q, entity.EventAppointmentCreated, dbAppointment.ID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	appointment.CreatedAt = dbAppointment.CreatedAt.Time
+	return r.Get(ctx, int(id))
+}
+
+func (r *appointmentRepository) DeleteHold(ctx context.Context, token string) error {
+	deleted, err := r.db.SQLC.DeleteAppointmentHold(ctx, r.db.ValidText(token))
+	if err != nil {
+		return fmt.Errorf("failed to delete hold: %w", err)
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
 
-func (r *appointmentRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.SQLC.CancelAppointment(ctx, int32(id))
+func (r *appointmentRepository) DeleteExpiredHolds(ctx context.Context, now time.Time) (int, error) {
+	deleted, err := r.db.SQLC.DeleteExpiredAppointmentHolds(ctx, pgtype.Timestamptz{Time: now, Valid: true})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return ErrNotFound
-		}
-		return fmt.Errorf("failed to cancel appointment: %w", err)
+		return 0, fmt.Errorf("failed to delete expired holds: %w", err)
+	}
+	return int(deleted), nil
+}
+
+func (r *appointmentRepository) ClaimDueReminders(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]int, error) {
+	dbIDs, err := r.db.SQLC.ClaimDueReminders(ctx, sqlc.ClaimDueRemindersParams{
+		ClaimedUntil: pgtype.Timestamptz{Time: now.Add(lease), Valid: true},
+		Now:          pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due reminders: %w", err)
+	}
+
+	ids := make([]int, len(dbIDs))
+	for i, id := range dbIDs {
+		ids[i] = int(id)
+	}
+	return ids, nil
+}
+
+func (r *appointmentRepository) MarkReminderSent(ctx context.Context, id int, sentAt time.Time) error {
+	err := r.db.SQLC.MarkReminderSent(ctx, sqlc.MarkReminderSentParams{
+		ID:             int32(id),
+		ReminderSentAt: pgtype.Timestamptz{Time: sentAt, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
 	}
 	return nil
 }
 
+func (r *appointmentRepository) ReleaseReminderClaim(ctx context.Context, id int) error {
+	if err := r.db.SQLC.ReleaseReminderClaim(ctx, int32(id)); err != nil {
+		return fmt.Errorf("failed to release reminder claim: %w", err)
+	}
+	return nil
+}
+
+// recordCreatedAppointment stores the appointment.created event of a booked appointment.
+// Held appointments are not booked yet and are recorded once confirmed.
+func recordCreatedAppointment(ctx context.Context, q *sqlc.Queries, appointment sqlc.Appointment) error {
+	if appointment.Status.String == entity.AppointmentStatusHeld {
+		return nil
+	}
+	return recordAppointmentEvent(ctx, q, entity.EventAppointmentCreated, appointment.ID)
+}
+
+// statusEvents are the events recorded when an appointment moves to a status
+var statusEvents = map[string]string{
+	entity.AppointmentStatusCompleted: entity.EventAppointmentCompleted,
+	entity.AppointmentStatusNoShow:    entity.EventAppointmentNoShow,
+	entity.AppointmentStatusCancelled: entity.EventAppointmentCancelled,
+}
+
+// releaseSeat frees the class session seat held by a cancelled appointment
+func releaseSeat(ctx context.Context, q *sqlc.Queries, appointment sqlc.Appointment) error {
+	if !appointment.SessionID.Valid {
+		return nil
+	}
+
+	if err := q.ReleaseClassSeat(ctx, appointment.SessionID.Int32); err != nil {
+		return fmt.Errorf("failed to release class seat: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus moves the appointment to newStatus only if it is still in currentStatus,
+// so concurrent transitions cannot overwrite each other.
+func (r *appointmentRepository) UpdateStatus(ctx context.Context, id int, currentStatus, newStatus string) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		_, err := q.UpdateAppointmentStatus(ctx, sqlc.UpdateAppointmentStatusParams{
+			ID:            int32(id),
+			CurrentStatus: r.db.ValidText(currentStatus),
+			NewStatus:     r.db.ValidText(newStatus),
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		eventType, ok := statusEvents[newStatus]
+		if !ok {
+			return nil
+		}
+		return recordAppointmentEvent(ctx, q, eventType, int32(id))
+	})
+}
+
 func (r *appointmentRepository) ListByBusiness(ctx context.Context, businessID int, startTime, endTime time.Time) ([]entity.Appointment, error) {
 	dbAppointments, err := r.db.SQLC.ListBusinessAppointments(ctx, sqlc.ListBusinessAppointmentsParams{
 		BusinessID:  pgtype.Int4{Int32: int32(businessID), Valid: true},
@@ -158,11 +601,12 @@ func (r *appointmentRepository) ListByClient(ctx context.Context, clientID int,
 	return appointments, nil
 }
 
-func (r *appointmentRepository) IsEmployeeAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time) (bool, error) {
+func (r *appointmentRepository) IsEmployeeAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time, excludeID int) (bool, error) {
 	available, err := r.db.SQLC.CheckEmployeeAvailability(ctx, sqlc.CheckEmployeeAvailabilityParams{
 		EmployeeID: pgtype.Int4{Int32: int32(employeeID), Valid: true},
-		Overlaps:   pgtype.Timestamptz{Time: startTime, Valid: true},
-		Overlaps_2: pgtype.Timestamptz{Time: endTime, Valid: true},
+		ExcludeID:  int32(excludeID),
+		StartTime:  pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:    pgtype.Timestamptz{Time: endTime, Valid: true},
 	})
 	if err != nil {
 		return false, fmt.Errorf("failed to check employee availability: %w", err)
@@ -171,6 +615,88 @@ func (r *appointmentRepository) IsEmployeeAvailable(ctx context.Context, employe
 	return available, nil
 }
 
+func (r *appointmentRepository) IsEmployeeAvailableForSeries(ctx context.Context, employeeID, seriesID int, from, startTime, endTime time.Time) (bool, error) {
+	available, err := r.db.SQLC.CheckEmployeeSeriesAvailability(ctx, sqlc.CheckEmployeeSeriesAvailabilityParams{
+		EmployeeID: pgtype.Int4{Int32: int32(employeeID), Valid: true},
+		SeriesID:   pgtype.Int4{Int32: int32(seriesID), Valid: true},
+		SeriesFrom: pgtype.Timestamptz{Time: from, Valid: true},
+		StartTime:  pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:    pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check employee availability: %w", err)
+	}
+
+	return available, nil
+}
+
+func (r *appointmentRepository) IsEmployeeAvailableForSession(ctx context.Context, employeeID, serviceID int, sessionStart, startTime, endTime time.Time) (bool, error) {
+	available, err := r.db.SQLC.CheckEmployeeSessionAvailability(ctx, sqlc.CheckEmployeeSessionAvailabilityParams{
+		EmployeeID:   pgtype.Int4{Int32: int32(employeeID), Valid: true},
+		ServiceID:    pgtype.Int4{Int32: int32(serviceID), Valid: true},
+		SessionStart: pgtype.Timestamptz{Time: sessionStart, Valid: true},
+		StartTime:    pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:      pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check employee availability: %w", err)
+	}
+
+	return available, nil
+}
+
+func (r *appointmentRepository) IsResourceAvailable(ctx context.Context, resourceID int, startTime, endTime time.Time, excludeID int) (bool, error) {
+	available, err := r.db.SQLC.CheckResourceAvailability(ctx, sqlc.CheckResourceAvailabilityParams{
+		ResourceID: pgtype.Int4{Int32: int32(resourceID), Valid: true},
+		ExcludeID:  int32(excludeID),
+		StartTime:  pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:    pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check resource availability: %w", err)
+	}
+
+	return available, nil
+}
+
+func (r *appointmentRepository) IsResourceAvailableForSeries(ctx context.Context, resourceID, seriesID int, from, startTime, endTime time.Time) (bool, error) {
+	available, err := r.db.SQLC.CheckResourceSeriesAvailability(ctx, sqlc.CheckResourceSeriesAvailabilityParams{
+		ResourceID: pgtype.Int4{Int32: int32(resourceID), Valid: true},
+		SeriesID:   pgtype.Int4{Int32: int32(seriesID), Valid: true},
+		SeriesFrom: pgtype.Timestamptz{Time: from, Valid: true},
+		StartTime:  pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:    pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check resource availability: %w", err)
+	}
+
+	return available, nil
+}
+
+func (r *appointmentRepository) ListByResources(ctx context.Context, resourceIDs []int, startTime, endTime time.Time) ([]entity.Appointment, error) {
+	ids := make([]int32, len(resourceIDs))
+	for i, id := range resourceIDs {
+		ids[i] = int32(id)
+	}
+
+	dbAppointments, err := r.db.SQLC.ListResourceAppointments(ctx, sqlc.ListResourceAppointmentsParams{
+		ResourceIds: ids,
+		StartTime:   pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:     pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource appointments: %w", err)
+	}
+
+	appointments := make([]entity.Appointment, len(dbAppointments))
+	for i, a := range dbAppointments {
+		appointments[i] = *convertDBAppointmentToEntity(sqlc.GetAppointmentRow(a))
+	}
+
+	return appointments, nil
+}
+
 type AppointmentWithDetails struct {
 	entity.Appointment
 	Client   *entity.User            `json:"client"`
@@ -196,6 +722,46 @@ func convertDBAppointmentToEntity(a sqlc.GetAppointmentRow) *entity.Appointment
 		appointment.ReminderTime = &reminderTime
 	}
 
+	if a.CancellationReason.Valid {
+		reason := a.CancellationReason.String
+		appointment.CancellationReason = &reason
+	}
+
+	if a.SeriesID.Valid {
+		seriesID := int(a.SeriesID.Int32)
+		appointment.SeriesID = &seriesID
+	}
+
+	if a.SessionID.Valid {
+		sessionID := int(a.SessionID.Int32)
+		appointment.SessionID = &sessionID
+	}
+
+	if a.HoldExpiresAt.Valid {
+		expiresAt := a.HoldExpiresAt.Time.UTC()
+		appointment.HoldExpiresAt = &expiresAt
+	}
+
+	if a.HoldToken.Valid {
+		token := a.HoldToken.String
+		appointment.HoldToken = &token
+	}
+
+	if a.ReminderSentAt.Valid {
+		sentAt := a.ReminderSentAt.Time.UTC()
+		appointment.ReminderSentAt = &sentAt
+	}
+
+	if a.ResourceID.Valid {
+		resourceID := int(a.ResourceID.Int32)
+		appointment.ResourceID = &resourceID
+	}
+
+	if a.LocationID.Valid {
+		locationID := int(a.LocationID.Int32)
+		appointment.LocationID = &locationID
+	}
+
 	// Add client details
 	appointment.Client = &entity.User{
 		FullName: a.ClientFullName,
@@ -224,9 +790,11 @@ func convertDBAppointmentToEntity(a sqlc.GetAppointmentRow) *entity.Appointment
 
 	// Add service details
 	appointment.Service = &entity.BusinessService{
-		Name:     a.ServiceName,
-		Duration: int(a.ServiceDuration),
-		Price:    int(a.ServicePrice),
+		Name:         a.ServiceName,
+		Duration:     int(a.ServiceDuration),
+		Price:        int(a.ServicePrice),
+		BufferBefore: int(a.ServiceBufferBefore),
+		BufferAfter:  int(a.ServiceBufferAfter),
 	}
 
 	return appointment
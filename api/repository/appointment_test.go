@@ -0,0 +1,369 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestAppointmentRepository_CreateConcurrent(t *testing.T) {
+	ctx := context.Background()
+
+	client := &entity.User{
+		BusinessID:   businessID,
+		Email:        stringPtr("appointment-client@example.com"),
+		FullName:     "Test Client",
+		PasswordHash: "hash",
+		Role:         entity.RoleClient,
+	}
+	require.NoError(t, userRepo.Create(ctx, client))
+
+	employeeUser := &entity.User{
+		BusinessID:   businessID,
+		Email:        stringPtr("appointment-employee@example.com"),
+		FullName:     "Test Employee",
+		PasswordHash: "hash",
+		Role:         entity.RoleEmployee,
+	}
+	require.NoError(t, userRepo.Create(ctx, employeeUser))
+
+	employee := &entity.Employee{
+		BusinessID: businessID,
+		UserID:     employeeUser.ID,
+		IsActive:   true,
+	}
+	require.NoError(t, employeeRepo.Create(ctx, employee))
+
+	service := createTestService(t)
+
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM appointments WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM employees WHERE id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM services WHERE id = $1", service.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM users WHERE id IN ($1, $2)", client.ID, employeeUser.ID)
+		require.NoError(t, err)
+	})
+
+	startTime := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	const attempts = 10
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		created   int
+		conflicts int
+		others    []error
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Shift every other attempt so that the slots overlap without being identical
+			offset := time.Duration(i%2) * 15 * time.Minute
+			err := appointmentRepo.Create(ctx, &entity.Appointment{
+				BusinessID: businessID,
+				ClientID:   client.ID,
+				EmployeeID: employee.ID,
+				ServiceID:  service.ID,
+				StartTime:  startTime.Add(offset),
+				EndTime:    startTime.Add(offset + 30*time.Minute),
+				Status:     "scheduled",
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, repository.ErrConflict):
+				conflicts++
+			default:
+				others = append(others, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Empty(t, others)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, attempts-1, conflicts)
+
+	// Cancelled appointments release the slot
+	var id int
+	err := db.PGX.QueryRow(ctx, "SELECT id FROM appointments WHERE employee_id = $1", employee.ID).Scan(&id)
+	require.NoError(t, err)
+	require.NoError(t, appointmentRepo.Cancel(ctx, id, ""))
+
+	err = appointmentRepo.Create(ctx, &entity.Appointment{
+		BusinessID: businessID,
+		ClientID:   client.ID,
+		EmployeeID: employee.ID,
+		ServiceID:  service.ID,
+		StartTime:  startTime,
+		EndTime:    startTime.Add(30 * time.Minute),
+		Status:     "scheduled",
+	})
+	assert.NoError(t, err)
+}
+
+func TestAppointmentRepository_CreateConcurrentWithBuffers(t *testing.T) {
+	ctx := context.Background()
+
+	client, employee := createTestBookingParties(t, "appointment-buffers")
+
+	service := &entity.BusinessService{
+		BusinessID:  businessID,
+		Name:        "Test Service With Buffer",
+		Duration:    30,
+		Price:       1000,
+		IsActive:    true,
+		BufferAfter: 15,
+	}
+	require.NoError(t, serviceRepo.Create(ctx, service))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM appointments WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM services WHERE id = $1", service.ID)
+		require.NoError(t, err)
+	})
+
+	startTime := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	// Every other attempt starts right after the first appointment ends, inside its buffer only
+	created, conflicts, others := createConcurrently(10, func(i int) error {
+		offset := time.Duration(i%2) * 40 * time.Minute
+		return appointmentRepo.Create(ctx, &entity.Appointment{
+			BusinessID: businessID,
+			ClientID:   client.ID,
+			EmployeeID: employee.ID,
+			ServiceID:  service.ID,
+			StartTime:  startTime.Add(offset),
+			EndTime:    startTime.Add(offset + 30*time.Minute),
+			Status:     entity.AppointmentStatusScheduled,
+		})
+	})
+
+	assert.Empty(t, others)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 9, conflicts)
+}
+
+// createTestBookingParties creates a client and an employee of the test business, removed after the test
+func createTestBookingParties(t *testing.T, prefix string) (*entity.User, *entity.Employee) {
+	ctx := context.Background()
+
+	client := &entity.User{
+		BusinessID:   businessID,
+		Email:        stringPtr(prefix + "-client@example.com"),
+		FullName:     "Test Client",
+		PasswordHash: "hash",
+		Role:         entity.RoleClient,
+	}
+	require.NoError(t, userRepo.Create(ctx, client))
+
+	employeeUser := &entity.User{
+		BusinessID:   businessID,
+		Email:        stringPtr(prefix + "-employee@example.com"),
+		FullName:     "Test Employee",
+		PasswordHash: "hash",
+		Role:         entity.RoleEmployee,
+	}
+	require.NoError(t, userRepo.Create(ctx, employeeUser))
+
+	employee := &entity.Employee{
+		BusinessID: businessID,
+		UserID:     employeeUser.ID,
+		IsActive:   true,
+	}
+	require.NoError(t, employeeRepo.Create(ctx, employee))
+
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM appointments WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM employees WHERE id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM users WHERE id IN ($1, $2)", client.ID, employeeUser.ID)
+		require.NoError(t, err)
+	})
+
+	return client, employee
+}
+
+// createConcurrently runs the attempts at once and counts the ones that succeeded or conflicted
+func createConcurrently(attempts int, create func(i int) error) (created, conflicts int, others []error) {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			err := create(i)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, repository.ErrConflict):
+				conflicts++
+			default:
+				others = append(others, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return created, conflicts, others
+}
+
+func TestAppointmentRepository_HoldAndCreateConcurrent(t *testing.T) {
+	ctx := context.Background()
+
+	client, employee := createTestBookingParties(t, "appointment-holds")
+	service := createTestService(t)
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM appointments WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM services WHERE id = $1", service.ID)
+		require.NoError(t, err)
+	})
+
+	startTime := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	holdExpiresAt := time.Now().Add(5 * time.Minute)
+
+	// Holds and bookings of the same slot race each other
+	created, conflicts, others := createConcurrently(10, func(i int) error {
+		appointment := &entity.Appointment{
+			BusinessID: businessID,
+			ClientID:   client.ID,
+			EmployeeID: employee.ID,
+			ServiceID:  service.ID,
+			StartTime:  startTime,
+			EndTime:    startTime.Add(30 * time.Minute),
+			Status:     entity.AppointmentStatusScheduled,
+		}
+		if i%2 == 0 {
+			token := fmt.Sprintf("hold-%d-%d", employee.ID, i)
+			appointment.Status = entity.AppointmentStatusHeld
+			appointment.HoldToken = &token
+			appointment.HoldExpiresAt = &holdExpiresAt
+		}
+		return appointmentRepo.Create(ctx, appointment)
+	})
+
+	assert.Empty(t, others)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 9, conflicts)
+
+	// An expired hold no longer blocks the slot
+	_, err := db.PGX.Exec(ctx, "UPDATE appointments SET hold_expires_at = now() - interval '1 minute' WHERE employee_id = $1 AND status = 'held'", employee.ID)
+	require.NoError(t, err)
+	_, err = db.PGX.Exec(ctx, "UPDATE appointments SET status = 'cancelled' WHERE employee_id = $1 AND status = 'scheduled'", employee.ID)
+	require.NoError(t, err)
+
+	err = appointmentRepo.Create(ctx, &entity.Appointment{
+		BusinessID: businessID,
+		ClientID:   client.ID,
+		EmployeeID: employee.ID,
+		ServiceID:  service.ID,
+		StartTime:  startTime,
+		EndTime:    startTime.Add(30 * time.Minute),
+		Status:     entity.AppointmentStatusScheduled,
+	})
+	assert.NoError(t, err)
+}
+
+func TestAppointmentRepository_UpdateManyOntoOldTimes(t *testing.T) {
+	ctx := context.Background()
+
+	appointments := createTestSeriesAppointments(t, "appointment-series", 2)
+
+	// The first occurrence takes the time of the second before that one moves
+	for _, appointment := range appointments {
+		appointment.StartTime = appointment.StartTime.AddDate(0, 0, 7)
+		appointment.EndTime = appointment.EndTime.AddDate(0, 0, 7)
+	}
+	require.NoError(t, appointmentRepo.UpdateMany(ctx, appointments))
+
+	// Moving one of them onto the other still conflicts
+	appointments[0].StartTime = appointments[1].StartTime
+	appointments[0].EndTime = appointments[1].EndTime
+	err := appointmentRepo.UpdateMany(ctx, appointments[:1])
+	assert.ErrorIs(t, err, repository.ErrConflict)
+}
+
+func TestAppointmentRepository_UpdateManySwap(t *testing.T) {
+	ctx := context.Background()
+
+	appointments := createTestSeriesAppointments(t, "appointment-swap", 2)
+
+	// Each occurrence takes the slot of the other one
+	first, second := appointments[0], appointments[1]
+	first.StartTime, second.StartTime = second.StartTime, first.StartTime
+	first.EndTime, second.EndTime = second.EndTime, first.EndTime
+	require.NoError(t, appointmentRepo.UpdateMany(ctx, appointments))
+
+	stored, err := appointmentRepo.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.StartTime.Equal(first.StartTime))
+	stored, err = appointmentRepo.Get(ctx, second.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.StartTime.Equal(second.StartTime))
+}
+
+// createTestSeriesAppointments books a weekly series of count appointments, removed after the test
+func createTestSeriesAppointments(t *testing.T, prefix string, count int) []*entity.Appointment {
+	ctx := context.Background()
+
+	client, employee := createTestBookingParties(t, prefix)
+	service := createTestService(t)
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM appointments WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM appointment_series WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM services WHERE id = $1", service.ID)
+		require.NoError(t, err)
+	})
+
+	startTime := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	series := &entity.AppointmentSeries{
+		BusinessID: businessID,
+		ClientID:   client.ID,
+		EmployeeID: employee.ID,
+		ServiceID:  service.ID,
+		RRule:      fmt.Sprintf("FREQ=WEEKLY;COUNT=%d", count),
+		StartTime:  startTime,
+	}
+	appointments := make([]*entity.Appointment, count)
+	for i := range appointments {
+		start := startTime.AddDate(0, 0, 7*i)
+		appointments[i] = &entity.Appointment{
+			BusinessID: businessID,
+			ClientID:   client.ID,
+			EmployeeID: employee.ID,
+			ServiceID:  service.ID,
+			StartTime:  start,
+			EndTime:    start.Add(30 * time.Minute),
+			Status:     entity.AppointmentStatusScheduled,
+		}
+	}
+	require.NoError(t, appointmentRepo.CreateSeries(ctx, series, appointments))
+
+	return appointments
+}
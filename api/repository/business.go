@@ -16,7 +16,9 @@ type BusinessRepository interface {
 	Get(ctx context.Context, id int) (*entity.Business, error)
 	Update(ctx context.Context, business *entity.Business) error
 	UpdateAppearance(ctx context.Context, id int, logoURL string, colorScheme map[string]interface{}) error
-	ListBySearch(ctx context.Context, search string) ([]entity.Business, error)
+	// ListBySearch returns the businesses matching the search, limited to those with an active branch
+	// whose name or address contains the location unless it is empty
+	ListBySearch(ctx context.Context, search, location string) ([]entity.Business, error)
 }
 
 type businessRepository struct {
@@ -29,8 +31,11 @@ func NewBusinessRepository(db *DB) BusinessRepository {
 	}
 }
 
-func (r *businessRepository) ListBySearch(ctx context.Context, search string) ([]entity.Business, error) {
-	dbBusinesses, err := r.db.SQLC.ListBySearch(ctx, "%"+search+"%")
+func (r *businessRepository) ListBySearch(ctx context.Context, search, location string) ([]entity.Business, error) {
+	dbBusinesses, err := r.db.SQLC.ListBySearch(ctx, sqlc.ListBySearchParams{
+		Name:     "%" + search + "%",
+		Location: location,
+	})
 	if err != nil {
 		return nil, r.db.HandleBasicErrors(err)
 	}
@@ -58,6 +63,7 @@ func (r *businessRepository) Create(ctx context.Context, business *entity.Busine
 		Name:        business.Name,
 		LogoUrl:     logoURL,
 		ColorScheme: colorSchemeJSON,
+		Timezone:    business.Timezone,
 	})
 	if err != nil {
 		return r.db.HandleBasicErrors(err)
@@ -79,8 +85,9 @@ func (r *businessRepository) Get(ctx context.Context, id int) (*entity.Business,
 
 func (r *businessRepository) Update(ctx context.Context, business *entity.Business) error {
 	dbBusiness, err := r.db.SQLC.UpdateBusiness(ctx, sqlc.UpdateBusinessParams{
-		ID:   int32(business.ID),
-		Name: business.Name,
+		ID:       int32(business.ID),
+		Name:     business.Name,
+		Timezone: business.Timezone,
 	})
 	if err != nil {
 		return r.db.HandleBasicErrors(err)
@@ -112,6 +119,7 @@ func convertDBBusinessToEntity(dbBusiness sqlc.Business) *entity.Business {
 	business := &entity.Business{
 		ID:        int(dbBusiness.ID),
 		Name:      dbBusiness.Name,
+		Timezone:  dbBusiness.Timezone,
 		CreatedAt: dbBusiness.CreatedAt.Time,
 	}
 
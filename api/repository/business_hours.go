@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+func (r *scheduleRepository) ListBusinessHours(ctx context.Context, businessID int) ([]entity.BusinessHours, error) {
+	dbHours, err := r.db.SQLC.ListBusinessHours(ctx, int32(businessID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list business hours: %w", err)
+	}
+
+	hours := make([]entity.BusinessHours, len(dbHours))
+	for i, h := range dbHours {
+		hours[i] = *convertDBBusinessHoursToEntity(h)
+	}
+
+	return hours, nil
+}
+
+func (r *scheduleRepository) ReplaceBusinessHours(ctx context.Context, businessID int, hours []entity.BusinessHours) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.DeleteBusinessHours(ctx, int32(businessID)); err != nil {
+			return fmt.Errorf("failed to delete business hours: %w", err)
+		}
+
+		for i := range hours {
+			dbHours, err := q.CreateBusinessHours(ctx, sqlc.CreateBusinessHoursParams{
+				BusinessID: int32(businessID),
+				DayOfWeek:  int32(hours[i].DayOfWeek),
+				OpenTime:   pgtype.Time{Microseconds: timeToMicroseconds(hours[i].OpenTime), Valid: true},
+				CloseTime:  pgtype.Time{Microseconds: timeToMicroseconds(hours[i].CloseTime), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create business hours: %w", r.db.HandleBasicErrors(err))
+			}
+
+			hours[i] = *convertDBBusinessHoursToEntity(dbHours)
+		}
+
+		return nil
+	})
+}
+
+func (r *scheduleRepository) CreateClosure(ctx context.Context, closure *entity.BusinessClosure) error {
+	dbClosure, err := r.db.SQLC.CreateClosure(ctx, sqlc.CreateClosureParams{
+		BusinessID: int32(closure.BusinessID),
+		Name:       closure.Name,
+		StartDate:  pgtype.Date{Time: closure.StartDate, Valid: true},
+		EndDate:    pgtype.Date{Time: closure.EndDate, Valid: true},
+		IsYearly:   closure.IsYearly,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create closure: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*closure = *convertDBClosureToEntity(dbClosure)
+	return nil
+}
+
+func (r *scheduleRepository) ImportClosures(ctx context.Context, closures []entity.BusinessClosure) (int, error) {
+	var created int
+	err := r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		created = 0
+		for _, closure := range closures {
+			rows, err := q.ImportClosure(ctx, sqlc.ImportClosureParams{
+				BusinessID: int32(closure.BusinessID),
+				Name:       closure.Name,
+				StartDate:  pgtype.Date{Time: closure.StartDate, Valid: true},
+				EndDate:    pgtype.Date{Time: closure.EndDate, Valid: true},
+				IsYearly:   closure.IsYearly,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to import closure: %w", r.db.HandleBasicErrors(err))
+			}
+			created += int(rows)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+func (r *scheduleRepository) GetClosure(ctx context.Context, id int) (*entity.BusinessClosure, error) {
+	dbClosure, err := r.db.SQLC.GetClosure(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBClosureToEntity(dbClosure), nil
+}
+
+func (r *scheduleRepository) ListClosures(ctx context.Context, businessID int, startDate, endDate time.Time) ([]entity.BusinessClosure, error) {
+	dbClosures, err := r.db.SQLC.ListClosures(ctx, sqlc.ListClosuresParams{
+		BusinessID: int32(businessID),
+		RangeStart: pgtype.Date{Time: startDate, Valid: true},
+		RangeEnd:   pgtype.Date{Time: endDate, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list closures: %w", err)
+	}
+
+	closures := make([]entity.BusinessClosure, len(dbClosures))
+	for i, c := range dbClosures {
+		closures[i] = *convertDBClosureToEntity(c)
+	}
+
+	return closures, nil
+}
+
+func (r *scheduleRepository) DeleteClosure(ctx context.Context, id int) error {
+	deleted, err := r.db.SQLC.DeleteClosure(ctx, int32(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete closure: %w", err)
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func convertDBBusinessHoursToEntity(h sqlc.BusinessHour) *entity.BusinessHours {
+	return &entity.BusinessHours{
+		ID:         int(h.ID),
+		BusinessID: int(h.BusinessID),
+		DayOfWeek:  int(h.DayOfWeek),
+		OpenTime:   microsecondsToTime(h.OpenTime.Microseconds),
+		CloseTime:  microsecondsToTime(h.CloseTime.Microseconds),
+		CreatedAt:  h.CreatedAt.Time,
+	}
+}
+
+func convertDBClosureToEntity(c sqlc.BusinessClosure) *entity.BusinessClosure {
+	return &entity.BusinessClosure{
+		ID:         int(c.ID),
+		BusinessID: int(c.BusinessID),
+		Name:       c.Name,
+		StartDate:  c.StartDate.Time,
+		EndDate:    c.EndDate.Time,
+		IsYearly:   c.IsYearly,
+		CreatedAt:  c.CreatedAt.Time,
+	}
+}
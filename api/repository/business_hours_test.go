@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestScheduleRepository_BusinessHours(t *testing.T) {
+	ctx := context.Background()
+	scheduleRepo := repository.NewScheduleRepository(db)
+
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	t.Cleanup(func() {
+		require.NoError(t, scheduleRepo.ReplaceBusinessHours(ctx, businessID, nil))
+	})
+
+	require.NoError(t, scheduleRepo.ReplaceBusinessHours(ctx, businessID, []entity.BusinessHours{
+		{DayOfWeek: 1, OpenTime: clock(9), CloseTime: clock(18)},
+		{DayOfWeek: 2, OpenTime: clock(9), CloseTime: clock(18)},
+	}))
+
+	// Replacing keeps only the new opening hours
+	hours := []entity.BusinessHours{
+		{DayOfWeek: 1, OpenTime: clock(14), CloseTime: clock(18)},
+		{DayOfWeek: 1, OpenTime: clock(8), CloseTime: clock(12)},
+	}
+	require.NoError(t, scheduleRepo.ReplaceBusinessHours(ctx, businessID, hours))
+	assert.NotZero(t, hours[0].ID)
+
+	got, err := scheduleRepo.ListBusinessHours(ctx, businessID)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, clock(8), got[0].OpenTime)
+	assert.Equal(t, clock(18), got[1].CloseTime)
+}
+
+func TestScheduleRepository_Closures(t *testing.T) {
+	ctx := context.Background()
+	scheduleRepo := repository.NewScheduleRepository(db)
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM business_closures WHERE business_id = $1", businessID)
+		require.NoError(t, err)
+	})
+
+	renovation := &entity.BusinessClosure{BusinessID: businessID, Name: "Renovation", StartDate: date(2025, 3, 10), EndDate: date(2025, 3, 14)}
+	require.NoError(t, scheduleRepo.CreateClosure(ctx, renovation))
+
+	// Importing the same list twice creates its closures once
+	holidays := []entity.BusinessClosure{
+		{BusinessID: businessID, Name: "New Year", StartDate: date(2020, 1, 1), EndDate: date(2020, 1, 1), IsYearly: true},
+		{BusinessID: businessID, Name: "Easter", StartDate: date(2025, 4, 20), EndDate: date(2025, 4, 21)},
+	}
+	created, err := scheduleRepo.ImportClosures(ctx, holidays)
+	require.NoError(t, err)
+	assert.Equal(t, 2, created)
+	created, err = scheduleRepo.ImportClosures(ctx, holidays)
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+
+	// Yearly closures are listed for any later range, other closures when they overlap it
+	closures, err := scheduleRepo.ListClosures(ctx, businessID, date(2025, 3, 14), date(2025, 3, 31))
+	require.NoError(t, err)
+	require.Len(t, closures, 2)
+	assert.Equal(t, "New Year", closures[0].Name)
+	assert.Equal(t, "Renovation", closures[1].Name)
+
+	require.NoError(t, scheduleRepo.DeleteClosure(ctx, renovation.ID))
+	_, err = scheduleRepo.GetClosure(ctx, renovation.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, scheduleRepo.DeleteClosure(ctx, renovation.ID), repository.ErrNotFound)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/vadimpk/ppc-project/entity"
@@ -10,13 +11,19 @@ import (
 
 //go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name BusinessServiceRepository --output ./mocks
 type BusinessServiceRepository interface {
+	// Create stores the service together with its resource types
 	Create(ctx context.Context, service *entity.BusinessService) error
 	Get(ctx context.Context, id int) (*entity.BusinessService, error)
+	// Update stores the service and replaces its resource types
 	Update(ctx context.Context, service *entity.BusinessService) error
 	Delete(ctx context.Context, id int) error
 	List(ctx context.Context, businessID int) ([]entity.BusinessService, error)
 	ListActive(ctx context.Context, businessID int) ([]entity.BusinessService, error)
-	ListServicesBySearch(ctx context.Context, search string) ([]entity.BusinessService, error)
+	// ListByLocation returns the active services provided by the active employees of the branch
+	ListByLocation(ctx context.Context, businessID, locationID int) ([]entity.BusinessService, error)
+	// ListServicesBySearch returns the services matching the search, limited to those provided at an active
+	// branch whose name or address contains the location unless it is empty
+	ListServicesBySearch(ctx context.Context, search, location string) ([]entity.BusinessService, error)
 }
 
 type businessServiceRepository struct {
@@ -29,8 +36,11 @@ func NewBusinessServiceRepository(db *DB) BusinessServiceRepository {
 	}
 }
 
-func (r *businessServiceRepository) ListServicesBySearch(ctx context.Context, search string) ([]entity.BusinessService, error) {
-	dbServices, err := r.db.SQLC.ListServicesBySearch(ctx, "%"+search+"%")
+func (r *businessServiceRepository) ListServicesBySearch(ctx context.Context, search, location string) ([]entity.BusinessService, error) {
+	dbServices, err := r.db.SQLC.ListServicesBySearch(ctx, sqlc.ListServicesBySearchParams{
+		Name:     "%" + search + "%",
+		Location: location,
+	})
 	if err != nil {
 		return nil, r.db.HandleBasicErrors(err)
 	}
@@ -49,21 +59,32 @@ func (r *businessServiceRepository) Create(ctx context.Context, service *entity.
 		description = r.db.ValidText(*service.Description)
 	}
 
-	dbService, err := r.db.SQLC.CreateService(ctx, sqlc.CreateServiceParams{
-		BusinessID:  pgtype.Int4{Int32: int32(service.BusinessID), Valid: true},
-		Name:        service.Name,
-		Description: description,
-		Duration:    int32(service.Duration),
-		Price:       int32(service.Price),
-		IsActive:    pgtype.Bool{Bool: service.IsActive, Valid: true},
-	})
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbService, err := q.CreateService(ctx, sqlc.CreateServiceParams{
+			BusinessID:     pgtype.Int4{Int32: int32(service.BusinessID), Valid: true},
+			Name:           service.Name,
+			Description:    description,
+			Duration:       int32(service.Duration),
+			Price:          int32(service.Price),
+			IsActive:       pgtype.Bool{Bool: service.IsActive, Valid: true},
+			SlotInterval:   int32(service.SlotInterval),
+			BufferBefore:   int32(service.BufferBefore),
+			BufferAfter:    int32(service.BufferAfter),
+			MinNotice:      int32(service.MinNotice),
+			MaxAdvanceDays: int32(service.MaxAdvanceDays),
+			Capacity:       int32(service.Capacity),
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
 
-	service.ID = int(dbService.ID)
-	service.CreatedAt = dbService.CreatedAt.Time
-	return nil
+		service.ID = int(dbService.ID)
+		service.CreatedAt = dbService.CreatedAt.Time
+		if err := replaceServiceResourceTypes(ctx, q, dbService.ID, service.ResourceTypes); err != nil {
+			return err
+		}
+		return recordServiceEvent(ctx, q, entity.EventServiceCreated, dbService)
+	})
 }
 
 func (r *businessServiceRepository) Get(ctx context.Context, id int) (*entity.BusinessService, error) {
@@ -72,7 +93,14 @@ func (r *businessServiceRepository) Get(ctx context.Context, id int) (*entity.Bu
 		return nil, r.db.HandleBasicErrors(err)
 	}
 
-	return convertDBServiceToEntity(dbService), nil
+	resourceTypes, err := r.db.SQLC.ListServiceResourceTypes(ctx, dbService.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service resource types: %w", err)
+	}
+
+	service := convertDBServiceToEntity(dbService)
+	service.ResourceTypes = resourceTypes
+	return service, nil
 }
 
 func (r *businessServiceRepository) Update(ctx context.Context, service *entity.BusinessService) error {
@@ -81,29 +109,42 @@ func (r *businessServiceRepository) Update(ctx context.Context, service *entity.
 		description = r.db.ValidText(*service.Description)
 	}
 
-	dbService, err := r.db.SQLC.UpdateService(ctx, sqlc.UpdateServiceParams{
-		ID:          int32(service.ID),
-		Name:        service.Name,
-		Description: description,
-		Duration:    int32(service.Duration),
-		Price:       int32(service.Price),
-		IsActive:    pgtype.Bool{Bool: service.IsActive, Valid: true},
-	})
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbService, err := q.UpdateService(ctx, sqlc.UpdateServiceParams{
+			ID:             int32(service.ID),
+			Name:           service.Name,
+			Description:    description,
+			Duration:       int32(service.Duration),
+			Price:          int32(service.Price),
+			IsActive:       pgtype.Bool{Bool: service.IsActive, Valid: true},
+			SlotInterval:   int32(service.SlotInterval),
+			BufferBefore:   int32(service.BufferBefore),
+			BufferAfter:    int32(service.BufferAfter),
+			MinNotice:      int32(service.MinNotice),
+			MaxAdvanceDays: int32(service.MaxAdvanceDays),
+			Capacity:       int32(service.Capacity),
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
 
-	service.CreatedAt = dbService.CreatedAt.Time
-	return nil
+		service.CreatedAt = dbService.CreatedAt.Time
+		if err := replaceServiceResourceTypes(ctx, q, dbService.ID, service.ResourceTypes); err != nil {
+			return err
+		}
+		return recordServiceEvent(ctx, q, entity.EventServiceUpdated, dbService)
+	})
 }
 
 func (r *businessServiceRepository) Delete(ctx context.Context, id int) error {
-	err := r.db.SQLC.DeleteService(ctx, int32(id))
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbService, err := q.DeleteService(ctx, int32(id))
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
 
-	return nil
+		return recordServiceEvent(ctx, q, entity.EventServiceDeleted, dbService)
+	})
 }
 
 func (r *businessServiceRepository) List(ctx context.Context, businessID int) ([]entity.BusinessService, error) {
@@ -117,7 +158,7 @@ func (r *businessServiceRepository) List(ctx context.Context, businessID int) ([
 		services[i] = *convertDBServiceToEntity(dbService)
 	}
 
-	return services, nil
+	return services, r.withResourceTypes(ctx, businessID, services)
 }
 
 func (r *businessServiceRepository) ListActive(ctx context.Context, businessID int) ([]entity.BusinessService, error) {
@@ -131,7 +172,61 @@ func (r *businessServiceRepository) ListActive(ctx context.Context, businessID i
 		services[i] = *convertDBServiceToEntity(dbService)
 	}
 
-	return services, nil
+	return services, r.withResourceTypes(ctx, businessID, services)
+}
+
+func (r *businessServiceRepository) ListByLocation(ctx context.Context, businessID, locationID int) ([]entity.BusinessService, error) {
+	dbServices, err := r.db.SQLC.ListLocationServices(ctx, sqlc.ListLocationServicesParams{
+		BusinessID: pgtype.Int4{Int32: int32(businessID), Valid: true},
+		LocationID: pgtype.Int4{Int32: int32(locationID), Valid: true},
+	})
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	services := make([]entity.BusinessService, len(dbServices))
+	for i, dbService := range dbServices {
+		services[i] = *convertDBServiceToEntity(dbService)
+	}
+
+	return services, r.withResourceTypes(ctx, businessID, services)
+}
+
+// withResourceTypes sets the resource types of the services of the business
+func (r *businessServiceRepository) withResourceTypes(ctx context.Context, businessID int, services []entity.BusinessService) error {
+	dbTypes, err := r.db.SQLC.ListBusinessServiceResourceTypes(ctx, int32(businessID))
+	if err != nil {
+		return fmt.Errorf("failed to list service resource types: %w", err)
+	}
+
+	types := make(map[int][]string)
+	for _, t := range dbTypes {
+		types[int(t.ServiceID)] = append(types[int(t.ServiceID)], t.ResourceType)
+	}
+	for i := range services {
+		services[i].ResourceTypes = types[services[i].ID]
+	}
+
+	return nil
+}
+
+// replaceServiceResourceTypes replaces the resource types of the service within the transaction
+func replaceServiceResourceTypes(ctx context.Context, q *sqlc.Queries, serviceID int32, types []string) error {
+	if err := q.DeleteServiceResourceTypes(ctx, serviceID); err != nil {
+		return fmt.Errorf("failed to delete service resource types: %w", err)
+	}
+
+	for _, resourceType := range types {
+		err := q.CreateServiceResourceType(ctx, sqlc.CreateServiceResourceTypeParams{
+			ServiceID:    serviceID,
+			ResourceType: resourceType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create service resource type: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func convertDBServiceToEntity(s sqlc.Service) *entity.BusinessService {
@@ -143,6 +238,13 @@ func convertDBServiceToEntity(s sqlc.Service) *entity.BusinessService {
 		Price:      int(s.Price),
 		IsActive:   s.IsActive.Bool,
 		CreatedAt:  s.CreatedAt.Time,
+
+		SlotInterval:   int(s.SlotInterval),
+		BufferBefore:   int(s.BufferBefore),
+		BufferAfter:    int(s.BufferAfter),
+		MinNotice:      int(s.MinNotice),
+		MaxAdvanceDays: int(s.MaxAdvanceDays),
+		Capacity:       int(s.Capacity),
 	}
 
 	if s.Description.Valid {
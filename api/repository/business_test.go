@@ -13,12 +13,13 @@ import (
 )
 
 var (
-	db           *repository.DB
-	businessRepo repository.BusinessRepository
-	serviceRepo  repository.BusinessServiceRepository
-	userRepo     repository.UserRepository
-	employeeRepo repository.EmployeeRepository
-	businessID   int
+	db              *repository.DB
+	businessRepo    repository.BusinessRepository
+	serviceRepo     repository.BusinessServiceRepository
+	userRepo        repository.UserRepository
+	employeeRepo    repository.EmployeeRepository
+	appointmentRepo repository.AppointmentRepository
+	businessID      int
 )
 
 func TestMain(m *testing.M) {
@@ -50,6 +51,7 @@ func TestMain(m *testing.M) {
 	serviceRepo = repository.NewBusinessServiceRepository(db)
 	userRepo = repository.NewUserRepository(db)
 	employeeRepo = repository.NewEmployeeRepository(db)
+	appointmentRepo = repository.NewAppointmentRepository(db)
 
 	// Run tests
 	code := m.Run()
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name CalendarFeedRepository --output ./mocks
+type CalendarFeedRepository interface {
+	// Replace stores the feed, deleting the previous feed of its owner in the same transaction
+	Replace(ctx context.Context, feed *entity.CalendarFeed) error
+	GetByToken(ctx context.Context, token string) (*entity.CalendarFeed, error)
+	// DeleteByEmployee revokes the feed of the employee, ErrNotFound is returned when there is none
+	DeleteByEmployee(ctx context.Context, employeeID int) error
+	// DeleteByClient revokes the feed of the client, ErrNotFound is returned when there is none
+	DeleteByClient(ctx context.Context, clientID int) error
+}
+
+type calendarFeedRepository struct {
+	db *DB
+}
+
+func NewCalendarFeedRepository(db *DB) CalendarFeedRepository {
+	return &calendarFeedRepository{
+		db: db,
+	}
+}
+
+func (r *calendarFeedRepository) Replace(ctx context.Context, feed *entity.CalendarFeed) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		if feed.EmployeeID != nil {
+			_, err = q.DeleteEmployeeCalendarFeed(ctx, optionalInt4(feed.EmployeeID))
+		} else {
+			_, err = q.DeleteClientCalendarFeed(ctx, optionalInt4(feed.ClientID))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete previous calendar feed: %w", err)
+		}
+
+		dbFeed, err := q.CreateCalendarFeed(ctx, sqlc.CreateCalendarFeedParams{
+			Token:      feed.Token,
+			EmployeeID: optionalInt4(feed.EmployeeID),
+			ClientID:   optionalInt4(feed.ClientID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create calendar feed: %w", r.db.HandleBasicErrors(err))
+		}
+
+		*feed = *convertDBCalendarFeedToEntity(dbFeed)
+		return nil
+	})
+}
+
+func (r *calendarFeedRepository) GetByToken(ctx context.Context, token string) (*entity.CalendarFeed, error) {
+	dbFeed, err := r.db.SQLC.GetCalendarFeedByToken(ctx, token)
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBCalendarFeedToEntity(dbFeed), nil
+}
+
+func (r *calendarFeedRepository) DeleteByEmployee(ctx context.Context, employeeID int) error {
+	deleted, err := r.db.SQLC.DeleteEmployeeCalendarFeed(ctx, pgtype.Int4{Int32: int32(employeeID), Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) DeleteByClient(ctx context.Context, clientID int) error {
+	deleted, err := r.db.SQLC.DeleteClientCalendarFeed(ctx, pgtype.Int4{Int32: int32(clientID), Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func convertDBCalendarFeedToEntity(f sqlc.CalendarFeed) *entity.CalendarFeed {
+	feed := &entity.CalendarFeed{
+		ID:        int(f.ID),
+		Token:     f.Token,
+		CreatedAt: f.CreatedAt.Time,
+	}
+	if f.EmployeeID.Valid {
+		employeeID := int(f.EmployeeID.Int32)
+		feed.EmployeeID = &employeeID
+	}
+	if f.ClientID.Valid {
+		clientID := int(f.ClientID.Int32)
+		feed.ClientID = &clientID
+	}
+	return feed
+}
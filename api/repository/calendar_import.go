@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name CalendarImportRepository --output ./mocks
+type CalendarImportRepository interface {
+	// Upsert stores the import, an existing import of the employee with the same name gets the source URL of this one
+	Upsert(ctx context.Context, calendarImport *entity.CalendarImport) error
+	Get(ctx context.Context, id int) (*entity.CalendarImport, error)
+	ListByEmployee(ctx context.Context, employeeID int) ([]entity.CalendarImport, error)
+	// ListSubscribed returns the imports that have a source URL
+	ListSubscribed(ctx context.Context) ([]entity.CalendarImport, error)
+	Delete(ctx context.Context, id int) error
+	// ReplaceBusyTime replaces the busy time of the import and stores its sync time and error in the same transaction
+	ReplaceBusyTime(ctx context.Context, calendarImport *entity.CalendarImport, busy []entity.BusyTime) error
+	// UpdateError stores the error of a failed import, keeping the busy time of the previous import
+	UpdateError(ctx context.Context, calendarImport *entity.CalendarImport) error
+}
+
+type calendarImportRepository struct {
+	db *DB
+}
+
+func NewCalendarImportRepository(db *DB) CalendarImportRepository {
+	return &calendarImportRepository{
+		db: db,
+	}
+}
+
+func (r *calendarImportRepository) Upsert(ctx context.Context, calendarImport *entity.CalendarImport) error {
+	dbImport, err := r.db.SQLC.UpsertCalendarImport(ctx, sqlc.UpsertCalendarImportParams{
+		EmployeeID: int32(calendarImport.EmployeeID),
+		Name:       calendarImport.Name,
+		SourceUrl:  optionalText(calendarImport.SourceURL),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store calendar import: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*calendarImport = *convertDBCalendarImportToEntity(dbImport)
+	return nil
+}
+
+func (r *calendarImportRepository) Get(ctx context.Context, id int) (*entity.CalendarImport, error) {
+	dbImport, err := r.db.SQLC.GetCalendarImport(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBCalendarImportToEntity(dbImport), nil
+}
+
+func (r *calendarImportRepository) ListByEmployee(ctx context.Context, employeeID int) ([]entity.CalendarImport, error) {
+	dbImports, err := r.db.SQLC.ListCalendarImports(ctx, int32(employeeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar imports: %w", err)
+	}
+
+	return convertDBCalendarImports(dbImports), nil
+}
+
+func (r *calendarImportRepository) ListSubscribed(ctx context.Context) ([]entity.CalendarImport, error) {
+	dbImports, err := r.db.SQLC.ListSubscribedCalendarImports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar imports: %w", err)
+	}
+
+	return convertDBCalendarImports(dbImports), nil
+}
+
+func (r *calendarImportRepository) Delete(ctx context.Context, id int) error {
+	deleted, err := r.db.SQLC.DeleteCalendarImport(ctx, int32(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar import: %w", err)
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *calendarImportRepository) ReplaceBusyTime(ctx context.Context, calendarImport *entity.CalendarImport, busy []entity.BusyTime) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.DeleteImportBusyTimes(ctx, int32(calendarImport.ID)); err != nil {
+			return fmt.Errorf("failed to delete previous busy time: %w", err)
+		}
+
+		for _, b := range busy {
+			err := q.CreateBusyTime(ctx, sqlc.CreateBusyTimeParams{
+				ImportID:   int32(calendarImport.ID),
+				EmployeeID: int32(calendarImport.EmployeeID),
+				StartTime:  pgtype.Timestamptz{Time: b.StartTime, Valid: true},
+				EndTime:    pgtype.Timestamptz{Time: b.EndTime, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create busy time: %w", r.db.HandleBasicErrors(err))
+			}
+		}
+
+		dbImport, err := q.UpdateCalendarImportSync(ctx, sqlc.UpdateCalendarImportSyncParams{
+			ID:           int32(calendarImport.ID),
+			BusyCount:    int32(len(busy)),
+			LastSyncedAt: optionalTimestamptz(calendarImport.LastSyncedAt),
+			LastError:    optionalText(calendarImport.LastError),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update calendar import: %w", r.db.HandleBasicErrors(err))
+		}
+
+		*calendarImport = *convertDBCalendarImportToEntity(dbImport)
+		return nil
+	})
+}
+
+func (r *calendarImportRepository) UpdateError(ctx context.Context, calendarImport *entity.CalendarImport) error {
+	dbImport, err := r.db.SQLC.UpdateCalendarImportError(ctx, sqlc.UpdateCalendarImportErrorParams{
+		ID:        int32(calendarImport.ID),
+		LastError: optionalText(calendarImport.LastError),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update calendar import: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*calendarImport = *convertDBCalendarImportToEntity(dbImport)
+	return nil
+}
+
+func convertDBCalendarImports(dbImports []sqlc.CalendarImport) []entity.CalendarImport {
+	imports := make([]entity.CalendarImport, len(dbImports))
+	for i, dbImport := range dbImports {
+		imports[i] = *convertDBCalendarImportToEntity(dbImport)
+	}
+	return imports
+}
+
+func convertDBCalendarImportToEntity(i sqlc.CalendarImport) *entity.CalendarImport {
+	calendarImport := &entity.CalendarImport{
+		ID:         int(i.ID),
+		EmployeeID: int(i.EmployeeID),
+		Name:       i.Name,
+		BusyCount:  int(i.BusyCount),
+		CreatedAt:  i.CreatedAt.Time,
+	}
+	if i.SourceUrl.Valid {
+		calendarImport.SourceURL = &i.SourceUrl.String
+	}
+	if i.LastSyncedAt.Valid {
+		calendarImport.LastSyncedAt = &i.LastSyncedAt.Time
+	}
+	if i.LastError.Valid {
+		calendarImport.LastError = &i.LastError.String
+	}
+	return calendarImport
+}
@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestCalendarImportRepository(t *testing.T) {
+	ctx := context.Background()
+	importRepo := repository.NewCalendarImportRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+
+	user := createTestUser(t, entity.RoleEmployee)
+	employee := &entity.Employee{BusinessID: businessID, UserID: user.ID, IsActive: true}
+	require.NoError(t, employeeRepo.Create(ctx, employee))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM employees WHERE id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM users WHERE id = $1", user.ID)
+		require.NoError(t, err)
+	})
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	syncedAt := at(1, 12)
+
+	calendarImport := &entity.CalendarImport{EmployeeID: employee.ID, Name: "Work"}
+	require.NoError(t, importRepo.Upsert(ctx, calendarImport))
+	assert.Nil(t, calendarImport.LastSyncedAt)
+
+	calendarImport.LastSyncedAt = &syncedAt
+	require.NoError(t, importRepo.ReplaceBusyTime(ctx, calendarImport, []entity.BusyTime{
+		{StartTime: at(10, 9), EndTime: at(10, 10)},
+		{StartTime: at(11, 9), EndTime: at(11, 10)},
+	}))
+	assert.Equal(t, 2, calendarImport.BusyCount)
+
+	busy, err := scheduleRepo.ListBusyTime(ctx, employee.ID, at(10, 0), at(11, 0))
+	require.NoError(t, err)
+	require.Len(t, busy, 1)
+	assert.True(t, at(10, 9).Equal(busy[0].StartTime))
+
+	// Importing a calendar with the same name replaces its busy time
+	url := "https://calendar.example.com/work.ics"
+	again := &entity.CalendarImport{EmployeeID: employee.ID, Name: "Work", SourceURL: &url}
+	require.NoError(t, importRepo.Upsert(ctx, again))
+	assert.Equal(t, calendarImport.ID, again.ID)
+
+	require.NoError(t, importRepo.ReplaceBusyTime(ctx, again, []entity.BusyTime{
+		{StartTime: at(12, 9), EndTime: at(12, 10)},
+	}))
+	busy, err = scheduleRepo.ListBusyTime(ctx, employee.ID, at(1, 0), at(31, 0))
+	require.NoError(t, err)
+	require.Len(t, busy, 1)
+	assert.True(t, at(12, 9).Equal(busy[0].StartTime))
+
+	subscribed, err := importRepo.ListSubscribed(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscribed, 1)
+	assert.Equal(t, &url, subscribed[0].SourceURL)
+
+	// A failed import keeps the previous busy time
+	failure := "calendar unavailable: server responded with 500"
+	again.LastError = &failure
+	require.NoError(t, importRepo.UpdateError(ctx, again))
+	got, err := importRepo.Get(ctx, again.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &failure, got.LastError)
+	assert.Equal(t, 1, got.BusyCount)
+
+	// Deleting the import removes its busy time
+	require.NoError(t, importRepo.Delete(ctx, again.ID))
+	busy, err = scheduleRepo.ListBusyTime(ctx, employee.ID, at(1, 0), at(31, 0))
+	require.NoError(t, err)
+	assert.Empty(t, busy)
+	assert.ErrorIs(t, importRepo.Delete(ctx, again.ID), repository.ErrNotFound)
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestCalendarFeedRepository(t *testing.T) {
+	ctx := context.Background()
+	calendarRepo := repository.NewCalendarFeedRepository(db)
+
+	client := &entity.User{
+		BusinessID:   businessID,
+		Email:        stringPtr("calendar-client@example.com"),
+		FullName:     "Calendar Client",
+		PasswordHash: "hash",
+		Role:         entity.RoleClient,
+	}
+	require.NoError(t, userRepo.Create(ctx, client))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM users WHERE id = $1", client.ID)
+		require.NoError(t, err)
+	})
+
+	first := &entity.CalendarFeed{Token: "calendar-token-1", ClientID: &client.ID}
+	require.NoError(t, calendarRepo.Replace(ctx, first))
+
+	feed, err := calendarRepo.GetByToken(ctx, "calendar-token-1")
+	require.NoError(t, err)
+	assert.Equal(t, &client.ID, feed.ClientID)
+	assert.Nil(t, feed.EmployeeID)
+
+	// A new token replaces the previous one
+	second := &entity.CalendarFeed{Token: "calendar-token-2", ClientID: &client.ID}
+	require.NoError(t, calendarRepo.Replace(ctx, second))
+
+	_, err = calendarRepo.GetByToken(ctx, "calendar-token-1")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = calendarRepo.GetByToken(ctx, "calendar-token-2")
+	require.NoError(t, err)
+
+	// Revoked feeds are gone
+	require.NoError(t, calendarRepo.DeleteByClient(ctx, client.ID))
+	_, err = calendarRepo.GetByToken(ctx, "calendar-token-2")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, calendarRepo.DeleteByClient(ctx, client.ID), repository.ErrNotFound)
+}
@@ -91,9 +91,30 @@ func (db *DB) Begin() (pgx.Tx, error) {
 	return db.PGX.Begin(context.Background())
 }
 
+// InTx runs fn with queries bound to a single transaction. The transaction is committed
+// when fn succeeds and rolled back otherwise.
+func (db *DB) InTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	tx, err := db.PGX.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(db.SQLC.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", db.HandleBasicErrors(err))
+	}
+	return nil
+}
+
 var (
 	ErrNotFound      = errors.New("not found in db")
 	ErrAlreadyExists = errors.New("already exists")
+	ErrConflict      = errors.New("conflicts with existing record")
+	ErrNoCapacity    = errors.New("no capacity left")
 )
 
 func (db *DB) HandleBasicErrors(err error) error {
@@ -113,6 +134,10 @@ func (db *DB) HandleBasicErrors(err error) error {
 		if pgError.Code == "23503" {
 			return ErrNotFound
 		}
+		// exclusion constraint violation, e.g. overlapping appointments
+		if pgError.Code == "23P01" {
+			return ErrConflict
+		}
 	}
 
 	return err
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE appointments
+    ADD COLUMN cancellation_reason TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS cancellation_reason;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Scheduled appointments of the same employee must never overlap.
+-- Checked by the database so concurrent bookings cannot both succeed.
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_employee_no_overlap
+        EXCLUDE USING gist (
+        employee_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+        ) WHERE (status = 'scheduled');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_employee_no_overlap;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE services
+    ADD COLUMN slot_interval    INTEGER NOT NULL DEFAULT 0 CHECK (slot_interval >= 0),   -- minutes between slot starts, 0 uses the duration
+    ADD COLUMN buffer_before    INTEGER NOT NULL DEFAULT 0 CHECK (buffer_before >= 0),   -- minutes blocked before the appointment
+    ADD COLUMN buffer_after     INTEGER NOT NULL DEFAULT 0 CHECK (buffer_after >= 0),    -- minutes blocked after the appointment
+    ADD COLUMN min_notice       INTEGER NOT NULL DEFAULT 15 CHECK (min_notice >= 0),     -- minutes required between booking and start
+    ADD COLUMN max_advance_days INTEGER NOT NULL DEFAULT 0 CHECK (max_advance_days >= 0); -- days ahead bookings are open, 0 is unlimited
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE services
+    DROP COLUMN IF EXISTS slot_interval,
+    DROP COLUMN IF EXISTS buffer_before,
+    DROP COLUMN IF EXISTS buffer_after,
+    DROP COLUMN IF EXISTS min_notice,
+    DROP COLUMN IF EXISTS max_advance_days;
+-- +goose StatementEnd
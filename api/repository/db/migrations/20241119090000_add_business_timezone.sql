@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE businesses
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC'; -- IANA name, schedules are kept in this local time
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE businesses
+    DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE appointment_series
+(
+    id            SERIAL PRIMARY KEY,
+    business_id   INTEGER REFERENCES businesses (id),
+    client_id     INTEGER REFERENCES users (id),
+    employee_id   INTEGER REFERENCES employees (id),
+    service_id    INTEGER REFERENCES services (id),
+    rrule         TEXT                     NOT NULL, -- RFC 5545 recurrence rule, e.g. FREQ=WEEKLY;INTERVAL=2;COUNT=10
+    start_time    TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the first occurrence
+    reminder_time INTEGER,                           -- minutes before each appointment
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE appointments
+    ADD COLUMN series_id INTEGER REFERENCES appointment_series (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_appointment_series_business ON appointment_series (business_id);
+CREATE INDEX idx_appointments_series_start_time ON appointments (series_id, start_time);
+
+-- Moving the occurrences of a series can overlap them with each other until every one is moved,
+-- so the overlap constraint can be deferred to the end of such a transaction
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_employee_no_overlap;
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_employee_no_overlap
+        EXCLUDE USING gist (
+        employee_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+        ) WHERE (status = 'scheduled') DEFERRABLE INITIALLY IMMEDIATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_employee_no_overlap;
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_employee_no_overlap
+        EXCLUDE USING gist (
+        employee_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+        ) WHERE (status = 'scheduled');
+
+DROP INDEX IF EXISTS idx_appointments_series_start_time;
+DROP INDEX IF EXISTS idx_appointment_series_business;
+
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS appointment_series;
+-- +goose StatementEnd
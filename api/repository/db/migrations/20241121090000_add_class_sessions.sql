@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE services
+    ADD COLUMN capacity INTEGER NOT NULL DEFAULT 1 CHECK (capacity >= 1); -- clients served at once, above 1 for group classes
+
+-- A session of a group service. Every client booking a seat gets an appointment linked to the session.
+CREATE TABLE class_sessions
+(
+    id           SERIAL PRIMARY KEY,
+    business_id  INTEGER REFERENCES businesses (id),
+    employee_id  INTEGER REFERENCES employees (id),
+    service_id   INTEGER REFERENCES services (id),
+    start_time   TIMESTAMPTZ NOT NULL,
+    end_time     TIMESTAMPTZ NOT NULL,
+    capacity     INTEGER     NOT NULL CHECK (capacity >= 1),
+    booked_seats INTEGER     NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (employee_id, service_id, start_time),
+    CHECK (booked_seats >= 0 AND booked_seats <= capacity)
+);
+
+ALTER TABLE appointments
+    ADD COLUMN session_id INTEGER REFERENCES class_sessions (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_appointments_session ON appointments (session_id);
+
+-- A client holds at most one seat of a session
+CREATE UNIQUE INDEX idx_appointments_session_client ON appointments (session_id, client_id)
+    WHERE status = 'scheduled' AND session_id IS NOT NULL;
+
+-- Bookings of the same session share their time, any other overlap of an employee is still rejected
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_employee_no_overlap;
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_employee_no_overlap
+        EXCLUDE USING gist (
+        employee_id WITH =,
+        tstzrange(start_time, end_time) WITH &&,
+        (COALESCE(session_id, -id)) WITH <>
+        ) WHERE (status = 'scheduled') DEFERRABLE INITIALLY IMMEDIATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_employee_no_overlap;
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_employee_no_overlap
+        EXCLUDE USING gist (
+        employee_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+        ) WHERE (status = 'scheduled') DEFERRABLE INITIALLY IMMEDIATE;
+
+DROP INDEX IF EXISTS idx_appointments_session_client;
+DROP INDEX IF EXISTS idx_appointments_session;
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS class_sessions;
+ALTER TABLE services
+    DROP COLUMN IF EXISTS capacity;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Held appointments keep an interval for a client until the hold expires
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_status_check;
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_status_check CHECK (status IN ('scheduled', 'completed', 'cancelled', 'no_show', 'held')),
+    ADD COLUMN hold_expires_at TIMESTAMPTZ;
+
+CREATE TABLE waitlist_entries
+(
+    id               SERIAL PRIMARY KEY,
+    business_id      INTEGER REFERENCES businesses (id),
+    client_id        INTEGER REFERENCES users (id),
+    service_id       INTEGER REFERENCES services (id),
+    employee_id      INTEGER REFERENCES employees (id), -- any employee providing the service when NULL
+    window_start     TIMESTAMPTZ NOT NULL,
+    window_end       TIMESTAMPTZ NOT NULL,
+    auto_book        BOOLEAN     NOT NULL DEFAULT false, -- book a freed slot right away instead of offering it
+    status           VARCHAR(20) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'offered', 'booked', 'expired', 'cancelled')),
+    appointment_id   INTEGER REFERENCES appointments (id) ON DELETE SET NULL,
+    offer_expires_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (window_end > window_start)
+);
+
+CREATE INDEX idx_waitlist_entries_matching ON waitlist_entries (service_id, status, window_start, window_end);
+CREATE INDEX idx_waitlist_entries_client ON waitlist_entries (client_id);
+CREATE INDEX idx_waitlist_entries_offer_expiry ON waitlist_entries (offer_expires_at) WHERE status = 'offered';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS waitlist_entries;
+
+DELETE
+FROM appointments
+WHERE status = 'held';
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS hold_expires_at,
+    DROP CONSTRAINT IF EXISTS appointments_status_check;
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_status_check CHECK (status IN ('scheduled', 'completed', 'cancelled', 'no_show'));
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Checkout holds are held appointments confirmed by their token
+ALTER TABLE appointments
+    ADD COLUMN hold_token VARCHAR(64) UNIQUE;
+
+CREATE INDEX idx_appointments_hold_expiry ON appointments (hold_expires_at) WHERE status = 'held';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_appointments_hold_expiry;
+
+DELETE
+FROM appointments
+WHERE hold_token IS NOT NULL;
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS hold_token;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A reminder is claimed by one dispatcher until reminder_claimed_until and never sent again once reminder_sent_at is set
+ALTER TABLE appointments
+    ADD COLUMN reminder_sent_at       TIMESTAMPTZ,
+    ADD COLUMN reminder_claimed_until TIMESTAMPTZ;
+
+CREATE INDEX idx_appointments_pending_reminders ON appointments (start_time)
+    WHERE status = 'scheduled' AND reminder_time IS NOT NULL AND reminder_sent_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_appointments_pending_reminders;
+
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS reminder_claimed_until,
+    DROP COLUMN IF EXISTS reminder_sent_at;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Outbox of domain events, written in the transaction of the change and published by the relay.
+-- A pending event is claimed by one relay until next_attempt_at, delivered_to lists the consumers that handled it.
+CREATE TABLE domain_events
+(
+    id              BIGSERIAL PRIMARY KEY,
+    business_id     INTEGER REFERENCES businesses (id),
+    aggregate_type  VARCHAR(32) NOT NULL,
+    aggregate_id    INTEGER     NOT NULL,
+    event_type      VARCHAR(64) NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'published', 'dead')),
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    delivered_to    TEXT[]      NOT NULL DEFAULT '{}',
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_domain_events_pending ON domain_events (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_domain_events_aggregate ON domain_events (aggregate_type, aggregate_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS domain_events;
+-- +goose StatementEnd
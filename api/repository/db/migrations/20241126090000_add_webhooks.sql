@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks
+(
+    id          SERIAL PRIMARY KEY,
+    business_id INTEGER REFERENCES businesses (id) ON DELETE CASCADE,
+    url         TEXT        NOT NULL,
+    secret      VARCHAR(64) NOT NULL, -- signs the deliveries with HMAC-SHA256
+    event_types TEXT[]      NOT NULL,
+    is_active   BOOLEAN     NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhooks_business ON webhooks (business_id);
+
+-- Deliveries of events to webhooks, also the delivery log. A pending delivery is claimed by one
+-- dispatcher until next_attempt_at. Test deliveries have no event.
+CREATE TABLE webhook_deliveries
+(
+    id              SERIAL PRIMARY KEY,
+    webhook_id      INTEGER     NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id        BIGINT REFERENCES domain_events (id) ON DELETE SET NULL,
+    event_type      VARCHAR(64) NOT NULL,
+    payload         JSONB       NOT NULL, -- the request body
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at    TIMESTAMPTZ,
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tokens of the read-only iCalendar feeds of employees and clients. Each owner has at most one token,
+-- revoking it or issuing a new one stops the previous subscription URL from working.
+CREATE TABLE calendar_feeds
+(
+    id          SERIAL PRIMARY KEY,
+    token       VARCHAR(64) NOT NULL UNIQUE,
+    employee_id INTEGER UNIQUE REFERENCES employees (id) ON DELETE CASCADE,
+    client_id   INTEGER UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (num_nonnulls(employee_id, client_id) = 1)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS calendar_feeds;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- External calendars of employees. Imports with a source URL are fetched periodically,
+-- imports without one are replaced by uploading the calendar again.
+CREATE TABLE calendar_imports
+(
+    id             SERIAL PRIMARY KEY,
+    employee_id    INTEGER      NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    name           VARCHAR(255) NOT NULL,
+    source_url     TEXT,
+    busy_count     INTEGER      NOT NULL DEFAULT 0,
+    last_synced_at TIMESTAMPTZ,
+    last_error     TEXT,
+    created_at     TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (employee_id, name)
+);
+
+-- Occurrences of the events of imported calendars, the employee is not available during them.
+-- The busy time of an import is replaced as a whole on every import.
+CREATE TABLE busy_times
+(
+    id          SERIAL PRIMARY KEY,
+    import_id   INTEGER     NOT NULL REFERENCES calendar_imports (id) ON DELETE CASCADE,
+    employee_id INTEGER     NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    start_time  TIMESTAMPTZ NOT NULL,
+    end_time    TIMESTAMPTZ NOT NULL,
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX idx_busy_times_employee ON busy_times (employee_id, start_time);
+CREATE INDEX idx_busy_times_import ON busy_times (import_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS busy_times;
+DROP TABLE IF EXISTS calendar_imports;
+-- +goose StatementEnd
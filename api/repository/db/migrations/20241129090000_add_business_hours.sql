@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Opening hours of businesses in their local time. Businesses without opening hours are open whenever
+-- their employees work, otherwise employees are only bookable while the business is open.
+CREATE TABLE business_hours
+(
+    id          SERIAL PRIMARY KEY,
+    business_id INTEGER NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    open_time   TIME    NOT NULL,
+    close_time  TIME    NOT NULL,
+    created_at  TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (close_time > open_time)
+);
+
+-- Whole days the business is closed, such as public holidays. Yearly closures repeat on the same dates every year.
+CREATE TABLE business_closures
+(
+    id          SERIAL PRIMARY KEY,
+    business_id INTEGER      NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    start_date  DATE         NOT NULL,
+    end_date    DATE         NOT NULL,
+    is_yearly   BOOLEAN      NOT NULL DEFAULT false,
+    created_at  TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    UNIQUE (business_id, start_date, name)
+);
+
+CREATE INDEX idx_business_hours_business ON business_hours (business_id);
+CREATE INDEX idx_business_closures_business ON business_closures (business_id, start_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS business_closures;
+DROP TABLE IF EXISTS business_hours;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Time off asked for by employees. Approving a request creates the non-working schedule overrides of its dates.
+CREATE TABLE time_off_requests
+(
+    id            SERIAL PRIMARY KEY,
+    business_id   INTEGER     NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    employee_id   INTEGER     NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    start_date    DATE        NOT NULL,
+    end_date      DATE        NOT NULL,
+    start_time    TIME, -- part of a single day when set, whole days otherwise
+    end_time      TIME,
+    reason        TEXT,
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by    INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    decided_at    TIMESTAMPTZ,
+    decision_note TEXT,
+    created_at    TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    CHECK ((start_time IS NULL AND end_time IS NULL) OR (end_time > start_time AND end_date = start_date))
+);
+
+CREATE INDEX idx_time_off_requests_employee ON time_off_requests (employee_id, start_date);
+CREATE INDEX idx_time_off_requests_business_status ON time_off_requests (business_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS time_off_requests;
+-- +goose StatementEnd
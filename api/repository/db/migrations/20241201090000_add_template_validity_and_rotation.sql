@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Templates apply from valid_from to valid_until inclusive, open ends apply indefinitely.
+-- Rotating templates apply every rotation_weeks weeks, in week rotation_week of a cycle
+-- whose first week starts on rotation_anchor.
+ALTER TABLE schedule_templates
+    ADD COLUMN valid_from      DATE,
+    ADD COLUMN valid_until     DATE,
+    ADD COLUMN rotation_weeks  INTEGER NOT NULL DEFAULT 1 CHECK (rotation_weeks BETWEEN 1 AND 8),
+    ADD COLUMN rotation_week   INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN rotation_anchor DATE,
+    ADD CONSTRAINT schedule_templates_valid_range CHECK (valid_until >= valid_from),
+    ADD CONSTRAINT schedule_templates_rotation CHECK (
+        rotation_week BETWEEN 1 AND rotation_weeks AND (rotation_weeks = 1 OR rotation_anchor IS NOT NULL)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE schedule_templates
+    DROP CONSTRAINT IF EXISTS schedule_templates_rotation,
+    DROP CONSTRAINT IF EXISTS schedule_templates_valid_range,
+    DROP COLUMN IF EXISTS rotation_anchor,
+    DROP COLUMN IF EXISTS rotation_week,
+    DROP COLUMN IF EXISTS rotation_weeks,
+    DROP COLUMN IF EXISTS valid_until,
+    DROP COLUMN IF EXISTS valid_from;
+-- +goose StatementEnd
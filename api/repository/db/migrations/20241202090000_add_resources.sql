@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rooms, chairs and equipment shared by the employees of a business
+CREATE TABLE resources
+(
+    id            SERIAL PRIMARY KEY,
+    business_id   INTEGER      NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    name          VARCHAR(255) NOT NULL,
+    resource_type VARCHAR(50)  NOT NULL, -- e.g. room, chair, laser
+    is_active     BOOLEAN      NOT NULL DEFAULT true,
+    created_at    TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_resources_business_type ON resources (business_id, resource_type);
+
+-- A service with resource types is booked together with a free resource of one of them
+CREATE TABLE service_resource_types
+(
+    service_id    INTEGER     NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+    resource_type VARCHAR(50) NOT NULL,
+    PRIMARY KEY (service_id, resource_type)
+);
+
+ALTER TABLE appointments
+    ADD COLUMN resource_id INTEGER REFERENCES resources (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_appointments_resource ON appointments (resource_id, start_time) WHERE resource_id IS NOT NULL;
+
+-- Like employees, a resource is never booked twice at once, except by the seats of one class session
+ALTER TABLE appointments
+    ADD CONSTRAINT appointments_resource_no_overlap
+        EXCLUDE USING gist (
+        resource_id WITH =,
+        tstzrange(start_time, end_time) WITH &&,
+        (COALESCE(session_id, -id)) WITH <>
+        ) WHERE (status = 'scheduled' AND resource_id IS NOT NULL) DEFERRABLE INITIALLY IMMEDIATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_resource_no_overlap;
+DROP INDEX IF EXISTS idx_appointments_resource;
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS resource_id;
+DROP TABLE IF EXISTS service_resource_types;
+DROP TABLE IF EXISTS resources;
+-- +goose StatementEnd
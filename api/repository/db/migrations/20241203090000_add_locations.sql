@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Branches of a business, each with its own staff and timezone
+CREATE TABLE locations
+(
+    id          SERIAL PRIMARY KEY,
+    business_id INTEGER      NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    address     TEXT         NOT NULL,
+    timezone    VARCHAR(64)  NOT NULL, -- IANA name, e.g. Europe/Kyiv
+    latitude    DOUBLE PRECISION,
+    longitude   DOUBLE PRECISION,
+    is_active   BOOLEAN      NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE INDEX idx_locations_business ON locations (business_id);
+
+-- Employees work at one branch, employees without one work for the whole business
+ALTER TABLE employees
+    ADD COLUMN location_id INTEGER REFERENCES locations (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_employees_location ON employees (location_id) WHERE location_id IS NOT NULL;
+
+-- Appointments take place at the branch of their employee
+ALTER TABLE appointments
+    ADD COLUMN location_id INTEGER REFERENCES locations (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_appointments_location ON appointments (location_id, start_time) WHERE location_id IS NOT NULL;
+
+-- Resources of a branch are only booked with its appointments, resources without one are shared
+ALTER TABLE resources
+    ADD COLUMN location_id INTEGER REFERENCES locations (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE resources
+    DROP COLUMN IF EXISTS location_id;
+DROP INDEX IF EXISTS idx_appointments_location;
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS location_id;
+DROP INDEX IF EXISTS idx_employees_location;
+ALTER TABLE employees
+    DROP COLUMN IF EXISTS location_id;
+DROP TABLE IF EXISTS locations;
+-- +goose StatementEnd
@@ -6,8 +6,14 @@ INSERT INTO appointments (business_id,
                           start_time,
                           end_time,
                           status,
-                          reminder_time)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+                          reminder_time,
+                          series_id,
+                          session_id,
+                          hold_expires_at,
+                          hold_token,
+                          resource_id,
+                          location_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 RETURNING *;
 
 -- name: GetAppointment :one
@@ -20,7 +26,9 @@ SELECT a.*,
        e.full_name as employee_full_name,
        s.name      as service_name,
        s.duration  as service_duration,
-       s.price     as service_price
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
 FROM appointments a
          JOIN users c ON c.id = a.client_id
          JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
@@ -28,18 +36,34 @@ FROM appointments a
 WHERE a.id = $1;
 
 -- name: UpdateAppointment :one
+-- A rescheduled appointment or a changed reminder gets its reminder again
 UPDATE appointments
-SET start_time    = $2,
-    end_time      = $3,
-    status        = $4,
-    reminder_time = $5
+SET employee_id      = $2,
+    start_time       = $3,
+    end_time         = $4,
+    status           = $5,
+    reminder_time    = $6,
+    resource_id      = $7,
+    location_id      = $8,
+    reminder_sent_at = CASE
+                           WHEN start_time <> $3 OR reminder_time IS DISTINCT FROM $6 THEN NULL
+                           ELSE reminder_sent_at END
 WHERE id = $1
 RETURNING *;
 
 -- name: CancelAppointment :one
 UPDATE appointments
-SET status = 'cancelled'
+SET status              = 'cancelled',
+    cancellation_reason = $2
 WHERE id = $1
+  AND status = 'scheduled'
+RETURNING *;
+
+-- name: UpdateAppointmentStatus :one
+UPDATE appointments
+SET status = sqlc.arg(new_status)
+WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(current_status)
 RETURNING *;
 
 -- name: ListBusinessAppointments :many
@@ -52,7 +76,9 @@ SELECT a.*,
        e.full_name as employee_full_name,
        s.name      as service_name,
        s.duration  as service_duration,
-       s.price     as service_price
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
 FROM appointments a
          JOIN users c ON c.id = a.client_id
          JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
@@ -71,7 +97,9 @@ SELECT a.*,
        e.full_name as employee_full_name,
        s.name      as service_name,
        s.duration  as service_duration,
-       s.price     as service_price
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
 FROM appointments a
          JOIN users c ON c.id = a.client_id
          JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
@@ -90,7 +118,9 @@ SELECT a.*,
        e.full_name as employee_full_name,
        s.name      as service_name,
        s.duration  as service_duration,
-       s.price     as service_price
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
 FROM appointments a
          JOIN users c ON c.id = a.client_id
          JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
@@ -100,12 +130,258 @@ WHERE a.client_id = $1
 ORDER BY a.start_time;
 
 -- name: CheckEmployeeAvailability :one
+-- Existing appointments block their service buffers as well, held ones until the hold expires
 SELECT COUNT(*) = 0 as is_available
-FROM appointments
-WHERE employee_id = $1
+FROM appointments a
+         JOIN services s ON s.id = a.service_id
+WHERE a.employee_id = sqlc.arg(employee_id)
+  AND a.id <> sqlc.arg(exclude_id)
+  AND (a.status = 'scheduled' OR (a.status = 'held' AND a.hold_expires_at > now()))
+  AND (a.start_time - make_interval(mins => s.buffer_before),
+       a.end_time + make_interval(mins => s.buffer_after)) OVERLAPS (sqlc.arg(start_time)::timestamptz, sqlc.arg(end_time)::timestamptz);
+
+-- name: LockEmployeeBookings :exec
+-- Serializes the bookings of an employee until the end of the transaction
+SELECT pg_advisory_xact_lock(hashtext('appointments'), sqlc.arg(employee_id)::int);
+
+-- name: HasEmployeeOverlap :one
+-- Compares a stored booking with the other bookings of its employee including the buffers of both services.
+-- Seats of the same class session share their time.
+SELECT EXISTS (SELECT 1
+               FROM appointments n
+                        JOIN services ns ON ns.id = n.service_id
+                        JOIN appointments a ON a.employee_id = n.employee_id AND a.id <> n.id
+                        JOIN services s ON s.id = a.service_id
+               WHERE n.id = sqlc.arg(id)
+                 AND (a.status = 'scheduled' OR (a.status = 'held' AND a.hold_expires_at > now()))
+                 AND NOT (a.session_id IS NOT NULL AND a.session_id = n.session_id)
+                 AND (a.start_time - make_interval(mins => s.buffer_before),
+                      a.end_time + make_interval(mins => s.buffer_after)) OVERLAPS
+                     (n.start_time - make_interval(mins => ns.buffer_before),
+                      n.end_time + make_interval(mins => ns.buffer_after))) AS has_overlap;
+
+-- name: CheckResourceAvailability :one
+-- Bookings of the resource block the buffers of their services like employee bookings do
+SELECT COUNT(*) = 0 as is_available
+FROM appointments a
+         JOIN services s ON s.id = a.service_id
+WHERE a.resource_id = sqlc.arg(resource_id)
+  AND a.id <> sqlc.arg(exclude_id)
+  AND (a.status = 'scheduled' OR (a.status = 'held' AND a.hold_expires_at > now()))
+  AND (a.start_time - make_interval(mins => s.buffer_before),
+       a.end_time + make_interval(mins => s.buffer_after)) OVERLAPS (sqlc.arg(start_time)::timestamptz, sqlc.arg(end_time)::timestamptz);
+
+-- name: CheckEmployeeSeriesAvailability :one
+-- The appointments of the series starting at or after series_from are moved together and do not block it
+SELECT COUNT(*) = 0 as is_available
+FROM appointments a
+         JOIN services s ON s.id = a.service_id
+WHERE a.employee_id = sqlc.arg(employee_id)
+  AND NOT (a.series_id IS NOT NULL AND a.series_id = sqlc.arg(series_id) AND a.start_time >= sqlc.arg(series_from))
+  AND (a.status = 'scheduled' OR (a.status = 'held' AND a.hold_expires_at > now()))
+  AND (a.start_time - make_interval(mins => s.buffer_before),
+       a.end_time + make_interval(mins => s.buffer_after)) OVERLAPS (sqlc.arg(start_time)::timestamptz, sqlc.arg(end_time)::timestamptz);
+
+-- name: CheckResourceSeriesAvailability :one
+-- The appointments of the series starting at or after series_from are moved together and do not block it
+SELECT COUNT(*) = 0 as is_available
+FROM appointments a
+         JOIN services s ON s.id = a.service_id
+WHERE a.resource_id = sqlc.arg(resource_id)
+  AND NOT (a.series_id IS NOT NULL AND a.series_id = sqlc.arg(series_id) AND a.start_time >= sqlc.arg(series_from))
+  AND (a.status = 'scheduled' OR (a.status = 'held' AND a.hold_expires_at > now()))
+  AND (a.start_time - make_interval(mins => s.buffer_before),
+       a.end_time + make_interval(mins => s.buffer_after)) OVERLAPS (sqlc.arg(start_time)::timestamptz, sqlc.arg(end_time)::timestamptz);
+
+-- name: DeferAppointmentOverlapConstraints :exec
+-- Checks the overlap constraints when the transaction commits
+SET CONSTRAINTS appointments_employee_no_overlap, appointments_resource_no_overlap DEFERRED;
+
+-- name: ListResourceAppointments :many
+SELECT a.*,
+       c.email     as client_email,
+       c.phone     as client_phone,
+       c.full_name as client_full_name,
+       e.email     as employee_email,
+       e.phone     as employee_phone,
+       e.full_name as employee_full_name,
+       s.name      as service_name,
+       s.duration  as service_duration,
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
+FROM appointments a
+         JOIN users c ON c.id = a.client_id
+         JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
+         JOIN services s ON s.id = a.service_id
+WHERE a.resource_id = ANY (sqlc.arg(resource_ids)::int[])
+  AND a.status IN ('scheduled', 'held')
+  AND a.start_time < sqlc.arg(end_time)
+  AND a.end_time > sqlc.arg(start_time)
+ORDER BY a.start_time;
+
+-- name: CreateAppointmentSeries :one
+INSERT INTO appointment_series (business_id,
+                                client_id,
+                                employee_id,
+                                service_id,
+                                rrule,
+                                start_time,
+                                reminder_time)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListSeriesAppointments :many
+SELECT a.*,
+       c.email     as client_email,
+       c.phone     as client_phone,
+       c.full_name as client_full_name,
+       e.email     as employee_email,
+       e.phone     as employee_phone,
+       e.full_name as employee_full_name,
+       s.name      as service_name,
+       s.duration  as service_duration,
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
+FROM appointments a
+         JOIN users c ON c.id = a.client_id
+         JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
+         JOIN services s ON s.id = a.service_id
+WHERE a.series_id = $1
+  AND a.start_time >= $2
+ORDER BY a.start_time;
+
+-- name: CancelSeriesAppointments :many
+UPDATE appointments
+SET status              = 'cancelled',
+    cancellation_reason = sqlc.arg(cancellation_reason)
+WHERE series_id = sqlc.arg(series_id)
+  AND start_time >= sqlc.arg(start_time)
   AND status = 'scheduled'
-  AND (
-    (start_time, end_time) OVERLAPS ($2, $3)
-        OR
-    (start_time, end_time) OVERLAPS ($2, $3)
-    );
\ No newline at end of file
+RETURNING *;
+
+-- name: CheckEmployeeSessionAvailability :one
+-- Bookings of the class session starting at session_start do not block it
+SELECT COUNT(*) = 0 as is_available
+FROM appointments a
+         JOIN services s ON s.id = a.service_id
+WHERE a.employee_id = sqlc.arg(employee_id)
+  AND (a.status = 'scheduled' OR (a.status = 'held' AND a.hold_expires_at > now()))
+  AND NOT (a.session_id IS NOT NULL AND a.service_id = sqlc.arg(service_id) AND a.start_time = sqlc.arg(session_start))
+  AND (a.start_time - make_interval(mins => s.buffer_before),
+       a.end_time + make_interval(mins => s.buffer_after)) OVERLAPS (sqlc.arg(start_time)::timestamptz, sqlc.arg(end_time)::timestamptz);
+
+-- name: GetOrCreateClassSession :one
+-- The no-op update makes the existing session returned on conflict
+INSERT INTO class_sessions (business_id,
+                            employee_id,
+                            service_id,
+                            start_time,
+                            end_time,
+                            capacity)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (employee_id, service_id, start_time) DO UPDATE
+    SET end_time = class_sessions.end_time
+RETURNING *;
+
+-- name: ReserveClassSeat :one
+UPDATE class_sessions
+SET booked_seats = booked_seats + 1
+WHERE id = $1
+  AND booked_seats < capacity
+RETURNING *;
+
+-- name: ReleaseClassSeat :exec
+UPDATE class_sessions
+SET booked_seats = booked_seats - 1
+WHERE id = $1
+  AND booked_seats > 0;
+
+-- name: ConfirmHeldAppointment :one
+UPDATE appointments
+SET status          = 'scheduled',
+    hold_expires_at = NULL
+WHERE id = $1
+  AND status = 'held'
+  AND hold_expires_at > now()
+RETURNING *;
+
+-- name: DeleteHeldAppointment :exec
+DELETE
+FROM appointments
+WHERE id = $1
+  AND status = 'held';
+
+-- name: GetAppointmentByHoldToken :one
+SELECT a.*,
+       c.email     as client_email,
+       c.phone     as client_phone,
+       c.full_name as client_full_name,
+       e.email     as employee_email,
+       e.phone     as employee_phone,
+       e.full_name as employee_full_name,
+       s.name      as service_name,
+       s.duration  as service_duration,
+       s.price     as service_price,
+       s.buffer_before as service_buffer_before,
+       s.buffer_after  as service_buffer_after
+FROM appointments a
+         JOIN users c ON c.id = a.client_id
+         JOIN users e ON e.id = (SELECT user_id FROM employees WHERE id = a.employee_id)
+         JOIN services s ON s.id = a.service_id
+WHERE a.hold_token = $1;
+
+-- name: ConfirmAppointmentHold :one
+UPDATE appointments
+SET status          = 'scheduled',
+    reminder_time   = sqlc.narg(reminder_time),
+    hold_token      = NULL,
+    hold_expires_at = NULL
+WHERE hold_token = sqlc.arg(hold_token)
+  AND status = 'held'
+  AND hold_expires_at > now()
+RETURNING *;
+
+-- name: DeleteAppointmentHold :execrows
+DELETE
+FROM appointments
+WHERE hold_token = $1
+  AND status = 'held';
+
+-- name: DeleteExpiredAppointmentHolds :execrows
+-- Holds offered to the waitlist have no token and are released by the waitlist
+DELETE
+FROM appointments
+WHERE hold_token IS NOT NULL
+  AND status = 'held'
+  AND hold_expires_at <= $1;
+
+-- name: ClaimDueReminders :many
+-- Claimed rows are skipped by other dispatchers until the claim expires
+UPDATE appointments
+SET reminder_claimed_until = sqlc.arg(claimed_until)
+WHERE id IN (SELECT a.id
+             FROM appointments a
+             WHERE a.status = 'scheduled'
+               AND a.reminder_time IS NOT NULL
+               AND a.reminder_sent_at IS NULL
+               AND a.start_time > sqlc.arg(now)
+               AND a.start_time - make_interval(mins => a.reminder_time) <= sqlc.arg(now)
+               AND (a.reminder_claimed_until IS NULL OR a.reminder_claimed_until <= sqlc.arg(now))
+             ORDER BY a.start_time
+             LIMIT sqlc.arg(batch_size) FOR UPDATE SKIP LOCKED)
+RETURNING id;
+
+-- name: MarkReminderSent :exec
+UPDATE appointments
+SET reminder_sent_at       = $2,
+    reminder_claimed_until = NULL
+WHERE id = $1
+  AND reminder_sent_at IS NULL;
+
+-- name: ReleaseReminderClaim :exec
+UPDATE appointments
+SET reminder_claimed_until = NULL
+WHERE id = $1
+  AND reminder_sent_at IS NULL;
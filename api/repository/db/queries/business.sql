@@ -1,10 +1,12 @@
 -- name: CreateBusiness :one
 INSERT INTO businesses (name,
                         logo_url,
-                        color_scheme)
+                        color_scheme,
+                        timezone)
 VALUES ($1,
         $2,
-        $3)
+        $3,
+        $4)
 RETURNING *;
 
 -- name: GetBusiness :one
@@ -14,7 +16,8 @@ WHERE id = $1;
 
 -- name: UpdateBusiness :one
 UPDATE businesses
-SET name = $2
+SET name     = $2,
+    timezone = $3
 WHERE id = $1
 RETURNING *;
 
@@ -27,12 +30,31 @@ RETURNING *;
 
 
 -- name: ListBySearch :many
-SELECT *
-FROM businesses
-WHERE name ILIKE $1;
+-- An empty location matches every business, otherwise one of its active branches must match
+SELECT b.*
+FROM businesses b
+WHERE b.name ILIKE sqlc.arg(name)
+  AND (sqlc.arg(location)::text = ''
+    OR EXISTS (SELECT 1
+               FROM locations l
+               WHERE l.business_id = b.id
+                 AND l.is_active = true
+                 AND (l.name ILIKE '%' || sqlc.arg(location)::text || '%'
+                   OR l.address ILIKE '%' || sqlc.arg(location)::text || '%')));
 
 
 -- name: ListServicesBySearch :many
-SELECT *
-FROM services
-WHERE name ILIKE $1;
\ No newline at end of file
+-- An empty location matches every service, otherwise an active employee of a matching branch must provide it
+SELECT s.*
+FROM services s
+WHERE s.name ILIKE sqlc.arg(name)
+  AND (sqlc.arg(location)::text = ''
+    OR EXISTS (SELECT 1
+               FROM employee_services es
+                        JOIN employees e ON e.id = es.employee_id
+                        JOIN locations l ON l.id = e.location_id
+               WHERE es.service_id = s.id
+                 AND e.is_active = true
+                 AND l.is_active = true
+                 AND (l.name ILIKE '%' || sqlc.arg(location)::text || '%'
+                   OR l.address ILIKE '%' || sqlc.arg(location)::text || '%')));
\ No newline at end of file
@@ -0,0 +1,54 @@
+-- name: ListBusinessHours :many
+SELECT *
+FROM business_hours
+WHERE business_id = $1
+ORDER BY day_of_week, open_time;
+
+-- name: DeleteBusinessHours :exec
+DELETE
+FROM business_hours
+WHERE business_id = $1;
+
+-- name: CreateBusinessHours :one
+INSERT INTO business_hours (business_id,
+                            day_of_week,
+                            open_time,
+                            close_time)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: CreateClosure :one
+INSERT INTO business_closures (business_id,
+                               name,
+                               start_date,
+                               end_date,
+                               is_yearly)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ImportClosure :execrows
+INSERT INTO business_closures (business_id,
+                               name,
+                               start_date,
+                               end_date,
+                               is_yearly)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (business_id, start_date, name) DO NOTHING;
+
+-- name: GetClosure :one
+SELECT *
+FROM business_closures
+WHERE id = $1;
+
+-- name: ListClosures :many
+SELECT *
+FROM business_closures
+WHERE business_id = sqlc.arg(business_id)
+  AND start_date <= sqlc.arg(range_end)
+  AND (is_yearly OR end_date >= sqlc.arg(range_start))
+ORDER BY start_date, id;
+
+-- name: DeleteClosure :execrows
+DELETE
+FROM business_closures
+WHERE id = $1;
@@ -4,8 +4,14 @@ INSERT INTO services (business_id,
                       description,
                       duration,
                       price,
-                      is_active)
-VALUES ($1, $2, $3, $4, $5, $6)
+                      is_active,
+                      slot_interval,
+                      buffer_before,
+                      buffer_after,
+                      min_notice,
+                      max_advance_days,
+                      capacity)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING *;
 
 -- name: GetService :one
@@ -15,18 +21,25 @@ WHERE id = $1;
 
 -- name: UpdateService :one
 UPDATE services
-SET name        = $2,
-    description = $3,
-    duration    = $4,
-    price       = $5,
-    is_active   = $6
+SET name             = $2,
+    description      = $3,
+    duration         = $4,
+    price            = $5,
+    is_active        = $6,
+    slot_interval    = $7,
+    buffer_before    = $8,
+    buffer_after     = $9,
+    min_notice       = $10,
+    max_advance_days = $11,
+    capacity         = $12
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteService :exec
+-- name: DeleteService :one
 UPDATE services
 SET is_active = false
-WHERE id = $1;
+WHERE id = $1
+RETURNING *;
 
 -- name: ListServices :many
 SELECT *
@@ -40,4 +53,17 @@ SELECT *
 FROM services
 WHERE business_id = $1
   AND is_active = true
-ORDER BY name;
\ No newline at end of file
+ORDER BY name;
+-- name: ListLocationServices :many
+-- A branch offers the services provided by its active employees
+SELECT s.*
+FROM services s
+WHERE s.business_id = sqlc.arg(business_id)
+  AND s.is_active = true
+  AND EXISTS (SELECT 1
+              FROM employee_services es
+                       JOIN employees e ON e.id = es.employee_id
+              WHERE es.service_id = s.id
+                AND e.is_active = true
+                AND e.location_id = sqlc.arg(location_id))
+ORDER BY s.name;
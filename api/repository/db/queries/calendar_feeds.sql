@@ -0,0 +1,21 @@
+-- name: CreateCalendarFeed :one
+INSERT INTO calendar_feeds (token,
+                            employee_id,
+                            client_id)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetCalendarFeedByToken :one
+SELECT *
+FROM calendar_feeds
+WHERE token = $1;
+
+-- name: DeleteEmployeeCalendarFeed :execrows
+DELETE
+FROM calendar_feeds
+WHERE employee_id = $1;
+
+-- name: DeleteClientCalendarFeed :execrows
+DELETE
+FROM calendar_feeds
+WHERE client_id = $1;
@@ -0,0 +1,56 @@
+-- name: UpsertCalendarImport :one
+INSERT INTO calendar_imports (employee_id,
+                              name,
+                              source_url)
+VALUES ($1, $2, $3)
+ON CONFLICT (employee_id, name) DO UPDATE
+    SET source_url = EXCLUDED.source_url
+RETURNING *;
+
+-- name: GetCalendarImport :one
+SELECT *
+FROM calendar_imports
+WHERE id = $1;
+
+-- name: ListCalendarImports :many
+SELECT *
+FROM calendar_imports
+WHERE employee_id = $1
+ORDER BY name;
+
+-- name: ListSubscribedCalendarImports :many
+SELECT *
+FROM calendar_imports
+WHERE source_url IS NOT NULL
+ORDER BY id;
+
+-- name: UpdateCalendarImportSync :one
+UPDATE calendar_imports
+SET busy_count     = $2,
+    last_synced_at = $3,
+    last_error     = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateCalendarImportError :one
+UPDATE calendar_imports
+SET last_error = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteCalendarImport :execrows
+DELETE
+FROM calendar_imports
+WHERE id = $1;
+
+-- name: CreateBusyTime :exec
+INSERT INTO busy_times (import_id,
+                        employee_id,
+                        start_time,
+                        end_time)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteImportBusyTimes :exec
+DELETE
+FROM busy_times
+WHERE import_id = $1;
@@ -0,0 +1,46 @@
+-- name: CreateDomainEvent :exec
+INSERT INTO domain_events (business_id,
+                           aggregate_type,
+                           aggregate_id,
+                           event_type,
+                           payload)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ClaimDomainEvents :many
+-- Claimed events are skipped by other relays until the claim expires, which also retries events of a stopped relay
+UPDATE domain_events
+SET attempts        = attempts + 1,
+    next_attempt_at = sqlc.arg(claimed_until)
+WHERE id IN (SELECT e.id
+             FROM domain_events e
+             WHERE e.status = 'pending'
+               AND e.next_attempt_at <= sqlc.arg(now)
+             ORDER BY e.id
+             LIMIT sqlc.arg(batch_size) FOR UPDATE SKIP LOCKED)
+RETURNING *;
+
+-- name: MarkDomainEventDelivered :exec
+UPDATE domain_events
+SET delivered_to = array_append(delivered_to, sqlc.arg(consumer)::text)
+WHERE id = sqlc.arg(id)
+  AND NOT (sqlc.arg(consumer)::text = ANY (delivered_to));
+
+-- name: MarkDomainEventPublished :exec
+UPDATE domain_events
+SET status       = 'published',
+    published_at = $2,
+    last_error   = NULL
+WHERE id = $1;
+
+-- name: RetryDomainEvent :exec
+UPDATE domain_events
+SET next_attempt_at = $2,
+    last_error      = $3
+WHERE id = $1
+  AND status = 'pending';
+
+-- name: MarkDomainEventDead :exec
+UPDATE domain_events
+SET status     = 'dead',
+    last_error = $2
+WHERE id = $1;
@@ -2,8 +2,9 @@
 INSERT INTO employees (business_id,
                        user_id,
                        specialization,
-                       is_active)
-VALUES ($1, $2, $3, $4)
+                       is_active,
+                       location_id)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetEmployee :one
@@ -13,6 +14,7 @@ SELECT e.id,
        e.specialization,
        e.is_active,
        e.created_at,
+       e.location_id,
        u.email      as user_email,
        u.phone      as user_phone,
        u.full_name  as user_full_name,
@@ -30,7 +32,8 @@ WHERE e.user_id = $1;
 -- name: UpdateEmployee :one
 UPDATE employees
 SET specialization = COALESCE($2, specialization),
-    is_active      = $3
+    is_active      = $3,
+    location_id    = $4
 WHERE id = $1
 RETURNING *;
 
@@ -41,6 +44,7 @@ SELECT e.id,
        e.specialization,
        e.is_active,
        e.created_at,
+       e.location_id,
        u.email      as user_email,
        u.phone      as user_phone,
        u.full_name  as user_full_name,
@@ -75,6 +79,7 @@ SELECT e.id,
        e.specialization,
        e.is_active,
        e.created_at,
+       e.location_id,
        u.email      as user_email,
        u.phone      as user_phone,
        u.full_name  as user_full_name,
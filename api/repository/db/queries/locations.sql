@@ -0,0 +1,39 @@
+-- name: CreateLocation :one
+INSERT INTO locations (business_id,
+                       name,
+                       address,
+                       timezone,
+                       latitude,
+                       longitude,
+                       is_active)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetLocation :one
+SELECT *
+FROM locations
+WHERE id = $1;
+
+-- name: UpdateLocation :one
+UPDATE locations
+SET name      = $2,
+    address   = $3,
+    timezone  = $4,
+    latitude  = $5,
+    longitude = $6,
+    is_active = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteLocation :one
+-- Locations are kept for the appointments that took place there
+UPDATE locations
+SET is_active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: ListLocations :many
+SELECT *
+FROM locations
+WHERE business_id = $1
+ORDER BY name, id;
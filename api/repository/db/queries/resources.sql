@@ -0,0 +1,67 @@
+-- name: CreateResource :one
+INSERT INTO resources (business_id,
+                       name,
+                       resource_type,
+                       is_active,
+                       location_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetResource :one
+SELECT *
+FROM resources
+WHERE id = $1;
+
+-- name: UpdateResource :one
+UPDATE resources
+SET name          = $2,
+    resource_type = $3,
+    is_active     = $4,
+    location_id   = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteResource :one
+-- Resources are kept for the appointments they were assigned to
+UPDATE resources
+SET is_active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: ListResources :many
+SELECT *
+FROM resources
+WHERE business_id = $1
+ORDER BY resource_type, name, id;
+
+-- name: ListActiveResourcesByTypes :many
+SELECT *
+FROM resources
+WHERE business_id = sqlc.arg(business_id)
+  AND is_active = true
+  AND resource_type = ANY (sqlc.arg(resource_types)::text[])
+ORDER BY id;
+
+-- name: ListServiceResourceTypes :many
+SELECT resource_type
+FROM service_resource_types
+WHERE service_id = $1
+ORDER BY resource_type;
+
+-- name: ListBusinessServiceResourceTypes :many
+SELECT t.service_id, t.resource_type
+FROM service_resource_types t
+         JOIN services s ON s.id = t.service_id
+WHERE s.business_id = $1
+ORDER BY t.service_id, t.resource_type;
+
+-- name: DeleteServiceResourceTypes :exec
+DELETE
+FROM service_resource_types
+WHERE service_id = $1;
+
+-- name: CreateServiceResourceType :exec
+INSERT INTO service_resource_types (service_id,
+                                    resource_type)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
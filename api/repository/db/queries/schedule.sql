@@ -4,28 +4,39 @@ INSERT INTO schedule_templates (employee_id,
                                 day_of_week,
                                 start_time,
                                 end_time,
-                                is_break)
-VALUES ($1, $2, $3, $4, $5)
+                                is_break,
+                                valid_from,
+                                valid_until,
+                                rotation_weeks,
+                                rotation_week,
+                                rotation_anchor)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
 -- name: UpdateTemplate :one
 UPDATE schedule_templates
-SET start_time = $2,
-    end_time   = $3,
-    is_break   = $4
+SET start_time      = $2,
+    end_time        = $3,
+    is_break        = $4,
+    valid_from      = $5,
+    valid_until     = $6,
+    rotation_weeks  = $7,
+    rotation_week   = $8,
+    rotation_anchor = $9
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteTemplate :exec
+-- name: DeleteTemplate :one
 DELETE
 FROM schedule_templates
-WHERE id = $1;
+WHERE id = $1
+RETURNING *;
 
 -- name: ListTemplates :many
 SELECT *
 FROM schedule_templates
 WHERE employee_id = $1
-ORDER BY day_of_week, start_time;
+ORDER BY day_of_week, start_time, valid_from NULLS FIRST;
 
 -- Schedule Overrides
 -- name: CreateOverride :one
@@ -47,10 +58,11 @@ SET start_time     = $2,
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteOverride :exec
+-- name: DeleteOverride :one
 DELETE
 FROM schedule_overrides
-WHERE id = $1;
+WHERE id = $1
+RETURNING *;
 
 -- name: ListOverrides :many
 SELECT *
@@ -59,7 +71,16 @@ WHERE employee_id = $1
   AND override_date BETWEEN $2 AND $3
 ORDER BY override_date, start_time;
 
--- name: GetEmployeeSchedule :one
+-- name: GetEmployeeSchedule :many
 SELECT *
 FROM schedule_templates
-WHERE employee_id = $1 AND day_of_week = $2;
\ No newline at end of file
+WHERE employee_id = $1 AND day_of_week = $2
+ORDER BY start_time;
+
+-- name: ListBusyTimes :many
+SELECT *
+FROM busy_times
+WHERE employee_id = sqlc.arg(employee_id)
+  AND start_time < sqlc.arg(range_end)
+  AND end_time > sqlc.arg(range_start)
+ORDER BY start_time;
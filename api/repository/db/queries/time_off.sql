@@ -0,0 +1,55 @@
+-- name: CreateTimeOffRequest :one
+INSERT INTO time_off_requests (business_id,
+                               employee_id,
+                               start_date,
+                               end_date,
+                               start_time,
+                               end_time,
+                               reason)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: LockEmployeeTimeOff :exec
+-- Serializes the time off requests of an employee until the end of the transaction
+SELECT pg_advisory_xact_lock(hashtext('time_off'), sqlc.arg(employee_id)::int);
+
+-- name: HasTimeOffOverlap :one
+-- Pending and approved requests of the employee sharing a date, or sharing times when both are parts of the day
+SELECT EXISTS (SELECT 1
+               FROM time_off_requests
+               WHERE employee_id = sqlc.arg(employee_id)
+                 AND status <> 'rejected'
+                 AND start_date <= sqlc.arg(end_date)
+                 AND end_date >= sqlc.arg(start_date)
+                 AND (start_time IS NULL
+                   OR sqlc.narg(start_time)::TIME IS NULL
+                   OR (start_time < sqlc.narg(end_time)::TIME AND end_time > sqlc.narg(start_time)::TIME)));
+
+-- name: GetTimeOffRequest :one
+SELECT *
+FROM time_off_requests
+WHERE id = $1;
+
+-- name: ListEmployeeTimeOffRequests :many
+SELECT *
+FROM time_off_requests
+WHERE employee_id = $1
+ORDER BY start_date DESC, id DESC;
+
+-- name: ListBusinessTimeOffRequests :many
+-- All requests of the business when status is NULL
+SELECT *
+FROM time_off_requests
+WHERE business_id = sqlc.arg(business_id)
+  AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status))
+ORDER BY start_date, id;
+
+-- name: DecideTimeOffRequest :one
+UPDATE time_off_requests
+SET status        = sqlc.arg(status),
+    decided_by    = sqlc.arg(decided_by),
+    decided_at    = sqlc.arg(decided_at),
+    decision_note = sqlc.arg(decision_note)
+WHERE id = sqlc.arg(id)
+  AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,55 @@
+-- name: CreateWaitlistEntry :one
+INSERT INTO waitlist_entries (business_id,
+                              client_id,
+                              service_id,
+                              employee_id,
+                              window_start,
+                              window_end,
+                              auto_book)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetWaitlistEntry :one
+SELECT *
+FROM waitlist_entries
+WHERE id = $1;
+
+-- name: ListClientWaitlistEntries :many
+SELECT *
+FROM waitlist_entries
+WHERE client_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListMatchingWaitlistEntries :many
+-- Waiting entries whose window contains the interval, first come first served
+SELECT *
+FROM waitlist_entries
+WHERE service_id = sqlc.arg(service_id)
+  AND (employee_id IS NULL OR employee_id = sqlc.arg(employee_id))
+  AND status = 'waiting'
+  AND window_start <= sqlc.arg(start_time)
+  AND window_end >= sqlc.arg(end_time)
+ORDER BY created_at, id;
+
+-- name: OfferWaitlistEntry :one
+UPDATE waitlist_entries
+SET status           = sqlc.arg(status),
+    appointment_id   = sqlc.arg(appointment_id),
+    offer_expires_at = sqlc.narg(offer_expires_at)
+WHERE id = sqlc.arg(id)
+  AND status = 'waiting'
+RETURNING *;
+
+-- name: UpdateWaitlistEntryStatus :one
+UPDATE waitlist_entries
+SET status = sqlc.arg(new_status)
+WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(current_status)
+RETURNING *;
+
+-- name: ListExpiredWaitlistOffers :many
+SELECT *
+FROM waitlist_entries
+WHERE status = 'offered'
+  AND offer_expires_at <= $1
+ORDER BY offer_expires_at;
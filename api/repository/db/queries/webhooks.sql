@@ -0,0 +1,84 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (business_id,
+                      url,
+                      secret,
+                      event_types,
+                      is_active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT *
+FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT *
+FROM webhooks
+WHERE business_id = $1
+ORDER BY id;
+
+-- name: ListSubscribedWebhooks :many
+SELECT *
+FROM webhooks
+WHERE business_id = sqlc.arg(business_id)
+  AND is_active = true
+  AND sqlc.arg(event_type)::text = ANY (event_types)
+ORDER BY id;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url         = $2,
+    event_types = $3,
+    is_active   = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE
+FROM webhooks
+WHERE id = $1;
+
+-- name: CreateWebhookDelivery :one
+-- An event is delivered to a webhook once, relaying the event again keeps the existing delivery
+INSERT INTO webhook_deliveries (webhook_id,
+                                event_id,
+                                event_type,
+                                payload)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (webhook_id, event_id) DO NOTHING
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT *
+FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT *
+FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY id DESC
+LIMIT $2;
+
+-- name: ClaimWebhookDeliveries :many
+-- Claimed deliveries are skipped by other dispatchers until the claim expires
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg(claimed_until)
+WHERE id IN (SELECT d.id
+             FROM webhook_deliveries d
+             WHERE d.status = 'pending'
+               AND d.next_attempt_at <= sqlc.arg(now)
+             ORDER BY d.next_attempt_at
+             LIMIT sqlc.arg(batch_size) FOR UPDATE SKIP LOCKED)
+RETURNING *;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status          = $2,
+    attempts        = $3,
+    next_attempt_at = $4,
+    response_status = $5,
+    last_error      = $6,
+    delivered_at    = $7
+WHERE id = $1;
@@ -37,19 +37,22 @@ func (r *employeeRepository) Create(ctx context.Context, employee *entity.Employ
 		specialization = r.db.ValidText(*employee.Specialization)
 	}
 
-	dbEmployee, err := r.db.SQLC.CreateEmployee(ctx, sqlc.CreateEmployeeParams{
-		BusinessID:     pgtype.Int4{Int32: int32(employee.BusinessID), Valid: true},
-		UserID:         pgtype.Int4{Int32: int32(employee.UserID), Valid: true},
-		Specialization: specialization,
-		IsActive:       pgtype.Bool{Bool: employee.IsActive, Valid: true},
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbEmployee, err := q.CreateEmployee(ctx, sqlc.CreateEmployeeParams{
+			BusinessID:     pgtype.Int4{Int32: int32(employee.BusinessID), Valid: true},
+			UserID:         pgtype.Int4{Int32: int32(employee.UserID), Valid: true},
+			Specialization: specialization,
+			IsActive:       pgtype.Bool{Bool: employee.IsActive, Valid: true},
+			LocationID:     optionalInt4(employee.LocationID),
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		employee.ID = int(dbEmployee.ID)
+		employee.CreatedAt = dbEmployee.CreatedAt.Time
+		return recordEmployeeEvent(ctx, q, entity.EventEmployeeCreated, dbEmployee.ID)
 	})
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
-
-	employee.ID = int(dbEmployee.ID)
-	employee.CreatedAt = dbEmployee.CreatedAt.Time
-	return nil
 }
 
 func (r *employeeRepository) Get(ctx context.Context, id int) (*entity.Employee, error) {
@@ -76,17 +79,20 @@ func (r *employeeRepository) Update(ctx context.Context, employee *entity.Employ
 		specialization = r.db.ValidText(*employee.Specialization)
 	}
 
-	dbEmployee, err := r.db.SQLC.UpdateEmployee(ctx, sqlc.UpdateEmployeeParams{
-		ID:             int32(employee.ID),
-		Specialization: specialization,
-		IsActive:       pgtype.Bool{Bool: employee.IsActive, Valid: true},
-	})
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbEmployee, err := q.UpdateEmployee(ctx, sqlc.UpdateEmployeeParams{
+			ID:             int32(employee.ID),
+			Specialization: specialization,
+			IsActive:       pgtype.Bool{Bool: employee.IsActive, Valid: true},
+			LocationID:     optionalInt4(employee.LocationID),
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
 
-	employee.CreatedAt = dbEmployee.CreatedAt.Time
-	return nil
+		employee.CreatedAt = dbEmployee.CreatedAt.Time
+		return recordEmployeeEvent(ctx, q, entity.EventEmployeeUpdated, dbEmployee.ID)
+	})
 }
 
 func (r *employeeRepository) List(ctx context.Context, businessID int) ([]entity.Employee, error) {
@@ -124,15 +130,17 @@ func (r *employeeRepository) AssignServices(ctx context.Context, employeeID int,
 		serviceIDsInt32[i] = int32(id)
 	}
 
-	err := r.db.SQLC.AssignServices(ctx, sqlc.AssignServicesParams{
-		EmployeeID: int32(employeeID),
-		Column2:    serviceIDsInt32,
-	})
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		err := q.AssignServices(ctx, sqlc.AssignServicesParams{
+			EmployeeID: int32(employeeID),
+			Column2:    serviceIDsInt32,
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
 
-	return nil
+		return recordEmployeeEvent(ctx, q, entity.EventEmployeeUpdated, int32(employeeID))
+	})
 }
 
 func (r *employeeRepository) RemoveServices(ctx context.Context, employeeID int, serviceIDs []int) error {
@@ -142,15 +150,17 @@ func (r *employeeRepository) RemoveServices(ctx context.Context, employeeID int,
 		serviceIDsInt32[i] = int32(id)
 	}
 
-	err := r.db.SQLC.RemoveServices(ctx, sqlc.RemoveServicesParams{
-		EmployeeID: int32(employeeID),
-		Column2:    serviceIDsInt32,
-	})
-	if err != nil {
-		return r.db.HandleBasicErrors(err)
-	}
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		err := q.RemoveServices(ctx, sqlc.RemoveServicesParams{
+			EmployeeID: int32(employeeID),
+			Column2:    serviceIDsInt32,
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
 
-	return nil
+		return recordEmployeeEvent(ctx, q, entity.EventEmployeeUpdated, int32(employeeID))
+	})
 }
 
 func (r *employeeRepository) GetServices(ctx context.Context, employeeID int) ([]entity.BusinessService, error) {
@@ -188,6 +198,11 @@ func convertDBEmployeeToEntity(row sqlc.GetEmployeeRow) *entity.Employee {
 		employee.Specialization = &spec
 	}
 
+	if row.LocationID.Valid {
+		locationID := int(row.LocationID.Int32)
+		employee.LocationID = &locationID
+	}
+
 	if row.UserEmail.Valid {
 		email := row.UserEmail.String
 		employee.User.Email = &email
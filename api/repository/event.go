@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name EventRepository --output ./mocks
+type EventRepository interface {
+	// ClaimPending claims up to limit pending events due at now, oldest first, and counts the attempt.
+	// Claimed events are not returned again until the lease passes.
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.DomainEvent, error)
+	// MarkDelivered records that the consumer handled the event, so retries skip it
+	MarkDelivered(ctx context.Context, id int, consumer string) error
+	// MarkPublished records that every consumer handled the event
+	MarkPublished(ctx context.Context, id int, publishedAt time.Time) error
+	// Retry schedules another attempt of a pending event
+	Retry(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error
+	// MarkDead stops retrying the event
+	MarkDead(ctx context.Context, id int, lastError string) error
+}
+
+type eventRepository struct {
+	db *DB
+}
+
+func NewEventRepository(db *DB) EventRepository {
+	return &eventRepository{
+		db: db,
+	}
+}
+
+func (r *eventRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.DomainEvent, error) {
+	dbEvents, err := r.db.SQLC.ClaimDomainEvents(ctx, sqlc.ClaimDomainEventsParams{
+		ClaimedUntil: pgtype.Timestamptz{Time: now.Add(lease), Valid: true},
+		Now:          pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim domain events: %w", err)
+	}
+
+	events := make([]entity.DomainEvent, len(dbEvents))
+	for i, e := range dbEvents {
+		events[i] = *convertDBDomainEventToEntity(e)
+	}
+	// UPDATE ... RETURNING does not keep the order of the claim
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events, nil
+}
+
+func (r *eventRepository) MarkDelivered(ctx context.Context, id int, consumer string) error {
+	err := r.db.SQLC.MarkDomainEventDelivered(ctx, sqlc.MarkDomainEventDeliveredParams{
+		ID:       int64(id),
+		Consumer: consumer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark domain event delivered: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) MarkPublished(ctx context.Context, id int, publishedAt time.Time) error {
+	err := r.db.SQLC.MarkDomainEventPublished(ctx, sqlc.MarkDomainEventPublishedParams{
+		ID:          int64(id),
+		PublishedAt: pgtype.Timestamptz{Time: publishedAt, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark domain event published: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) Retry(ctx context.Context, id int, nextAttemptAt time.Time, lastError string) error {
+	err := r.db.SQLC.RetryDomainEvent(ctx, sqlc.RetryDomainEventParams{
+		ID:            int64(id),
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+		LastError:     r.db.ValidText(lastError),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retry domain event: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) MarkDead(ctx context.Context, id int, lastError string) error {
+	err := r.db.SQLC.MarkDomainEventDead(ctx, sqlc.MarkDomainEventDeadParams{
+		ID:        int64(id),
+		LastError: r.db.ValidText(lastError),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark domain event dead: %w", err)
+	}
+	return nil
+}
+
+// recordEvent stores a domain event in the outbox. It must be called with the queries of the
+// transaction making the change, so the event is stored if and only if the change is.
+func recordEvent(ctx context.Context, q *sqlc.Queries, eventType, aggregateType string, aggregateID, businessID int, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	err = q.CreateDomainEvent(ctx, sqlc.CreateDomainEventParams{
+		BusinessID:    pgtype.Int4{Int32: int32(businessID), Valid: true},
+		AggregateType: aggregateType,
+		AggregateID:   int32(aggregateID),
+		EventType:     eventType,
+		Payload:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// recordAppointmentEvent stores an event with the appointment as it is within the transaction
+func recordAppointmentEvent(ctx context.Context, q *sqlc.Queries, eventType string, id int32) error {
+	dbAppointment, err := q.GetAppointment(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get appointment for %s event: %w", eventType, err)
+	}
+
+	appointment := convertDBAppointmentToEntity(dbAppointment)
+	return recordEvent(ctx, q, eventType, entity.AggregateAppointment, appointment.ID, appointment.BusinessID, appointment)
+}
+
+// recordEmployeeEvent stores an event with the employee as it is within the transaction
+func recordEmployeeEvent(ctx context.Context, q *sqlc.Queries, eventType string, id int32) error {
+	dbEmployee, err := q.GetEmployee(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get employee for %s event: %w", eventType, err)
+	}
+
+	employee := convertDBEmployeeToEntity(dbEmployee)
+	return recordEvent(ctx, q, eventType, entity.AggregateEmployee, employee.ID, employee.BusinessID, employee)
+}
+
+// recordServiceEvent stores an event with the service as it is after the change
+func recordServiceEvent(ctx context.Context, q *sqlc.Queries, eventType string, dbService sqlc.Service) error {
+	service := convertDBServiceToEntity(dbService)
+	return recordEvent(ctx, q, eventType, entity.AggregateService, service.ID, service.BusinessID, service)
+}
+
+// recordScheduleEvent stores a schedule.updated event of the employee's schedule
+func recordScheduleEvent(ctx context.Context, q *sqlc.Queries, change entity.ScheduleChange) error {
+	dbEmployee, err := q.GetEmployee(ctx, int32(change.EmployeeID))
+	if err != nil {
+		return fmt.Errorf("failed to get employee for schedule event: %w", err)
+	}
+
+	return recordEvent(ctx, q, entity.EventScheduleUpdated, entity.AggregateSchedule, change.EmployeeID, int(dbEmployee.BusinessID.Int32), change)
+}
+
+func convertDBDomainEventToEntity(e sqlc.DomainEvent) *entity.DomainEvent {
+	event := &entity.DomainEvent{
+		ID:            int(e.ID),
+		BusinessID:    int(e.BusinessID.Int32),
+		AggregateType: e.AggregateType,
+		AggregateID:   int(e.AggregateID),
+		EventType:     e.EventType,
+		Payload:       e.Payload,
+		Status:        e.Status,
+		Attempts:      int(e.Attempts),
+		DeliveredTo:   e.DeliveredTo,
+		NextAttemptAt: e.NextAttemptAt.Time,
+		CreatedAt:     e.CreatedAt.Time,
+	}
+	if e.LastError.Valid {
+		event.LastError = &e.LastError.String
+	}
+	if e.PublishedAt.Valid {
+		event.PublishedAt = &e.PublishedAt.Time
+	}
+	return event
+}
@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestEventRepository_Outbox(t *testing.T) {
+	ctx := context.Background()
+	eventRepo := repository.NewEventRepository(db)
+
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM domain_events WHERE aggregate_type = $1", entity.AggregateService)
+		require.NoError(t, err)
+	})
+
+	// A change that fails records no event
+	err := serviceRepo.Create(ctx, &entity.BusinessService{BusinessID: 99999, Name: "Invalid Service", Duration: 30})
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	service := &entity.BusinessService{BusinessID: businessID, Name: "Outbox Haircut", Duration: 30, Price: 1000, IsActive: true}
+	require.NoError(t, serviceRepo.Create(ctx, service))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM services WHERE id = $1", service.ID)
+		require.NoError(t, err)
+	})
+
+	claimServiceEvents := func(now time.Time) []entity.DomainEvent {
+		events, err := eventRepo.ClaimPending(ctx, now, time.Minute, 100)
+		require.NoError(t, err)
+
+		var result []entity.DomainEvent
+		for _, event := range events {
+			if event.AggregateType == entity.AggregateService && event.AggregateID == service.ID {
+				result = append(result, event)
+			}
+		}
+		return result
+	}
+
+	now := time.Now()
+	events := claimServiceEvents(now)
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, entity.EventServiceCreated, event.EventType)
+	assert.Equal(t, service.ID, event.AggregateID)
+	assert.Equal(t, businessID, event.BusinessID)
+	assert.Equal(t, 1, event.Attempts)
+
+	var payload entity.BusinessService
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "Outbox Haircut", payload.Name)
+
+	// Claimed events are not returned again until the lease passes
+	assert.Empty(t, claimServiceEvents(now))
+
+	// Deliveries are recorded once per consumer
+	require.NoError(t, eventRepo.MarkDelivered(ctx, event.ID, "webhooks"))
+	require.NoError(t, eventRepo.MarkDelivered(ctx, event.ID, "webhooks"))
+	require.NoError(t, eventRepo.Retry(ctx, event.ID, now, "analytics: unavailable"))
+
+	events = claimServiceEvents(now.Add(time.Second))
+	require.Len(t, events, 1)
+	assert.Equal(t, []string{"webhooks"}, events[0].DeliveredTo)
+	assert.Equal(t, 2, events[0].Attempts)
+	require.NotNil(t, events[0].LastError)
+	assert.Equal(t, "analytics: unavailable", *events[0].LastError)
+
+	// Published events are never claimed again
+	require.NoError(t, eventRepo.MarkPublished(ctx, event.ID, now))
+	assert.Empty(t, claimServiceEvents(now.Add(time.Hour)))
+}
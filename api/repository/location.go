@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name LocationRepository --output ./mocks
+type LocationRepository interface {
+	Create(ctx context.Context, location *entity.Location) error
+	Get(ctx context.Context, id int) (*entity.Location, error)
+	Update(ctx context.Context, location *entity.Location) error
+	// Delete deactivates the location, its employees and appointments keep it
+	Delete(ctx context.Context, id int) error
+	// List returns all locations of the business, including inactive ones
+	List(ctx context.Context, businessID int) ([]entity.Location, error)
+}
+
+type locationRepository struct {
+	db *DB
+}
+
+func NewLocationRepository(db *DB) LocationRepository {
+	return &locationRepository{
+		db: db,
+	}
+}
+
+func (r *locationRepository) Create(ctx context.Context, location *entity.Location) error {
+	dbLocation, err := r.db.SQLC.CreateLocation(ctx, sqlc.CreateLocationParams{
+		BusinessID: int32(location.BusinessID),
+		Name:       location.Name,
+		Address:    location.Address,
+		Timezone:   location.Timezone,
+		Latitude:   optionalFloat8(location.Latitude),
+		Longitude:  optionalFloat8(location.Longitude),
+		IsActive:   location.IsActive,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create location: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*location = *convertDBLocationToEntity(dbLocation)
+	return nil
+}
+
+func (r *locationRepository) Get(ctx context.Context, id int) (*entity.Location, error) {
+	dbLocation, err := r.db.SQLC.GetLocation(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBLocationToEntity(dbLocation), nil
+}
+
+func (r *locationRepository) Update(ctx context.Context, location *entity.Location) error {
+	dbLocation, err := r.db.SQLC.UpdateLocation(ctx, sqlc.UpdateLocationParams{
+		ID:        int32(location.ID),
+		Name:      location.Name,
+		Address:   location.Address,
+		Timezone:  location.Timezone,
+		Latitude:  optionalFloat8(location.Latitude),
+		Longitude: optionalFloat8(location.Longitude),
+		IsActive:  location.IsActive,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update location: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*location = *convertDBLocationToEntity(dbLocation)
+	return nil
+}
+
+func (r *locationRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.SQLC.DeleteLocation(ctx, int32(id)); err != nil {
+		return r.db.HandleBasicErrors(err)
+	}
+	return nil
+}
+
+func (r *locationRepository) List(ctx context.Context, businessID int) ([]entity.Location, error) {
+	dbLocations, err := r.db.SQLC.ListLocations(ctx, int32(businessID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locations: %w", err)
+	}
+
+	locations := make([]entity.Location, len(dbLocations))
+	for i, l := range dbLocations {
+		locations[i] = *convertDBLocationToEntity(l)
+	}
+	return locations, nil
+}
+
+func optionalFloat8(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
+
+func convertDBLocationToEntity(l sqlc.Location) *entity.Location {
+	location := &entity.Location{
+		ID:         int(l.ID),
+		BusinessID: int(l.BusinessID),
+		Name:       l.Name,
+		Address:    l.Address,
+		Timezone:   l.Timezone,
+		IsActive:   l.IsActive,
+		CreatedAt:  l.CreatedAt.Time,
+	}
+
+	if l.Latitude.Valid && l.Longitude.Valid {
+		latitude, longitude := l.Latitude.Float64, l.Longitude.Float64
+		location.Latitude = &latitude
+		location.Longitude = &longitude
+	}
+
+	return location
+}
@@ -62,6 +62,36 @@ func (_m *BusinessRepository) Get(ctx context.Context, id int) (*entity.Business
 	return r0, r1
 }
 
+// ListBySearch provides a mock function with given fields: ctx, search, location
+func (_m *BusinessRepository) ListBySearch(ctx context.Context, search string, location string) ([]entity.Business, error) {
+	ret := _m.Called(ctx, search, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBySearch")
+	}
+
+	var r0 []entity.Business
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]entity.Business, error)); ok {
+		return rf(ctx, search, location)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []entity.Business); ok {
+		r0 = rf(ctx, search, location)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Business)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, search, location)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, business
 func (_m *BusinessRepository) Update(ctx context.Context, business *entity.Business) error {
 	ret := _m.Called(ctx, business)
@@ -140,6 +140,66 @@ func (_m *BusinessServiceRepository) ListActive(ctx context.Context, businessID
 	return r0, r1
 }
 
+// ListByLocation provides a mock function with given fields: ctx, businessID, locationID
+func (_m *BusinessServiceRepository) ListByLocation(ctx context.Context, businessID int, locationID int) ([]entity.BusinessService, error) {
+	ret := _m.Called(ctx, businessID, locationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByLocation")
+	}
+
+	var r0 []entity.BusinessService
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]entity.BusinessService, error)); ok {
+		return rf(ctx, businessID, locationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []entity.BusinessService); ok {
+		r0 = rf(ctx, businessID, locationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BusinessService)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, businessID, locationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListServicesBySearch provides a mock function with given fields: ctx, search, location
+func (_m *BusinessServiceRepository) ListServicesBySearch(ctx context.Context, search string, location string) ([]entity.BusinessService, error) {
+	ret := _m.Called(ctx, search, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListServicesBySearch")
+	}
+
+	var r0 []entity.BusinessService
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]entity.BusinessService, error)); ok {
+		return rf(ctx, search, location)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []entity.BusinessService); ok {
+		r0 = rf(ctx, search, location)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BusinessService)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, search, location)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, service
 func (_m *BusinessServiceRepository) Update(ctx context.Context, service *entity.BusinessService) error {
 	ret := _m.Called(ctx, service)
@@ -80,6 +80,34 @@ func (_m *EmployeeRepository) Get(ctx context.Context, id int) (*entity.Employee
 	return r0, r1
 }
 
+// GetIDByUserID provides a mock function with given fields: ctx, userID
+func (_m *EmployeeRepository) GetIDByUserID(ctx context.Context, userID int) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIDByUserID")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServices provides a mock function with given fields: ctx, employeeID
 func (_m *EmployeeRepository) GetServices(ctx context.Context, employeeID int) ([]entity.BusinessService, error) {
 	ret := _m.Called(ctx, employeeID)
@@ -140,6 +168,36 @@ func (_m *EmployeeRepository) List(ctx context.Context, businessID int) ([]entit
 	return r0, r1
 }
 
+// ListByServiceID provides a mock function with given fields: ctx, serviceID
+func (_m *EmployeeRepository) ListByServiceID(ctx context.Context, serviceID int) ([]entity.Employee, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByServiceID")
+	}
+
+	var r0 []entity.Employee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.Employee, error)); ok {
+		return rf(ctx, serviceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.Employee); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Employee)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveServices provides a mock function with given fields: ctx, employeeID, serviceIDs
 func (_m *EmployeeRepository) RemoveServices(ctx context.Context, employeeID int, serviceIDs []int) error {
 	ret := _m.Called(ctx, employeeID, serviceIDs)
@@ -1,21 +1,37 @@
 package repository
 
 type Repositories struct {
-	Business    BusinessRepository
-	User        UserRepository
-	Employee    EmployeeRepository
-	Schedule    ScheduleRepository
-	Service     BusinessServiceRepository
-	Appointment AppointmentRepository
+	Business       BusinessRepository
+	User           UserRepository
+	Employee       EmployeeRepository
+	Schedule       ScheduleRepository
+	Service        BusinessServiceRepository
+	Appointment    AppointmentRepository
+	Waitlist       WaitlistRepository
+	Event          EventRepository
+	Webhook        WebhookRepository
+	Calendar       CalendarFeedRepository
+	CalendarImport CalendarImportRepository
+	TimeOff        TimeOffRepository
+	Resource       ResourceRepository
+	Location       LocationRepository
 }
 
 func NewRepositories(db *DB) *Repositories {
 	return &Repositories{
-		Business:    NewBusinessRepository(db),
-		User:        NewUserRepository(db),
-		Employee:    NewEmployeeRepository(db),
-		Schedule:    NewScheduleRepository(db),
-		Service:     NewBusinessServiceRepository(db),
-		Appointment: NewAppointmentRepository(db),
+		Business:       NewBusinessRepository(db),
+		User:           NewUserRepository(db),
+		Employee:       NewEmployeeRepository(db),
+		Schedule:       NewScheduleRepository(db),
+		Service:        NewBusinessServiceRepository(db),
+		Appointment:    NewAppointmentRepository(db),
+		Waitlist:       NewWaitlistRepository(db),
+		Event:          NewEventRepository(db),
+		Webhook:        NewWebhookRepository(db),
+		Calendar:       NewCalendarFeedRepository(db),
+		CalendarImport: NewCalendarImportRepository(db),
+		TimeOff:        NewTimeOffRepository(db),
+		Resource:       NewResourceRepository(db),
+		Location:       NewLocationRepository(db),
 	}
 }
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name ResourceRepository --output ./mocks
+type ResourceRepository interface {
+	Create(ctx context.Context, resource *entity.Resource) error
+	Get(ctx context.Context, id int) (*entity.Resource, error)
+	Update(ctx context.Context, resource *entity.Resource) error
+	// Delete deactivates the resource, appointments keep the resource they were booked with
+	Delete(ctx context.Context, id int) error
+	// List returns all resources of the business, including inactive ones
+	List(ctx context.Context, businessID int) ([]entity.Resource, error)
+	// ListActiveByTypes returns the active resources of the business with any of the types, sorted by ID.
+	// Resources of every branch are returned.
+	ListActiveByTypes(ctx context.Context, businessID int, types []string) ([]entity.Resource, error)
+}
+
+type resourceRepository struct {
+	db *DB
+}
+
+func NewResourceRepository(db *DB) ResourceRepository {
+	return &resourceRepository{
+		db: db,
+	}
+}
+
+func (r *resourceRepository) Create(ctx context.Context, resource *entity.Resource) error {
+	dbResource, err := r.db.SQLC.CreateResource(ctx, sqlc.CreateResourceParams{
+		BusinessID:   int32(resource.BusinessID),
+		Name:         resource.Name,
+		ResourceType: resource.Type,
+		IsActive:     resource.IsActive,
+		LocationID:   optionalInt4(resource.LocationID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create resource: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*resource = *convertDBResourceToEntity(dbResource)
+	return nil
+}
+
+func (r *resourceRepository) Get(ctx context.Context, id int) (*entity.Resource, error) {
+	dbResource, err := r.db.SQLC.GetResource(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBResourceToEntity(dbResource), nil
+}
+
+func (r *resourceRepository) Update(ctx context.Context, resource *entity.Resource) error {
+	dbResource, err := r.db.SQLC.UpdateResource(ctx, sqlc.UpdateResourceParams{
+		ID:           int32(resource.ID),
+		Name:         resource.Name,
+		ResourceType: resource.Type,
+		IsActive:     resource.IsActive,
+		LocationID:   optionalInt4(resource.LocationID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update resource: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*resource = *convertDBResourceToEntity(dbResource)
+	return nil
+}
+
+func (r *resourceRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.SQLC.DeleteResource(ctx, int32(id)); err != nil {
+		return r.db.HandleBasicErrors(err)
+	}
+	return nil
+}
+
+func (r *resourceRepository) List(ctx context.Context, businessID int) ([]entity.Resource, error) {
+	dbResources, err := r.db.SQLC.ListResources(ctx, int32(businessID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	return convertDBResources(dbResources), nil
+}
+
+func (r *resourceRepository) ListActiveByTypes(ctx context.Context, businessID int, types []string) ([]entity.Resource, error) {
+	dbResources, err := r.db.SQLC.ListActiveResourcesByTypes(ctx, sqlc.ListActiveResourcesByTypesParams{
+		BusinessID:    int32(businessID),
+		ResourceTypes: types,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	return convertDBResources(dbResources), nil
+}
+
+func convertDBResources(dbResources []sqlc.Resource) []entity.Resource {
+	resources := make([]entity.Resource, len(dbResources))
+	for i, r := range dbResources {
+		resources[i] = *convertDBResourceToEntity(r)
+	}
+	return resources
+}
+
+func convertDBResourceToEntity(r sqlc.Resource) *entity.Resource {
+	resource := &entity.Resource{
+		ID:         int(r.ID),
+		BusinessID: int(r.BusinessID),
+		Name:       r.Name,
+		Type:       r.ResourceType,
+		IsActive:   r.IsActive,
+		CreatedAt:  r.CreatedAt.Time,
+	}
+
+	if r.LocationID.Valid {
+		locationID := int(r.LocationID.Int32)
+		resource.LocationID = &locationID
+	}
+
+	return resource
+}
@@ -12,16 +12,42 @@ import (
 	"github.com/vadimpk/ppc-project/repository/db/sqlc"
 )
 
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name ScheduleRepository --output ./mocks
 type ScheduleRepository interface {
 	CreateTemplate(ctx context.Context, template *entity.ScheduleTemplate) error
 	UpdateTemplate(ctx context.Context, template *entity.ScheduleTemplate) error
 	DeleteTemplate(ctx context.Context, id int) error
 	ListTemplates(ctx context.Context, employeeID int) ([]entity.ScheduleTemplate, error)
+	// ApplyTemplateBatch deletes, updates and creates the templates of the batch in a single transaction
+	ApplyTemplateBatch(ctx context.Context, batch TemplateBatch) error
 	CreateOverride(ctx context.Context, override *entity.ScheduleOverride) error
+	// CreateOverrides creates all overrides in a single transaction
+	CreateOverrides(ctx context.Context, overrides []*entity.ScheduleOverride) error
 	UpdateOverride(ctx context.Context, override *entity.ScheduleOverride) error
 	DeleteOverride(ctx context.Context, id int) error
 	ListOverrides(ctx context.Context, employeeID int, startDate, endDate time.Time) ([]entity.ScheduleOverride, error)
-	GetEmployeeSchedule(ctx context.Context, employeeID int, date time.Time) (*entity.ScheduleTemplate, error)
+	// GetEmployeeSchedule returns all template blocks, including breaks, for the weekday of the date
+	GetEmployeeSchedule(ctx context.Context, employeeID int, date time.Time) ([]entity.ScheduleTemplate, error)
+	// ListBusyTime returns the imported busy time of the employee that overlaps [start, end)
+	ListBusyTime(ctx context.Context, employeeID int, start, end time.Time) ([]entity.BusyTime, error)
+
+	ListBusinessHours(ctx context.Context, businessID int) ([]entity.BusinessHours, error)
+	// ReplaceBusinessHours replaces all opening hours of the business in a single transaction
+	ReplaceBusinessHours(ctx context.Context, businessID int, hours []entity.BusinessHours) error
+	CreateClosure(ctx context.Context, closure *entity.BusinessClosure) error
+	// ImportClosures creates the closures that do not exist yet, by start date and name, and returns how many were created
+	ImportClosures(ctx context.Context, closures []entity.BusinessClosure) (int, error)
+	GetClosure(ctx context.Context, id int) (*entity.BusinessClosure, error)
+	// ListClosures returns the closures overlapping the dates, including every yearly closure started by endDate
+	ListClosures(ctx context.Context, businessID int, startDate, endDate time.Time) ([]entity.BusinessClosure, error)
+	DeleteClosure(ctx context.Context, id int) error
+}
+
+// TemplateBatch holds template changes of one or more employees that are applied together
+type TemplateBatch struct {
+	Delete []int
+	Update []*entity.ScheduleTemplate
+	Create []*entity.ScheduleTemplate
 }
 
 type scheduleRepository struct {
@@ -45,12 +71,67 @@ func microsecondsToTime(microseconds int64) time.Time {
 }
 
 func (r *scheduleRepository) CreateTemplate(ctx context.Context, template *entity.ScheduleTemplate) error {
-	dbTemplate, err := r.db.SQLC.CreateTemplate(ctx, sqlc.CreateTemplateParams{
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		return createTemplate(ctx, q, template)
+	})
+}
+
+func (r *scheduleRepository) UpdateTemplate(ctx context.Context, template *entity.ScheduleTemplate) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		return updateTemplate(ctx, q, template)
+	})
+}
+
+func (r *scheduleRepository) DeleteTemplate(ctx context.Context, id int) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := deleteTemplate(ctx, q, id); err != nil {
+			if err = r.db.HandleBasicErrors(err); errors.Is(err, ErrNotFound) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to delete template: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *scheduleRepository) ApplyTemplateBatch(ctx context.Context, batch TemplateBatch) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		for _, id := range batch.Delete {
+			if err := deleteTemplate(ctx, q, id); err != nil {
+				if err = r.db.HandleBasicErrors(err); errors.Is(err, ErrNotFound) {
+					return ErrNotFound
+				}
+				return fmt.Errorf("failed to delete template: %w", err)
+			}
+		}
+		for _, template := range batch.Update {
+			if err := updateTemplate(ctx, q, template); err != nil {
+				return err
+			}
+		}
+		for _, template := range batch.Create {
+			if err := createTemplate(ctx, q, template); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createTemplate stores the template and records its schedule change within the transaction of q
+func createTemplate(ctx context.Context, q *sqlc.Queries, template *entity.ScheduleTemplate) error {
+	dbTemplate, err := q.CreateTemplate(ctx, sqlc.CreateTemplateParams{
 		EmployeeID: pgtype.Int4{Int32: int32(template.EmployeeID), Valid: true},
 		DayOfWeek:  pgtype.Int4{Int32: int32(template.DayOfWeek), Valid: true},
 		StartTime:  pgtype.Time{Microseconds: timeToMicroseconds(template.StartTime), Valid: true},
 		EndTime:    pgtype.Time{Microseconds: timeToMicroseconds(template.EndTime), Valid: true},
 		IsBreak:    pgtype.Bool{Bool: template.IsBreak, Valid: true},
+
+		ValidFrom:      optionalDate(template.ValidFrom),
+		ValidUntil:     optionalDate(template.ValidUntil),
+		RotationWeeks:  int32(template.RotationWeeks),
+		RotationWeek:   int32(template.RotationWeek),
+		RotationAnchor: optionalDate(template.RotationAnchor),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create template: %w", err)
@@ -58,15 +139,22 @@ func (r *scheduleRepository) CreateTemplate(ctx context.Context, template *entit
 
 	template.ID = int(dbTemplate.ID)
 	template.CreatedAt = dbTemplate.CreatedAt.Time
-	return nil
+	return recordTemplateEvent(ctx, q, entity.ScheduleActionCreated, dbTemplate)
 }
 
-func (r *scheduleRepository) UpdateTemplate(ctx context.Context, template *entity.ScheduleTemplate) error {
-	dbTemplate, err := r.db.SQLC.UpdateTemplate(ctx, sqlc.UpdateTemplateParams{
+// updateTemplate stores the changes of the template and records its schedule change within the transaction of q
+func updateTemplate(ctx context.Context, q *sqlc.Queries, template *entity.ScheduleTemplate) error {
+	dbTemplate, err := q.UpdateTemplate(ctx, sqlc.UpdateTemplateParams{
 		ID:        int32(template.ID),
 		StartTime: pgtype.Time{Microseconds: timeToMicroseconds(template.StartTime), Valid: true},
 		EndTime:   pgtype.Time{Microseconds: timeToMicroseconds(template.EndTime), Valid: true},
 		IsBreak:   pgtype.Bool{Bool: template.IsBreak, Valid: true},
+
+		ValidFrom:      optionalDate(template.ValidFrom),
+		ValidUntil:     optionalDate(template.ValidUntil),
+		RotationWeeks:  int32(template.RotationWeeks),
+		RotationWeek:   int32(template.RotationWeek),
+		RotationAnchor: optionalDate(template.RotationAnchor),
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -75,19 +163,18 @@ func (r *scheduleRepository) UpdateTemplate(ctx context.Context, template *entit
 		return fmt.Errorf("failed to update template: %w", err)
 	}
 
-	template.CreatedAt = dbTemplate.CreatedAt.Time
-	return nil
+	*template = *convertDBTemplateToEntity(dbTemplate)
+	return recordTemplateEvent(ctx, q, entity.ScheduleActionUpdated, dbTemplate)
 }
 
-func (r *scheduleRepository) DeleteTemplate(ctx context.Context, id int) error {
-	err := r.db.SQLC.DeleteTemplate(ctx, int32(id))
+// deleteTemplate removes the template and records its schedule change within the transaction of q
+func deleteTemplate(ctx context.Context, q *sqlc.Queries, id int) error {
+	dbTemplate, err := q.DeleteTemplate(ctx, int32(id))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return ErrNotFound
-		}
-		return fmt.Errorf("failed to delete template: %w", err)
+		return err
 	}
-	return nil
+
+	return recordTemplateEvent(ctx, q, entity.ScheduleActionDeleted, dbTemplate)
 }
 
 func (r *scheduleRepository) ListTemplates(ctx context.Context, employeeID int) ([]entity.ScheduleTemplate, error) {
@@ -105,6 +192,24 @@ func (r *scheduleRepository) ListTemplates(ctx context.Context, employeeID int)
 }
 
 func (r *scheduleRepository) CreateOverride(ctx context.Context, override *entity.ScheduleOverride) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		return createOverride(ctx, q, override)
+	})
+}
+
+func (r *scheduleRepository) CreateOverrides(ctx context.Context, overrides []*entity.ScheduleOverride) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		for _, override := range overrides {
+			if err := createOverride(ctx, q, override); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createOverride stores the override and records its schedule change within the transaction of q
+func createOverride(ctx context.Context, q *sqlc.Queries, override *entity.ScheduleOverride) error {
 	params := sqlc.CreateOverrideParams{
 		EmployeeID:   pgtype.Int4{Int32: int32(override.EmployeeID), Valid: true},
 		OverrideDate: pgtype.Date{Time: override.OverrideDate, Valid: true},
@@ -125,14 +230,14 @@ func (r *scheduleRepository) CreateOverride(ctx context.Context, override *entit
 		}
 	}
 
-	dbOverride, err := r.db.SQLC.CreateOverride(ctx, params)
+	dbOverride, err := q.CreateOverride(ctx, params)
 	if err != nil {
 		return fmt.Errorf("failed to create override: %w", err)
 	}
 
 	override.ID = int(dbOverride.ID)
 	override.CreatedAt = dbOverride.CreatedAt.Time
-	return nil
+	return recordOverrideEvent(ctx, q, entity.ScheduleActionCreated, dbOverride)
 }
 
 func (r *scheduleRepository) UpdateOverride(ctx context.Context, override *entity.ScheduleOverride) error {
@@ -155,27 +260,32 @@ func (r *scheduleRepository) UpdateOverride(ctx context.Context, override *entit
 		}
 	}
 
-	dbOverride, err := r.db.SQLC.UpdateOverride(ctx, params)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return ErrNotFound
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbOverride, err := q.UpdateOverride(ctx, params)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to update override: %w", err)
 		}
-		return fmt.Errorf("failed to update override: %w", err)
-	}
 
-	override.CreatedAt = dbOverride.CreatedAt.Time
-	return nil
+		override.CreatedAt = dbOverride.CreatedAt.Time
+		return recordOverrideEvent(ctx, q, entity.ScheduleActionUpdated, dbOverride)
+	})
 }
 
 func (r *scheduleRepository) DeleteOverride(ctx context.Context, id int) error {
-	err := r.db.SQLC.DeleteOverride(ctx, int32(id))
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return ErrNotFound
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		dbOverride, err := q.DeleteOverride(ctx, int32(id))
+		if err != nil {
+			if err = r.db.HandleBasicErrors(err); errors.Is(err, ErrNotFound) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("failed to delete override: %w", err)
 		}
-		return fmt.Errorf("failed to delete override: %w", err)
-	}
-	return nil
+
+		return recordOverrideEvent(ctx, q, entity.ScheduleActionDeleted, dbOverride)
+	})
 }
 
 func (r *scheduleRepository) ListOverrides(ctx context.Context, employeeID int, startDate, endDate time.Time) ([]entity.ScheduleOverride, error) {
@@ -198,16 +308,51 @@ func (r *scheduleRepository) ListOverrides(ctx context.Context, employeeID int,
 	return overrides, nil
 }
 
+func recordTemplateEvent(ctx context.Context, q *sqlc.Queries, action string, dbTemplate sqlc.ScheduleTemplate) error {
+	template := convertDBTemplateToEntity(dbTemplate)
+	return recordScheduleEvent(ctx, q, entity.ScheduleChange{
+		EmployeeID: template.EmployeeID,
+		Action:     action,
+		Template:   template,
+	})
+}
+
+func recordOverrideEvent(ctx context.Context, q *sqlc.Queries, action string, dbOverride sqlc.ScheduleOverride) error {
+	override := convertDBOverrideToEntity(dbOverride)
+	return recordScheduleEvent(ctx, q, entity.ScheduleChange{
+		EmployeeID: override.EmployeeID,
+		Action:     action,
+		Override:   override,
+	})
+}
+
 func convertDBTemplateToEntity(t sqlc.ScheduleTemplate) *entity.ScheduleTemplate {
-	return &entity.ScheduleTemplate{
-		ID:         int(t.ID),
-		EmployeeID: int(t.EmployeeID.Int32),
-		DayOfWeek:  int(t.DayOfWeek.Int32),
-		StartTime:  microsecondsToTime(t.StartTime.Microseconds),
-		EndTime:    microsecondsToTime(t.EndTime.Microseconds),
-		IsBreak:    t.IsBreak.Bool,
-		CreatedAt:  t.CreatedAt.Time,
+	template := &entity.ScheduleTemplate{
+		ID:            int(t.ID),
+		EmployeeID:    int(t.EmployeeID.Int32),
+		DayOfWeek:     int(t.DayOfWeek.Int32),
+		StartTime:     microsecondsToTime(t.StartTime.Microseconds),
+		EndTime:       microsecondsToTime(t.EndTime.Microseconds),
+		IsBreak:       t.IsBreak.Bool,
+		RotationWeeks: int(t.RotationWeeks),
+		RotationWeek:  int(t.RotationWeek),
+		CreatedAt:     t.CreatedAt.Time,
+	}
+
+	if t.ValidFrom.Valid {
+		validFrom := t.ValidFrom.Time
+		template.ValidFrom = &validFrom
+	}
+	if t.ValidUntil.Valid {
+		validUntil := t.ValidUntil.Time
+		template.ValidUntil = &validUntil
 	}
+	if t.RotationAnchor.Valid {
+		anchor := t.RotationAnchor.Time
+		template.RotationAnchor = &anchor
+	}
+
+	return template
 }
 
 func convertDBOverrideToEntity(o sqlc.ScheduleOverride) *entity.ScheduleOverride {
@@ -232,18 +377,43 @@ func convertDBOverrideToEntity(o sqlc.ScheduleOverride) *entity.ScheduleOverride
 	return override
 }
 
-func (r *scheduleRepository) GetEmployeeSchedule(ctx context.Context, employeeID int, date time.Time) (*entity.ScheduleTemplate, error) {
-	dbTemplate, err := r.db.SQLC.GetEmployeeSchedule(ctx, sqlc.GetEmployeeScheduleParams{
+func (r *scheduleRepository) GetEmployeeSchedule(ctx context.Context, employeeID int, date time.Time) ([]entity.ScheduleTemplate, error) {
+	dbTemplates, err := r.db.SQLC.GetEmployeeSchedule(ctx, sqlc.GetEmployeeScheduleParams{
 		EmployeeID: pgtype.Int4{Int32: int32(employeeID), Valid: true},
 		DayOfWeek:  pgtype.Int4{Int32: int32(date.Weekday()), Valid: true},
 	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNotFound
-		}
 		return nil, fmt.Errorf("failed to get employee schedule: %w", err)
 	}
 
-	template := convertDBTemplateToEntity(dbTemplate)
-	return template, nil
+	templates := make([]entity.ScheduleTemplate, len(dbTemplates))
+	for i, t := range dbTemplates {
+		templates[i] = *convertDBTemplateToEntity(t)
+	}
+
+	return templates, nil
+}
+
+func (r *scheduleRepository) ListBusyTime(ctx context.Context, employeeID int, start, end time.Time) ([]entity.BusyTime, error) {
+	dbBusyTimes, err := r.db.SQLC.ListBusyTimes(ctx, sqlc.ListBusyTimesParams{
+		EmployeeID: int32(employeeID),
+		RangeStart: pgtype.Timestamptz{Time: start, Valid: true},
+		RangeEnd:   pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list busy time: %w", err)
+	}
+
+	busy := make([]entity.BusyTime, len(dbBusyTimes))
+	for i, b := range dbBusyTimes {
+		busy[i] = entity.BusyTime{
+			ID:         int(b.ID),
+			ImportID:   int(b.ImportID),
+			EmployeeID: int(b.EmployeeID),
+			StartTime:  b.StartTime.Time,
+			EndTime:    b.EndTime.Time,
+		}
+	}
+
+	return busy, nil
 }
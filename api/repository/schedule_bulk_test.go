@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestScheduleRepository_ApplyTemplateBatch(t *testing.T) {
+	ctx := context.Background()
+	scheduleRepo := repository.NewScheduleRepository(db)
+
+	user := createTestUser(t, entity.RoleEmployee)
+	employee := &entity.Employee{BusinessID: businessID, UserID: user.ID, IsActive: true}
+	require.NoError(t, employeeRepo.Create(ctx, employee))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM schedule_templates WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM schedule_overrides WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM employees WHERE id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM users WHERE id = $1", user.ID)
+		require.NoError(t, err)
+	})
+
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	date := func(day int) time.Time {
+		return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	monday := &entity.ScheduleTemplate{EmployeeID: employee.ID, DayOfWeek: int(time.Monday), StartTime: clock(9), EndTime: clock(17), RotationWeeks: 1, RotationWeek: 1}
+	tuesday := &entity.ScheduleTemplate{EmployeeID: employee.ID, DayOfWeek: int(time.Tuesday), StartTime: clock(9), EndTime: clock(17), RotationWeeks: 1, RotationWeek: 1}
+	require.NoError(t, scheduleRepo.CreateTemplate(ctx, monday))
+	require.NoError(t, scheduleRepo.CreateTemplate(ctx, tuesday))
+
+	// Ending one template, deleting another and creating a new one happens at once
+	validUntil := date(31)
+	monday.ValidUntil = &validUntil
+	validFrom := date(31).AddDate(0, 0, 1)
+	created := &entity.ScheduleTemplate{EmployeeID: employee.ID, DayOfWeek: int(time.Monday), StartTime: clock(10), EndTime: clock(18), RotationWeeks: 1, RotationWeek: 1, ValidFrom: &validFrom}
+	require.NoError(t, scheduleRepo.ApplyTemplateBatch(ctx, repository.TemplateBatch{
+		Delete: []int{tuesday.ID},
+		Update: []*entity.ScheduleTemplate{monday},
+		Create: []*entity.ScheduleTemplate{created},
+	}))
+	assert.NotZero(t, created.ID)
+
+	templates, err := scheduleRepo.ListTemplates(ctx, employee.ID)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, validUntil, *templates[0].ValidUntil)
+	assert.Equal(t, validFrom, *templates[1].ValidFrom)
+
+	// A failing change leaves every template as it was
+	err = scheduleRepo.ApplyTemplateBatch(ctx, repository.TemplateBatch{
+		Delete: []int{created.ID},
+		Create: []*entity.ScheduleTemplate{{EmployeeID: employee.ID, DayOfWeek: int(time.Monday), StartTime: clock(10), EndTime: clock(18), RotationWeeks: 1, RotationWeek: 5}},
+	})
+	assert.Error(t, err)
+
+	templates, err = scheduleRepo.ListTemplates(ctx, employee.ID)
+	require.NoError(t, err)
+	assert.Len(t, templates, 2)
+
+	require.NoError(t, scheduleRepo.CreateOverrides(ctx, []*entity.ScheduleOverride{
+		{EmployeeID: employee.ID, OverrideDate: date(10)},
+		{EmployeeID: employee.ID, OverrideDate: date(11)},
+	}))
+
+	overrides, err := scheduleRepo.ListOverrides(ctx, employee.ID, date(10), date(11))
+	require.NoError(t, err)
+	assert.Len(t, overrides, 2)
+}
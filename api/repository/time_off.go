@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name TimeOffRepository --output ./mocks
+type TimeOffRepository interface {
+	// Create stores the request unless it overlaps a pending or approved request of the employee,
+	// ErrConflict is returned then. Concurrent requests of the employee are checked one at a time.
+	Create(ctx context.Context, request *entity.TimeOffRequest) error
+	Get(ctx context.Context, id int) (*entity.TimeOffRequest, error)
+	// ListByEmployee returns the requests of the employee, latest first
+	ListByEmployee(ctx context.Context, employeeID int) ([]entity.TimeOffRequest, error)
+	// ListByBusiness returns the requests of the business with the status, or all of them when status is empty
+	ListByBusiness(ctx context.Context, businessID int, status string) ([]entity.TimeOffRequest, error)
+	// Approve stores the decision and creates the overrides in one transaction.
+	// ErrNotFound is returned when the request is no longer pending.
+	Approve(ctx context.Context, request *entity.TimeOffRequest, overrides []*entity.ScheduleOverride) error
+	// Reject stores the decision. ErrNotFound is returned when the request is no longer pending.
+	Reject(ctx context.Context, request *entity.TimeOffRequest) error
+}
+
+type timeOffRepository struct {
+	db *DB
+}
+
+func NewTimeOffRepository(db *DB) TimeOffRepository {
+	return &timeOffRepository{
+		db: db,
+	}
+}
+
+func (r *timeOffRepository) Create(ctx context.Context, request *entity.TimeOffRequest) error {
+	params := sqlc.CreateTimeOffRequestParams{
+		BusinessID: int32(request.BusinessID),
+		EmployeeID: int32(request.EmployeeID),
+		StartDate:  pgtype.Date{Time: request.StartDate, Valid: true},
+		EndDate:    pgtype.Date{Time: request.EndDate, Valid: true},
+		Reason:     optionalText(request.Reason),
+	}
+	if request.StartTime != nil && request.EndTime != nil {
+		params.StartTime = pgtype.Time{Microseconds: timeToMicroseconds(*request.StartTime), Valid: true}
+		params.EndTime = pgtype.Time{Microseconds: timeToMicroseconds(*request.EndTime), Valid: true}
+	}
+
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := q.LockEmployeeTimeOff(ctx, params.EmployeeID); err != nil {
+			return fmt.Errorf("failed to lock employee time off: %w", err)
+		}
+
+		overlaps, err := q.HasTimeOffOverlap(ctx, sqlc.HasTimeOffOverlapParams{
+			EmployeeID: params.EmployeeID,
+			EndDate:    params.EndDate,
+			StartDate:  params.StartDate,
+			StartTime:  params.StartTime,
+			EndTime:    params.EndTime,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check time off requests: %w", err)
+		}
+		if overlaps {
+			return ErrConflict
+		}
+
+		dbRequest, err := q.CreateTimeOffRequest(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create time off request: %w", r.db.HandleBasicErrors(err))
+		}
+
+		*request = *convertDBTimeOffRequestToEntity(dbRequest)
+		return nil
+	})
+}
+
+func (r *timeOffRepository) Get(ctx context.Context, id int) (*entity.TimeOffRequest, error) {
+	dbRequest, err := r.db.SQLC.GetTimeOffRequest(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBTimeOffRequestToEntity(dbRequest), nil
+}
+
+func (r *timeOffRepository) ListByEmployee(ctx context.Context, employeeID int) ([]entity.TimeOffRequest, error) {
+	dbRequests, err := r.db.SQLC.ListEmployeeTimeOffRequests(ctx, int32(employeeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+
+	return convertDBTimeOffRequests(dbRequests), nil
+}
+
+func (r *timeOffRepository) ListByBusiness(ctx context.Context, businessID int, status string) ([]entity.TimeOffRequest, error) {
+	dbRequests, err := r.db.SQLC.ListBusinessTimeOffRequests(ctx, sqlc.ListBusinessTimeOffRequestsParams{
+		BusinessID: int32(businessID),
+		Status:     pgtype.Text{String: status, Valid: status != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+
+	return convertDBTimeOffRequests(dbRequests), nil
+}
+
+func (r *timeOffRepository) Approve(ctx context.Context, request *entity.TimeOffRequest, overrides []*entity.ScheduleOverride) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := decideTimeOffRequest(ctx, q, request, entity.TimeOffStatusApproved); err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		for _, override := range overrides {
+			if err := createOverride(ctx, q, override); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *timeOffRepository) Reject(ctx context.Context, request *entity.TimeOffRequest) error {
+	if err := decideTimeOffRequest(ctx, r.db.SQLC, request, entity.TimeOffStatusRejected); err != nil {
+		return r.db.HandleBasicErrors(err)
+	}
+	return nil
+}
+
+// decideTimeOffRequest moves a pending request to the status, storing the decision fields of the request
+func decideTimeOffRequest(ctx context.Context, q *sqlc.Queries, request *entity.TimeOffRequest, status string) error {
+	dbRequest, err := q.DecideTimeOffRequest(ctx, sqlc.DecideTimeOffRequestParams{
+		ID:           int32(request.ID),
+		Status:       status,
+		DecidedBy:    optionalInt4(request.DecidedBy),
+		DecidedAt:    optionalTimestamptz(request.DecidedAt),
+		DecisionNote: optionalText(request.DecisionNote),
+	})
+	if err != nil {
+		return err
+	}
+
+	*request = *convertDBTimeOffRequestToEntity(dbRequest)
+	return nil
+}
+
+func convertDBTimeOffRequests(dbRequests []sqlc.TimeOffRequest) []entity.TimeOffRequest {
+	requests := make([]entity.TimeOffRequest, len(dbRequests))
+	for i, dbRequest := range dbRequests {
+		requests[i] = *convertDBTimeOffRequestToEntity(dbRequest)
+	}
+	return requests
+}
+
+func convertDBTimeOffRequestToEntity(t sqlc.TimeOffRequest) *entity.TimeOffRequest {
+	request := &entity.TimeOffRequest{
+		ID:         int(t.ID),
+		BusinessID: int(t.BusinessID),
+		EmployeeID: int(t.EmployeeID),
+		StartDate:  t.StartDate.Time,
+		EndDate:    t.EndDate.Time,
+		Status:     t.Status,
+		CreatedAt:  t.CreatedAt.Time,
+	}
+
+	if t.StartTime.Valid && t.EndTime.Valid {
+		startTime := microsecondsToTime(t.StartTime.Microseconds)
+		endTime := microsecondsToTime(t.EndTime.Microseconds)
+		request.StartTime = &startTime
+		request.EndTime = &endTime
+	}
+	if t.Reason.Valid {
+		request.Reason = &t.Reason.String
+	}
+	if t.DecidedBy.Valid {
+		decidedBy := int(t.DecidedBy.Int32)
+		request.DecidedBy = &decidedBy
+	}
+	if t.DecidedAt.Valid {
+		decidedAt := t.DecidedAt.Time
+		request.DecidedAt = &decidedAt
+	}
+	if t.DecisionNote.Valid {
+		request.DecisionNote = &t.DecisionNote.String
+	}
+
+	return request
+}
@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestTimeOffRepository(t *testing.T) {
+	ctx := context.Background()
+	timeOffRepo := repository.NewTimeOffRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+
+	user := createTestUser(t, entity.RoleEmployee)
+	employee := &entity.Employee{BusinessID: businessID, UserID: user.ID, IsActive: true}
+	require.NoError(t, employeeRepo.Create(ctx, employee))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM time_off_requests WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM schedule_overrides WHERE employee_id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM employees WHERE id = $1", employee.ID)
+		require.NoError(t, err)
+		_, err = db.PGX.Exec(ctx, "DELETE FROM users WHERE id = $1", user.ID)
+		require.NoError(t, err)
+	})
+
+	date := func(day int) time.Time {
+		return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)
+	}
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	vacation := &entity.TimeOffRequest{BusinessID: businessID, EmployeeID: employee.ID, StartDate: date(10), EndDate: date(11)}
+	require.NoError(t, timeOffRepo.Create(ctx, vacation))
+	assert.Equal(t, entity.TimeOffStatusPending, vacation.Status)
+
+	startTime, endTime := clock(9), clock(12)
+	dentist := &entity.TimeOffRequest{BusinessID: businessID, EmployeeID: employee.ID, StartDate: date(20), EndDate: date(20), StartTime: &startTime, EndTime: &endTime}
+	require.NoError(t, timeOffRepo.Create(ctx, dentist))
+
+	// Requests overlapping pending or approved ones of the employee are refused
+	overlapping := &entity.TimeOffRequest{BusinessID: businessID, EmployeeID: employee.ID, StartDate: date(11), EndDate: date(12)}
+	assert.ErrorIs(t, timeOffRepo.Create(ctx, overlapping), repository.ErrConflict)
+	lateStart, lateEnd := clock(11), clock(13)
+	overlapping = &entity.TimeOffRequest{BusinessID: businessID, EmployeeID: employee.ID, StartDate: date(20), EndDate: date(20), StartTime: &lateStart, EndTime: &lateEnd}
+	assert.ErrorIs(t, timeOffRepo.Create(ctx, overlapping), repository.ErrConflict)
+
+	// Approving stores the decision together with the overrides
+	decidedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	vacation.DecidedBy = &user.ID
+	vacation.DecidedAt = &decidedAt
+	require.NoError(t, timeOffRepo.Approve(ctx, vacation, []*entity.ScheduleOverride{
+		{EmployeeID: employee.ID, OverrideDate: date(10)},
+		{EmployeeID: employee.ID, OverrideDate: date(11)},
+	}))
+	assert.Equal(t, entity.TimeOffStatusApproved, vacation.Status)
+
+	overrides, err := scheduleRepo.ListOverrides(ctx, employee.ID, date(10), date(11))
+	require.NoError(t, err)
+	assert.Len(t, overrides, 2)
+
+	// A decided request cannot be decided again
+	assert.ErrorIs(t, timeOffRepo.Reject(ctx, vacation), repository.ErrNotFound)
+
+	require.NoError(t, timeOffRepo.Reject(ctx, dentist))
+	got, err := timeOffRepo.Get(ctx, dentist.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.TimeOffStatusRejected, got.Status)
+	assert.Equal(t, clock(9), *got.StartTime)
+
+	requests, err := timeOffRepo.ListByBusiness(ctx, businessID, entity.TimeOffStatusApproved)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, vacation.ID, requests[0].ID)
+
+	requests, err = timeOffRepo.ListByEmployee(ctx, employee.ID)
+	require.NoError(t, err)
+	assert.Len(t, requests, 2)
+}
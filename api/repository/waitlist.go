@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name WaitlistRepository --output ./mocks
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *entity.WaitlistEntry) error
+	Get(ctx context.Context, id int) (*entity.WaitlistEntry, error)
+	ListByClient(ctx context.Context, clientID int) ([]entity.WaitlistEntry, error)
+	// ListMatching returns the waiting entries of the service that accept the employee
+	// and whose window contains the interval, oldest first
+	ListMatching(ctx context.Context, serviceID, employeeID int, startTime, endTime time.Time) ([]entity.WaitlistEntry, error)
+	// Offer creates the appointment for a waiting entry and links it in one transaction. A held appointment
+	// makes the entry offered until the hold expires, a scheduled one books it right away.
+	// ErrNotFound is returned when the entry is no longer waiting.
+	Offer(ctx context.Context, entry *entity.WaitlistEntry, appointment *entity.Appointment) error
+	// Accept confirms the held appointment of an offered entry and marks the entry booked.
+	// ErrNotFound is returned when the offer has expired.
+	Accept(ctx context.Context, entry *entity.WaitlistEntry) error
+	// Release deletes the held appointment of an offered entry and moves the entry to the status
+	Release(ctx context.Context, entry *entity.WaitlistEntry, status string) error
+	// UpdateStatus moves the entry to newStatus only if it is still in currentStatus
+	UpdateStatus(ctx context.Context, id int, currentStatus, newStatus string) error
+	// ListExpiredOffers returns the offered entries whose hold expired at or before now
+	ListExpiredOffers(ctx context.Context, now time.Time) ([]entity.WaitlistEntry, error)
+}
+
+type waitlistRepository struct {
+	db *DB
+}
+
+func NewWaitlistRepository(db *DB) WaitlistRepository {
+	return &waitlistRepository{
+		db: db,
+	}
+}
+
+func (r *waitlistRepository) Create(ctx context.Context, entry *entity.WaitlistEntry) error {
+	dbEntry, err := r.db.SQLC.CreateWaitlistEntry(ctx, sqlc.CreateWaitlistEntryParams{
+		BusinessID:  pgtype.Int4{Int32: int32(entry.BusinessID), Valid: true},
+		ClientID:    pgtype.Int4{Int32: int32(entry.ClientID), Valid: true},
+		ServiceID:   pgtype.Int4{Int32: int32(entry.ServiceID), Valid: true},
+		EmployeeID:  optionalInt4(entry.EmployeeID),
+		WindowStart: pgtype.Timestamptz{Time: entry.WindowStart, Valid: true},
+		WindowEnd:   pgtype.Timestamptz{Time: entry.WindowEnd, Valid: true},
+		AutoBook:    entry.AutoBook,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*entry = *convertDBWaitlistEntryToEntity(dbEntry)
+	return nil
+}
+
+func (r *waitlistRepository) Get(ctx context.Context, id int) (*entity.WaitlistEntry, error) {
+	dbEntry, err := r.db.SQLC.GetWaitlistEntry(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBWaitlistEntryToEntity(dbEntry), nil
+}
+
+func (r *waitlistRepository) ListByClient(ctx context.Context, clientID int) ([]entity.WaitlistEntry, error) {
+	dbEntries, err := r.db.SQLC.ListClientWaitlistEntries(ctx, pgtype.Int4{Int32: int32(clientID), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client waitlist entries: %w", err)
+	}
+
+	return convertDBWaitlistEntries(dbEntries), nil
+}
+
+func (r *waitlistRepository) ListMatching(ctx context.Context, serviceID, employeeID int, startTime, endTime time.Time) ([]entity.WaitlistEntry, error) {
+	dbEntries, err := r.db.SQLC.ListMatchingWaitlistEntries(ctx, sqlc.ListMatchingWaitlistEntriesParams{
+		ServiceID:  pgtype.Int4{Int32: int32(serviceID), Valid: true},
+		EmployeeID: pgtype.Int4{Int32: int32(employeeID), Valid: true},
+		StartTime:  pgtype.Timestamptz{Time: startTime, Valid: true},
+		EndTime:    pgtype.Timestamptz{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list matching waitlist entries: %w", err)
+	}
+
+	return convertDBWaitlistEntries(dbEntries), nil
+}
+
+func (r *waitlistRepository) Offer(ctx context.Context, entry *entity.WaitlistEntry, appointment *entity.Appointment) error {
+	status := entity.WaitlistStatusBooked
+	if appointment.Status == entity.AppointmentStatusHeld {
+		status = entity.WaitlistStatusOffered
+	}
+
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if err := lockEmployeeBookings(ctx, q, appointment.EmployeeID); err != nil {
+			return err
+		}
+
+		dbAppointment, err := q.CreateAppointment(ctx, createAppointmentParams(appointment))
+		if err != nil {
+			return fmt.Errorf("failed to create appointment: %w", r.db.HandleBasicErrors(err))
+		}
+		// Held offers are not covered by the exclusion constraint
+		if err := checkEmployeeOverlap(ctx, q, dbAppointment.ID); err != nil {
+			return err
+		}
+
+		dbEntry, err := q.OfferWaitlistEntry(ctx, sqlc.OfferWaitlistEntryParams{
+			ID:             int32(entry.ID),
+			Status:         status,
+			AppointmentID:  pgtype.Int4{Int32: dbAppointment.ID, Valid: true},
+			OfferExpiresAt: optionalTimestamptz(appointment.HoldExpiresAt),
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		appointment.ID = int(dbAppointment.ID)
+		appointment.CreatedAt = dbAppointment.CreatedAt.Time
+		*entry = *convertDBWaitlistEntryToEntity(dbEntry)
+		return recordCreatedAppointment(ctx, q, dbAppointment)
+	})
+}
+
+func (r *waitlistRepository) Accept(ctx context.Context, entry *entity.WaitlistEntry) error {
+	if entry.AppointmentID == nil {
+		return ErrNotFound
+	}
+
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.ConfirmHeldAppointment(ctx, int32(*entry.AppointmentID)); err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+		if err := recordAppointmentEvent(ctx, q, entity.EventAppointmentCreated, int32(*entry.AppointmentID)); err != nil {
+			return err
+		}
+
+		dbEntry, err := q.UpdateWaitlistEntryStatus(ctx, sqlc.UpdateWaitlistEntryStatusParams{
+			ID:            int32(entry.ID),
+			CurrentStatus: entity.WaitlistStatusOffered,
+			NewStatus:     entity.WaitlistStatusBooked,
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		*entry = *convertDBWaitlistEntryToEntity(dbEntry)
+		return nil
+	})
+}
+
+func (r *waitlistRepository) Release(ctx context.Context, entry *entity.WaitlistEntry, status string) error {
+	return r.db.InTx(ctx, func(q *sqlc.Queries) error {
+		if entry.AppointmentID != nil {
+			if err := q.DeleteHeldAppointment(ctx, int32(*entry.AppointmentID)); err != nil {
+				return fmt.Errorf("failed to delete held appointment: %w", err)
+			}
+		}
+
+		dbEntry, err := q.UpdateWaitlistEntryStatus(ctx, sqlc.UpdateWaitlistEntryStatusParams{
+			ID:            int32(entry.ID),
+			CurrentStatus: entity.WaitlistStatusOffered,
+			NewStatus:     status,
+		})
+		if err != nil {
+			return r.db.HandleBasicErrors(err)
+		}
+
+		*entry = *convertDBWaitlistEntryToEntity(dbEntry)
+		return nil
+	})
+}
+
+func (r *waitlistRepository) UpdateStatus(ctx context.Context, id int, currentStatus, newStatus string) error {
+	_, err := r.db.SQLC.UpdateWaitlistEntryStatus(ctx, sqlc.UpdateWaitlistEntryStatusParams{
+		ID:            int32(id),
+		CurrentStatus: currentStatus,
+		NewStatus:     newStatus,
+	})
+	if err != nil {
+		return r.db.HandleBasicErrors(err)
+	}
+	return nil
+}
+
+func (r *waitlistRepository) ListExpiredOffers(ctx context.Context, now time.Time) ([]entity.WaitlistEntry, error) {
+	dbEntries, err := r.db.SQLC.ListExpiredWaitlistOffers(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired waitlist offers: %w", err)
+	}
+
+	return convertDBWaitlistEntries(dbEntries), nil
+}
+
+func convertDBWaitlistEntries(dbEntries []sqlc.WaitlistEntry) []entity.WaitlistEntry {
+	entries := make([]entity.WaitlistEntry, len(dbEntries))
+	for i, e := range dbEntries {
+		entries[i] = *convertDBWaitlistEntryToEntity(e)
+	}
+	return entries
+}
+
+func convertDBWaitlistEntryToEntity(e sqlc.WaitlistEntry) *entity.WaitlistEntry {
+	entry := &entity.WaitlistEntry{
+		ID:          int(e.ID),
+		BusinessID:  int(e.BusinessID.Int32),
+		ClientID:    int(e.ClientID.Int32),
+		ServiceID:   int(e.ServiceID.Int32),
+		WindowStart: e.WindowStart.Time.UTC(),
+		WindowEnd:   e.WindowEnd.Time.UTC(),
+		AutoBook:    e.AutoBook,
+		Status:      e.Status,
+		CreatedAt:   e.CreatedAt.Time.UTC(),
+	}
+
+	if e.EmployeeID.Valid {
+		employeeID := int(e.EmployeeID.Int32)
+		entry.EmployeeID = &employeeID
+	}
+
+	if e.AppointmentID.Valid {
+		appointmentID := int(e.AppointmentID.Int32)
+		entry.AppointmentID = &appointmentID
+	}
+
+	if e.OfferExpiresAt.Valid {
+		expiresAt := e.OfferExpiresAt.Time.UTC()
+		entry.OfferExpiresAt = &expiresAt
+	}
+
+	return entry
+}
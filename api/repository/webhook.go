@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository/db/sqlc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.3 --dir . --name WebhookRepository --output ./mocks
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	Get(ctx context.Context, id int) (*entity.Webhook, error)
+	ListByBusiness(ctx context.Context, businessID int) ([]entity.Webhook, error)
+	// ListSubscribed returns the active webhooks of the business subscribed to the event type
+	ListSubscribed(ctx context.Context, businessID int, eventType string) ([]entity.Webhook, error)
+	Update(ctx context.Context, webhook *entity.Webhook) error
+	Delete(ctx context.Context, id int) error
+
+	// CreateDelivery stores a pending delivery due at its next attempt time.
+	// ErrAlreadyExists is returned when the event already has a delivery to the webhook.
+	CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id int) (*entity.WebhookDelivery, error)
+	// ListDeliveries returns up to limit latest deliveries of the webhook, newest first
+	ListDeliveries(ctx context.Context, webhookID int, limit int) ([]entity.WebhookDelivery, error)
+	// ClaimDue claims up to limit pending deliveries due at now, oldest first.
+	// Claimed deliveries are not returned again until the lease passes.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error)
+	// UpdateDelivery stores the outcome of a delivery attempt
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *DB
+}
+
+func NewWebhookRepository(db *DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	dbWebhook, err := r.db.SQLC.CreateWebhook(ctx, sqlc.CreateWebhookParams{
+		BusinessID: pgtype.Int4{Int32: int32(webhook.BusinessID), Valid: true},
+		Url:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventTypes: webhook.EventTypes,
+		IsActive:   webhook.IsActive,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*webhook = *convertDBWebhookToEntity(dbWebhook)
+	return nil
+}
+
+func (r *webhookRepository) Get(ctx context.Context, id int) (*entity.Webhook, error) {
+	dbWebhook, err := r.db.SQLC.GetWebhook(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBWebhookToEntity(dbWebhook), nil
+}
+
+func (r *webhookRepository) ListByBusiness(ctx context.Context, businessID int) ([]entity.Webhook, error) {
+	dbWebhooks, err := r.db.SQLC.ListWebhooks(ctx, pgtype.Int4{Int32: int32(businessID), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return convertDBWebhooks(dbWebhooks), nil
+}
+
+func (r *webhookRepository) ListSubscribed(ctx context.Context, businessID int, eventType string) ([]entity.Webhook, error) {
+	dbWebhooks, err := r.db.SQLC.ListSubscribedWebhooks(ctx, sqlc.ListSubscribedWebhooksParams{
+		BusinessID: pgtype.Int4{Int32: int32(businessID), Valid: true},
+		EventType:  eventType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscribed webhooks: %w", err)
+	}
+
+	return convertDBWebhooks(dbWebhooks), nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	dbWebhook, err := r.db.SQLC.UpdateWebhook(ctx, sqlc.UpdateWebhookParams{
+		ID:         int32(webhook.ID),
+		Url:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		IsActive:   webhook.IsActive,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", r.db.HandleBasicErrors(err))
+	}
+
+	*webhook = *convertDBWebhookToEntity(dbWebhook)
+	return nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int) error {
+	deleted, err := r.db.SQLC.DeleteWebhook(ctx, int32(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if deleted == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	var eventID pgtype.Int8
+	if delivery.EventID != nil {
+		eventID = pgtype.Int8{Int64: int64(*delivery.EventID), Valid: true}
+	}
+
+	dbDelivery, err := r.db.SQLC.CreateWebhookDelivery(ctx, sqlc.CreateWebhookDeliveryParams{
+		WebhookID: int32(delivery.WebhookID),
+		EventID:   eventID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+	})
+	if err != nil {
+		err = r.db.HandleBasicErrors(err)
+		// ON CONFLICT DO NOTHING returns no row for a delivery that already exists
+		if errors.Is(err, ErrNotFound) && delivery.EventID != nil {
+			err = ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	*delivery = *convertDBWebhookDeliveryToEntity(dbDelivery)
+	return nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id int) (*entity.WebhookDelivery, error) {
+	dbDelivery, err := r.db.SQLC.GetWebhookDelivery(ctx, int32(id))
+	if err != nil {
+		return nil, r.db.HandleBasicErrors(err)
+	}
+
+	return convertDBWebhookDeliveryToEntity(dbDelivery), nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID int, limit int) ([]entity.WebhookDelivery, error) {
+	dbDeliveries, err := r.db.SQLC.ListWebhookDeliveries(ctx, sqlc.ListWebhookDeliveriesParams{
+		WebhookID: int32(webhookID),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return convertDBWebhookDeliveries(dbDeliveries), nil
+}
+
+func (r *webhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	dbDeliveries, err := r.db.SQLC.ClaimWebhookDeliveries(ctx, sqlc.ClaimWebhookDeliveriesParams{
+		ClaimedUntil: pgtype.Timestamptz{Time: now.Add(lease), Valid: true},
+		Now:          pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	deliveries := convertDBWebhookDeliveries(dbDeliveries)
+	// UPDATE ... RETURNING does not keep the order of the claim
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	err := r.db.SQLC.UpdateWebhookDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
+		ID:             int32(delivery.ID),
+		Status:         delivery.Status,
+		Attempts:       int32(delivery.Attempts),
+		NextAttemptAt:  pgtype.Timestamptz{Time: delivery.NextAttemptAt, Valid: true},
+		ResponseStatus: optionalInt4(delivery.ResponseStatus),
+		LastError:      optionalText(delivery.LastError),
+		DeliveredAt:    optionalTimestamptz(delivery.DeliveredAt),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func convertDBWebhookToEntity(w sqlc.Webhook) *entity.Webhook {
+	return &entity.Webhook{
+		ID:         int(w.ID),
+		BusinessID: int(w.BusinessID.Int32),
+		URL:        w.Url,
+		Secret:     w.Secret,
+		EventTypes: w.EventTypes,
+		IsActive:   w.IsActive,
+		CreatedAt:  w.CreatedAt.Time,
+	}
+}
+
+func convertDBWebhooks(dbWebhooks []sqlc.Webhook) []entity.Webhook {
+	webhooks := make([]entity.Webhook, len(dbWebhooks))
+	for i, w := range dbWebhooks {
+		webhooks[i] = *convertDBWebhookToEntity(w)
+	}
+	return webhooks
+}
+
+func convertDBWebhookDeliveryToEntity(d sqlc.WebhookDelivery) *entity.WebhookDelivery {
+	delivery := &entity.WebhookDelivery{
+		ID:            int(d.ID),
+		WebhookID:     int(d.WebhookID),
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        d.Status,
+		Attempts:      int(d.Attempts),
+		NextAttemptAt: d.NextAttemptAt.Time,
+		CreatedAt:     d.CreatedAt.Time,
+	}
+	if d.EventID.Valid {
+		eventID := int(d.EventID.Int64)
+		delivery.EventID = &eventID
+	}
+	if d.ResponseStatus.Valid {
+		status := int(d.ResponseStatus.Int32)
+		delivery.ResponseStatus = &status
+	}
+	if d.LastError.Valid {
+		delivery.LastError = &d.LastError.String
+	}
+	if d.DeliveredAt.Valid {
+		delivery.DeliveredAt = &d.DeliveredAt.Time
+	}
+	return delivery
+}
+
+func convertDBWebhookDeliveries(dbDeliveries []sqlc.WebhookDelivery) []entity.WebhookDelivery {
+	deliveries := make([]entity.WebhookDelivery, len(dbDeliveries))
+	for i, d := range dbDeliveries {
+		deliveries[i] = *convertDBWebhookDeliveryToEntity(d)
+	}
+	return deliveries
+}
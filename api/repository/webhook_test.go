@@ -0,0 +1,103 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := repository.NewWebhookRepository(db)
+
+	hook := &entity.Webhook{
+		BusinessID: businessID,
+		URL:        "https://crm.example.com/hooks",
+		Secret:     "whsec_test",
+		EventTypes: []string{entity.EventAppointmentCreated, entity.EventEmployeeUpdated},
+		IsActive:   true,
+	}
+	require.NoError(t, webhookRepo.Create(ctx, hook))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", hook.ID)
+		require.NoError(t, err)
+	})
+
+	// Only active webhooks subscribed to the event type are listed
+	subscribed, err := webhookRepo.ListSubscribed(ctx, businessID, entity.EventEmployeeUpdated)
+	require.NoError(t, err)
+	require.Len(t, subscribed, 1)
+	assert.Equal(t, "whsec_test", subscribed[0].Secret)
+
+	subscribed, err = webhookRepo.ListSubscribed(ctx, businessID, entity.EventServiceCreated)
+	require.NoError(t, err)
+	assert.Empty(t, subscribed)
+
+	// An event is delivered to a webhook once
+	var eventID int
+	require.NoError(t, db.PGX.QueryRow(ctx,
+		`INSERT INTO domain_events (business_id, aggregate_type, aggregate_id, event_type, payload)
+		 VALUES ($1, 'appointment', 1, 'appointment.created', '{}') RETURNING id`, businessID).Scan(&eventID))
+	t.Cleanup(func() {
+		_, err := db.PGX.Exec(ctx, "DELETE FROM domain_events WHERE id = $1", eventID)
+		require.NoError(t, err)
+	})
+
+	delivery := &entity.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   &eventID,
+		EventType: entity.EventAppointmentCreated,
+		Payload:   []byte(`{"type":"appointment.created"}`),
+	}
+	require.NoError(t, webhookRepo.CreateDelivery(ctx, delivery))
+	assert.Equal(t, entity.WebhookDeliveryStatusPending, delivery.Status)
+
+	err = webhookRepo.CreateDelivery(ctx, &entity.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventID:   &eventID,
+		EventType: entity.EventAppointmentCreated,
+		Payload:   []byte(`{}`),
+	})
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+
+	// Claimed deliveries are not returned again until the lease passes
+	now := time.Now()
+	claimed, err := webhookRepo.ClaimDue(ctx, now, time.Minute, 100)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, delivery.ID, claimed[0].ID)
+
+	claimed, err = webhookRepo.ClaimDue(ctx, now, time.Minute, 100)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// The outcome of an attempt is kept in the delivery log
+	status := 200
+	delivery.Status = entity.WebhookDeliveryStatusSucceeded
+	delivery.Attempts = 1
+	delivery.ResponseStatus = &status
+	delivery.DeliveredAt = &now
+	require.NoError(t, webhookRepo.UpdateDelivery(ctx, delivery))
+
+	deliveries, err := webhookRepo.ListDeliveries(ctx, hook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entity.WebhookDeliveryStatusSucceeded, deliveries[0].Status)
+	assert.Equal(t, &status, deliveries[0].ResponseStatus)
+	assert.Equal(t, &eventID, deliveries[0].EventID)
+
+	claimed, err = webhookRepo.ClaimDue(ctx, now.Add(time.Hour), time.Minute, 100)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	// Deleting the webhook deletes its deliveries
+	require.NoError(t, webhookRepo.Delete(ctx, hook.ID))
+	_, err = webhookRepo.GetDelivery(ctx, delivery.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, webhookRepo.Delete(ctx, hook.ID), repository.ErrNotFound)
+}
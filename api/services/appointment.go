@@ -2,28 +2,89 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/vadimpk/ppc-project/entity"
 	"github.com/vadimpk/ppc-project/repository"
 )
 
+var (
+	ErrInvalidStatusTransition = errors.New("invalid appointment status transition")
+	ErrSlotUnavailable         = errors.New("time slot is not available")
+	ErrNoAvailableSlots        = errors.New("no available slots found")
+	ErrSessionFull             = errors.New("class session is full")
+	ErrAlreadyBooked           = errors.New("client has already booked this session")
+)
+
+const (
+	// maxRangeDays is the longest date range that can be requested at once
+	maxRangeDays = 31
+	// nextAvailableHorizonDays limits how far ahead the next available slot is searched
+	nextAvailableHorizonDays = 90
+)
+
+// appointmentTransitions lists the statuses an appointment can move to from each status.
+// Completed, cancelled and no-show appointments are final.
+var appointmentTransitions = map[string][]string{
+	entity.AppointmentStatusScheduled: {
+		entity.AppointmentStatusCompleted,
+		entity.AppointmentStatusCancelled,
+		entity.AppointmentStatusNoShow,
+	},
+}
+
+var errOutsideWorkingHours = errors.New("appointment time is outside employee's working hours")
+
 type appointmentService struct {
-	repos *repository.Repositories
+	repos      *repository.Repositories
+	assignment AssignmentStrategy
+	notifier   Notifier
+	clock      Clock
 }
 
-func NewAppointmentService(repos *repository.Repositories) AppointmentService {
+// NewAppointmentService creates the appointment service. The assignment strategy picks the employee
+// for appointments booked without one and defaults to the first free employee when nil.
+// The notifier tells waitlisted clients about the slots held for them and may be nil, other appointment
+// changes reach clients through the domain events consumed by NotificationConsumer.
+// The clock sets and checks hold expiry and defaults to the system clock when nil.
+func NewAppointmentService(repos *repository.Repositories, assignment AssignmentStrategy, notifier Notifier, clock Clock) AppointmentService {
+	if assignment == nil {
+		assignment = NewFirstFreeStrategy()
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+
 	return &appointmentService{
-		repos: repos,
+		repos:      repos,
+		assignment: assignment,
+		notifier:   notifier,
+		clock:      clock,
 	}
 }
 
 func (s *appointmentService) Create(ctx context.Context, appointment *entity.Appointment) error {
+	// A hold token books the interval reserved during checkout
+	if appointment.HoldToken != nil {
+		return s.confirmHold(ctx, appointment)
+	}
+
+	appointment.Status = entity.AppointmentStatusScheduled
+	return s.create(ctx, appointment)
+}
+
+// create validates and stores the appointment with the status it was given
+func (s *appointmentService) create(ctx context.Context, appointment *entity.Appointment) error {
 	// Validate business existence
-	if _, err := s.repos.Business.Get(ctx, appointment.BusinessID); err != nil {
+	business, err := s.repos.Business.Get(ctx, appointment.BusinessID)
+	if err != nil {
 		return fmt.Errorf("invalid business: %w", err)
 	}
+	loc := loadLocation(business.Timezone)
 
 	// Validate client existence
 	client, err := s.repos.User.Get(ctx, appointment.ClientID)
@@ -34,6 +95,25 @@ func (s *appointmentService) Create(ctx context.Context, appointment *entity.App
 		return fmt.Errorf("user is not a client")
 	}
 
+	// Validate service existence and availability
+	service, err := s.repos.Service.Get(ctx, appointment.ServiceID)
+	if err != nil {
+		return fmt.Errorf("invalid service: %w", err)
+	}
+	if !service.IsActive {
+		return fmt.Errorf("service is not active")
+	}
+	if appointment.Status == entity.AppointmentStatusHeld && isGroupService(service) {
+		return fmt.Errorf("seats of group services cannot be held")
+	}
+
+	appointment.EndTime = appointment.StartTime.Add(time.Duration(service.Duration) * time.Minute)
+
+	// Without a requested employee any free employee providing the service is assigned
+	if appointment.EmployeeID == 0 {
+		return s.createWithAssignment(ctx, appointment, service, loc)
+	}
+
 	// Validate employee existence and active status
 	employee, err := s.repos.Employee.Get(ctx, appointment.EmployeeID)
 	if err != nil {
@@ -43,49 +123,132 @@ func (s *appointmentService) Create(ctx context.Context, appointment *entity.App
 		return fmt.Errorf("employee is not active")
 	}
 
-	// Validate service existence and availability for employee
-	service, err := s.repos.Service.Get(ctx, appointment.ServiceID)
-	if err != nil {
-		return fmt.Errorf("invalid service: %w", err)
-	}
-	if !service.IsActive {
-		return fmt.Errorf("service is not active")
-	}
-
 	// Validate service assignment to employee
-	employeeServices, err := s.repos.Employee.GetServices(ctx, appointment.EmployeeID)
-	if err != nil {
-		return fmt.Errorf("failed to check employee services: %w", err)
+	if err := s.validateServiceAssignment(ctx, appointment.EmployeeID, appointment.ServiceID); err != nil {
+		return err
 	}
 
-	var serviceAssigned bool
-	for _, s := range employeeServices {
-		if s.ID == appointment.ServiceID {
-			serviceAssigned = true
-			break
-		}
+	// The appointment takes place at the branch of the employee, whose schedule follows its timezone
+	if appointment.LocationID != nil && !sameLocation(employee.LocationID, *appointment.LocationID) {
+		return fmt.Errorf("%w: employee does not work at the location", ErrInvalidLocation)
 	}
-	if !serviceAssigned {
-		return fmt.Errorf("service is not assigned to employee")
+	appointment.LocationID = employee.LocationID
+	if loc, err = s.employeeLocation(ctx, employee, loc); err != nil {
+		return err
 	}
 
-	appointment.EndTime = appointment.StartTime.Add(time.Duration(service.Duration) * time.Minute)
 	// Validate appointment time
-	if err := s.validateAppointmentTime(ctx, appointment, service.Duration); err != nil {
+	if err := s.validateAppointmentTime(ctx, appointment, service, loc); err != nil {
 		return fmt.Errorf("invalid appointment time: %w", err)
 	}
 
-	// Set default status
-	appointment.Status = entity.AppointmentStatusScheduled
+	// Book a free resource with the appointment when the service needs one
+	if err := s.assignResource(ctx, appointment, service); err != nil {
+		return fmt.Errorf("invalid appointment time: %w", err)
+	}
 
-	// Create appointment
-	if err := s.repos.Appointment.Create(ctx, appointment); err != nil {
+	// Create appointment. The database rejects overlapping bookings that
+	// passed the availability check concurrently.
+	if err := s.book(ctx, appointment, service); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrConflict):
+			return ErrSlotUnavailable
+		case errors.Is(err, repository.ErrNoCapacity):
+			return ErrSessionFull
+		case errors.Is(err, repository.ErrAlreadyExists):
+			return ErrAlreadyBooked
+		}
 		return fmt.Errorf("failed to create appointment: %w", err)
 	}
 
 	return nil
 }
 
+// book stores the appointment. Group services take a seat in the class session of the employee instead.
+func (s *appointmentService) book(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService) error {
+	if isGroupService(service) {
+		return s.repos.Appointment.CreateInSession(ctx, appointment, service.Capacity)
+	}
+
+	return s.repos.Appointment.Create(ctx, appointment)
+}
+
+// createWithAssignment books the appointment with an employee chosen by the assignment strategy
+// among the employees that are free for the appointment time
+func (s *appointmentService) createWithAssignment(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService, loc *time.Location) error {
+	if err := validateAppointmentPeriod(appointment, service, time.Now()); err != nil {
+		return fmt.Errorf("invalid appointment time: %w", err)
+	}
+
+	// A requested location limits the candidates to the employees of the branch
+	locationID := 0
+	if appointment.LocationID != nil {
+		locationID = *appointment.LocationID
+		if _, err := getBusinessLocation(ctx, s.repos, service.BusinessID, locationID); err != nil {
+			return err
+		}
+	}
+
+	employees, err := s.listServiceEmployees(ctx, service, locationID)
+	if err != nil {
+		return err
+	}
+
+	var candidates []entity.Employee
+	for _, employee := range employees {
+		employeeLoc, err := s.employeeLocation(ctx, &employee, loc)
+		if err != nil {
+			return err
+		}
+
+		err = s.checkEmployeeTime(ctx, employee.ID, service, appointment.StartTime, appointment.EndTime, appointment.ID, employeeLoc)
+		if err == nil {
+			candidates = append(candidates, employee)
+			continue
+		}
+		if !errors.Is(err, errOutsideWorkingHours) && !errors.Is(err, ErrSlotUnavailable) {
+			return fmt.Errorf("invalid appointment time: %w", err)
+		}
+	}
+
+	for len(candidates) > 0 {
+		employee, err := s.assignment.Pick(ctx, candidates, appointment)
+		if err != nil {
+			return fmt.Errorf("failed to assign employee: %w", err)
+		}
+
+		appointment.EmployeeID = employee.ID
+		appointment.Employee = employee.User
+		appointment.LocationID = employee.LocationID
+
+		// The resources depend on the branch of the employee and on its class session
+		err = s.assignResource(ctx, appointment, service)
+		if err == nil {
+			err = s.book(ctx, appointment, service)
+		}
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return ErrAlreadyBooked
+		}
+		if !errors.Is(err, repository.ErrConflict) && !errors.Is(err, repository.ErrNoCapacity) && !errors.Is(err, ErrSlotUnavailable) {
+			return fmt.Errorf("failed to create appointment: %w", err)
+		}
+
+		// The employee or the resources were booked concurrently or the session is full, try the remaining ones
+		candidates = removeEmployee(candidates, employee.ID)
+	}
+
+	appointment.EmployeeID = 0
+	appointment.Employee = nil
+	appointment.ResourceID = nil
+	if locationID == 0 {
+		appointment.LocationID = nil
+	}
+	return ErrSlotUnavailable
+}
+
 func (s *appointmentService) Get(ctx context.Context, id int) (*entity.Appointment, error) {
 	appointment, err := s.repos.Appointment.Get(ctx, id)
 	if err != nil {
@@ -96,59 +259,99 @@ func (s *appointmentService) Get(ctx context.Context, id int) (*entity.Appointme
 }
 
 func (s *appointmentService) Update(ctx context.Context, appointment *entity.Appointment) error {
-	panic("implement me")
-	//// Verify appointment exists and get current data
-	//existing, err := s.repos.Appointment.Get(ctx, appointment.ID)
-	//if err != nil {
-	//	return fmt.Errorf("invalid appointment: %w", err)
-	//}
-	//
-	//// Only allow updates for scheduled appointments
-	//if existing.Status != entity.AppointmentStatusScheduled {
-	//	return fmt.Errorf("can only update scheduled appointments")
-	//}
-	//
-	//// Cannot update past appointments
-	//if existing.StartTime.Before(time.Now()) {
-	//	return fmt.Errorf("cannot update past appointments")
-	//}
-	//
-	//// Maintain original IDs and creation data
-	//appointment.BusinessID = existing.BusinessID
-	//appointment.ClientID = existing.ClientID
-	//appointment.EmployeeID = existing.EmployeeID
-	//appointment.ServiceID = existing.ServiceID
-	//appointment.CreatedAt = existing.CreatedAt
-	//
-	//// Get service duration for validation
-	//service, err := s.repos.Service.Get(ctx, appointment.ServiceID)
-	//if err != nil {
-	//	return fmt.Errorf("failed to get service details: %w", err)
-	//}
-	//
-	//// Validate new appointment time
-	//if err := s.validateAppointmentTime(ctx, appointment, service.Duration); err != nil {
-	//	return fmt.Errorf("invalid appointment time: %w", err)
-	//}
-	//
-	//// Update appointment
-	//if err := s.repos.Appointment.Update(ctx, appointment); err != nil {
-	//	return fmt.Errorf("failed to update appointment: %w", err)
-	//}
-	//
-	//return nil
-}
-
-func (s *appointmentService) Cancel(ctx context.Context, id int) error {
+	// Verify appointment exists and get current data
+	existing, err := s.repos.Appointment.Get(ctx, appointment.ID)
+	if err != nil {
+		return fmt.Errorf("invalid appointment: %w", err)
+	}
+
+	// Only allow updates for scheduled appointments
+	if existing.Status != entity.AppointmentStatusScheduled {
+		return fmt.Errorf("can only update scheduled appointments")
+	}
+
+	// Cannot update past appointments
+	if existing.StartTime.Before(time.Now()) {
+		return fmt.Errorf("cannot update past appointments")
+	}
+
+	// A seat belongs to its session, moving it means booking another session
+	if existing.SessionID != nil {
+		return fmt.Errorf("class bookings cannot be rescheduled, cancel and book another session")
+	}
+
+	// Keep the current employee unless a different one is requested
+	if appointment.EmployeeID != 0 && appointment.EmployeeID != existing.EmployeeID {
+		employee, err := s.repos.Employee.Get(ctx, appointment.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("invalid employee: %w", err)
+		}
+		if !employee.IsActive {
+			return fmt.Errorf("employee is not active")
+		}
+		if employee.BusinessID != existing.BusinessID {
+			return fmt.Errorf("employee does not belong to the business")
+		}
+
+		if err := s.validateServiceAssignment(ctx, appointment.EmployeeID, existing.ServiceID); err != nil {
+			return err
+		}
+
+		existing.EmployeeID = appointment.EmployeeID
+		existing.Employee = employee.User
+		existing.LocationID = employee.LocationID
+	}
+
+	// Keep the reminder setting unless a new one is provided
+	if appointment.ReminderTime != nil {
+		existing.ReminderTime = appointment.ReminderTime
+	}
+
+	// Get service duration for validation
+	service, err := s.repos.Service.Get(ctx, existing.ServiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service details: %w", err)
+	}
+
+	existing.StartTime = appointment.StartTime
+	existing.EndTime = appointment.StartTime.Add(time.Duration(service.Duration) * time.Minute)
+
+	loc, err := scheduleLocation(ctx, s.repos, existing.BusinessID, existing.LocationID)
+	if err != nil {
+		return err
+	}
+
+	// Validate new appointment time
+	if err := s.validateAppointmentTime(ctx, existing, service, loc); err != nil {
+		return fmt.Errorf("invalid appointment time: %w", err)
+	}
+
+	// Keep the resource when it is free at the new time, otherwise book another one
+	if err := s.assignResource(ctx, existing, service); err != nil {
+		return fmt.Errorf("invalid appointment time: %w", err)
+	}
+
+	// Update appointment
+	if err := s.repos.Appointment.Update(ctx, existing); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrSlotUnavailable
+		}
+		return fmt.Errorf("failed to update appointment: %w", err)
+	}
+
+	*appointment = *existing
+	return nil
+}
+
+func (s *appointmentService) Cancel(ctx context.Context, id int, reason string) error {
 	// Verify appointment exists
 	appointment, err := s.repos.Appointment.Get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("invalid appointment: %w", err)
 	}
 
-	// Only allow cancellation of scheduled appointments
-	if appointment.Status != entity.AppointmentStatusScheduled {
-		return fmt.Errorf("can only cancel scheduled appointments")
+	if err := validateStatusTransition(appointment.Status, entity.AppointmentStatusCancelled); err != nil {
+		return err
 	}
 
 	// Cannot cancel past appointments
@@ -156,11 +359,47 @@ func (s *appointmentService) Cancel(ctx context.Context, id int) error {
 		return fmt.Errorf("cannot cancel past appointments")
 	}
 
-	// Cancel appointment
-	if err := s.repos.Appointment.Delete(ctx, id); err != nil {
+	// Cancel appointment, keeping the record for history
+	if err := s.repos.Appointment.Cancel(ctx, id, reason); err != nil {
 		return fmt.Errorf("failed to cancel appointment: %w", err)
 	}
 
+	// The cancellation stands even when the freed slot cannot be offered
+	if err := s.offerToWaitlist(ctx, appointment); err != nil {
+		log.Printf("failed to offer appointment %d to waitlist: %v", id, err)
+	}
+
+	return nil
+}
+
+func (s *appointmentService) Complete(ctx context.Context, id int) error {
+	return s.finishAppointment(ctx, id, entity.AppointmentStatusCompleted)
+}
+
+func (s *appointmentService) MarkNoShow(ctx context.Context, id int) error {
+	return s.finishAppointment(ctx, id, entity.AppointmentStatusNoShow)
+}
+
+// finishAppointment records the outcome of an appointment that has already started
+func (s *appointmentService) finishAppointment(ctx context.Context, id int, status string) error {
+	appointment, err := s.repos.Appointment.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("invalid appointment: %w", err)
+	}
+
+	if err := validateStatusTransition(appointment.Status, status); err != nil {
+		return err
+	}
+
+	// The outcome is only known once the appointment has started
+	if appointment.StartTime.After(time.Now()) {
+		return fmt.Errorf("appointment has not started yet")
+	}
+
+	if err := s.repos.Appointment.UpdateStatus(ctx, id, appointment.Status, status); err != nil {
+		return fmt.Errorf("failed to update appointment status: %w", err)
+	}
+
 	return nil
 }
 
@@ -218,164 +457,496 @@ func (s *appointmentService) ListByEmployee(ctx context.Context, employeeID int,
 	return appointments, nil
 }
 
-func (s *appointmentService) GetAvailableSlots(ctx context.Context, employeeID int, serviceID int, date time.Time) ([]TimeSlot, error) {
-	// Validate employee and service
-	employee, err := s.repos.Employee.Get(ctx, employeeID)
+func (s *appointmentService) GetAvailableSlots(ctx context.Context, employeeID int, serviceID int, locationID int, date time.Time) ([]TimeSlot, error) {
+	days, err := s.GetAvailability(ctx, employeeID, serviceID, locationID, date, date)
 	if err != nil {
-		return nil, fmt.Errorf("invalid employee: %w", err)
+		return nil, err
 	}
-	if !employee.IsActive {
-		return nil, fmt.Errorf("employee is not active")
+
+	return days[0].Slots, nil
+}
+
+func (s *appointmentService) GetServiceSlots(ctx context.Context, serviceID int, locationID int, date time.Time) ([]TimeSlot, error) {
+	days, err := s.GetAvailability(ctx, 0, serviceID, locationID, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return days[0].Slots, nil
+}
+
+func (s *appointmentService) GetAvailability(ctx context.Context, employeeID int, serviceID int, locationID int, startDate, endDate time.Time) ([]DayAvailability, error) {
+	if err := validateDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	service, employees, err := s.getSlotEmployees(ctx, employeeID, serviceID, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates are calendar days of the branch or of the business
+	loc, err := s.slotLocation(ctx, service, employees, employeeID, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.collectAvailability(ctx, service, employees, dateIn(startDate, loc), dateIn(endDate, loc))
+}
+
+func (s *appointmentService) GetNextAvailableSlot(ctx context.Context, employeeID int, serviceID int, locationID int, from time.Time) (*TimeSlot, error) {
+	service, employees, err := s.getSlotEmployees(ctx, employeeID, serviceID, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.slotLocation(ctx, service, employees, employeeID, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a start date the search begins today in the timezone of the branch or of the business
+	if from.IsZero() {
+		from = time.Now().In(loc)
+	}
+
+	// Scan forward in batches of the maximum range until a slot is found
+	from = dateIn(from, loc)
+	for offset := 0; offset < nextAvailableHorizonDays; offset += maxRangeDays {
+		batchStart := from.AddDate(0, 0, offset)
+		batchEnd := batchStart.AddDate(0, 0, min(maxRangeDays, nextAvailableHorizonDays-offset)-1)
+
+		days, err := s.collectAvailability(ctx, service, employees, batchStart, batchEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, day := range days {
+			if len(day.Slots) > 0 {
+				return &day.Slots[0], nil
+			}
+		}
+	}
+
+	return nil, ErrNoAvailableSlots
+}
+
+// getSlotEmployees validates the service and returns the employees whose slots are offered for it:
+// the requested employee, or every active employee providing the service when employeeID is 0.
+// A locationID other than 0 limits the employees to the branch.
+func (s *appointmentService) getSlotEmployees(ctx context.Context, employeeID int, serviceID int, locationID int) (*entity.BusinessService, []entity.Employee, error) {
+	var employee *entity.Employee
+	if employeeID != 0 {
+		var err error
+		employee, err = s.repos.Employee.Get(ctx, employeeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid employee: %w", err)
+		}
+		if !employee.IsActive {
+			return nil, nil, fmt.Errorf("employee is not active")
+		}
 	}
 
 	service, err := s.repos.Service.Get(ctx, serviceID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid service: %w", err)
+		return nil, nil, fmt.Errorf("invalid service: %w", err)
 	}
 	if !service.IsActive {
-		return nil, fmt.Errorf("service is not active")
+		return nil, nil, fmt.Errorf("service is not active")
 	}
 
-	// Check if service is assigned to employee
-	employeeServices, err := s.repos.Employee.GetServices(ctx, employeeID)
+	if locationID != 0 {
+		if _, err := getBusinessLocation(ctx, s.repos, service.BusinessID, locationID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if employeeID != 0 {
+		if locationID != 0 && !sameLocation(employee.LocationID, locationID) {
+			return nil, nil, fmt.Errorf("%w: employee does not work at the location", ErrInvalidLocation)
+		}
+
+		// Check if service is assigned to employee
+		if err := s.validateServiceAssignment(ctx, employeeID, serviceID); err != nil {
+			return nil, nil, err
+		}
+		return service, []entity.Employee{*employee}, nil
+	}
+
+	employees, err := s.listServiceEmployees(ctx, service, locationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service, employees, nil
+}
+
+// slotLocation returns the timezone the dates of the slots are in: the timezone of the requested branch,
+// of the branch of the requested employee, or of the business
+func (s *appointmentService) slotLocation(ctx context.Context, service *entity.BusinessService, employees []entity.Employee, employeeID int, locationID int) (*time.Location, error) {
+	switch {
+	case locationID != 0:
+		return scheduleLocation(ctx, s.repos, service.BusinessID, &locationID)
+	case employeeID != 0:
+		return scheduleLocation(ctx, s.repos, service.BusinessID, employees[0].LocationID)
+	default:
+		return businessLocation(ctx, s.repos, service.BusinessID)
+	}
+}
+
+// employeeLocation returns the timezone of the employee's branch, or businessLoc for employees without one
+func (s *appointmentService) employeeLocation(ctx context.Context, employee *entity.Employee, businessLoc *time.Location) (*time.Location, error) {
+	if employee.LocationID == nil {
+		return businessLoc, nil
+	}
+
+	return scheduleLocation(ctx, s.repos, employee.BusinessID, employee.LocationID)
+}
+
+// collectAvailability combines the free slots of the employees for every day in the range,
+// keeping each start time once per day. The free seats of group sessions starting at the same time
+// are added up. Slots of services that need a resource are only kept while one is free.
+func (s *appointmentService) collectAvailability(ctx context.Context, service *entity.BusinessService, employees []entity.Employee, startDate, endDate time.Time) ([]DayAvailability, error) {
+	var days []DayAvailability
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		days = append(days, DayAvailability{
+			Date:  day.Format("2006-01-02"),
+			Slots: []TimeSlot{},
+		})
+	}
+
+	pool, err := s.loadResourcePool(ctx, service, startDate, endDate.AddDate(0, 0, 1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to check employee services: %w", err)
+		return nil, err
 	}
-	var serviceAssigned bool
-	for _, s := range employeeServices {
-		if s.ID == serviceID {
-			serviceAssigned = true
-			break
+
+	// Position of each start time among the slots of its day
+	seen := make(map[time.Time]int)
+	for _, employee := range employees {
+		employeeDays, err := s.employeeSlots(ctx, employee.ID, service, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, slots := range employeeDays {
+			for _, slot := range pool.filter(employee, service, slots) {
+				if j, ok := seen[slot.StartTime]; ok {
+					days[i].Slots[j].RemainingSeats += slot.RemainingSeats
+					continue
+				}
+				seen[slot.StartTime] = len(days[i].Slots)
+				days[i].Slots = append(days[i].Slots, slot)
+			}
 		}
 	}
-	if !serviceAssigned {
-		return nil, fmt.Errorf("service is not assigned to employee")
+
+	for i := range days {
+		sort.Slice(days[i].Slots, func(a, b int) bool {
+			return days[i].Slots[a].StartTime.Before(days[i].Slots[b].StartTime)
+		})
 	}
 
-	// Get schedule for the date
-	schedules, err := s.repos.Schedule.GetEmployeeSchedule(ctx, employeeID, date)
+	return days, nil
+}
+
+// employeeSlots returns the free slots of an employee for the service on each day in the range.
+// The schedule and appointments are loaded once for the whole range.
+func (s *appointmentService) employeeSlots(ctx context.Context, employeeID int, service *entity.BusinessService, startDate, endDate time.Time) ([][]TimeSlot, error) {
+	// Resolve working hours for every day
+	workingDays, err := getWorkingDays(ctx, s.repos, service.BusinessID, employeeID, startDate, endDate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get employee schedule: %w", err)
+		return nil, err
 	}
 
-	if schedules == nil {
-		return nil, fmt.Errorf("no schedule found for the date")
+	slots := make([][]TimeSlot, len(workingDays))
+	working := false
+	for _, day := range workingDays {
+		working = working || len(day.Intervals) > 0
+	}
+	if !working {
+		return slots, nil
 	}
 
 	// Get existing appointments
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-	appointments, err := s.repos.Appointment.ListByEmployee(ctx, employeeID, startOfDay, endOfDay)
+	appointments, err := s.repos.Appointment.ListByEmployee(ctx, employeeID, startDate, endDate.AddDate(0, 0, 1))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get existing appointments: %w", err)
 	}
 
 	// Generate available slots
-	slots := generateAvailableSlots(schedules, appointments, service.Duration, date)
+	now := time.Now()
+	appointments = withoutExpiredHolds(appointments, now)
+	earliest, latest := bookingWindow(service, now)
+	for i, day := range workingDays {
+		slots[i] = generateAvailableSlots(day.Intervals, appointments, day.Busy, service, earliest, latest)
+	}
+
 	return slots, nil
 }
 
-func (s *appointmentService) validateAppointmentTime(ctx context.Context, appointment *entity.Appointment, serviceDuration int) error {
-	// Appointment must be in the future
-	if appointment.StartTime.Before(time.Now()) {
-		return fmt.Errorf("cannot create appointments in the past")
+// listServiceEmployees returns the active employees of the service's business that provide it, sorted by ID.
+// A locationID other than 0 limits them to the employees of the branch.
+func (s *appointmentService) listServiceEmployees(ctx context.Context, service *entity.BusinessService, locationID int) ([]entity.Employee, error) {
+	employees, err := s.repos.Employee.ListByServiceID(ctx, service.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service employees: %w", err)
 	}
 
-	// Calculate end time based on service duration if not provided
-	if appointment.EndTime.IsZero() {
-		appointment.EndTime = appointment.StartTime.Add(time.Duration(serviceDuration) * time.Minute)
+	var result []entity.Employee
+	for _, employee := range employees {
+		if locationID != 0 && !sameLocation(employee.LocationID, locationID) {
+			continue
+		}
+		if employee.IsActive && employee.BusinessID == service.BusinessID {
+			result = append(result, employee)
+		}
 	}
 
-	// Validate time slot duration matches service duration
-	duration := int(appointment.EndTime.Sub(appointment.StartTime).Minutes())
-	if duration != serviceDuration {
-		return fmt.Errorf("appointment duration must match service duration")
-	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
 
-	// Check employee schedule
-	date := appointment.StartTime.Truncate(24 * time.Hour)
-	schedules, err := s.repos.Schedule.GetEmployeeSchedule(ctx, appointment.EmployeeID, date)
-	if err != nil {
-		return fmt.Errorf("failed to check employee schedule: %w", err)
+	return result, nil
+}
+
+func (s *appointmentService) validateAppointmentTime(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService, loc *time.Location) error {
+	if err := validateAppointmentPeriod(appointment, service, time.Now()); err != nil {
+		return err
 	}
 
-	if schedules == nil || !isTimeSlotInSchedule(appointment.StartTime, appointment.EndTime, schedules) {
-		return fmt.Errorf("appointment time is outside employee's working hours")
+	return s.checkEmployeeTime(ctx, appointment.EmployeeID, service, appointment.StartTime, appointment.EndTime, appointment.ID, loc)
+}
+
+// checkEmployeeTime verifies the employee works during the whole period and has no appointments
+// overlapping it, including the buffers of the service and of the existing appointments.
+// Working hours are resolved for the local date of the start time in loc, the timezone of the employee's schedule.
+func (s *appointmentService) checkEmployeeTime(ctx context.Context, employeeID int, service *entity.BusinessService, startTime, endTime time.Time, excludeID int, loc *time.Location) error {
+	if err := s.checkWorkingTime(ctx, employeeID, service, startTime, endTime, loc); err != nil {
+		return err
 	}
 
-	// Check for overlapping appointments
-	isAvailable, err := s.repos.Appointment.IsEmployeeAvailable(ctx, appointment.EmployeeID, appointment.StartTime, appointment.EndTime)
+	// Check for overlapping appointments. Other seats of the same class session do not overlap.
+	blockedStart, blockedEnd := withBuffers(service, startTime, endTime)
+
+	var (
+		isAvailable bool
+		err         error
+	)
+	if isGroupService(service) {
+		isAvailable, err = s.repos.Appointment.IsEmployeeAvailableForSession(ctx, employeeID, service.ID, startTime, blockedStart, blockedEnd)
+	} else {
+		isAvailable, err = s.repos.Appointment.IsEmployeeAvailable(ctx, employeeID, blockedStart, blockedEnd, excludeID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to check employee availability: %w", err)
 	}
 	if !isAvailable {
-		return fmt.Errorf("time slot is not available")
+		return ErrSlotUnavailable
 	}
 
 	return nil
 }
 
-func validateDateRange(startTime, endTime time.Time) error {
-	if endTime.Before(startTime) {
-		return fmt.Errorf("end time must be after start time")
+// checkWorkingTime verifies the employee works during the whole period in loc
+// and has no imported busy time overlapping it with the buffers of the service
+func (s *appointmentService) checkWorkingTime(ctx context.Context, employeeID int, service *entity.BusinessService, startTime, endTime time.Time, loc *time.Location) error {
+	// Check employee working hours, including overrides and breaks
+	schedule, err := getWorkingDay(ctx, s.repos, service.BusinessID, employeeID, startTime.In(loc))
+	if err != nil {
+		return fmt.Errorf("failed to check employee schedule: %w", err)
 	}
 
-	maxRange := 31 * 24 * time.Hour // 31 days
-	if endTime.Sub(startTime) > maxRange {
-		return fmt.Errorf("date range cannot exceed 31 days")
+	if !isWithinIntervals(startTime, endTime, schedule.Intervals) {
+		return errOutsideWorkingHours
+	}
+
+	blockedStart, blockedEnd := withBuffers(service, startTime, endTime)
+	if overlapsAny(blockedStart, blockedEnd, schedule.Busy) {
+		return ErrSlotUnavailable
 	}
 
 	return nil
 }
 
-func isTimeSlotInSchedule(start, end time.Time, schedule *entity.ScheduleTemplate) bool {
-	startTime := time.Date(start.Year(), start.Month(), start.Day(), schedule.StartTime.Hour(), schedule.StartTime.Minute(), 0, 0, start.Location()).Add(-1 * time.Minute)
-	endTime := time.Date(end.Year(), end.Month(), end.Day(), schedule.EndTime.Hour(), schedule.EndTime.Minute(), 0, 0, end.Location()).Add(1 * time.Minute)
-	if !schedule.IsBreak && start.After(startTime) && end.Before(endTime) {
-		return true
+func (s *appointmentService) validateServiceAssignment(ctx context.Context, employeeID, serviceID int) error {
+	employeeServices, err := s.repos.Employee.GetServices(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to check employee services: %w", err)
+	}
+
+	for _, service := range employeeServices {
+		if service.ID == serviceID {
+			return nil
+		}
 	}
-	return false
+
+	return fmt.Errorf("service is not assigned to employee")
 }
 
-func generateAvailableSlots(schedule *entity.ScheduleTemplate, appointments []entity.Appointment, duration int, date time.Time) []TimeSlot {
-	if schedule.IsBreak {
-		return nil
+func validateAppointmentPeriod(appointment *entity.Appointment, service *entity.BusinessService, now time.Time) error {
+	// Appointment must be in the future
+	if appointment.StartTime.Before(now) {
+		return fmt.Errorf("cannot create appointments in the past")
 	}
 
-	var availableSlots []TimeSlot
-	slotDuration := time.Duration(duration) * time.Minute
+	// Appointment must fit the booking window of the service
+	earliest, latest := bookingWindow(service, now)
+	if appointment.StartTime.Before(earliest) {
+		return fmt.Errorf("appointment must be booked at least %d minutes in advance", service.MinNotice)
+	}
+	if !latest.IsZero() && appointment.StartTime.After(latest) {
+		return fmt.Errorf("appointment cannot be booked more than %d days in advance", service.MaxAdvanceDays)
+	}
 
-	now := time.Now().Add(15 * time.Minute).UTC()
+	// Calculate end time based on service duration if not provided
+	if appointment.EndTime.IsZero() {
+		appointment.EndTime = appointment.StartTime.Add(time.Duration(service.Duration) * time.Minute)
+	}
 
-	// Start iterating from the start time of the schedule to the end time
-	currentStartTime := time.Date(date.Year(), date.Month(), date.Day(), schedule.StartTime.Hour(), schedule.StartTime.Minute(), 0, 0, now.Location())
-	endTime := time.Date(date.Year(), date.Month(), date.Day(), schedule.EndTime.Hour(), schedule.EndTime.Minute(), 0, 0, now.Location())
-	for currentStartTime.Add(slotDuration).Before(endTime) || currentStartTime.Add(slotDuration).Equal(endTime) {
-		currentEndTime := currentStartTime.Add(slotDuration)
-		if currentEndTime.Before(now) {
-			continue
+	// Validate time slot duration matches service duration
+	duration := int(appointment.EndTime.Sub(appointment.StartTime).Minutes())
+	if duration != service.Duration {
+		return fmt.Errorf("appointment duration must match service duration")
+	}
+
+	return nil
+}
+
+// bookingWindow returns the earliest and latest start times the service can be booked for at the given moment.
+// The latest time is zero when the service has no advance limit.
+func bookingWindow(service *entity.BusinessService, now time.Time) (time.Time, time.Time) {
+	earliest := now.Add(time.Duration(service.MinNotice) * time.Minute)
+
+	var latest time.Time
+	if service.MaxAdvanceDays > 0 {
+		latest = now.AddDate(0, 0, service.MaxAdvanceDays)
+	}
+
+	return earliest, latest
+}
+
+// isGroupService reports whether the service is booked by seats in shared class sessions
+func isGroupService(service *entity.BusinessService) bool {
+	return service.Capacity > 1
+}
+
+// withBuffers extends the period by the time the service blocks before and after it
+func withBuffers(service *entity.BusinessService, startTime, endTime time.Time) (time.Time, time.Time) {
+	if service == nil {
+		return startTime, endTime
+	}
+
+	return startTime.Add(-time.Duration(service.BufferBefore) * time.Minute),
+		endTime.Add(time.Duration(service.BufferAfter) * time.Minute)
+}
+
+func removeEmployee(employees []entity.Employee, id int) []entity.Employee {
+	result := make([]entity.Employee, 0, len(employees))
+	for _, employee := range employees {
+		if employee.ID != id {
+			result = append(result, employee)
 		}
+	}
+	return result
+}
+
+func validateStatusTransition(from, to string) error {
+	for _, status := range appointmentTransitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+}
+
+func validateDateRange(startTime, endTime time.Time) error {
+	if endTime.Before(startTime) {
+		return fmt.Errorf("end time must be after start time")
+	}
+
+	maxRange := maxRangeDays * 24 * time.Hour
+	if endTime.Sub(startTime) > maxRange {
+		return fmt.Errorf("date range cannot exceed %d days", maxRangeDays)
+	}
+
+	return nil
+}
 
-		// Check if this slot conflicts with any appointment
-		conflict := false
-		for _, appointment := range appointments {
-			if appointment.StartTime.Before(currentEndTime) && appointment.EndTime.After(currentStartTime) {
-				conflict = true
+// generateAvailableSlots splits the working intervals into slots of the service duration, starting
+// a slot every slot interval. Slots outside the booking window or overlapping an appointment are
+// skipped, with the buffers of both the service and the appointments taken into account.
+// For group services the seats of the session starting at the slot are counted instead,
+// and the slot is offered while seats remain.
+func generateAvailableSlots(intervals []TimeSlot, appointments []entity.Appointment, busy []TimeSlot, service *entity.BusinessService, earliest, latest time.Time) []TimeSlot {
+	availableSlots := []TimeSlot{}
+	slotDuration := time.Duration(service.Duration) * time.Minute
+	step := time.Duration(service.SlotInterval) * time.Minute
+	if step <= 0 {
+		step = slotDuration
+	}
+	if slotDuration <= 0 {
+		return availableSlots
+	}
+
+	for _, interval := range intervals {
+		for start := interval.StartTime; !start.Add(slotDuration).After(interval.EndTime); start = start.Add(step) {
+			end := start.Add(slotDuration)
+			if start.Before(earliest) {
+				continue
+			}
+			if !latest.IsZero() && start.After(latest) {
 				break
 			}
-		}
 
-		// If no conflict, add the slot to available slots
-		if !conflict {
-			availableSlots = append(availableSlots, TimeSlot{
-				StartTime: currentStartTime,
-				EndTime:   currentEndTime,
-			})
-		}
+			// Check if this slot conflicts with imported busy time or any appointment
+			blockedStart, blockedEnd := withBuffers(service, start, end)
+			if overlapsAny(blockedStart, blockedEnd, busy) {
+				continue
+			}
+			conflict := false
+			bookedSeats := 0
+			for _, appointment := range appointments {
+				// Cancelled appointments no longer hold their time
+				if appointment.Status == entity.AppointmentStatusCancelled {
+					continue
+				}
+				if isSessionSeat(appointment, service, start) {
+					bookedSeats++
+					continue
+				}
+				appointmentStart, appointmentEnd := withBuffers(appointment.Service, appointment.StartTime, appointment.EndTime)
+				if appointmentStart.Before(blockedEnd) && appointmentEnd.After(blockedStart) {
+					conflict = true
+					break
+				}
+			}
 
-		// Move to the next time slot
-		currentStartTime = currentEndTime
+			if conflict {
+				continue
+			}
+
+			slot := TimeSlot{
+				StartTime: start,
+				EndTime:   end,
+			}
+			if isGroupService(service) {
+				slot.RemainingSeats = service.Capacity - bookedSeats
+				if slot.RemainingSeats <= 0 {
+					continue
+				}
+			}
+			availableSlots = append(availableSlots, slot)
+		}
 	}
 
 	return availableSlots
 }
+
+// isSessionSeat reports whether the appointment is a seat of the group service session starting at start
+func isSessionSeat(appointment entity.Appointment, service *entity.BusinessService, start time.Time) bool {
+	return isGroupService(service) &&
+		appointment.SessionID != nil &&
+		appointment.ServiceID == service.ID &&
+		appointment.StartTime.Equal(start)
+}
@@ -0,0 +1,1803 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestAppointmentService_Create(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		businessRepo    *mocks.BusinessRepository
+		userRepo        *mocks.UserRepository
+		employeeRepo    *mocks.EmployeeRepository
+		serviceRepo     *mocks.BusinessServiceRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type args struct {
+		appointment *entity.Appointment
+	}
+
+	type expected struct {
+		err        error
+		employeeID int
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	firstEmployeeID := 1
+	secondEmployeeID := 2
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, MinNotice: 15, MaxAdvanceDays: 30, IsActive: true}
+	client := &entity.User{ID: clientID, BusinessID: businessID, Role: entity.RoleClient}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+	employees := []entity.Employee{
+		{ID: secondEmployeeID, BusinessID: businessID, IsActive: true},
+		{ID: firstEmployeeID, BusinessID: businessID, IsActive: true},
+		{ID: 3, BusinessID: businessID, IsActive: false},
+		{ID: 4, BusinessID: 2, IsActive: true},
+	}
+
+	ctx := context.Background()
+
+	// mockFree sets up the working hours and availability of an employee for the appointment time
+	mockFree := func(m mocksForExecution, employeeID int, free bool) {
+		m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, start).Return(schedule, nil)
+		m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, start, end, 0).Return(free, nil)
+	}
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: appointment with requested employee",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, secondEmployeeID).Return(&employees[0], nil)
+				m.employeeRepo.On("GetServices", ctx, secondEmployeeID).Return([]entity.BusinessService{*service}, nil)
+				mockFree(m, secondEmployeeID, true)
+				m.appointmentRepo.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, EmployeeID: secondEmployeeID, ServiceID: serviceID, StartTime: start},
+			},
+			expected: expected{
+				employeeID: secondEmployeeID,
+			},
+		},
+		{
+			name: "positive: first free employee assigned",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("ListByServiceID", ctx, serviceID).Return(employees, nil)
+				mockFree(m, firstEmployeeID, false)
+				mockFree(m, secondEmployeeID, true)
+				m.appointmentRepo.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, StartTime: start},
+			},
+			expected: expected{
+				employeeID: secondEmployeeID,
+			},
+		},
+		{
+			name: "positive: next employee assigned when booked concurrently",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("ListByServiceID", ctx, serviceID).Return(employees, nil)
+				mockFree(m, firstEmployeeID, true)
+				mockFree(m, secondEmployeeID, true)
+				m.appointmentRepo.On("Create", ctx, mock.MatchedBy(func(a *entity.Appointment) bool {
+					return a.EmployeeID == firstEmployeeID
+				})).Return(fmt.Errorf("failed to create appointment: %w", repository.ErrConflict)).Once()
+				m.appointmentRepo.On("Create", ctx, mock.MatchedBy(func(a *entity.Appointment) bool {
+					return a.EmployeeID == secondEmployeeID
+				})).Return(nil).Once()
+			},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, StartTime: start},
+			},
+			expected: expected{
+				employeeID: secondEmployeeID,
+			},
+		},
+		{
+			name: "negative: requested employee busy with an imported event",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, secondEmployeeID).Return(&employees[0], nil)
+				m.employeeRepo.On("GetServices", ctx, secondEmployeeID).Return([]entity.BusinessService{*service}, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, secondEmployeeID, start).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, secondEmployeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, secondEmployeeID, mock.Anything, mock.Anything).Return([]entity.BusyTime{
+					{EmployeeID: secondEmployeeID, StartTime: start.Add(-time.Hour), EndTime: start.Add(15 * time.Minute)},
+				}, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, EmployeeID: secondEmployeeID, ServiceID: serviceID, StartTime: start},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", services.ErrSlotUnavailable),
+			},
+		},
+		{
+			name: "negative: no free employee",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("ListByServiceID", ctx, serviceID).Return(employees, nil)
+				mockFree(m, firstEmployeeID, false)
+				mockFree(m, secondEmployeeID, false)
+			},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, StartTime: start},
+			},
+			expected: expected{
+				err: services.ErrSlotUnavailable,
+			},
+		},
+		{
+			name: "negative: appointment in the past",
+			mock: func(m mocksForExecution) {},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, StartTime: now.Add(-time.Hour)},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("cannot create appointments in the past")),
+			},
+		},
+		{
+			name: "negative: booked with less than the minimum notice",
+			mock: func(m mocksForExecution) {},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, StartTime: now.Add(10 * time.Minute)},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("appointment must be booked at least 15 minutes in advance")),
+			},
+		},
+		{
+			name: "negative: booked beyond the advance window",
+			mock: func(m mocksForExecution) {},
+			args: args{
+				appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, StartTime: start.AddDate(0, 0, 60)},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("appointment cannot be booked more than 30 days in advance")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			userRepoMock := mocks.NewUserRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			userRepoMock.On("Get", ctx, clientID).Return(client, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				businessRepo:    businessRepoMock,
+				userRepo:        userRepoMock,
+				employeeRepo:    employeeRepoMock,
+				serviceRepo:     serviceRepoMock,
+				scheduleRepo:    scheduleRepoMock,
+			})
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				User:        userRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, services.NewFirstFreeStrategy(), nil, nil)
+
+			// Execute
+			err := appointmentService.Create(ctx, tc.args.appointment)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected.employeeID, tc.args.appointment.EmployeeID)
+				assert.Equal(t, end, tc.args.appointment.EndTime)
+				assert.Equal(t, entity.AppointmentStatusScheduled, tc.args.appointment.Status)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_CreateGroupBooking(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err error
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	employeeID := 1
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(60 * time.Minute)
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 60, Capacity: 10, IsActive: true}
+	client := &entity.User{ID: clientID, BusinessID: businessID, Role: entity.RoleClient}
+	employee := &entity.Employee{ID: employeeID, BusinessID: businessID, IsActive: true}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m *mocks.AppointmentRepository)
+		expected expected
+	}{
+		{
+			name: "positive: seat booked in the session",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSession", ctx, employeeID, serviceID, start, start, end).Return(true, nil)
+				m.On("CreateInSession", ctx, mock.Anything, 10).Return(nil)
+			},
+		},
+		{
+			name: "negative: session is full",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSession", ctx, employeeID, serviceID, start, start, end).Return(true, nil)
+				m.On("CreateInSession", ctx, mock.Anything, 10).Return(repository.ErrNoCapacity)
+			},
+			expected: expected{
+				err: services.ErrSessionFull,
+			},
+		},
+		{
+			name: "negative: client already in the session",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSession", ctx, employeeID, serviceID, start, start, end).Return(true, nil)
+				m.On("CreateInSession", ctx, mock.Anything, 10).Return(fmt.Errorf("failed to create appointment: %w", repository.ErrAlreadyExists))
+			},
+			expected: expected{
+				err: services.ErrAlreadyBooked,
+			},
+		},
+		{
+			name: "negative: employee busy with another appointment",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSession", ctx, employeeID, serviceID, start, start, end).Return(false, nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", services.ErrSlotUnavailable),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			userRepoMock := mocks.NewUserRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			userRepoMock.On("Get", ctx, clientID).Return(client, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+			scheduleRepoMock.On("GetEmployeeSchedule", ctx, employeeID, start).Return(schedule, nil)
+			scheduleRepoMock.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+			// Setup mocks
+			tc.mock(appointmentRepoMock)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				User:        userRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			appointment := &entity.Appointment{BusinessID: businessID, ClientID: clientID, EmployeeID: employeeID, ServiceID: serviceID, StartTime: start}
+			err := appointmentService.Create(ctx, appointment)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, end, appointment.EndTime)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_Update(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		businessRepo    *mocks.BusinessRepository
+		employeeRepo    *mocks.EmployeeRepository
+		serviceRepo     *mocks.BusinessServiceRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type args struct {
+		appointment *entity.Appointment
+	}
+
+	type expected struct {
+		err         error
+		appointment *entity.Appointment
+	}
+
+	appointmentID := 1
+	businessID := 1
+	employeeID := 1
+	otherEmployeeID := 2
+	serviceID := 1
+	reminderTime := 30
+
+	now := time.Now().UTC()
+	currentStart := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	newStart := currentStart.Add(3 * time.Hour)
+
+	// existingAppointment returns a fresh copy, since the service modifies the loaded appointment
+	existingAppointment := func() *entity.Appointment {
+		return &entity.Appointment{
+			ID:           appointmentID,
+			BusinessID:   businessID,
+			ClientID:     1,
+			EmployeeID:   employeeID,
+			ServiceID:    serviceID,
+			StartTime:    currentStart,
+			EndTime:      currentStart.Add(30 * time.Minute),
+			Status:       entity.AppointmentStatusScheduled,
+			ReminderTime: &reminderTime,
+		}
+	}
+
+	business := &entity.Business{ID: businessID, Timezone: "UTC"}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, IsActive: true}
+	schedule := []entity.ScheduleTemplate{
+		{
+			EmployeeID: employeeID,
+			StartTime:  time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:    time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: appointment rescheduled and reminder kept",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, newStart, newStart.Add(30*time.Minute), appointmentID).Return(true, nil)
+				m.appointmentRepo.On("Update", ctx, mock.Anything).Return(nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				appointment: &entity.Appointment{
+					ID:           appointmentID,
+					EmployeeID:   employeeID,
+					StartTime:    newStart,
+					EndTime:      newStart.Add(30 * time.Minute),
+					Status:       entity.AppointmentStatusScheduled,
+					ReminderTime: &reminderTime,
+				},
+			},
+		},
+		{
+			name: "positive: appointment moved to another employee",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.employeeRepo.On("Get", ctx, otherEmployeeID).Return(&entity.Employee{ID: otherEmployeeID, BusinessID: businessID, IsActive: true}, nil)
+				m.employeeRepo.On("GetServices", ctx, otherEmployeeID).Return([]entity.BusinessService{*service}, nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, otherEmployeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, otherEmployeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, otherEmployeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, otherEmployeeID, newStart, newStart.Add(30*time.Minute), appointmentID).Return(true, nil)
+				m.appointmentRepo.On("Update", ctx, mock.Anything).Return(nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, EmployeeID: otherEmployeeID, StartTime: newStart},
+			},
+			expected: expected{
+				appointment: &entity.Appointment{
+					ID:           appointmentID,
+					EmployeeID:   otherEmployeeID,
+					StartTime:    newStart,
+					EndTime:      newStart.Add(30 * time.Minute),
+					Status:       entity.AppointmentStatusScheduled,
+					ReminderTime: &reminderTime,
+				},
+			},
+		},
+		{
+			name: "negative: appointment not found",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(nil, repository.ErrNotFound)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment: %w", repository.ErrNotFound),
+			},
+		},
+		{
+			name: "negative: appointment is not scheduled",
+			mock: func(m mocksForExecution) {
+				cancelled := existingAppointment()
+				cancelled.Status = entity.AppointmentStatusCancelled
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(cancelled, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				err: fmt.Errorf("can only update scheduled appointments"),
+			},
+		},
+		{
+			name: "negative: appointment already started",
+			mock: func(m mocksForExecution) {
+				past := existingAppointment()
+				past.StartTime = now.Add(-time.Hour)
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(past, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				err: fmt.Errorf("cannot update past appointments"),
+			},
+		},
+		{
+			name: "negative: new employee does not provide the service",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.employeeRepo.On("Get", ctx, otherEmployeeID).Return(&entity.Employee{ID: otherEmployeeID, BusinessID: businessID, IsActive: true}, nil)
+				m.employeeRepo.On("GetServices", ctx, otherEmployeeID).Return([]entity.BusinessService{}, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, EmployeeID: otherEmployeeID, StartTime: newStart},
+			},
+			expected: expected{
+				err: fmt.Errorf("service is not assigned to employee"),
+			},
+		},
+		{
+			name: "negative: new time outside working hours",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: currentStart.Add(8 * time.Hour)},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("appointment time is outside employee's working hours")),
+			},
+		},
+		{
+			name: "negative: new date is a day off",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return([]entity.ScheduleOverride{
+					{EmployeeID: employeeID, OverrideDate: currentStart, IsWorkingDay: false},
+				}, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("appointment time is outside employee's working hours")),
+			},
+		},
+		{
+			name: "negative: new time overlaps another appointment",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, newStart, newStart.Add(30*time.Minute), appointmentID).Return(false, nil)
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("time slot is not available")),
+			},
+		},
+		{
+			name: "negative: slot taken concurrently",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(existingAppointment(), nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, newStart, newStart.Add(30*time.Minute), appointmentID).Return(true, nil)
+				m.appointmentRepo.On("Update", ctx, mock.Anything).Return(fmt.Errorf("failed to update appointment: %w", repository.ErrConflict))
+			},
+			args: args{
+				appointment: &entity.Appointment{ID: appointmentID, StartTime: newStart},
+			},
+			expected: expected{
+				err: services.ErrSlotUnavailable,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				businessRepo:    businessRepoMock,
+				employeeRepo:    employeeRepoMock,
+				serviceRepo:     serviceRepoMock,
+				scheduleRepo:    scheduleRepoMock,
+			})
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			err := appointmentService.Update(ctx, tc.args.appointment)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected.appointment.EmployeeID, tc.args.appointment.EmployeeID)
+				assert.Equal(t, tc.expected.appointment.StartTime, tc.args.appointment.StartTime)
+				assert.Equal(t, tc.expected.appointment.EndTime, tc.args.appointment.EndTime)
+				assert.Equal(t, tc.expected.appointment.Status, tc.args.appointment.Status)
+				assert.Equal(t, tc.expected.appointment.ReminderTime, tc.args.appointment.ReminderTime)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_Cancel(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		waitlistRepo    *mocks.WaitlistRepository
+	}
+
+	type args struct {
+		id     int
+		reason string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	appointmentID := 1
+	reason := "client is sick"
+	now := time.Now().UTC()
+
+	appointmentWith := func(status string, start time.Time) *entity.Appointment {
+		return &entity.Appointment{
+			ID:        appointmentID,
+			StartTime: start,
+			EndTime:   start.Add(30 * time.Minute),
+			Status:    status,
+		}
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: appointment cancelled with reason",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusScheduled, now.Add(24*time.Hour)), nil)
+				m.appointmentRepo.On("Cancel", ctx, appointmentID, reason).Return(nil)
+				m.waitlistRepo.On("ListMatching", ctx, 0, 0, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{id: appointmentID, reason: reason},
+		},
+		{
+			name: "negative: appointment not found",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(nil, repository.ErrNotFound)
+			},
+			args: args{id: appointmentID, reason: reason},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment: %w", repository.ErrNotFound),
+			},
+		},
+		{
+			name: "negative: appointment already cancelled",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusCancelled, now.Add(24*time.Hour)), nil)
+			},
+			args: args{id: appointmentID, reason: reason},
+			expected: expected{
+				err: fmt.Errorf("%w: cancelled -> cancelled", services.ErrInvalidStatusTransition),
+			},
+		},
+		{
+			name: "negative: completed appointment cannot be cancelled",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusCompleted, now.Add(-24*time.Hour)), nil)
+			},
+			args: args{id: appointmentID, reason: reason},
+			expected: expected{
+				err: fmt.Errorf("%w: completed -> cancelled", services.ErrInvalidStatusTransition),
+			},
+		},
+		{
+			name: "negative: past appointment",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusScheduled, now.Add(-time.Hour)), nil)
+			},
+			args: args{id: appointmentID, reason: reason},
+			expected: expected{
+				err: fmt.Errorf("cannot cancel past appointments"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			m := mocksForExecution{
+				appointmentRepo: mocks.NewAppointmentRepository(t),
+				waitlistRepo:    mocks.NewWaitlistRepository(t),
+			}
+
+			// Setup mocks
+			tc.mock(m)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: m.appointmentRepo,
+				Waitlist:    m.waitlistRepo,
+			}, nil, nil, nil)
+
+			// Execute
+			err := appointmentService.Cancel(ctx, tc.args.id, tc.args.reason)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_Complete(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err error
+	}
+
+	appointmentID := 1
+	now := time.Now().UTC()
+
+	appointmentWith := func(status string, start time.Time) *entity.Appointment {
+		return &entity.Appointment{
+			ID:        appointmentID,
+			StartTime: start,
+			EndTime:   start.Add(30 * time.Minute),
+			Status:    status,
+		}
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m *mocks.AppointmentRepository)
+		noShow   bool
+		expected expected
+	}{
+		{
+			name: "positive: appointment completed",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusScheduled, now.Add(-time.Hour)), nil)
+				m.On("UpdateStatus", ctx, appointmentID, entity.AppointmentStatusScheduled, entity.AppointmentStatusCompleted).Return(nil)
+			},
+		},
+		{
+			name: "positive: appointment marked as no-show",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusScheduled, now.Add(-time.Hour)), nil)
+				m.On("UpdateStatus", ctx, appointmentID, entity.AppointmentStatusScheduled, entity.AppointmentStatusNoShow).Return(nil)
+			},
+			noShow: true,
+		},
+		{
+			name: "negative: appointment has not started yet",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusScheduled, now.Add(time.Hour)), nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("appointment has not started yet"),
+			},
+		},
+		{
+			name: "negative: cancelled appointment cannot be completed",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusCancelled, now.Add(-time.Hour)), nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: cancelled -> completed", services.ErrInvalidStatusTransition),
+			},
+		},
+		{
+			name: "negative: no-show cannot be changed to completed",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusNoShow, now.Add(-time.Hour)), nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: no_show -> completed", services.ErrInvalidStatusTransition),
+			},
+		},
+		{
+			name: "negative: status changed concurrently",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(appointmentWith(entity.AppointmentStatusScheduled, now.Add(-time.Hour)), nil)
+				m.On("UpdateStatus", ctx, appointmentID, entity.AppointmentStatusScheduled, entity.AppointmentStatusCompleted).Return(repository.ErrNotFound)
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to update appointment status: %w", repository.ErrNotFound),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+
+			// Setup mocks
+			tc.mock(appointmentRepoMock)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			var err error
+			if tc.noShow {
+				err = appointmentService.MarkNoShow(ctx, appointmentID)
+			} else {
+				err = appointmentService.Complete(ctx, appointmentID)
+			}
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_GetAvailableSlots(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		employeeRepo    *mocks.EmployeeRepository
+		serviceRepo     *mocks.BusinessServiceRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type expected struct {
+		err   error
+		slots []services.TimeSlot
+	}
+
+	employeeID := 1
+	serviceID := 1
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	weekday := int(date.Weekday())
+	at := func(hour, minute int) time.Time {
+		return date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	clockPtr := func(hour, minute int) *time.Time {
+		c := clock(hour, minute)
+		return &c
+	}
+
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1, IsActive: true}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 60, IsActive: true}
+
+	// Split shift with a lunch break inside the morning block
+	splitShift := []entity.ScheduleTemplate{
+		{EmployeeID: employeeID, DayOfWeek: weekday, StartTime: clock(9, 0), EndTime: clock(13, 0)},
+		{EmployeeID: employeeID, DayOfWeek: weekday, StartTime: clock(12, 0), EndTime: clock(12, 30), IsBreak: true},
+		{EmployeeID: employeeID, DayOfWeek: weekday, StartTime: clock(15, 0), EndTime: clock(17, 0)},
+	}
+
+	ctx := context.Background()
+
+	// Booking settings that step by 15 minutes and keep 15 minutes free after each appointment
+	stepped := &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 45, SlotInterval: 15, IsActive: true}
+	buffered := &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 60, SlotInterval: 30, BufferAfter: 15, IsActive: true}
+	morning := []entity.ScheduleTemplate{
+		{EmployeeID: employeeID, DayOfWeek: weekday, StartTime: clock(9, 0), EndTime: clock(11, 0)},
+	}
+
+	// Group class for three clients
+	group := &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 60, Capacity: 3, IsActive: true}
+	sessionID, otherSessionID := 7, 8
+
+	testCases := []struct {
+		name string
+		// service overrides the default service when set
+		service  *entity.BusinessService
+		mock     func(m mocksForExecution)
+		expected expected
+	}{
+		{
+			name: "positive: split shift with break",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(splitShift, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+					{EmployeeID: employeeID, StartTime: at(10, 0), EndTime: at(11, 0), Status: entity.AppointmentStatusScheduled},
+					{EmployeeID: employeeID, StartTime: at(15, 0), EndTime: at(16, 0), Status: entity.AppointmentStatusCancelled},
+				}, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(9, 0), EndTime: at(10, 0)},
+					{StartTime: at(11, 0), EndTime: at(12, 0)},
+					{StartTime: at(15, 0), EndTime: at(16, 0)},
+					{StartTime: at(16, 0), EndTime: at(17, 0)},
+				},
+			},
+		},
+		{
+			name: "positive: imported busy time blocks slots like an appointment",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(splitShift, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.BusyTime{
+					{EmployeeID: employeeID, StartTime: at(10, 15), EndTime: at(10, 45)},
+					// Started the day before and ends during the afternoon block
+					{EmployeeID: employeeID, StartTime: date.Add(-time.Hour), EndTime: at(15, 30)},
+				}, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return(nil, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(16, 0), EndTime: at(17, 0)},
+				},
+			},
+		},
+		{
+			name: "positive: working hours limited to the opening hours of the business",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(splitShift, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, service.BusinessID).Return([]entity.BusinessHours{
+					{BusinessID: service.BusinessID, DayOfWeek: weekday, OpenTime: clock(10, 0), CloseTime: clock(16, 0)},
+				}, nil)
+				m.scheduleRepo.On("ListClosures", ctx, service.BusinessID, date, date).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return(nil, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(10, 0), EndTime: at(11, 0)},
+					{StartTime: at(11, 0), EndTime: at(12, 0)},
+					{StartTime: at(15, 0), EndTime: at(16, 0)},
+				},
+			},
+		},
+		{
+			name: "positive: business closed for the day",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(splitShift, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, service.BusinessID).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, service.BusinessID, date, date).Return([]entity.BusinessClosure{
+					{BusinessID: service.BusinessID, Name: "Renovation", StartDate: date.AddDate(0, 0, -1), EndDate: date.AddDate(0, 0, 1)},
+				}, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{},
+			},
+		},
+		{
+			name: "positive: day off override",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(splitShift, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return([]entity.ScheduleOverride{
+					{EmployeeID: employeeID, OverrideDate: date, IsWorkingDay: false},
+				}, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{},
+			},
+		},
+		{
+			name: "positive: override replaces working hours and adds a break",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(splitShift, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return([]entity.ScheduleOverride{
+					{EmployeeID: employeeID, OverrideDate: date, IsWorkingDay: true, StartTime: clockPtr(13, 0), EndTime: clockPtr(18, 0)},
+					{EmployeeID: employeeID, OverrideDate: date, IsWorkingDay: true, IsBreak: true, StartTime: clockPtr(14, 0), EndTime: clockPtr(15, 0)},
+				}, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return(nil, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(13, 0), EndTime: at(14, 0)},
+					{StartTime: at(15, 0), EndTime: at(16, 0)},
+					{StartTime: at(16, 0), EndTime: at(17, 0)},
+					{StartTime: at(17, 0), EndTime: at(18, 0)},
+				},
+			},
+		},
+		{
+			name: "positive: no templates for the weekday",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return([]entity.ScheduleTemplate{}, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{},
+			},
+		},
+		{
+			name:    "positive: slot interval shorter than duration",
+			service: stepped,
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(morning, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return(nil, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(9, 0), EndTime: at(9, 45)},
+					{StartTime: at(9, 15), EndTime: at(10, 0)},
+					{StartTime: at(9, 30), EndTime: at(10, 15)},
+					{StartTime: at(9, 45), EndTime: at(10, 30)},
+					{StartTime: at(10, 0), EndTime: at(10, 45)},
+					{StartTime: at(10, 15), EndTime: at(11, 0)},
+				},
+			},
+		},
+		{
+			name:    "positive: buffers block adjacent slots",
+			service: buffered,
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return([]entity.ScheduleTemplate{
+					{EmployeeID: employeeID, DayOfWeek: weekday, StartTime: clock(9, 0), EndTime: clock(13, 0)},
+				}, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+					{EmployeeID: employeeID, StartTime: at(11, 0), EndTime: at(11, 30), Status: entity.AppointmentStatusScheduled,
+						Service: &entity.BusinessService{BufferBefore: 30}},
+				}, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(9, 0), EndTime: at(10, 0)},
+					{StartTime: at(11, 30), EndTime: at(12, 30)},
+					{StartTime: at(12, 0), EndTime: at(13, 0)},
+				},
+			},
+		},
+		{
+			name:    "positive: group class slots show remaining seats",
+			service: group,
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(morning, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+					{ServiceID: serviceID, SessionID: &sessionID, StartTime: at(9, 0), EndTime: at(10, 0), Status: entity.AppointmentStatusScheduled},
+					{ServiceID: serviceID, SessionID: &sessionID, StartTime: at(9, 0), EndTime: at(10, 0), Status: entity.AppointmentStatusCancelled},
+					{ServiceID: serviceID, SessionID: &otherSessionID, StartTime: at(10, 0), EndTime: at(11, 0), Status: entity.AppointmentStatusScheduled},
+					{ServiceID: serviceID, SessionID: &otherSessionID, StartTime: at(10, 0), EndTime: at(11, 0), Status: entity.AppointmentStatusScheduled},
+				}, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(9, 0), EndTime: at(10, 0), RemainingSeats: 2},
+					{StartTime: at(10, 0), EndTime: at(11, 0), RemainingSeats: 1},
+				},
+			},
+		},
+		{
+			name:    "positive: full class session is not offered",
+			service: group,
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(morning, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				full := make([]entity.Appointment, 3)
+				for i := range full {
+					full[i] = entity.Appointment{ServiceID: serviceID, SessionID: &sessionID, StartTime: at(9, 0), EndTime: at(10, 0), Status: entity.AppointmentStatusScheduled}
+				}
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return(full, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(10, 0), EndTime: at(11, 0), RemainingSeats: 3},
+				},
+			},
+		},
+		{
+			name:    "positive: one-on-one appointment blocks the class slot",
+			service: group,
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(morning, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+					{ServiceID: 2, StartTime: at(9, 30), EndTime: at(10, 0), Status: entity.AppointmentStatusScheduled},
+				}, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(10, 0), EndTime: at(11, 0), RemainingSeats: 3},
+				},
+			},
+		},
+		{
+			name:    "positive: active hold blocks its slot, expired hold does not",
+			service: &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 60, IsActive: true},
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(morning, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				active, expired := now.Add(5*time.Minute), now.Add(-time.Minute)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+					{StartTime: at(9, 0), EndTime: at(10, 0), Status: entity.AppointmentStatusHeld, HoldExpiresAt: &active},
+					{StartTime: at(10, 0), EndTime: at(11, 0), Status: entity.AppointmentStatusHeld, HoldExpiresAt: &expired},
+				}, nil)
+			},
+			expected: expected{
+				slots: []services.TimeSlot{
+					{StartTime: at(10, 0), EndTime: at(11, 0)},
+				},
+			},
+		},
+		{
+			name: "negative: failed to get schedule",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(nil, fmt.Errorf("db error"))
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to get employee schedule: %w", fmt.Errorf("db error")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			svc := service
+			if tc.service != nil {
+				svc = tc.service
+			}
+			employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*svc}, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(svc, nil)
+			businessRepoMock.On("Get", ctx, svc.BusinessID).Return(&entity.Business{ID: svc.BusinessID, Timezone: "UTC"}, nil)
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				employeeRepo:    employeeRepoMock,
+				serviceRepo:     serviceRepoMock,
+				scheduleRepo:    scheduleRepoMock,
+			})
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			slots, err := appointmentService.GetAvailableSlots(ctx, employeeID, serviceID, 0, date)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected.slots, slots)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_GetServiceSlots(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	serviceID := 1
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	weekday := int(date.Weekday())
+	at := func(hour, minute int) time.Time {
+		return date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 60, IsActive: true}
+
+	ctx := context.Background()
+
+	// Init mocks
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	businessRepoMock := mocks.NewBusinessRepository(t)
+	employeeRepoMock := mocks.NewEmployeeRepository(t)
+	serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+	scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+	serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+	businessRepoMock.On("Get", ctx, service.BusinessID).Return(&entity.Business{ID: service.BusinessID, Timezone: "UTC"}, nil)
+	employeeRepoMock.On("ListByServiceID", ctx, serviceID).Return([]entity.Employee{
+		{ID: 1, BusinessID: businessID, IsActive: true},
+		{ID: 2, BusinessID: businessID, IsActive: true},
+		{ID: 3, BusinessID: businessID, IsActive: false},
+	}, nil)
+
+	// The first employee works in the morning and is booked at 9:00, the second one works 10:00-12:00
+	scheduleRepoMock.On("ListTemplates", ctx, 1).Return([]entity.ScheduleTemplate{
+		{EmployeeID: 1, DayOfWeek: weekday, StartTime: clock(9, 0), EndTime: clock(11, 0)},
+	}, nil)
+	scheduleRepoMock.On("ListTemplates", ctx, 2).Return([]entity.ScheduleTemplate{
+		{EmployeeID: 2, DayOfWeek: weekday, StartTime: clock(10, 0), EndTime: clock(12, 0)},
+	}, nil)
+	scheduleRepoMock.On("ListOverrides", ctx, mock.Anything, date, date).Return(nil, nil)
+	scheduleRepoMock.On("ListBusyTime", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 1, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+		{EmployeeID: 1, StartTime: at(9, 0), EndTime: at(10, 0), Status: entity.AppointmentStatusScheduled},
+	}, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 2, date, date.AddDate(0, 0, 1)).Return(nil, nil)
+
+	// Init service
+	appointmentService := services.NewAppointmentService(&repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Business:    businessRepoMock,
+		Employee:    employeeRepoMock,
+		Service:     serviceRepoMock,
+		Schedule:    scheduleRepoMock,
+	}, nil, nil, nil)
+
+	// Execute
+	slots, err := appointmentService.GetServiceSlots(ctx, serviceID, 0, date)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []services.TimeSlot{
+		{StartTime: at(10, 0), EndTime: at(11, 0)},
+		{StartTime: at(11, 0), EndTime: at(12, 0)},
+	}, slots)
+}
+
+func TestAppointmentService_GetServiceSlotsGroupSeats(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	serviceID := 1
+	sessionID := 7
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	weekday := int(date.Weekday())
+	at := func(hour, minute int) time.Time {
+		return date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 60, Capacity: 3, IsActive: true}
+
+	ctx := context.Background()
+
+	// Init mocks
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	businessRepoMock := mocks.NewBusinessRepository(t)
+	employeeRepoMock := mocks.NewEmployeeRepository(t)
+	serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+	scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+	serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+	businessRepoMock.On("Get", ctx, service.BusinessID).Return(&entity.Business{ID: service.BusinessID, Timezone: "UTC"}, nil)
+	employeeRepoMock.On("ListByServiceID", ctx, serviceID).Return([]entity.Employee{
+		{ID: 1, BusinessID: businessID, IsActive: true},
+		{ID: 2, BusinessID: businessID, IsActive: true},
+	}, nil)
+
+	// Both employees teach at 9:00, one seat of the first employee's session is taken
+	for _, employeeID := range []int{1, 2} {
+		scheduleRepoMock.On("ListTemplates", ctx, employeeID).Return([]entity.ScheduleTemplate{
+			{EmployeeID: employeeID, DayOfWeek: weekday, StartTime: clock(9, 0), EndTime: clock(10, 0)},
+		}, nil)
+	}
+	scheduleRepoMock.On("ListOverrides", ctx, mock.Anything, date, date).Return(nil, nil)
+	scheduleRepoMock.On("ListBusyTime", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 1, date, date.AddDate(0, 0, 1)).Return([]entity.Appointment{
+		{EmployeeID: 1, ServiceID: serviceID, SessionID: &sessionID, StartTime: at(9, 0), EndTime: at(10, 0), Status: entity.AppointmentStatusScheduled},
+	}, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 2, date, date.AddDate(0, 0, 1)).Return(nil, nil)
+
+	// Init service
+	appointmentService := services.NewAppointmentService(&repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Business:    businessRepoMock,
+		Employee:    employeeRepoMock,
+		Service:     serviceRepoMock,
+		Schedule:    scheduleRepoMock,
+	}, nil, nil, nil)
+
+	// Execute
+	slots, err := appointmentService.GetServiceSlots(ctx, serviceID, 0, date)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []services.TimeSlot{
+		{StartTime: at(9, 0), EndTime: at(10, 0), RemainingSeats: 5},
+	}, slots)
+}
+
+func TestAppointmentService_GetAvailability(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type args struct {
+		startDate time.Time
+		endDate   time.Time
+	}
+
+	type expected struct {
+		err  error
+		days []services.DayAvailability
+	}
+
+	employeeID := 1
+	serviceID := 1
+
+	now := time.Now().UTC()
+	startDate := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 0, 2)
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	clockPtr := func(hour, minute int) *time.Time {
+		c := clock(hour, minute)
+		return &c
+	}
+	slot := func(day time.Time, hour int) services.TimeSlot {
+		start := day.Add(time.Duration(hour) * time.Hour)
+		return services.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)}
+	}
+
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1, IsActive: true}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 60, IsActive: true}
+
+	// Works 9:00-11:00 on the weekdays of the first and last day of the range
+	templates := []entity.ScheduleTemplate{
+		{EmployeeID: employeeID, DayOfWeek: int(startDate.Weekday()), StartTime: clock(9, 0), EndTime: clock(11, 0)},
+		{EmployeeID: employeeID, DayOfWeek: int(endDate.Weekday()), StartTime: clock(9, 0), EndTime: clock(11, 0)},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: slots per day loaded in one batch",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return(templates, nil).Once()
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, startDate, endDate).Return([]entity.ScheduleOverride{
+					{EmployeeID: employeeID, OverrideDate: endDate, StartTime: clockPtr(10, 0), EndTime: clockPtr(11, 0), IsWorkingDay: true, IsBreak: true},
+				}, nil).Once()
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, startDate, endDate.AddDate(0, 0, 1)).Return([]entity.Appointment{
+					{EmployeeID: employeeID, StartTime: startDate.Add(9 * time.Hour), EndTime: startDate.Add(10 * time.Hour), Status: entity.AppointmentStatusScheduled},
+				}, nil).Once()
+			},
+			args: args{startDate: startDate, endDate: endDate},
+			expected: expected{
+				days: []services.DayAvailability{
+					{Date: startDate.Format("2006-01-02"), Slots: []services.TimeSlot{slot(startDate, 10)}},
+					{Date: startDate.AddDate(0, 0, 1).Format("2006-01-02"), Slots: []services.TimeSlot{}},
+					{Date: endDate.Format("2006-01-02"), Slots: []services.TimeSlot{slot(endDate, 9)}},
+				},
+			},
+		},
+		{
+			name: "positive: templates in effect by validity dates and rotation weeks",
+			mock: func(m mocksForExecution) {
+				dayBefore := startDate.AddDate(0, 0, -1)
+				m.scheduleRepo.On("ListTemplates", ctx, employeeID).Return([]entity.ScheduleTemplate{
+					// The current schedule ends before the range and the next one starts with it
+					{EmployeeID: employeeID, DayOfWeek: int(startDate.Weekday()), StartTime: clock(9, 0), EndTime: clock(11, 0), ValidUntil: &dayBefore},
+					{EmployeeID: employeeID, DayOfWeek: int(startDate.Weekday()), StartTime: clock(13, 0), EndTime: clock(14, 0), ValidFrom: &startDate},
+					// Alternating weeks, the last day is in the first week of the rotation
+					{EmployeeID: employeeID, DayOfWeek: int(endDate.Weekday()), StartTime: clock(9, 0), EndTime: clock(10, 0), RotationWeeks: 2, RotationWeek: 2, RotationAnchor: &endDate},
+					{EmployeeID: employeeID, DayOfWeek: int(endDate.Weekday()), StartTime: clock(10, 0), EndTime: clock(11, 0), RotationWeeks: 2, RotationWeek: 1, RotationAnchor: &endDate},
+				}, nil).Once()
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, startDate, endDate).Return(nil, nil).Once()
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("ListByEmployee", ctx, employeeID, startDate, endDate.AddDate(0, 0, 1)).Return(nil, nil).Once()
+			},
+			args: args{startDate: startDate, endDate: endDate},
+			expected: expected{
+				days: []services.DayAvailability{
+					{Date: startDate.Format("2006-01-02"), Slots: []services.TimeSlot{slot(startDate, 13)}},
+					{Date: startDate.AddDate(0, 0, 1).Format("2006-01-02"), Slots: []services.TimeSlot{}},
+					{Date: endDate.Format("2006-01-02"), Slots: []services.TimeSlot{slot(endDate, 10)}},
+				},
+			},
+		},
+		{
+			name: "negative: range exceeds 31 days",
+			mock: func(m mocksForExecution) {},
+			args: args{startDate: startDate, endDate: startDate.AddDate(0, 0, 32)},
+			expected: expected{
+				err: fmt.Errorf("date range cannot exceed 31 days"),
+			},
+		},
+		{
+			name: "negative: end date before start date",
+			mock: func(m mocksForExecution) {},
+			args: args{startDate: endDate, endDate: startDate},
+			expected: expected{
+				err: fmt.Errorf("end time must be after start time"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			if tc.expected.err == nil {
+				employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+				employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+				serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+				businessRepoMock.On("Get", ctx, service.BusinessID).Return(&entity.Business{ID: service.BusinessID, Timezone: "UTC"}, nil)
+			}
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				scheduleRepo:    scheduleRepoMock,
+			})
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			days, err := appointmentService.GetAvailability(ctx, employeeID, serviceID, 0, tc.args.startDate, tc.args.endDate)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected.days, days)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_GetAvailabilityAcrossDST(t *testing.T) {
+	t.Parallel()
+
+	employeeID := 1
+	serviceID := 1
+
+	kyiv := loadLocation(t, "Europe/Kyiv")
+	business := &entity.Business{ID: 1, Timezone: "Europe/Kyiv"}
+	employee := &entity.Employee{ID: employeeID, BusinessID: business.ID, IsActive: true}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: business.ID, Duration: 60, IsActive: true}
+
+	// Works 9:00-11:00 local time every day
+	var templates []entity.ScheduleTemplate
+	for weekday := 0; weekday < 7; weekday++ {
+		templates = append(templates, entity.ScheduleTemplate{
+			EmployeeID: employeeID,
+			DayOfWeek:  weekday,
+			StartTime:  time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:    time.Date(0, 1, 1, 11, 0, 0, 0, time.UTC),
+		})
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		// day is the date the clocks change on
+		day time.Time
+	}{
+		{
+			name: "positive: clocks move forward",
+			day:  nextClockChange(kyiv, time.Now(), true),
+		},
+		{
+			name: "positive: clocks move back",
+			day:  nextClockChange(kyiv, time.Now(), false),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dayBefore := tc.day.AddDate(0, 0, -1)
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil)
+			scheduleRepoMock.On("ListTemplates", ctx, employeeID).Return(templates, nil)
+			scheduleRepoMock.On("ListOverrides", ctx, employeeID, sameInstant(dayBefore), sameInstant(tc.day)).Return(nil, nil)
+			scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			appointmentRepoMock.On("ListByEmployee", ctx, employeeID, sameInstant(dayBefore), sameInstant(tc.day.AddDate(0, 0, 1))).Return(nil, nil)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute with dates as the controller parses them
+			startDate := time.Date(dayBefore.Year(), dayBefore.Month(), dayBefore.Day(), 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(tc.day.Year(), tc.day.Month(), tc.day.Day(), 0, 0, 0, 0, time.UTC)
+			days, err := appointmentService.GetAvailability(ctx, employeeID, serviceID, 0, startDate, endDate)
+
+			// Assert the slots start at 9:00 and 10:00 local time on both sides of the change
+			require.NoError(t, err)
+			require.Len(t, days, 2)
+			for i, day := range []time.Time{dayBefore, tc.day} {
+				assert.Equal(t, day.Format("2006-01-02"), days[i].Date)
+
+				var starts []time.Time
+				for _, slot := range days[i].Slots {
+					starts = append(starts, slot.StartTime.UTC())
+				}
+				assert.Equal(t, []time.Time{
+					time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, kyiv).UTC(),
+					time.Date(day.Year(), day.Month(), day.Day(), 10, 0, 0, 0, kyiv).UTC(),
+				}, starts)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_GetNextAvailableSlot(t *testing.T) {
+	t.Parallel()
+
+	employeeID := 1
+	serviceID := 1
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1, IsActive: true}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: 1, Duration: 60, IsActive: true}
+
+	ctx := context.Background()
+
+	t.Run("positive: slot found in a later batch", func(t *testing.T) {
+		t.Parallel()
+
+		appointmentRepoMock := mocks.NewAppointmentRepository(t)
+		businessRepoMock := mocks.NewBusinessRepository(t)
+		employeeRepoMock := mocks.NewEmployeeRepository(t)
+		serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+		scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+		employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+		employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+		serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+		businessRepoMock.On("Get", ctx, service.BusinessID).Return(&entity.Business{ID: service.BusinessID, Timezone: "UTC"}, nil)
+
+		// Every day of the first batch is taken off, the second batch starts with a working day
+		var overrides []entity.ScheduleOverride
+		for day := from; day.Before(from.AddDate(0, 0, 31)); day = day.AddDate(0, 0, 1) {
+			overrides = append(overrides, entity.ScheduleOverride{EmployeeID: employeeID, OverrideDate: day, IsWorkingDay: false})
+		}
+		secondBatch := from.AddDate(0, 0, 31)
+
+		scheduleRepoMock.On("ListTemplates", ctx, employeeID).Return([]entity.ScheduleTemplate{
+			{EmployeeID: employeeID, DayOfWeek: int(secondBatch.Weekday()), StartTime: clock(9, 0), EndTime: clock(10, 0)},
+		}, nil).Twice()
+		scheduleRepoMock.On("ListOverrides", ctx, employeeID, from, from.AddDate(0, 0, 30)).Return(overrides, nil).Once()
+		scheduleRepoMock.On("ListOverrides", ctx, employeeID, secondBatch, secondBatch.AddDate(0, 0, 30)).Return(nil, nil).Once()
+		scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+		scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		appointmentRepoMock.On("ListByEmployee", ctx, employeeID, secondBatch, secondBatch.AddDate(0, 0, 31)).Return(nil, nil).Once()
+
+		appointmentService := services.NewAppointmentService(&repository.Repositories{
+			Appointment: appointmentRepoMock,
+			Business:    businessRepoMock,
+			Employee:    employeeRepoMock,
+			Service:     serviceRepoMock,
+			Schedule:    scheduleRepoMock,
+		}, nil, nil, nil)
+
+		slot, err := appointmentService.GetNextAvailableSlot(ctx, employeeID, serviceID, 0, from.Add(5*time.Hour))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &services.TimeSlot{
+			StartTime: secondBatch.Add(9 * time.Hour),
+			EndTime:   secondBatch.Add(10 * time.Hour),
+		}, slot)
+	})
+
+	t.Run("negative: no slots within the horizon", func(t *testing.T) {
+		t.Parallel()
+
+		appointmentRepoMock := mocks.NewAppointmentRepository(t)
+		businessRepoMock := mocks.NewBusinessRepository(t)
+		employeeRepoMock := mocks.NewEmployeeRepository(t)
+		serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+		scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+		employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+		employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+		serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+		businessRepoMock.On("Get", ctx, service.BusinessID).Return(&entity.Business{ID: service.BusinessID, Timezone: "UTC"}, nil)
+		scheduleRepoMock.On("ListTemplates", ctx, employeeID).Return(nil, nil).Times(3)
+		scheduleRepoMock.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil).Times(3)
+		scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+		scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+		appointmentService := services.NewAppointmentService(&repository.Repositories{
+			Appointment: appointmentRepoMock,
+			Business:    businessRepoMock,
+			Employee:    employeeRepoMock,
+			Service:     serviceRepoMock,
+			Schedule:    scheduleRepoMock,
+		}, nil, nil, nil)
+
+		slot, err := appointmentService.GetNextAvailableSlot(ctx, employeeID, serviceID, 0, from)
+
+		assert.ErrorIs(t, err, services.ErrNoAvailableSlots)
+		assert.Nil(t, slot)
+	})
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// AssignmentStrategy picks an employee for an appointment booked without one.
+// Candidates are sorted by ID and are all free for the appointment time.
+type AssignmentStrategy interface {
+	Pick(ctx context.Context, candidates []entity.Employee, appointment *entity.Appointment) (*entity.Employee, error)
+}
+
+type firstFreeStrategy struct{}
+
+// NewFirstFreeStrategy assigns the first free employee
+func NewFirstFreeStrategy() AssignmentStrategy {
+	return &firstFreeStrategy{}
+}
+
+func (s *firstFreeStrategy) Pick(_ context.Context, candidates []entity.Employee, _ *entity.Appointment) (*entity.Employee, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no candidates to assign")
+	}
+	return &candidates[0], nil
+}
+
+type roundRobinStrategy struct {
+	mu sync.Mutex
+	// last holds the ID of the employee assigned last for each service
+	last map[int]int
+}
+
+// NewRoundRobinStrategy rotates assignments between the employees of a service.
+// The rotation state is kept in memory and is not shared between instances.
+func NewRoundRobinStrategy() AssignmentStrategy {
+	return &roundRobinStrategy{
+		last: make(map[int]int),
+	}
+}
+
+func (s *roundRobinStrategy) Pick(_ context.Context, candidates []entity.Employee, appointment *entity.Appointment) (*entity.Employee, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no candidates to assign")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Take the first candidate after the last assigned one, wrapping around
+	picked := &candidates[0]
+	for i := range candidates {
+		if candidates[i].ID > s.last[appointment.ServiceID] {
+			picked = &candidates[i]
+			break
+		}
+	}
+
+	s.last[appointment.ServiceID] = picked.ID
+	return picked, nil
+}
+
+type leastBookedStrategy struct {
+	repos *repository.Repositories
+}
+
+// NewLeastBookedStrategy assigns the employee with the fewest scheduled appointments on the appointment's day
+// in the timezone of their branch
+func NewLeastBookedStrategy(repos *repository.Repositories) AssignmentStrategy {
+	return &leastBookedStrategy{
+		repos: repos,
+	}
+}
+
+func (s *leastBookedStrategy) Pick(ctx context.Context, candidates []entity.Employee, appointment *entity.Appointment) (*entity.Employee, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no candidates to assign")
+	}
+
+	var picked *entity.Employee
+	minBooked := -1
+	for i := range candidates {
+		loc, err := scheduleLocation(ctx, s.repos, appointment.BusinessID, candidates[i].LocationID)
+		if err != nil {
+			return nil, err
+		}
+		dayStart := startOfDay(appointment.StartTime.In(loc))
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		appointments, err := s.repos.Appointment.ListByEmployee(ctx, candidates[i].ID, dayStart, dayEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count employee appointments: %w", err)
+		}
+
+		booked := 0
+		for _, a := range appointments {
+			if a.Status == entity.AppointmentStatusScheduled {
+				booked++
+			}
+		}
+
+		if minBooked == -1 || booked < minBooked {
+			picked = &candidates[i]
+			minBooked = booked
+		}
+	}
+
+	return picked, nil
+}
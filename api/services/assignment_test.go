@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestRoundRobinStrategy_Pick(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	strategy := services.NewRoundRobinStrategy()
+	candidates := []entity.Employee{{ID: 1}, {ID: 2}, {ID: 3}}
+	appointment := &entity.Appointment{ServiceID: 1}
+
+	var picked []int
+	for i := 0; i < 4; i++ {
+		employee, err := strategy.Pick(ctx, candidates, appointment)
+		require.NoError(t, err)
+		picked = append(picked, employee.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 1}, picked)
+
+	// Busy employees are skipped without losing the position in the rotation
+	employee, err := strategy.Pick(ctx, []entity.Employee{{ID: 1}, {ID: 3}}, appointment)
+	require.NoError(t, err)
+	assert.Equal(t, 3, employee.ID)
+
+	// Each service keeps its own rotation
+	employee, err = strategy.Pick(ctx, candidates, &entity.Appointment{ServiceID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 1, employee.ID)
+}
+
+func TestLeastBookedStrategy_Pick(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// 23:30 UTC is already the next day in Kyiv, so the local day is counted
+	start := time.Date(2024, 11, 20, 23, 30, 0, 0, time.UTC)
+	dayStart := sameInstant(time.Date(2024, 11, 20, 22, 0, 0, 0, time.UTC))
+	dayEnd := sameInstant(time.Date(2024, 11, 21, 22, 0, 0, 0, time.UTC))
+
+	businessRepoMock := mocks.NewBusinessRepository(t)
+	businessRepoMock.On("Get", ctx, 1).Return(&entity.Business{ID: 1, Timezone: "Europe/Kyiv"}, nil)
+
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	appointmentRepoMock.On("ListByEmployee", ctx, 1, dayStart, dayEnd).Return([]entity.Appointment{
+		{Status: entity.AppointmentStatusScheduled},
+		{Status: entity.AppointmentStatusScheduled},
+	}, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 2, dayStart, dayEnd).Return([]entity.Appointment{
+		{Status: entity.AppointmentStatusScheduled},
+		{Status: entity.AppointmentStatusCancelled},
+		{Status: entity.AppointmentStatusCancelled},
+	}, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 3, dayStart, dayEnd).Return([]entity.Appointment{
+		{Status: entity.AppointmentStatusScheduled},
+	}, nil)
+
+	strategy := services.NewLeastBookedStrategy(&repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Business:    businessRepoMock,
+	})
+
+	// Ties go to the first candidate
+	employee, err := strategy.Pick(ctx, []entity.Employee{{ID: 1}, {ID: 2}, {ID: 3}}, &entity.Appointment{BusinessID: 1, StartTime: start})
+	require.NoError(t, err)
+	assert.Equal(t, 2, employee.ID)
+}
@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// workingDay describes the resolved schedule of an employee for a single date
+type workingDay struct {
+	Date time.Time
+	// DayOff is set when an override explicitly takes the whole day off
+	DayOff bool
+	// Closed is set when the business is closed for the whole day, by a closure or by its opening hours
+	Closed bool
+	// Hours are the working blocks within the opening hours of the business, before breaks are removed
+	Hours []TimeSlot
+	// Breaks are the break blocks from templates and overrides
+	Breaks []TimeSlot
+	// Intervals are the bookable working intervals with all breaks removed
+	Intervals []TimeSlot
+	// Busy are the blocks of imported calendar events overlapping the day. They conflict with
+	// bookings like appointments do, so they are not removed from the intervals.
+	Busy []TimeSlot
+}
+
+// getWorkingDay resolves the hours an employee of the business works on the given date.
+// The date's location is used to place template and override times, so it should be the business timezone.
+func getWorkingDay(ctx context.Context, repos *repository.Repositories, businessID, employeeID int, date time.Time) (*workingDay, error) {
+	templates, err := repos.Schedule.GetEmployeeSchedule(ctx, employeeID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee schedule: %w", err)
+	}
+
+	day := startOfDay(date)
+	overrides, err := repos.Schedule.ListOverrides(ctx, employeeID, day, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule overrides: %w", err)
+	}
+
+	busy, err := repos.Schedule.ListBusyTime(ctx, employeeID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get busy time: %w", err)
+	}
+
+	calendar, err := getBusinessCalendar(ctx, repos, businessID, day, day)
+	if err != nil {
+		return nil, err
+	}
+
+	workingDay := resolveWorkingDay(day, templates, overrides)
+	calendar.apply(workingDay)
+	workingDay.Busy = busyOnDay(day, busy)
+	return workingDay, nil
+}
+
+// getWorkingDays resolves the working days of an employee of the business for every date from startDate to
+// endDate inclusive. Templates, overrides and the business calendar are loaded once for the whole range. Days are
+// stepped by calendar date in the location of startDate, so days around DST transitions keep their local working hours.
+func getWorkingDays(ctx context.Context, repos *repository.Repositories, businessID, employeeID int, startDate, endDate time.Time) ([]*workingDay, error) {
+	templates, err := repos.Schedule.ListTemplates(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee schedule: %w", err)
+	}
+
+	startDate, endDate = startOfDay(startDate), startOfDay(endDate)
+	overrides, err := repos.Schedule.ListOverrides(ctx, employeeID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule overrides: %w", err)
+	}
+
+	busy, err := repos.Schedule.ListBusyTime(ctx, employeeID, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get busy time: %w", err)
+	}
+
+	calendar, err := getBusinessCalendar(ctx, repos, businessID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	templatesByWeekday := make(map[time.Weekday][]entity.ScheduleTemplate)
+	for _, t := range templates {
+		weekday := time.Weekday(t.DayOfWeek)
+		templatesByWeekday[weekday] = append(templatesByWeekday[weekday], t)
+	}
+
+	overridesByDate := make(map[string][]entity.ScheduleOverride)
+	for _, o := range overrides {
+		date := o.OverrideDate.Format("2006-01-02")
+		overridesByDate[date] = append(overridesByDate[date], o)
+	}
+
+	var days []*workingDay
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		workingDay := resolveWorkingDay(day, templatesByWeekday[day.Weekday()], overridesByDate[day.Format("2006-01-02")])
+		calendar.apply(workingDay)
+		workingDay.Busy = busyOnDay(day, busy)
+		days = append(days, workingDay)
+	}
+
+	return days, nil
+}
+
+// resolveWorkingDay merges the weekday templates in effect on that date with the overrides for that date
+// and computes the sorted working intervals with all breaks removed. Templates outside their validity dates
+// or rotation week are skipped.
+//
+// Overrides are applied as follows:
+//   - a non-working override without times marks the whole day off;
+//   - working overrides with times replace the working blocks of the templates;
+//   - break overrides and non-working overrides with times are subtracted as breaks.
+//
+// Break templates keep applying when the working hours are overridden.
+func resolveWorkingDay(day time.Time, templates []entity.ScheduleTemplate, overrides []entity.ScheduleOverride) *workingDay {
+	var working, breaks, overrideWorking []TimeSlot
+
+	for _, o := range overrides {
+		if o.StartTime == nil || o.EndTime == nil {
+			if !o.IsWorkingDay {
+				return &workingDay{Date: day, DayOff: true}
+			}
+			continue
+		}
+
+		interval := timeOfDayInterval(day, *o.StartTime, *o.EndTime)
+		if o.IsBreak || !o.IsWorkingDay {
+			breaks = append(breaks, interval)
+		} else {
+			overrideWorking = append(overrideWorking, interval)
+		}
+	}
+
+	for _, t := range templates {
+		if !templateAppliesOn(t, day) {
+			continue
+		}
+
+		interval := timeOfDayInterval(day, t.StartTime, t.EndTime)
+		if t.IsBreak {
+			breaks = append(breaks, interval)
+		} else {
+			working = append(working, interval)
+		}
+	}
+
+	if len(overrideWorking) > 0 {
+		working = overrideWorking
+	}
+
+	hours := mergeIntervals(working)
+	intervals := hours
+	for _, b := range breaks {
+		intervals = subtractInterval(intervals, b)
+	}
+
+	return &workingDay{
+		Date:      day,
+		Hours:     hours,
+		Breaks:    breaks,
+		Intervals: intervals,
+	}
+}
+
+// businessCalendar holds the opening hours and closures of a business, they limit the working hours of its employees
+type businessCalendar struct {
+	hours    map[time.Weekday][]entity.BusinessHours
+	closures []entity.BusinessClosure
+}
+
+// getBusinessCalendar loads the opening hours of the business and its closures from startDate to endDate inclusive
+func getBusinessCalendar(ctx context.Context, repos *repository.Repositories, businessID int, startDate, endDate time.Time) (*businessCalendar, error) {
+	hours, err := repos.Schedule.ListBusinessHours(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business hours: %w", err)
+	}
+
+	// Closures are stored as dates, so the range is passed as UTC dates the way override dates are
+	closures, err := repos.Schedule.ListClosures(ctx, businessID, dateIn(startDate, time.UTC), dateIn(endDate, time.UTC))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business closures: %w", err)
+	}
+
+	calendar := &businessCalendar{closures: closures}
+	if len(hours) > 0 {
+		calendar.hours = make(map[time.Weekday][]entity.BusinessHours)
+		for _, h := range hours {
+			weekday := time.Weekday(h.DayOfWeek)
+			calendar.hours[weekday] = append(calendar.hours[weekday], h)
+		}
+	}
+
+	return calendar, nil
+}
+
+// apply limits the working day to the time the business is open.
+// Businesses without opening hours are open all day unless they are closed by a closure.
+func (c *businessCalendar) apply(day *workingDay) {
+	closed := false
+	for _, closure := range c.closures {
+		closed = closed || closureCovers(closure, day.Date)
+	}
+
+	var open []TimeSlot
+	if c.hours != nil {
+		for _, h := range c.hours[day.Date.Weekday()] {
+			open = append(open, timeOfDayInterval(day.Date, h.OpenTime, h.CloseTime))
+		}
+		closed = closed || len(open) == 0
+	}
+
+	if closed {
+		day.Closed = true
+		day.Hours = nil
+		day.Intervals = nil
+		return
+	}
+	if open != nil {
+		day.Hours = intersectIntervals(day.Hours, open)
+		day.Intervals = intersectIntervals(day.Intervals, open)
+	}
+}
+
+// closureCovers reports whether the closure includes the calendar date of the day.
+// Yearly closures are moved to the year of the day and the year before, for closures over the new year.
+// A yearly closure on February 29 falls on March 1 in other years.
+func closureCovers(closure entity.BusinessClosure, day time.Time) bool {
+	date := dateIn(day, time.UTC)
+	start, end := dateIn(closure.StartDate, time.UTC), dateIn(closure.EndDate, time.UTC)
+	if !closure.IsYearly {
+		return !date.Before(start) && !date.After(end)
+	}
+
+	days := int(end.Sub(start).Hours() / 24)
+	for _, year := range []int{date.Year() - 1, date.Year()} {
+		if year < start.Year() {
+			continue
+		}
+		from := time.Date(year, start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		if !date.Before(from) && !date.After(from.AddDate(0, 0, days)) {
+			return true
+		}
+	}
+	return false
+}
+
+// busyOnDay returns the busy time overlapping the day, blocks spanning several days are returned for each of them
+func busyOnDay(day time.Time, busy []entity.BusyTime) []TimeSlot {
+	next := day.AddDate(0, 0, 1)
+
+	var result []TimeSlot
+	for _, b := range busy {
+		if b.StartTime.Before(next) && b.EndTime.After(day) {
+			result = append(result, TimeSlot{StartTime: b.StartTime, EndTime: b.EndTime})
+		}
+	}
+	return result
+}
+
+// overlapsAny reports whether [start, end) overlaps any of the intervals
+func overlapsAny(start, end time.Time, intervals []TimeSlot) bool {
+	for _, interval := range intervals {
+		if start.Before(interval.EndTime) && end.After(interval.StartTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithinIntervals reports whether [start, end) fits entirely inside one of the intervals
+func isWithinIntervals(start, end time.Time, intervals []TimeSlot) bool {
+	for _, interval := range intervals {
+		if !start.Before(interval.StartTime) && !end.After(interval.EndTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectIntervals returns the parts of the intervals that lie within one of the limits, sorted by start
+func intersectIntervals(intervals, limits []TimeSlot) []TimeSlot {
+	var result []TimeSlot
+	for _, interval := range intervals {
+		for _, limit := range limits {
+			start, end := interval.StartTime, interval.EndTime
+			if limit.StartTime.After(start) {
+				start = limit.StartTime
+			}
+			if limit.EndTime.Before(end) {
+				end = limit.EndTime
+			}
+			if start.Before(end) {
+				result = append(result, TimeSlot{StartTime: start, EndTime: end})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartTime.Before(result[j].StartTime)
+	})
+	return result
+}
+
+// mergeIntervals sorts the intervals and joins the ones that overlap or touch
+func mergeIntervals(intervals []TimeSlot) []TimeSlot {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sorted := make([]TimeSlot, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+
+	merged := []TimeSlot{sorted[0]}
+	for _, interval := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if interval.StartTime.After(last.EndTime) {
+			merged = append(merged, interval)
+			continue
+		}
+		if interval.EndTime.After(last.EndTime) {
+			last.EndTime = interval.EndTime
+		}
+	}
+
+	return merged
+}
+
+// subtractInterval removes the cut from every interval, splitting intervals that contain it
+func subtractInterval(intervals []TimeSlot, cut TimeSlot) []TimeSlot {
+	var result []TimeSlot
+	for _, interval := range intervals {
+		if !cut.StartTime.Before(interval.EndTime) || !cut.EndTime.After(interval.StartTime) {
+			result = append(result, interval)
+			continue
+		}
+		if cut.StartTime.After(interval.StartTime) {
+			result = append(result, TimeSlot{StartTime: interval.StartTime, EndTime: cut.StartTime})
+		}
+		if cut.EndTime.Before(interval.EndTime) {
+			result = append(result, TimeSlot{StartTime: cut.EndTime, EndTime: interval.EndTime})
+		}
+	}
+	return result
+}
+
+// templateAppliesOn reports whether the template is in effect on the date by its validity dates and rotation week.
+// The weekday of the template is not checked.
+func templateAppliesOn(t entity.ScheduleTemplate, day time.Time) bool {
+	date := dateIn(day, time.UTC)
+	if t.ValidFrom != nil && date.Before(*t.ValidFrom) {
+		return false
+	}
+	if t.ValidUntil != nil && date.After(*t.ValidUntil) {
+		return false
+	}
+	if t.RotationWeeks <= 1 || t.RotationAnchor == nil {
+		return true
+	}
+
+	return rotationWeek(*t.RotationAnchor, date, t.RotationWeeks) == t.RotationWeek
+}
+
+// rotationWeek returns the week of a cycle of the given length the date falls in. Weeks are counted from 1
+// and the cycles start on the anchor date, dates before the anchor continue the cycle backwards.
+func rotationWeek(anchor, date time.Time, weeks int) int {
+	days := int(date.Sub(dateIn(anchor, time.UTC)).Hours() / 24)
+	week := days / 7
+	if days < 0 && days%7 != 0 {
+		week--
+	}
+
+	week %= weeks
+	if week < 0 {
+		week += weeks
+	}
+	return week + 1
+}
+
+// timeOfDayInterval places the clock times of a schedule entry on the given day
+func timeOfDayInterval(day time.Time, start, end time.Time) TimeSlot {
+	return TimeSlot{
+		StartTime: time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, day.Location()),
+		EndTime:   time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), end.Second(), 0, day.Location()),
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dateIn returns the start of the calendar date of t in the location.
+// Dates parsed from requests are in UTC and keep their day this way.
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// businessLocation returns the timezone the schedules of the business are kept in
+func businessLocation(ctx context.Context, repos *repository.Repositories, businessID int) (*time.Location, error) {
+	business, err := repos.Business.Get(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business: %w", err)
+	}
+
+	return loadLocation(business.Timezone), nil
+}
+
+// scheduleLocation returns the timezone of the branch, or of the business for employees and appointments
+// without one. Schedules of the employees of a branch are kept in its timezone.
+func scheduleLocation(ctx context.Context, repos *repository.Repositories, businessID int, locationID *int) (*time.Location, error) {
+	if locationID == nil {
+		return businessLocation(ctx, repos, businessID)
+	}
+
+	location, err := repos.Location.Get(ctx, *locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location: %w", err)
+	}
+
+	return loadLocation(location.Timezone), nil
+}
+
+// branchLocation returns the timezone of the branch, or of the already loaded business without one
+func branchLocation(ctx context.Context, repos *repository.Repositories, business *entity.Business, locationID *int) (*time.Location, error) {
+	if locationID == nil {
+		return loadLocation(business.Timezone), nil
+	}
+
+	return scheduleLocation(ctx, repos, business.ID, locationID)
+}
+
+// loadLocation returns the location of a stored timezone, falling back to UTC for unknown names
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// validateTimezone accepts IANA timezone names only
+func validateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return fmt.Errorf("invalid timezone: %q", name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("invalid timezone: %q", name)
+	}
+	return nil
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/vadimpk/ppc-project/repository"
 )
 
+// defaultTimezone is used for businesses created without a timezone
+const defaultTimezone = "UTC"
+
 type businessService struct {
 	repos *repository.Repositories
 }
@@ -18,8 +21,8 @@ func NewBusinessService(repos *repository.Repositories) BusinessService {
 	}
 }
 
-func (s *businessService) ListBySearch(ctx context.Context, search string) ([]entity.Business, error) {
-	business, err := s.repos.Business.ListBySearch(ctx, search)
+func (s *businessService) ListBySearch(ctx context.Context, search, location string) ([]entity.Business, error) {
+	business, err := s.repos.Business.ListBySearch(ctx, search, location)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list businesses: %w", err)
 	}
@@ -27,8 +30,8 @@ func (s *businessService) ListBySearch(ctx context.Context, search string) ([]en
 	return business, nil
 }
 
-func (s *businessService) ListServicesBySearch(ctx context.Context, search string) ([]entity.BusinessService, error) {
-	services, err := s.repos.Service.ListServicesBySearch(ctx, search)
+func (s *businessService) ListServicesBySearch(ctx context.Context, search, location string) ([]entity.BusinessService, error) {
+	services, err := s.repos.Service.ListServicesBySearch(ctx, search, location)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list services: %w", err)
 	}
@@ -42,6 +45,13 @@ func (s *businessService) Create(ctx context.Context, business *entity.Business)
 		return fmt.Errorf("business name is required")
 	}
 
+	if business.Timezone == "" {
+		business.Timezone = defaultTimezone
+	}
+	if err := validateTimezone(business.Timezone); err != nil {
+		return err
+	}
+
 	// Create business
 	if err := s.repos.Business.Create(ctx, business); err != nil {
 		return fmt.Errorf("failed to create business: %w", err)
@@ -71,8 +81,14 @@ func (s *businessService) Update(ctx context.Context, business *entity.Business)
 		return fmt.Errorf("business name is required")
 	}
 
-	// Update only allows changing the name
+	// Update only allows changing the name and the timezone
 	existing.Name = business.Name
+	if business.Timezone != "" {
+		if err := validateTimezone(business.Timezone); err != nil {
+			return err
+		}
+		existing.Timezone = business.Timezone
+	}
 
 	if err := s.repos.Business.Update(ctx, existing); err != nil {
 		return fmt.Errorf("failed to update business: %w", err)
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/ical"
+	"github.com/vadimpk/ppc-project/pkg/rrule"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// maxClosureListDays limits the date range closures are listed for
+	maxClosureListDays = 366
+	// maxImportedClosures limits the closures created from a single holiday list
+	maxImportedClosures = 1000
+)
+
+var (
+	// ErrInvalidBusinessHours is returned for opening hours with invalid days or times
+	ErrInvalidBusinessHours = errors.New("invalid business hours")
+	// ErrInvalidClosure is returned for closures with an invalid name or dates
+	ErrInvalidClosure = errors.New("invalid closure")
+)
+
+type businessHoursService struct {
+	repos *repository.Repositories
+	clock Clock
+}
+
+// NewBusinessHoursService creates the service. The clock defaults to the system clock when nil.
+func NewBusinessHoursService(repos *repository.Repositories, clock Clock) BusinessHoursService {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &businessHoursService{
+		repos: repos,
+		clock: clock,
+	}
+}
+
+func (s *businessHoursService) GetHours(ctx context.Context, businessID int) ([]entity.BusinessHours, error) {
+	hours, err := s.repos.Schedule.ListBusinessHours(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+func (s *businessHoursService) SetHours(ctx context.Context, businessID int, hours []entity.BusinessHours) error {
+	if err := validateBusinessHours(hours); err != nil {
+		return err
+	}
+
+	for i := range hours {
+		hours[i].BusinessID = businessID
+	}
+
+	if err := s.repos.Schedule.ReplaceBusinessHours(ctx, businessID, hours); err != nil {
+		return fmt.Errorf("failed to set business hours: %w", err)
+	}
+
+	return nil
+}
+
+func (s *businessHoursService) CreateClosure(ctx context.Context, closure *entity.BusinessClosure) error {
+	today, err := s.businessToday(ctx, closure.BusinessID)
+	if err != nil {
+		return err
+	}
+
+	closure.Name = strings.TrimSpace(closure.Name)
+	closure.StartDate = dateIn(closure.StartDate, time.UTC)
+	closure.EndDate = dateIn(closure.EndDate, time.UTC)
+	if err := validateClosure(closure, today); err != nil {
+		return err
+	}
+
+	if err := s.repos.Schedule.CreateClosure(ctx, closure); err != nil {
+		return fmt.Errorf("failed to create closure: %w", err)
+	}
+
+	return nil
+}
+
+func (s *businessHoursService) GetClosure(ctx context.Context, id int) (*entity.BusinessClosure, error) {
+	closure, err := s.repos.Schedule.GetClosure(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closure: %w", err)
+	}
+
+	return closure, nil
+}
+
+func (s *businessHoursService) ListClosures(ctx context.Context, businessID int, startDate, endDate time.Time) ([]entity.BusinessClosure, error) {
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date must be after start date")
+	}
+	if endDate.Sub(startDate).Hours()/24 > maxClosureListDays {
+		return nil, fmt.Errorf("date range cannot exceed %d days", maxClosureListDays)
+	}
+
+	closures, err := s.repos.Schedule.ListClosures(ctx, businessID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list closures: %w", err)
+	}
+
+	return closures, nil
+}
+
+func (s *businessHoursService) DeleteClosure(ctx context.Context, id int) error {
+	if err := s.repos.Schedule.DeleteClosure(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete closure: %w", err)
+	}
+	return nil
+}
+
+// ImportClosures creates a closure for every all-day event of the holiday list. Events repeating every year
+// become yearly closures, other recurring events are imported for their dates within the next year.
+// Timed, cancelled and past events are skipped.
+func (s *businessHoursService) ImportClosures(ctx context.Context, businessID int, data []byte) (int, error) {
+	loc, err := businessLocation(ctx, s.repos, businessID)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := ical.Parse(bytes.NewReader(data), loc)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	from := startOfDay(s.clock.Now().In(loc))
+	today := dateIn(from, time.UTC)
+
+	var closures []entity.BusinessClosure
+	for _, event := range events {
+		if !event.AllDay || event.Status == ical.StatusCancelled || !event.RecurrenceID.IsZero() {
+			continue
+		}
+
+		closure := entity.BusinessClosure{
+			BusinessID: businessID,
+			Name:       closureName(event.Summary),
+			StartDate:  dateIn(event.Start, time.UTC),
+			EndDate:    dateIn(event.End, time.UTC).AddDate(0, 0, -1),
+		}
+		if closure.EndDate.Before(closure.StartDate) {
+			closure.EndDate = closure.StartDate
+		}
+
+		switch {
+		case event.RRule == "":
+			if !closure.EndDate.Before(today) {
+				closures = append(closures, closure)
+			}
+		case isEveryYear(event.RRule):
+			closure.IsYearly = true
+			closures = append(closures, closure)
+		default:
+			// Rules that cannot be expanded are skipped like timed events
+			occurrences, _ := ical.Expand([]ical.Event{event}, from, from.AddDate(1, 0, 0))
+			for _, o := range occurrences {
+				occurrence := closure
+				occurrence.StartDate = dateIn(o.Start, time.UTC)
+				occurrence.EndDate = occurrence.StartDate.Add(closure.EndDate.Sub(closure.StartDate))
+				closures = append(closures, occurrence)
+			}
+		}
+	}
+
+	if len(closures) > maxImportedClosures {
+		return 0, fmt.Errorf("%w: calendar has more than %d holidays", ErrInvalidCalendar, maxImportedClosures)
+	}
+
+	created, err := s.repos.Schedule.ImportClosures(ctx, closures)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import closures: %w", err)
+	}
+
+	return created, nil
+}
+
+// businessToday returns the current date of the business as a UTC date, the way closure dates are stored
+func (s *businessHoursService) businessToday(ctx context.Context, businessID int) (time.Time, error) {
+	loc, err := businessLocation(ctx, s.repos, businessID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return dateIn(s.clock.Now().In(loc), time.UTC), nil
+}
+
+// validateBusinessHours checks the blocks of each weekday, blocks of the same weekday may not overlap
+func validateBusinessHours(hours []entity.BusinessHours) error {
+	for i, h := range hours {
+		if h.DayOfWeek < 0 || h.DayOfWeek > 6 {
+			return fmt.Errorf("%w: invalid day of week", ErrInvalidBusinessHours)
+		}
+		if h.OpenTime.Hour()*60+h.OpenTime.Minute() >= h.CloseTime.Hour()*60+h.CloseTime.Minute() {
+			return fmt.Errorf("%w: close time must be after open time", ErrInvalidBusinessHours)
+		}
+
+		for _, other := range hours[:i] {
+			if other.DayOfWeek == h.DayOfWeek && isTimeOverlap(other.OpenTime, other.CloseTime, h.OpenTime, h.CloseTime) {
+				return fmt.Errorf("%w: opening hours overlap on %s", ErrInvalidBusinessHours, time.Weekday(h.DayOfWeek))
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateClosure(closure *entity.BusinessClosure, today time.Time) error {
+	if closure.Name == "" || len(closure.Name) > 255 {
+		return fmt.Errorf("%w: name must be between 1 and 255 characters", ErrInvalidClosure)
+	}
+	if closure.EndDate.Before(closure.StartDate) {
+		return fmt.Errorf("%w: end date must not be before start date", ErrInvalidClosure)
+	}
+
+	if closure.IsYearly {
+		if !closure.EndDate.Before(closure.StartDate.AddDate(1, 0, 0)) {
+			return fmt.Errorf("%w: yearly closures must be shorter than a year", ErrInvalidClosure)
+		}
+	} else if closure.EndDate.Before(today) {
+		return fmt.Errorf("%w: cannot create closures for past dates", ErrInvalidClosure)
+	}
+
+	return nil
+}
+
+// isEveryYear reports whether the rule repeats on the same date every year without an end
+func isEveryYear(s string) bool {
+	rule, err := rrule.Parse(s)
+	if err != nil {
+		return false
+	}
+	return rule.Freq == rrule.Yearly && rule.Interval == 1 && rule.Count == 0 && rule.Until.IsZero() && len(rule.ByDay) == 0
+}
+
+// closureName returns the event summary as a closure name, shortened to 255 bytes
+func closureName(summary string) string {
+	name := strings.TrimSpace(summary)
+	if name == "" {
+		return "Holiday"
+	}
+
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
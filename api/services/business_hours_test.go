@@ -0,0 +1,307 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestBusinessHoursService_SetHours(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name  string
+		hours []entity.BusinessHours
+		mock  func(s *mocks.ScheduleRepository)
+		err   error
+	}{
+		{
+			name: "positive: split opening hours",
+			hours: []entity.BusinessHours{
+				{DayOfWeek: 1, OpenTime: clock(9, 0), CloseTime: clock(13, 0)},
+				{DayOfWeek: 1, OpenTime: clock(14, 0), CloseTime: clock(18, 0)},
+				{DayOfWeek: 2, OpenTime: clock(9, 0), CloseTime: clock(18, 0)},
+			},
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ReplaceBusinessHours", ctx, businessID, mock.MatchedBy(func(hours []entity.BusinessHours) bool {
+					return len(hours) == 3 && hours[0].BusinessID == businessID && hours[2].BusinessID == businessID
+				})).Return(nil)
+			},
+		},
+		{
+			name: "positive: no opening hours removes the restriction",
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ReplaceBusinessHours", ctx, businessID, []entity.BusinessHours(nil)).Return(nil)
+			},
+		},
+		{
+			name: "negative: overlapping hours on the same day",
+			hours: []entity.BusinessHours{
+				{DayOfWeek: 1, OpenTime: clock(9, 0), CloseTime: clock(13, 0)},
+				{DayOfWeek: 1, OpenTime: clock(12, 0), CloseTime: clock(18, 0)},
+			},
+			mock: func(s *mocks.ScheduleRepository) {},
+			err:  fmt.Errorf("%w: opening hours overlap on Monday", services.ErrInvalidBusinessHours),
+		},
+		{
+			name: "negative: closing before opening",
+			hours: []entity.BusinessHours{
+				{DayOfWeek: 1, OpenTime: clock(18, 0), CloseTime: clock(9, 0)},
+			},
+			mock: func(s *mocks.ScheduleRepository) {},
+			err:  fmt.Errorf("%w: close time must be after open time", services.ErrInvalidBusinessHours),
+		},
+		{
+			name: "negative: invalid day of week",
+			hours: []entity.BusinessHours{
+				{DayOfWeek: 7, OpenTime: clock(9, 0), CloseTime: clock(18, 0)},
+			},
+			mock: func(s *mocks.ScheduleRepository) {},
+			err:  fmt.Errorf("%w: invalid day of week", services.ErrInvalidBusinessHours),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			tc.mock(scheduleRepoMock)
+
+			// Init service
+			businessHoursService := services.NewBusinessHoursService(&repository.Repositories{
+				Schedule: scheduleRepoMock,
+			}, nil)
+
+			// Execute
+			err := businessHoursService.SetHours(ctx, businessID, tc.hours)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBusinessHoursService_CreateClosure(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 23, 30, 0, 0, time.UTC)
+	business := &entity.Business{ID: 1, Timezone: "Europe/Kyiv"}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		closure *entity.BusinessClosure
+		mock    func(s *mocks.ScheduleRepository)
+		err     error
+	}{
+		{
+			name:    "positive: closure starting today in the business timezone",
+			closure: &entity.BusinessClosure{BusinessID: business.ID, Name: " Inventory ", StartDate: date(3, 11), EndDate: date(3, 12)},
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("CreateClosure", ctx, &entity.BusinessClosure{
+					BusinessID: business.ID, Name: "Inventory", StartDate: date(3, 11), EndDate: date(3, 12),
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "positive: yearly closure started in the past",
+			closure: &entity.BusinessClosure{BusinessID: business.ID, Name: "Christmas", StartDate: date(12, 25).AddDate(-5, 0, 0), EndDate: date(12, 26).AddDate(-5, 0, 0), IsYearly: true},
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("CreateClosure", ctx, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:    "negative: closure in the past",
+			closure: &entity.BusinessClosure{BusinessID: business.ID, Name: "Inventory", StartDate: date(3, 9), EndDate: date(3, 10)},
+			mock:    func(s *mocks.ScheduleRepository) {},
+			err:     fmt.Errorf("%w: cannot create closures for past dates", services.ErrInvalidClosure),
+		},
+		{
+			name:    "negative: yearly closure of a whole year",
+			closure: &entity.BusinessClosure{BusinessID: business.ID, Name: "Closed", StartDate: date(3, 11), EndDate: date(3, 11).AddDate(1, 0, 0), IsYearly: true},
+			mock:    func(s *mocks.ScheduleRepository) {},
+			err:     fmt.Errorf("%w: yearly closures must be shorter than a year", services.ErrInvalidClosure),
+		},
+		{
+			name:    "negative: ending before it starts",
+			closure: &entity.BusinessClosure{BusinessID: business.ID, Name: "Inventory", StartDate: date(3, 12), EndDate: date(3, 11)},
+			mock:    func(s *mocks.ScheduleRepository) {},
+			err:     fmt.Errorf("%w: end date must not be before start date", services.ErrInvalidClosure),
+		},
+		{
+			name:    "negative: without a name",
+			closure: &entity.BusinessClosure{BusinessID: business.ID, Name: " ", StartDate: date(3, 12), EndDate: date(3, 12)},
+			mock:    func(s *mocks.ScheduleRepository) {},
+			err:     fmt.Errorf("%w: name must be between 1 and 255 characters", services.ErrInvalidClosure),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+
+			// Setup mocks
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil)
+			tc.mock(scheduleRepoMock)
+
+			// Init service
+			businessHoursService := services.NewBusinessHoursService(&repository.Repositories{
+				Schedule: scheduleRepoMock,
+				Business: businessRepoMock,
+			}, &fakeClock{now: now})
+
+			// Execute
+			err := businessHoursService.CreateClosure(ctx, tc.closure)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBusinessHoursService_ImportClosures(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	business := &entity.Business{ID: 1, Timezone: "Europe/Kyiv"}
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	holidays := icsCalendar(
+		"BEGIN:VEVENT",
+		"UID:new-year",
+		"DTSTART;VALUE=DATE:20200101",
+		"RRULE:FREQ=YEARLY",
+		"SUMMARY:New Year",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:easter",
+		"DTSTART;VALUE=DATE:20250420",
+		"DTEND;VALUE=DATE:20250422",
+		"SUMMARY:Easter",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:past",
+		"DTSTART;VALUE=DATE:20250308",
+		"SUMMARY:Women's Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:staff-day",
+		"DTSTART;VALUE=DATE:20250103",
+		"RRULE:FREQ=MONTHLY;INTERVAL=6;COUNT=3",
+		"SUMMARY:Staff day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled",
+		"DTSTART;VALUE=DATE:20250501",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:timed",
+		"DTSTART:20250601T100000Z",
+		"DTEND:20250601T110000Z",
+		"SUMMARY:Meeting",
+		"END:VEVENT",
+	)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		calendar string
+		mock     func(s *mocks.ScheduleRepository)
+		created  int
+		err      error
+	}{
+		{
+			name:     "positive: yearly, one-off and expanded recurring holidays",
+			calendar: holidays,
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ImportClosures", ctx, []entity.BusinessClosure{
+					{BusinessID: business.ID, Name: "New Year", StartDate: date(2020, 1, 1), EndDate: date(2020, 1, 1), IsYearly: true},
+					{BusinessID: business.ID, Name: "Easter", StartDate: date(2025, 4, 20), EndDate: date(2025, 4, 21)},
+					{BusinessID: business.ID, Name: "Staff day", StartDate: date(2025, 7, 3), EndDate: date(2025, 7, 3)},
+					{BusinessID: business.ID, Name: "Staff day", StartDate: date(2026, 1, 3), EndDate: date(2026, 1, 3)},
+				}).Return(3, nil)
+			},
+			created: 3,
+		},
+		{
+			name:     "negative: not a calendar",
+			calendar: "BEGIN:VEVENT\r\nEND:VEVENT\r\n",
+			mock:     func(s *mocks.ScheduleRepository) {},
+			err:      fmt.Errorf("%w: no VCALENDAR found", services.ErrInvalidCalendar),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+
+			// Setup mocks
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil)
+			tc.mock(scheduleRepoMock)
+
+			// Init service
+			businessHoursService := services.NewBusinessHoursService(&repository.Repositories{
+				Schedule: scheduleRepoMock,
+				Business: businessRepoMock,
+			}, &fakeClock{now: now})
+
+			// Execute
+			created, err := businessHoursService.ImportClosures(ctx, business.ID, []byte(tc.calendar))
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.created, created)
+		})
+	}
+}
@@ -24,6 +24,11 @@ func (s *businessServiceService) Create(ctx context.Context, service *entity.Bus
 		return fmt.Errorf("invalid business: %w", err)
 	}
 
+	// Services without a capacity serve one client at a time
+	if service.Capacity == 0 {
+		service.Capacity = 1
+	}
+
 	// Validate service data
 	if err := validateServiceData(service); err != nil {
 		return err
@@ -67,6 +72,11 @@ func (s *businessServiceService) Update(ctx context.Context, service *entity.Bus
 		return fmt.Errorf("service does not belong to the business")
 	}
 
+	// Services without a capacity serve one client at a time
+	if service.Capacity == 0 {
+		service.Capacity = 1
+	}
+
 	// Validate service data
 	if err := validateServiceData(service); err != nil {
 		return err
@@ -122,6 +132,19 @@ func (s *businessServiceService) ListActive(ctx context.Context, businessID int)
 	return services, nil
 }
 
+func (s *businessServiceService) ListByLocation(ctx context.Context, businessID, locationID int) ([]entity.BusinessService, error) {
+	if _, err := getBusinessLocation(ctx, s.repos, businessID, locationID); err != nil {
+		return nil, err
+	}
+
+	services, err := s.repos.Service.ListByLocation(ctx, businessID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list location services: %w", err)
+	}
+
+	return services, nil
+}
+
 func validateServiceData(service *entity.BusinessService) error {
 	if service.Name == "" {
 		return fmt.Errorf("service name is required")
@@ -132,5 +155,20 @@ func validateServiceData(service *entity.BusinessService) error {
 	if service.Price < 0 {
 		return fmt.Errorf("service price cannot be negative")
 	}
+	if service.SlotInterval < 0 || service.BufferBefore < 0 || service.BufferAfter < 0 {
+		return fmt.Errorf("slot interval and buffers cannot be negative")
+	}
+	if service.MinNotice < 0 || service.MaxAdvanceDays < 0 {
+		return fmt.Errorf("booking notice and advance window cannot be negative")
+	}
+	if service.Capacity < 1 {
+		return fmt.Errorf("service capacity must be positive")
+	}
+
+	resourceTypes, err := normalizeResourceTypes(service.ResourceTypes)
+	if err != nil {
+		return err
+	}
+	service.ResourceTypes = resourceTypes
 	return nil
 }
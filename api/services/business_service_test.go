@@ -121,6 +121,24 @@ func TestBusinessServiceService_Create(t *testing.T) {
 				err: fmt.Errorf("service price cannot be negative"),
 			},
 		},
+		{
+			name: "negative: negative buffer",
+			mock: func(m mocksForExecution) {
+				m.businessRepo.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			},
+			args: args{
+				service: &entity.BusinessService{
+					BusinessID:   businessID,
+					Name:         "Test Service",
+					Duration:     30,
+					Price:        1000,
+					BufferBefore: -5,
+				},
+			},
+			expected: expected{
+				err: fmt.Errorf("slot interval and buffers cannot be negative"),
+			},
+		},
 		{
 			name: "negative: failed to create service",
 			mock: func(m mocksForExecution) {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/vadimpk/ppc-project/entity"
 	"github.com/vadimpk/ppc-project/repository"
 	"github.com/vadimpk/ppc-project/repository/mocks"
@@ -68,6 +69,19 @@ func TestBusinessService_Create(t *testing.T) {
 				err: fmt.Errorf("business name is required"),
 			},
 		},
+		{
+			name: "negative: invalid timezone",
+			mock: func(m mocksForExecution) {},
+			args: args{
+				business: &entity.Business{
+					Name:     "Test Business",
+					Timezone: "Europe/Atlantis",
+				},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid timezone: %q", "Europe/Atlantis"),
+			},
+		},
 		{
 			name: "negative: failed to create business",
 			mock: func(m mocksForExecution) {
@@ -283,6 +297,38 @@ func TestBusinessService_Update(t *testing.T) {
 				err: fmt.Errorf("business name is required"),
 			},
 		},
+		{
+			name: "positive: timezone updated",
+			mock: func(m mocksForExecution) {
+				m.businessRepo.On("Get", ctx, 2).Return(&entity.Business{ID: 2, Name: "Original Name", Timezone: "UTC"}, nil)
+				m.businessRepo.On("Update", ctx, mock.MatchedBy(func(b *entity.Business) bool {
+					return b.Timezone == "Europe/Kyiv"
+				})).Return(nil)
+			},
+			args: args{
+				business: &entity.Business{
+					ID:       2,
+					Name:     "Updated Name",
+					Timezone: "Europe/Kyiv",
+				},
+			},
+		},
+		{
+			name: "negative: server local timezone",
+			mock: func(m mocksForExecution) {
+				m.businessRepo.On("Get", ctx, 2).Return(&entity.Business{ID: 2, Name: "Original Name", Timezone: "UTC"}, nil)
+			},
+			args: args{
+				business: &entity.Business{
+					ID:       2,
+					Name:     "Updated Name",
+					Timezone: "Local",
+				},
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid timezone: %q", "Local"),
+			},
+		},
 		{
 			name: "negative: failed to update business",
 			mock: func(m mocksForExecution) {
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/ical"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// calendarFeedPast and calendarFeedFuture bound the appointments included in calendar feeds
+	calendarFeedPast   = 90 * 24 * time.Hour
+	calendarFeedFuture = 365 * 24 * time.Hour
+
+	calendarProdID = "-//PPC Project//Bookings//EN"
+	// calendarUIDDomain makes the UIDs of appointments globally unique
+	calendarUIDDomain = "bookings.ppc-project"
+)
+
+type calendarService struct {
+	repos *repository.Repositories
+	clock Clock
+}
+
+// NewCalendarService creates the service. The clock defaults to the system clock when nil.
+func NewCalendarService(repos *repository.Repositories, clock Clock) CalendarService {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &calendarService{
+		repos: repos,
+		clock: clock,
+	}
+}
+
+func (s *calendarService) CreateEmployeeFeed(ctx context.Context, employeeID int) (*entity.CalendarFeed, error) {
+	// Validate employee existence
+	if _, err := s.repos.Employee.Get(ctx, employeeID); err != nil {
+		return nil, fmt.Errorf("invalid employee: %w", err)
+	}
+
+	return s.createFeed(ctx, &entity.CalendarFeed{EmployeeID: &employeeID})
+}
+
+func (s *calendarService) CreateClientFeed(ctx context.Context, clientID int) (*entity.CalendarFeed, error) {
+	// Validate client existence
+	client, err := s.repos.User.Get(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client: %w", err)
+	}
+	if client.Role != entity.RoleClient {
+		return nil, fmt.Errorf("user is not a client")
+	}
+
+	return s.createFeed(ctx, &entity.CalendarFeed{ClientID: &clientID})
+}
+
+func (s *calendarService) createFeed(ctx context.Context, feed *entity.CalendarFeed) (*entity.CalendarFeed, error) {
+	token, err := newHoldToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	feed.Token = token
+
+	// The previous token of the owner stops working
+	if err := s.repos.Calendar.Replace(ctx, feed); err != nil {
+		return nil, fmt.Errorf("failed to create calendar feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+func (s *calendarService) RevokeEmployeeFeed(ctx context.Context, employeeID int) error {
+	if err := s.repos.Calendar.DeleteByEmployee(ctx, employeeID); err != nil {
+		return fmt.Errorf("failed to revoke calendar feed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *calendarService) RevokeClientFeed(ctx context.Context, clientID int) error {
+	if err := s.repos.Calendar.DeleteByClient(ctx, clientID); err != nil {
+		return fmt.Errorf("failed to revoke calendar feed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *calendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.repos.Calendar.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed token: %w", err)
+	}
+
+	now := s.clock.Now()
+	from, to := now.Add(-calendarFeedPast), now.Add(calendarFeedFuture)
+
+	if feed.EmployeeID != nil {
+		return s.employeeCalendar(ctx, *feed.EmployeeID, from, to)
+	}
+	return s.clientCalendar(ctx, *feed.ClientID, from, to)
+}
+
+func (s *calendarService) AppointmentCalendar(ctx context.Context, appointmentID int) ([]byte, error) {
+	appointment, err := s.repos.Appointment.Get(ctx, appointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid appointment: %w", err)
+	}
+	if appointment.Status == entity.AppointmentStatusHeld {
+		return nil, fmt.Errorf("appointment is not booked")
+	}
+
+	business, loc, err := appointmentBusiness(ctx, s.repos, appointment)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProdID: calendarProdID,
+		Events: []ical.Event{clientAppointmentEvent(appointment, business, loc, s.clock.Now())},
+	}
+	return calendar.Marshal(), nil
+}
+
+func (s *calendarService) employeeCalendar(ctx context.Context, employeeID int, from, to time.Time) ([]byte, error) {
+	employee, err := s.repos.Employee.Get(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+	business, err := s.repos.Business.Get(ctx, employee.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business: %w", err)
+	}
+	loc, err := branchLocation(ctx, s.repos, business, employee.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := s.repos.Appointment.ListByEmployee(ctx, employeeID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+
+	now := s.clock.Now()
+	calendar := &ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   business.Name,
+		Events: []ical.Event{},
+	}
+	for i := range appointments {
+		appointment := &appointments[i]
+		if appointment.Status == entity.AppointmentStatusHeld {
+			continue
+		}
+
+		event := appointmentEvent(appointment, loc, now)
+		event.Summary = appointment.Service.Name
+		if appointment.Client != nil {
+			event.Summary += " - " + appointment.Client.FullName
+			event.Description = joinLines(append(contactLines(appointment.Client), event.Description)...)
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	return calendar.Marshal(), nil
+}
+
+func (s *calendarService) clientCalendar(ctx context.Context, clientID int, from, to time.Time) ([]byte, error) {
+	appointments, err := s.repos.Appointment.ListByClient(ctx, clientID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+
+	// A client may book with several businesses and branches, each with its own timezone
+	businesses := make(map[int]*entity.Business)
+	locations := make(map[int]*time.Location)
+	now := s.clock.Now()
+	calendar := &ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "My appointments",
+		Events: []ical.Event{},
+	}
+	for i := range appointments {
+		appointment := &appointments[i]
+		if appointment.Status == entity.AppointmentStatusHeld {
+			continue
+		}
+
+		business, ok := businesses[appointment.BusinessID]
+		if !ok {
+			business, err = s.repos.Business.Get(ctx, appointment.BusinessID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get business: %w", err)
+			}
+			businesses[business.ID] = business
+		}
+
+		loc := loadLocation(business.Timezone)
+		if appointment.LocationID != nil {
+			if loc, ok = locations[*appointment.LocationID]; !ok {
+				loc, err = branchLocation(ctx, s.repos, business, appointment.LocationID)
+				if err != nil {
+					return nil, err
+				}
+				locations[*appointment.LocationID] = loc
+			}
+		}
+
+		calendar.Events = append(calendar.Events, clientAppointmentEvent(appointment, business, loc, now))
+	}
+
+	return calendar.Marshal(), nil
+}
+
+// clientAppointmentEvent describes the appointment as seen by its client, with its times in loc
+func clientAppointmentEvent(appointment *entity.Appointment, business *entity.Business, loc *time.Location, now time.Time) ical.Event {
+	event := appointmentEvent(appointment, loc, now)
+	event.Summary = business.Name
+	if appointment.Service != nil {
+		event.Summary = appointment.Service.Name + " at " + business.Name
+	}
+	event.Location = business.Name
+	if appointment.Employee != nil {
+		event.Description = joinLines("With "+appointment.Employee.FullName, event.Description)
+	}
+	return event
+}
+
+// appointmentEvent returns the event of the appointment in the location, with the cancellation in its description
+func appointmentEvent(appointment *entity.Appointment, loc *time.Location, now time.Time) ical.Event {
+	event := ical.Event{
+		// The UID stays the same when the appointment is rescheduled or cancelled, so clients update the event
+		UID:     fmt.Sprintf("appointment-%d@%s", appointment.ID, calendarUIDDomain),
+		Start:   appointment.StartTime.In(loc),
+		End:     appointment.EndTime.In(loc),
+		Status:  ical.StatusConfirmed,
+		Created: appointment.CreatedAt,
+		Stamp:   now,
+	}
+
+	if appointment.Status == entity.AppointmentStatusCancelled {
+		event.Status = ical.StatusCancelled
+		event.Description = "Cancelled"
+		if appointment.CancellationReason != nil && *appointment.CancellationReason != "" {
+			event.Description += ": " + *appointment.CancellationReason
+		}
+	}
+
+	return event
+}
+
+func contactLines(user *entity.User) []string {
+	var lines []string
+	if user.Phone != nil {
+		lines = append(lines, "Phone: "+*user.Phone)
+	}
+	if user.Email != nil {
+		lines = append(lines, "Email: "+*user.Email)
+	}
+	return lines
+}
+
+// joinLines joins the non-empty lines
+func joinLines(lines ...string) string {
+	var nonEmpty []string
+	for _, line := range lines {
+		if line != "" {
+			nonEmpty = append(nonEmpty, line)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/ical"
+	"github.com/vadimpk/ppc-project/pkg/netguard"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// busyTimePast and busyTimeFuture bound the occurrences stored for imported calendars.
+	// Subscribed calendars are refreshed regularly, so the stored period moves with time.
+	busyTimePast   = 24 * time.Hour
+	busyTimeFuture = 365 * 24 * time.Hour
+
+	// maxCalendarSize limits uploaded and fetched calendars
+	maxCalendarSize = 5 << 20
+	// maxBusyTimes limits the busy time blocks of a single import
+	maxBusyTimes = 10000
+
+	calendarFetchTimeout = 30 * time.Second
+)
+
+var (
+	// ErrInvalidCalendar is returned when an imported calendar cannot be read or has invalid settings
+	ErrInvalidCalendar = errors.New("invalid calendar")
+	// ErrCalendarUnavailable is returned when a subscribed calendar cannot be fetched from its URL
+	ErrCalendarUnavailable = errors.New("calendar unavailable")
+)
+
+type calendarImportService struct {
+	repos  *repository.Repositories
+	client *http.Client
+	clock  Clock
+}
+
+// NewCalendarImportService creates the service. Subscribed calendars are only fetched from the addresses
+// the policy allows once their host is resolved, netguard.PublicAddresses is used when nil.
+// The clock defaults to the system clock when nil.
+func NewCalendarImportService(repos *repository.Repositories, policy netguard.AddressPolicy, clock Clock) CalendarImportService {
+	if policy == nil {
+		policy = netguard.PublicAddresses
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &calendarImportService{
+		repos:  repos,
+		client: netguard.NewClient(policy, calendarFetchTimeout),
+		clock:  clock,
+	}
+}
+
+func (s *calendarImportService) ImportFile(ctx context.Context, employeeID int, name string, data []byte) (*entity.CalendarImport, error) {
+	calendarImport := &entity.CalendarImport{EmployeeID: employeeID, Name: strings.TrimSpace(name)}
+	if err := validateCalendarImport(calendarImport); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.employeeLocation(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing is stored for calendars that cannot be read
+	busy, warning, err := s.busyTime(data, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// An upload replaces a subscription with the same name
+	if err := s.repos.CalendarImport.Upsert(ctx, calendarImport); err != nil {
+		return nil, fmt.Errorf("failed to store calendar import: %w", err)
+	}
+
+	return s.store(ctx, calendarImport, busy, warning)
+}
+
+func (s *calendarImportService) Subscribe(ctx context.Context, employeeID int, name, sourceURL string) (*entity.CalendarImport, error) {
+	calendarImport := &entity.CalendarImport{
+		EmployeeID: employeeID,
+		Name:       strings.TrimSpace(name),
+		SourceURL:  &sourceURL,
+	}
+	if err := validateCalendarImport(calendarImport); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.employeeLocation(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The calendar is fetched before subscribing, so a wrong URL is reported right away
+	busy, warning, err := s.fetchBusyTime(ctx, *calendarImport.SourceURL, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repos.CalendarImport.Upsert(ctx, calendarImport); err != nil {
+		return nil, fmt.Errorf("failed to store calendar import: %w", err)
+	}
+
+	return s.store(ctx, calendarImport, busy, warning)
+}
+
+func (s *calendarImportService) Refresh(ctx context.Context, id int) (*entity.CalendarImport, error) {
+	calendarImport, err := s.repos.CalendarImport.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar import: %w", err)
+	}
+	if calendarImport.SourceURL == nil {
+		return nil, fmt.Errorf("%w: uploaded calendars are refreshed by uploading them again", ErrInvalidCalendar)
+	}
+
+	return s.refresh(ctx, calendarImport)
+}
+
+func (s *calendarImportService) RefreshAll(ctx context.Context) (int, error) {
+	imports, err := s.repos.CalendarImport.ListSubscribed(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list subscribed calendars: %w", err)
+	}
+
+	failed := 0
+	for i := range imports {
+		if ctx.Err() != nil {
+			return failed, ctx.Err()
+		}
+		if _, err := s.refresh(ctx, &imports[i]); err != nil {
+			log.Printf("Failed to refresh calendar import %d: %v", imports[i].ID, err)
+			failed++
+		}
+	}
+
+	return failed, nil
+}
+
+// refresh imports a subscribed calendar again, recording the error of a failed import
+func (s *calendarImportService) refresh(ctx context.Context, calendarImport *entity.CalendarImport) (*entity.CalendarImport, error) {
+	loc, err := s.employeeLocation(ctx, calendarImport.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	busy, warning, err := s.fetchBusyTime(ctx, *calendarImport.SourceURL, loc)
+	if err != nil {
+		message := err.Error()
+		calendarImport.LastError = &message
+		if updateErr := s.repos.CalendarImport.UpdateError(ctx, calendarImport); updateErr != nil {
+			return nil, fmt.Errorf("failed to record calendar import error: %w", updateErr)
+		}
+		return calendarImport, err
+	}
+
+	return s.store(ctx, calendarImport, busy, warning)
+}
+
+func (s *calendarImportService) Get(ctx context.Context, id int) (*entity.CalendarImport, error) {
+	return s.repos.CalendarImport.Get(ctx, id)
+}
+
+func (s *calendarImportService) List(ctx context.Context, employeeID int) ([]entity.CalendarImport, error) {
+	return s.repos.CalendarImport.ListByEmployee(ctx, employeeID)
+}
+
+func (s *calendarImportService) Delete(ctx context.Context, id int) error {
+	if err := s.repos.CalendarImport.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete calendar import: %w", err)
+	}
+
+	return nil
+}
+
+// store replaces the busy time of the import. The warning lists the events that were skipped.
+func (s *calendarImportService) store(ctx context.Context, calendarImport *entity.CalendarImport, busy []entity.BusyTime, warning *string) (*entity.CalendarImport, error) {
+	now := s.clock.Now()
+	calendarImport.LastSyncedAt = &now
+	calendarImport.LastError = warning
+
+	if err := s.repos.CalendarImport.ReplaceBusyTime(ctx, calendarImport, busy); err != nil {
+		return nil, fmt.Errorf("failed to store busy time: %w", err)
+	}
+
+	return calendarImport, nil
+}
+
+func (s *calendarImportService) fetchBusyTime(ctx context.Context, sourceURL string, loc *time.Location) ([]entity.BusyTime, *string, error) {
+	data, err := s.fetch(ctx, sourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.busyTime(data, loc)
+}
+
+// fetch downloads the calendar at the URL. Subscription URLs with the webcal scheme are fetched over HTTPS.
+func (s *calendarImportService) fetch(ctx context.Context, sourceURL string) ([]byte, error) {
+	if strings.HasPrefix(sourceURL, "webcal://") {
+		sourceURL = "https://" + strings.TrimPrefix(sourceURL, "webcal://")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar request: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, netguard.ErrAddressNotAllowed) {
+			return nil, fmt.Errorf("%w: url must point to a public address", ErrInvalidCalendar)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrCalendarUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: server responded with %d", ErrCalendarUnavailable, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCalendarUnavailable, err)
+	}
+	if len(data) > maxCalendarSize {
+		return nil, fmt.Errorf("%w: calendar is larger than %d bytes", ErrInvalidCalendar, maxCalendarSize)
+	}
+
+	return data, nil
+}
+
+// busyTime returns the busy time of the calendar from a day ago until a year ahead. Cancelled events and
+// events marked as free are skipped, and overlapping occurrences are merged. Events whose recurrence is not
+// supported are skipped and listed in the returned warning.
+func (s *calendarImportService) busyTime(data []byte, loc *time.Location) ([]entity.BusyTime, *string, error) {
+	if len(data) > maxCalendarSize {
+		return nil, nil, fmt.Errorf("%w: calendar is larger than %d bytes", ErrInvalidCalendar, maxCalendarSize)
+	}
+
+	events, err := ical.Parse(bytes.NewReader(data), loc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	now := s.clock.Now()
+	occurrences, expandErr := ical.Expand(events, now.Add(-busyTimePast), now.Add(busyTimeFuture))
+
+	var blocks []TimeSlot
+	for _, occurrence := range occurrences {
+		if occurrence.Event.Status == ical.StatusCancelled || occurrence.Event.Transp == ical.TranspTransparent {
+			continue
+		}
+		if !occurrence.End.After(occurrence.Start) {
+			continue
+		}
+		blocks = append(blocks, TimeSlot{StartTime: occurrence.Start.UTC(), EndTime: occurrence.End.UTC()})
+	}
+
+	blocks = mergeIntervals(blocks)
+	if len(blocks) > maxBusyTimes {
+		return nil, nil, fmt.Errorf("%w: calendar has more than %d busy periods", ErrInvalidCalendar, maxBusyTimes)
+	}
+
+	busy := make([]entity.BusyTime, len(blocks))
+	for i, block := range blocks {
+		busy[i] = entity.BusyTime{StartTime: block.StartTime, EndTime: block.EndTime}
+	}
+
+	var warning *string
+	if expandErr != nil {
+		message := "skipped events: " + strings.ReplaceAll(expandErr.Error(), "\n", "; ")
+		warning = &message
+	}
+
+	return busy, warning, nil
+}
+
+// employeeLocation returns the timezone of the employee's branch or business, used for calendar times without a zone
+func (s *calendarImportService) employeeLocation(ctx context.Context, employeeID int) (*time.Location, error) {
+	employee, err := s.repos.Employee.Get(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid employee: %w", err)
+	}
+
+	return scheduleLocation(ctx, s.repos, employee.BusinessID, employee.LocationID)
+}
+
+func validateCalendarImport(calendarImport *entity.CalendarImport) error {
+	if calendarImport.Name == "" || len(calendarImport.Name) > 255 {
+		return fmt.Errorf("%w: name must be between 1 and 255 characters", ErrInvalidCalendar)
+	}
+
+	if calendarImport.SourceURL != nil {
+		u, err := url.Parse(*calendarImport.SourceURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "webcal") || u.Host == "" {
+			return fmt.Errorf("%w: url must be an absolute http, https or webcal URL", ErrInvalidCalendar)
+		}
+	}
+
+	return nil
+}
+
+// CalendarImportSyncer periodically refreshes the subscribed calendars of employees
+type CalendarImportSyncer struct {
+	imports  CalendarImportService
+	interval time.Duration
+}
+
+func NewCalendarImportSyncer(imports CalendarImportService, interval time.Duration) *CalendarImportSyncer {
+	return &CalendarImportSyncer{
+		imports:  imports,
+		interval: interval,
+	}
+}
+
+// Run refreshes the calendars every interval until the context is cancelled
+func (s *CalendarImportSyncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sync(ctx)
+		}
+	}
+}
+
+// Sync makes a single pass. Failed calendars keep their busy time and are retried on the next pass.
+func (s *CalendarImportSyncer) Sync(ctx context.Context) {
+	failed, err := s.imports.RefreshAll(ctx)
+	if err != nil {
+		log.Printf("Failed to refresh calendar imports: %v", err)
+	}
+	if failed > 0 {
+		log.Printf("Failed to refresh %d calendar imports", failed)
+	}
+}
@@ -0,0 +1,367 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/netguard"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+// anyAddress lets the tests fetch calendars from their local test servers
+func anyAddress(netip.Addr) bool {
+	return true
+}
+
+func icsCalendar(lines ...string) string {
+	lines = append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Test//EN"}, lines...)
+	return strings.Join(append(lines, "END:VCALENDAR"), "\r\n") + "\r\n"
+}
+
+func TestCalendarImportService_ImportFile(t *testing.T) {
+	t.Parallel()
+
+	kyiv := loadLocation(t, "Europe/Kyiv")
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	employeeID := 2
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1}
+	business := &entity.Business{ID: 1, Timezone: "Europe/Kyiv"}
+	kyivAt := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, kyiv).UTC()
+	}
+
+	// Weekly on Mondays at 09:00 Kyiv time, the second week is excluded and the third moved,
+	// clocks move forward on March 30 so the fourth occurrence is an hour earlier in UTC
+	weekly := icsCalendar(
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"DTSTART;TZID=Europe/Kyiv:20250310T090000",
+		"DTEND;TZID=Europe/Kyiv:20250310T093000",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE;TZID=Europe/Kyiv:20250317T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"RECURRENCE-ID;TZID=Europe/Kyiv:20250324T090000",
+		"DTSTART;TZID=Europe/Kyiv:20250324T140000",
+		"DTEND;TZID=Europe/Kyiv:20250324T150000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled",
+		"DTSTART:20250312T100000Z",
+		"DTEND:20250312T110000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:free",
+		"DTSTART:20250313T100000Z",
+		"DTEND:20250313T110000Z",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:overlapping",
+		"DTSTART;TZID=Europe/Kyiv:20250310T091500",
+		"DURATION:PT1H",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:past",
+		"DTSTART:20250101T100000Z",
+		"DTEND:20250101T110000Z",
+		"END:VEVENT",
+	)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		calendar string
+		mock     func(c *mocks.CalendarImportRepository)
+		busy     []entity.BusyTime
+		warning  *string
+		err      error
+	}{
+		{
+			name:     "positive: recurring events with exclusions and a moved occurrence",
+			calendar: weekly,
+			mock: func(c *mocks.CalendarImportRepository) {
+				c.On("Upsert", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					args.Get(1).(*entity.CalendarImport).ID = 7
+				})
+			},
+			busy: []entity.BusyTime{
+				// The standup and the overlapping event are merged
+				{StartTime: kyivAt(3, 10, 9, 0), EndTime: kyivAt(3, 10, 10, 15)},
+				{StartTime: kyivAt(3, 24, 14, 0), EndTime: kyivAt(3, 24, 15, 0)},
+				{StartTime: kyivAt(3, 31, 9, 0), EndTime: kyivAt(3, 31, 9, 30)},
+			},
+		},
+		{
+			name: "positive: events with unsupported rules are skipped with a warning",
+			calendar: icsCalendar(
+				"BEGIN:VEVENT",
+				"UID:board",
+				"DTSTART:20250303T100000Z",
+				"DTEND:20250303T110000Z",
+				"RRULE:FREQ=MONTHLY;BYSETPOS=1",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:lunch",
+				"DTSTART;VALUE=DATE:20250311",
+				"END:VEVENT",
+			),
+			mock: func(c *mocks.CalendarImportRepository) {
+				c.On("Upsert", ctx, mock.Anything).Return(nil)
+			},
+			busy: []entity.BusyTime{
+				// All-day events take the whole day in the business timezone
+				{StartTime: kyivAt(3, 11, 0, 0), EndTime: kyivAt(3, 12, 0, 0)},
+			},
+			warning: stringPtr(`skipped events: event "board": unsupported rule part "BYSETPOS"`),
+		},
+		{
+			name:     "negative: not a calendar",
+			calendar: "<html></html>",
+			mock:     func(c *mocks.CalendarImportRepository) {},
+			err:      fmt.Errorf("%w: line 1: invalid content line %q", services.ErrInvalidCalendar, "<html></html>"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			calendarImportRepoMock := mocks.NewCalendarImportRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+
+			// Setup mocks
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil)
+			tc.mock(calendarImportRepoMock)
+
+			var stored []entity.BusyTime
+			if tc.err == nil {
+				calendarImportRepoMock.On("ReplaceBusyTime", ctx, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+					stored = args.Get(2).([]entity.BusyTime)
+				})
+			}
+
+			// Init service
+			calendarImportService := services.NewCalendarImportService(&repository.Repositories{
+				CalendarImport: calendarImportRepoMock,
+				Employee:       employeeRepoMock,
+				Business:       businessRepoMock,
+			}, nil, &fakeClock{now: now})
+
+			// Execute
+			calendarImport, err := calendarImportService.ImportFile(ctx, employeeID, " Work ", []byte(tc.calendar))
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				assert.True(t, errors.Is(err, services.ErrInvalidCalendar))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Work", calendarImport.Name)
+			assert.Nil(t, calendarImport.SourceURL)
+			assert.Equal(t, &now, calendarImport.LastSyncedAt)
+			assert.Equal(t, tc.warning, calendarImport.LastError)
+			assert.Equal(t, tc.busy, stored)
+		})
+	}
+}
+
+func TestCalendarImportService_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	employeeID := 2
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1}
+	business := &entity.Business{ID: 1, Timezone: "UTC"}
+
+	calendar := icsCalendar(
+		"BEGIN:VEVENT",
+		"UID:1",
+		"DTSTART:20250311T100000Z",
+		"DTEND:20250311T110000Z",
+		"END:VEVENT",
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/work.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(calendar))
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name   string
+		url    string
+		policy netguard.AddressPolicy
+		mock   func(c *mocks.CalendarImportRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository)
+		err    error
+	}{
+		{
+			name: "positive: calendar fetched and stored",
+			url:  server.URL + "/work.ics",
+			mock: func(c *mocks.CalendarImportRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository) {
+				e.On("Get", ctx, employeeID).Return(employee, nil)
+				b.On("Get", ctx, business.ID).Return(business, nil)
+				c.On("Upsert", ctx, mock.Anything).Return(nil)
+				c.On("ReplaceBusyTime", ctx, mock.Anything, []entity.BusyTime{
+					{StartTime: time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 11, 11, 0, 0, 0, time.UTC)},
+				}).Return(nil)
+			},
+		},
+		{
+			name: "negative: calendar not found",
+			url:  server.URL + "/missing.ics",
+			mock: func(c *mocks.CalendarImportRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository) {
+				e.On("Get", ctx, employeeID).Return(employee, nil)
+				b.On("Get", ctx, business.ID).Return(business, nil)
+			},
+			err: fmt.Errorf("%w: server responded with 404", services.ErrCalendarUnavailable),
+		},
+		{
+			name: "negative: unsupported scheme",
+			url:  "ftp://example.com/work.ics",
+			mock: func(c *mocks.CalendarImportRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository) {},
+			err:  fmt.Errorf("%w: url must be an absolute http, https or webcal URL", services.ErrInvalidCalendar),
+		},
+		{
+			name:   "negative: loopback address",
+			url:    server.URL + "/work.ics",
+			policy: netguard.PublicAddresses,
+			mock: func(c *mocks.CalendarImportRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository) {
+				e.On("Get", ctx, employeeID).Return(employee, nil)
+				b.On("Get", ctx, business.ID).Return(business, nil)
+			},
+			err: fmt.Errorf("%w: url must point to a public address", services.ErrInvalidCalendar),
+		},
+		{
+			name:   "negative: cloud metadata address",
+			url:    "http://169.254.169.254/latest/meta-data",
+			policy: netguard.PublicAddresses,
+			mock: func(c *mocks.CalendarImportRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository) {
+				e.On("Get", ctx, employeeID).Return(employee, nil)
+				b.On("Get", ctx, business.ID).Return(business, nil)
+			},
+			err: fmt.Errorf("%w: url must point to a public address", services.ErrInvalidCalendar),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			calendarImportRepoMock := mocks.NewCalendarImportRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+
+			// Setup mocks
+			tc.mock(calendarImportRepoMock, employeeRepoMock, businessRepoMock)
+
+			policy := tc.policy
+			if policy == nil {
+				policy = anyAddress
+			}
+
+			// Init service
+			calendarImportService := services.NewCalendarImportService(&repository.Repositories{
+				CalendarImport: calendarImportRepoMock,
+				Employee:       employeeRepoMock,
+				Business:       businessRepoMock,
+			}, policy, &fakeClock{now: now})
+
+			// Execute
+			calendarImport, err := calendarImportService.Subscribe(ctx, employeeID, "Work", tc.url)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &tc.url, calendarImport.SourceURL)
+			assert.Nil(t, calendarImport.LastError)
+		})
+	}
+}
+
+func TestCalendarImportService_RefreshAll(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	employee := &entity.Employee{ID: 2, BusinessID: 1}
+	business := &entity.Business{ID: 1, Timezone: "UTC"}
+
+	calendar := icsCalendar(
+		"BEGIN:VEVENT",
+		"UID:1",
+		"DTSTART:20250311T100000Z",
+		"DTEND:20250311T110000Z",
+		"END:VEVENT",
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.ics" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(calendar))
+	}))
+	t.Cleanup(server.Close)
+
+	working, broken := server.URL+"/work.ics", server.URL+"/broken.ics"
+
+	ctx := context.Background()
+
+	calendarImportRepoMock := mocks.NewCalendarImportRepository(t)
+	employeeRepoMock := mocks.NewEmployeeRepository(t)
+	businessRepoMock := mocks.NewBusinessRepository(t)
+
+	employeeRepoMock.On("Get", ctx, employee.ID).Return(employee, nil)
+	businessRepoMock.On("Get", ctx, business.ID).Return(business, nil)
+	calendarImportRepoMock.On("ListSubscribed", ctx).Return([]entity.CalendarImport{
+		{ID: 1, EmployeeID: employee.ID, Name: "Work", SourceURL: &working},
+		{ID: 2, EmployeeID: employee.ID, Name: "Broken", SourceURL: &broken},
+	}, nil)
+	// The working calendar replaces its busy time, the broken one keeps it and records the error
+	calendarImportRepoMock.On("ReplaceBusyTime", ctx, mock.MatchedBy(func(i *entity.CalendarImport) bool {
+		return i.ID == 1 && i.LastError == nil
+	}), mock.Anything).Return(nil).Once()
+	calendarImportRepoMock.On("UpdateError", ctx, mock.MatchedBy(func(i *entity.CalendarImport) bool {
+		return i.ID == 2 && i.LastError != nil && *i.LastError == "calendar unavailable: server responded with 500"
+	})).Return(nil).Once()
+
+	calendarImportService := services.NewCalendarImportService(&repository.Repositories{
+		CalendarImport: calendarImportRepoMock,
+		Employee:       employeeRepoMock,
+		Business:       businessRepoMock,
+	}, anyAddress, &fakeClock{now: now})
+
+	failed, err := calendarImportService.RefreshAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+}
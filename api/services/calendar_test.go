@@ -0,0 +1,259 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestCalendarService_Feed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	from, to := now.Add(-90*24*time.Hour), now.Add(365*24*time.Hour)
+	employeeID, clientID := 2, 5
+	reason := "client is ill"
+	phone := "+380501234567"
+
+	appointments := []entity.Appointment{
+		{
+			ID: 1, BusinessID: 1, ClientID: 5, EmployeeID: 2,
+			StartTime: time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC),
+			Status:    entity.AppointmentStatusScheduled,
+			Client:    &entity.User{FullName: "Olena Shevchenko", Phone: &phone},
+			Employee:  &entity.User{FullName: "Anna Koval"},
+			Service:   &entity.BusinessService{Name: "Haircut"},
+		},
+		{
+			ID: 2, BusinessID: 1, ClientID: 5, EmployeeID: 2,
+			StartTime:          time.Date(2025, 3, 14, 8, 0, 0, 0, time.UTC),
+			EndTime:            time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+			Status:             entity.AppointmentStatusCancelled,
+			CancellationReason: &reason,
+			Client:             &entity.User{FullName: "Olena Shevchenko"},
+			Employee:           &entity.User{FullName: "Anna Koval"},
+			Service:            &entity.BusinessService{Name: "Coloring"},
+		},
+		{
+			ID: 3, BusinessID: 1, ClientID: 6, EmployeeID: 2,
+			StartTime: time.Date(2025, 3, 15, 8, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2025, 3, 15, 9, 0, 0, 0, time.UTC),
+			Status:    entity.AppointmentStatusHeld,
+			Client:    &entity.User{FullName: "Checkout Client"},
+			Service:   &entity.BusinessService{Name: "Haircut"},
+		},
+	}
+	business := &entity.Business{ID: 1, Name: "Salon", Timezone: "Europe/Kyiv"}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		token    string
+		mock     func(c *mocks.CalendarFeedRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository, a *mocks.AppointmentRepository)
+		contains []string
+		excludes []string
+		err      error
+	}{
+		{
+			name:  "positive: employee feed",
+			token: "employee-token",
+			mock: func(c *mocks.CalendarFeedRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository, a *mocks.AppointmentRepository) {
+				c.On("GetByToken", ctx, "employee-token").Return(&entity.CalendarFeed{EmployeeID: &employeeID}, nil)
+				e.On("Get", ctx, 2).Return(&entity.Employee{ID: 2, BusinessID: 1}, nil)
+				b.On("Get", ctx, 1).Return(business, nil)
+				a.On("ListByEmployee", ctx, 2, from, to).Return(appointments, nil)
+			},
+			contains: []string{
+				"X-WR-CALNAME:Salon",
+				"BEGIN:VTIMEZONE\r\nTZID:Europe/Kyiv",
+				"UID:appointment-1@bookings.ppc-project\r\n",
+				"DTSTART;TZID=Europe/Kyiv:20250312T100000\r\n",
+				"SUMMARY:Haircut - Olena Shevchenko\r\n",
+				`DESCRIPTION:Phone: +380501234567`,
+				"STATUS:CONFIRMED\r\n",
+				"UID:appointment-2@bookings.ppc-project\r\n",
+				`DESCRIPTION:Cancelled: client is ill`,
+				"STATUS:CANCELLED\r\n",
+			},
+			excludes: []string{"appointment-3@", "Checkout Client"},
+		},
+		{
+			name:  "positive: client feed",
+			token: "client-token",
+			mock: func(c *mocks.CalendarFeedRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository, a *mocks.AppointmentRepository) {
+				c.On("GetByToken", ctx, "client-token").Return(&entity.CalendarFeed{ClientID: &clientID}, nil)
+				b.On("Get", ctx, 1).Return(business, nil).Once()
+				a.On("ListByClient", ctx, 5, from, to).Return(appointments[:2], nil)
+			},
+			contains: []string{
+				"X-WR-CALNAME:My appointments",
+				"SUMMARY:Haircut at Salon\r\n",
+				"DESCRIPTION:With Anna Koval\r\n",
+				"LOCATION:Salon\r\n",
+				`DESCRIPTION:With Anna Koval\nCancelled: client is ill`,
+			},
+			excludes: []string{"+380501234567"},
+		},
+		{
+			name:  "negative: revoked token",
+			token: "revoked",
+			mock: func(c *mocks.CalendarFeedRepository, e *mocks.EmployeeRepository, b *mocks.BusinessRepository, a *mocks.AppointmentRepository) {
+				c.On("GetByToken", ctx, "revoked").Return(nil, repository.ErrNotFound)
+			},
+			err: fmt.Errorf("invalid feed token: %w", repository.ErrNotFound),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			calendarRepoMock := mocks.NewCalendarFeedRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+
+			// Setup mocks
+			tc.mock(calendarRepoMock, employeeRepoMock, businessRepoMock, appointmentRepoMock)
+
+			// Init service
+			service := services.NewCalendarService(&repository.Repositories{
+				Calendar:    calendarRepoMock,
+				Employee:    employeeRepoMock,
+				Business:    businessRepoMock,
+				Appointment: appointmentRepoMock,
+			}, &fakeClock{now: now})
+
+			// Execute
+			calendar, err := service.Feed(ctx, tc.token)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			output := string(calendar)
+			assert.True(t, strings.HasPrefix(output, "BEGIN:VCALENDAR\r\n"))
+			for _, s := range tc.contains {
+				assert.Contains(t, output, s)
+			}
+			for _, s := range tc.excludes {
+				assert.NotContains(t, output, s)
+			}
+		})
+	}
+}
+
+func TestCalendarService_CreateClientFeed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name string
+		mock func(u *mocks.UserRepository, c *mocks.CalendarFeedRepository)
+		err  error
+	}{
+		{
+			name: "positive: new token replaces the previous one",
+			mock: func(u *mocks.UserRepository, c *mocks.CalendarFeedRepository) {
+				u.On("Get", ctx, 5).Return(&entity.User{ID: 5, Role: entity.RoleClient}, nil)
+				c.On("Replace", ctx, mock.MatchedBy(func(feed *entity.CalendarFeed) bool {
+					return feed.ClientID != nil && *feed.ClientID == 5 && feed.EmployeeID == nil && len(feed.Token) == 32
+				})).Return(nil)
+			},
+		},
+		{
+			name: "negative: user is not a client",
+			mock: func(u *mocks.UserRepository, c *mocks.CalendarFeedRepository) {
+				u.On("Get", ctx, 5).Return(&entity.User{ID: 5, Role: entity.RoleAdmin}, nil)
+			},
+			err: fmt.Errorf("user is not a client"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			userRepoMock := mocks.NewUserRepository(t)
+			calendarRepoMock := mocks.NewCalendarFeedRepository(t)
+
+			// Setup mocks
+			tc.mock(userRepoMock, calendarRepoMock)
+
+			// Init service
+			service := services.NewCalendarService(&repository.Repositories{
+				User:     userRepoMock,
+				Calendar: calendarRepoMock,
+			}, nil)
+
+			// Execute
+			feed, err := service.CreateClientFeed(ctx, 5)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, feed.Token, 32)
+			}
+		})
+	}
+}
+
+func TestCalendarService_AppointmentCalendar(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	// Init mocks
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	businessRepoMock := mocks.NewBusinessRepository(t)
+
+	// Setup mocks
+	appointmentRepoMock.On("Get", ctx, 1).Return(&entity.Appointment{
+		ID: 1, BusinessID: 1,
+		StartTime: time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC),
+		Status:    entity.AppointmentStatusScheduled,
+		Service:   &entity.BusinessService{Name: "Haircut"},
+	}, nil)
+	appointmentRepoMock.On("Get", ctx, 2).Return(&entity.Appointment{ID: 2, Status: entity.AppointmentStatusHeld}, nil)
+	businessRepoMock.On("Get", ctx, 1).Return(&entity.Business{ID: 1, Name: "Salon", Timezone: "UTC"}, nil)
+
+	// Init service
+	service := services.NewCalendarService(&repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Business:    businessRepoMock,
+	}, &fakeClock{now: now})
+
+	// Execute
+	calendar, err := service.AppointmentCalendar(ctx, 1)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Contains(t, string(calendar), "\r\nDTSTART:20250312T080000Z\r\n")
+	assert.Contains(t, string(calendar), "\r\nSUMMARY:Haircut at Salon\r\n")
+
+	_, err = service.AppointmentCalendar(ctx, 2)
+	assert.EqualError(t, err, "appointment is not booked")
+}
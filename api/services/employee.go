@@ -35,6 +35,12 @@ func (s *employeeService) Create(ctx context.Context, employee *entity.Employee)
 		return fmt.Errorf("user does not belong to the business")
 	}
 
+	if employee.LocationID != nil {
+		if _, err := getBusinessLocation(ctx, s.repos, employee.BusinessID, *employee.LocationID); err != nil {
+			return err
+		}
+	}
+
 	// Update user role to employee if it's not already
 	if user.Role != entity.RoleEmployee {
 		user.Role = entity.RoleEmployee
@@ -80,6 +86,18 @@ func (s *employeeService) Update(ctx context.Context, employee *entity.Employee)
 	existing.Specialization = employee.Specialization
 	existing.IsActive = employee.IsActive
 
+	// The branch is kept unless another one is given, location 0 removes it
+	if employee.LocationID != nil {
+		if *employee.LocationID == 0 {
+			existing.LocationID = nil
+		} else {
+			if _, err := getBusinessLocation(ctx, s.repos, existing.BusinessID, *employee.LocationID); err != nil {
+				return err
+			}
+			existing.LocationID = employee.LocationID
+		}
+	}
+
 	if err := s.repos.Employee.Update(ctx, existing); err != nil {
 		return fmt.Errorf("failed to update employee: %w", err)
 	}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// ErrHoldExpired is returned when a slot hold has expired, was released or was already confirmed
+var ErrHoldExpired = errors.New("slot hold has expired")
+
+// slotHoldDuration is how long a slot is reserved for a client during checkout
+const slotHoldDuration = 5 * time.Minute
+
+func (s *appointmentService) Hold(ctx context.Context, appointment *entity.Appointment) (*SlotHold, error) {
+	token, err := newHoldToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate hold token: %w", err)
+	}
+	expiresAt := s.clock.Now().Add(slotHoldDuration).UTC()
+
+	appointment.Status = entity.AppointmentStatusHeld
+	appointment.HoldToken = &token
+	appointment.HoldExpiresAt = &expiresAt
+
+	// The hold is validated and stored like a booking, so only free slots can be held
+	// and a concurrent hold or booking of the slot gets ErrSlotUnavailable
+	if err := s.create(ctx, appointment); err != nil {
+		return nil, err
+	}
+
+	return &SlotHold{
+		Token:       token,
+		ExpiresAt:   expiresAt,
+		Appointment: appointment,
+	}, nil
+}
+
+func (s *appointmentService) ReleaseHold(ctx context.Context, token string) error {
+	if err := s.repos.Appointment.DeleteHold(ctx, token); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrHoldExpired
+		}
+		return fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	return nil
+}
+
+func (s *appointmentService) DeleteExpiredHolds(ctx context.Context) (int, error) {
+	deleted, err := s.repos.Appointment.DeleteExpiredHolds(ctx, s.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired holds: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// confirmHold books the held appointment of the hold token. Fields set on the appointment must match the hold.
+func (s *appointmentService) confirmHold(ctx context.Context, appointment *entity.Appointment) error {
+	hold, err := s.repos.Appointment.GetByHoldToken(ctx, *appointment.HoldToken)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrHoldExpired
+		}
+		return fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold.Status != entity.AppointmentStatusHeld || hold.HoldExpiresAt == nil || !hold.HoldExpiresAt.After(s.clock.Now()) {
+		return ErrHoldExpired
+	}
+
+	if hold.ClientID != appointment.ClientID || hold.BusinessID != appointment.BusinessID {
+		return fmt.Errorf("slot hold belongs to another client")
+	}
+	if (appointment.ServiceID != 0 && appointment.ServiceID != hold.ServiceID) ||
+		(appointment.EmployeeID != 0 && appointment.EmployeeID != hold.EmployeeID) ||
+		(!appointment.StartTime.IsZero() && !appointment.StartTime.Equal(hold.StartTime)) {
+		return fmt.Errorf("appointment does not match the slot hold")
+	}
+
+	confirmed, err := s.repos.Appointment.ConfirmHold(ctx, *appointment.HoldToken, appointment.ReminderTime)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return ErrHoldExpired
+		case errors.Is(err, repository.ErrConflict):
+			return ErrSlotUnavailable
+		}
+		return fmt.Errorf("failed to confirm hold: %w", err)
+	}
+
+	*appointment = *confirmed
+	return nil
+}
+
+func newHoldToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,347 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestAppointmentService_Hold(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		businessRepo    *mocks.BusinessRepository
+		userRepo        *mocks.UserRepository
+		employeeRepo    *mocks.EmployeeRepository
+		serviceRepo     *mocks.BusinessServiceRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type expected struct {
+		err error
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	employeeID := 1
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, Capacity: 1, IsActive: true}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ctx := context.Background()
+
+	// mockEmployee sets up the checks of the requested employee and their availability
+	mockEmployee := func(m mocksForExecution, free bool) {
+		m.employeeRepo.On("Get", ctx, employeeID).Return(&entity.Employee{ID: employeeID, BusinessID: businessID, IsActive: true}, nil)
+		m.employeeRepo.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+		m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, start).Return(schedule, nil)
+		m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, start, end, 0).Return(free, nil)
+	}
+
+	testCases := []struct {
+		name     string
+		service  *entity.BusinessService
+		mock     func(m mocksForExecution)
+		expected expected
+	}{
+		{
+			name:    "positive: free slot held",
+			service: service,
+			mock: func(m mocksForExecution) {
+				mockEmployee(m, true)
+				m.appointmentRepo.On("Create", ctx, mock.MatchedBy(func(a *entity.Appointment) bool {
+					return a.Status == entity.AppointmentStatusHeld && a.HoldToken != nil && a.HoldExpiresAt != nil
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "negative: slot already taken",
+			service: service,
+			mock: func(m mocksForExecution) {
+				mockEmployee(m, false)
+			},
+			expected: expected{
+				err: fmt.Errorf("invalid appointment time: %w", services.ErrSlotUnavailable),
+			},
+		},
+		{
+			name:    "negative: group service",
+			service: &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, Capacity: 5, IsActive: true},
+			mock:    func(m mocksForExecution) {},
+			expected: expected{
+				err: fmt.Errorf("seats of group services cannot be held"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			m := mocksForExecution{
+				appointmentRepo: mocks.NewAppointmentRepository(t),
+				businessRepo:    mocks.NewBusinessRepository(t),
+				userRepo:        mocks.NewUserRepository(t),
+				employeeRepo:    mocks.NewEmployeeRepository(t),
+				serviceRepo:     mocks.NewBusinessServiceRepository(t),
+				scheduleRepo:    mocks.NewScheduleRepository(t),
+			}
+
+			// Common mocks
+			m.businessRepo.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID, Timezone: "UTC"}, nil)
+			m.userRepo.On("Get", ctx, clientID).Return(&entity.User{ID: clientID, BusinessID: businessID, Role: entity.RoleClient}, nil)
+			m.serviceRepo.On("Get", ctx, serviceID).Return(tc.service, nil)
+
+			// Setup mocks
+			tc.mock(m)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: m.appointmentRepo,
+				Business:    m.businessRepo,
+				User:        m.userRepo,
+				Employee:    m.employeeRepo,
+				Service:     m.serviceRepo,
+				Schedule:    m.scheduleRepo,
+			}, nil, nil, nil)
+
+			// Execute
+			hold, err := appointmentService.Hold(ctx, &entity.Appointment{
+				BusinessID: businessID,
+				ClientID:   clientID,
+				EmployeeID: employeeID,
+				ServiceID:  serviceID,
+				StartTime:  start,
+			})
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, hold.Token)
+				assert.Equal(t, hold.Token, *hold.Appointment.HoldToken)
+				assert.True(t, hold.ExpiresAt.After(now))
+				assert.Equal(t, end, hold.Appointment.EndTime)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_CreateWithHoldToken(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err error
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	employeeID := 1
+	holdID := 5
+	token := "3f2a9c"
+	reminder := 60
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(3 * time.Minute)
+	expiredAt := now.Add(-time.Minute)
+
+	holdWith := func(expiresAt *time.Time) *entity.Appointment {
+		return &entity.Appointment{
+			ID: holdID, BusinessID: businessID, ClientID: clientID, EmployeeID: employeeID, ServiceID: serviceID,
+			StartTime: start, EndTime: start.Add(30 * time.Minute),
+			Status: entity.AppointmentStatusHeld, HoldToken: &token, HoldExpiresAt: expiresAt,
+		}
+	}
+	confirmed := &entity.Appointment{
+		ID: holdID, BusinessID: businessID, ClientID: clientID, EmployeeID: employeeID, ServiceID: serviceID,
+		StartTime: start, EndTime: start.Add(30 * time.Minute),
+		Status: entity.AppointmentStatusScheduled, ReminderTime: &reminder,
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name        string
+		appointment *entity.Appointment
+		mock        func(m *mocks.AppointmentRepository)
+		expected    expected
+	}{
+		{
+			name:        "positive: hold confirmed with the token only",
+			appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, ReminderTime: &reminder, HoldToken: &token},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("GetByHoldToken", ctx, token).Return(holdWith(&expiresAt), nil)
+				m.On("ConfirmHold", ctx, token, &reminder).Return(confirmed, nil)
+			},
+		},
+		{
+			name:        "negative: hold expired",
+			appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, HoldToken: &token},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("GetByHoldToken", ctx, token).Return(holdWith(&expiredAt), nil)
+			},
+			expected: expected{
+				err: services.ErrHoldExpired,
+			},
+		},
+		{
+			name:        "negative: hold already swept",
+			appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, HoldToken: &token},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("GetByHoldToken", ctx, token).Return(nil, repository.ErrNotFound)
+			},
+			expected: expected{
+				err: services.ErrHoldExpired,
+			},
+		},
+		{
+			name:        "negative: hold expired while confirming",
+			appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, HoldToken: &token},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("GetByHoldToken", ctx, token).Return(holdWith(&expiresAt), nil)
+				m.On("ConfirmHold", ctx, token, (*int)(nil)).Return(nil, repository.ErrNotFound)
+			},
+			expected: expected{
+				err: services.ErrHoldExpired,
+			},
+		},
+		{
+			name:        "negative: hold of another client",
+			appointment: &entity.Appointment{BusinessID: businessID, ClientID: 11, HoldToken: &token},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("GetByHoldToken", ctx, token).Return(holdWith(&expiresAt), nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("slot hold belongs to another client"),
+			},
+		},
+		{
+			name:        "negative: start time differs from the hold",
+			appointment: &entity.Appointment{BusinessID: businessID, ClientID: clientID, StartTime: start.Add(time.Hour), HoldToken: &token},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("GetByHoldToken", ctx, token).Return(holdWith(&expiresAt), nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("appointment does not match the slot hold"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+
+			// Setup mocks
+			tc.mock(appointmentRepoMock)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			err := appointmentService.Create(ctx, tc.appointment)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, confirmed, tc.appointment)
+			}
+		})
+	}
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	// Init mocks
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	waitlistRepoMock := mocks.NewWaitlistRepository(t)
+
+	// Setup mocks. Holds expire by the service clock, a failing step does not stop the others.
+	appointmentRepoMock.On("DeleteExpiredHolds", ctx, now).Return(0, fmt.Errorf("db error"))
+	waitlistRepoMock.On("ListExpiredOffers", ctx, mock.Anything).Return(nil, nil)
+
+	// Init services
+	repos := &repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Waitlist:    waitlistRepoMock,
+	}
+	appointments := services.NewAppointmentService(repos, nil, nil, &fakeClock{now: now})
+	sweeper := services.NewSweeper(appointments, services.NewWaitlistService(repos, appointments), time.Minute)
+
+	// Execute
+	sweeper.Sweep(ctx)
+}
+
+func TestSweeper_RunStopsWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Init mocks, every pass finds nothing to release
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	waitlistRepoMock := mocks.NewWaitlistRepository(t)
+	appointmentRepoMock.On("DeleteExpiredHolds", ctx, mock.Anything).Return(0, nil).Maybe()
+	waitlistRepoMock.On("ListExpiredOffers", ctx, mock.Anything).Return(nil, nil).Maybe()
+
+	repos := &repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Waitlist:    waitlistRepoMock,
+	}
+	appointments := services.NewAppointmentService(repos, nil, nil, nil)
+	sweeper := services.NewSweeper(appointments, services.NewWaitlistService(repos, appointments), time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sweeper.Run(ctx)
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after the context was cancelled")
+	}
+}
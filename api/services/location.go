@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// ErrInvalidLocation is returned when a location has invalid details or does not belong to the business
+var ErrInvalidLocation = errors.New("invalid location")
+
+type locationService struct {
+	repos *repository.Repositories
+}
+
+func NewLocationService(repos *repository.Repositories) LocationService {
+	return &locationService{
+		repos: repos,
+	}
+}
+
+func (s *locationService) Create(ctx context.Context, location *entity.Location) error {
+	// Validate business existence
+	business, err := s.repos.Business.Get(ctx, location.BusinessID)
+	if err != nil {
+		return fmt.Errorf("invalid business: %w", err)
+	}
+
+	// Branches are in the business timezone unless another one is given
+	if strings.TrimSpace(location.Timezone) == "" {
+		location.Timezone = business.Timezone
+	}
+	if err := validateLocation(location); err != nil {
+		return err
+	}
+	location.IsActive = true
+
+	if err := s.repos.Location.Create(ctx, location); err != nil {
+		return fmt.Errorf("failed to create location: %w", err)
+	}
+
+	return nil
+}
+
+func (s *locationService) Get(ctx context.Context, id int) (*entity.Location, error) {
+	location, err := s.repos.Location.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location: %w", err)
+	}
+
+	return location, nil
+}
+
+func (s *locationService) List(ctx context.Context, businessID int) ([]entity.Location, error) {
+	locations, err := s.repos.Location.List(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+func (s *locationService) Update(ctx context.Context, location *entity.Location) error {
+	if _, err := s.repos.Location.Get(ctx, location.ID); err != nil {
+		return fmt.Errorf("failed to get location: %w", err)
+	}
+
+	if err := validateLocation(location); err != nil {
+		return err
+	}
+
+	if err := s.repos.Location.Update(ctx, location); err != nil {
+		return fmt.Errorf("failed to update location: %w", err)
+	}
+
+	return nil
+}
+
+func (s *locationService) Delete(ctx context.Context, id int) error {
+	if err := s.repos.Location.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete location: %w", err)
+	}
+
+	return nil
+}
+
+// validateLocation requires a name, an address and a known timezone. Coordinates are optional but given together.
+func validateLocation(location *entity.Location) error {
+	location.Name = strings.TrimSpace(location.Name)
+	if location.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLocation)
+	}
+
+	location.Address = strings.TrimSpace(location.Address)
+	if location.Address == "" {
+		return fmt.Errorf("%w: address is required", ErrInvalidLocation)
+	}
+
+	location.Timezone = strings.TrimSpace(location.Timezone)
+	if err := validateTimezone(location.Timezone); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLocation, err)
+	}
+
+	if (location.Latitude == nil) != (location.Longitude == nil) {
+		return fmt.Errorf("%w: latitude and longitude must be given together", ErrInvalidLocation)
+	}
+	if location.Latitude != nil && (*location.Latitude < -90 || *location.Latitude > 90) {
+		return fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidLocation)
+	}
+	if location.Longitude != nil && (*location.Longitude < -180 || *location.Longitude > 180) {
+		return fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidLocation)
+	}
+
+	return nil
+}
+
+// getBusinessLocation returns the active location of the business
+func getBusinessLocation(ctx context.Context, repos *repository.Repositories, businessID, locationID int) (*entity.Location, error) {
+	location, err := repos.Location.Get(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: location not found", ErrInvalidLocation)
+		}
+		return nil, fmt.Errorf("failed to get location: %w", err)
+	}
+	if location.BusinessID != businessID {
+		return nil, fmt.Errorf("%w: location does not belong to the business", ErrInvalidLocation)
+	}
+	if !location.IsActive {
+		return nil, fmt.Errorf("%w: location is not active", ErrInvalidLocation)
+	}
+
+	return location, nil
+}
+
+// sameLocation reports whether the optional location is the given one
+func sameLocation(locationID *int, id int) bool {
+	return locationID != nil && *locationID == id
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestLocationService_Create(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	latitude, longitude := 50.45, 30.52
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		location *entity.Location
+		mock     func(l *mocks.LocationRepository)
+		expected *entity.Location
+		err      error
+	}{
+		{
+			name:     "positive: business timezone used when omitted",
+			location: &entity.Location{BusinessID: businessID, Name: " Center ", Address: "Khreshchatyk 1", Latitude: &latitude, Longitude: &longitude},
+			mock: func(l *mocks.LocationRepository) {
+				l.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			expected: &entity.Location{BusinessID: businessID, Name: "Center", Address: "Khreshchatyk 1", Timezone: "Europe/Kyiv", Latitude: &latitude, Longitude: &longitude, IsActive: true},
+		},
+		{
+			name:     "positive: own timezone",
+			location: &entity.Location{BusinessID: businessID, Name: "Warsaw", Address: "Marszałkowska 1", Timezone: "Europe/Warsaw"},
+			mock: func(l *mocks.LocationRepository) {
+				l.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			expected: &entity.Location{BusinessID: businessID, Name: "Warsaw", Address: "Marszałkowska 1", Timezone: "Europe/Warsaw", IsActive: true},
+		},
+		{
+			name:     "negative: empty address",
+			location: &entity.Location{BusinessID: businessID, Name: "Center"},
+			mock:     func(l *mocks.LocationRepository) {},
+			err:      fmt.Errorf("%w: address is required", services.ErrInvalidLocation),
+		},
+		{
+			name:     "negative: latitude without longitude",
+			location: &entity.Location{BusinessID: businessID, Name: "Center", Address: "Khreshchatyk 1", Latitude: &latitude},
+			mock:     func(l *mocks.LocationRepository) {},
+			err:      fmt.Errorf("%w: latitude and longitude must be given together", services.ErrInvalidLocation),
+		},
+		{
+			name:     "negative: unknown timezone",
+			location: &entity.Location{BusinessID: businessID, Name: "Center", Address: "Khreshchatyk 1", Timezone: "Mars/Olympus"},
+			mock:     func(l *mocks.LocationRepository) {},
+			err:      fmt.Errorf("%w: invalid timezone: %q", services.ErrInvalidLocation, "Mars/Olympus"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			locationRepoMock := mocks.NewLocationRepository(t)
+
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID, Timezone: "Europe/Kyiv"}, nil)
+			tc.mock(locationRepoMock)
+
+			locationService := services.NewLocationService(&repository.Repositories{
+				Business: businessRepoMock,
+				Location: locationRepoMock,
+			})
+
+			err := locationService.Create(ctx, tc.location)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.location)
+		})
+	}
+}
+
+func TestAppointmentService_GetServiceSlotsAtLocation(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	serviceID := 1
+	locationID := 7
+	otherLocationID := 8
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, kyiv)
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 60, IsActive: true}
+
+	ctx := context.Background()
+
+	// Init mocks
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	businessRepoMock := mocks.NewBusinessRepository(t)
+	employeeRepoMock := mocks.NewEmployeeRepository(t)
+	serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+	scheduleRepoMock := mocks.NewScheduleRepository(t)
+	locationRepoMock := mocks.NewLocationRepository(t)
+
+	serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+	businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID, Timezone: "UTC"}, nil).Maybe()
+	locationRepoMock.On("Get", ctx, locationID).Return(&entity.Location{ID: locationID, BusinessID: businessID, Timezone: "Europe/Kyiv", IsActive: true}, nil)
+
+	// Only the second employee works at the branch, its schedule is in the branch timezone
+	employeeRepoMock.On("ListByServiceID", ctx, serviceID).Return([]entity.Employee{
+		{ID: 1, BusinessID: businessID, IsActive: true},
+		{ID: 2, BusinessID: businessID, IsActive: true, LocationID: &locationID},
+		{ID: 3, BusinessID: businessID, IsActive: true, LocationID: &otherLocationID},
+	}, nil)
+	scheduleRepoMock.On("ListTemplates", ctx, 2).Return([]entity.ScheduleTemplate{
+		{EmployeeID: 2, DayOfWeek: int(day.Weekday()), StartTime: clock(10, 0), EndTime: clock(12, 0)},
+	}, nil)
+	scheduleRepoMock.On("ListOverrides", ctx, 2, mock.Anything, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListBusyTime", ctx, 2, mock.Anything, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	appointmentRepoMock.On("ListByEmployee", ctx, 2, day, day.AddDate(0, 0, 1)).Return(nil, nil)
+
+	// Init service
+	appointmentService := services.NewAppointmentService(&repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Business:    businessRepoMock,
+		Employee:    employeeRepoMock,
+		Service:     serviceRepoMock,
+		Schedule:    scheduleRepoMock,
+		Location:    locationRepoMock,
+	}, nil, nil, nil)
+
+	// Execute
+	slots, err := appointmentService.GetServiceSlots(ctx, serviceID, locationID, date)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.True(t, slots[0].StartTime.Equal(day.Add(10*time.Hour)))
+	assert.True(t, slots[1].StartTime.Equal(day.Add(11*time.Hour)))
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// Notifier informs clients and employees about appointment events
+type Notifier interface {
+	Notify(ctx context.Context, event string, appointment *entity.Appointment, business *entity.Business, loc *time.Location) error
+}
+
+// appointmentBusiness returns the business of the appointment and the timezone its times are shown in,
+// the timezone of the appointment's branch or of the business for appointments without one
+func appointmentBusiness(ctx context.Context, repos *repository.Repositories, appointment *entity.Appointment) (*entity.Business, *time.Location, error) {
+	business, err := repos.Business.Get(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get business: %w", err)
+	}
+
+	loc, err := branchLocation(ctx, repos, business, appointment.LocationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return business, loc, nil
+}
+
+// notify sends the event of a stored appointment. Notifications never fail the change that caused them,
+// so errors are only logged.
+func (s *appointmentService) notify(ctx context.Context, event string, appointmentID int) {
+	if s.notifier == nil {
+		return
+	}
+
+	appointment, err := s.repos.Appointment.Get(ctx, appointmentID)
+	if err != nil {
+		log.Printf("failed to get appointment %d for %s notification: %v", appointmentID, event, err)
+		return
+	}
+
+	business, loc, err := appointmentBusiness(ctx, s.repos, appointment)
+	if err != nil {
+		log.Printf("failed to get business %d for %s notification: %v", appointment.BusinessID, event, err)
+		return
+	}
+
+	if err := s.notifier.Notify(ctx, event, appointment, business, loc); err != nil {
+		log.Printf("failed to send %s notification for appointment %d: %v", event, appointmentID, err)
+	}
+}
+
+// NotificationConsumer notifies clients and employees about booked, rescheduled and cancelled appointments
+type NotificationConsumer struct {
+	repos    *repository.Repositories
+	notifier Notifier
+}
+
+func NewNotificationConsumer(repos *repository.Repositories, notifier Notifier) *NotificationConsumer {
+	return &NotificationConsumer{
+		repos:    repos,
+		notifier: notifier,
+	}
+}
+
+func (c *NotificationConsumer) Name() string {
+	return "notifications"
+}
+
+func (c *NotificationConsumer) Consume(ctx context.Context, event *entity.DomainEvent) error {
+	switch event.EventType {
+	case entity.EventAppointmentCreated, entity.EventAppointmentRescheduled, entity.EventAppointmentCancelled:
+	default:
+		return nil
+	}
+
+	// The payload is the appointment as it was right after the change
+	var appointment entity.Appointment
+	if err := json.Unmarshal(event.Payload, &appointment); err != nil {
+		return fmt.Errorf("invalid appointment payload: %w", err)
+	}
+
+	business, loc, err := appointmentBusiness(ctx, c.repos, &appointment)
+	if err != nil {
+		return err
+	}
+
+	// A returned error makes the relay deliver the event again. Notify only fails when no message went out,
+	// so the messages already sent are not repeated.
+	return c.notifier.Notify(ctx, event.EventType, &appointment, business, loc)
+}
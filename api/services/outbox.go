@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// outboxBatchSize is the number of events claimed in a single pass
+	outboxBatchSize = 100
+	// outboxClaimLease is how long a claimed event is reserved for the relay that claimed it.
+	// Events of a relay that stops while publishing are retried once the lease passes.
+	outboxClaimLease = 5 * time.Minute
+	// outboxMaxAttempts is the number of attempts after which an event is dead-lettered
+	outboxMaxAttempts = 10
+	// outboxRetryBase is the delay before the first retry, doubled on every following one up to outboxRetryMax
+	outboxRetryBase = 30 * time.Second
+	outboxRetryMax  = time.Hour
+)
+
+// EventConsumer handles the domain events published by the relay. Events are delivered at least once,
+// so consumers must tolerate receiving an event again. Consumers ignore the event types they do not handle.
+type EventConsumer interface {
+	// Name identifies the consumer in the delivery records of events and must not change between releases
+	Name() string
+	Consume(ctx context.Context, event *entity.DomainEvent) error
+}
+
+// EventRelay publishes the pending events of the outbox to its consumers. An event is retried with
+// exponential backoff until every consumer has handled it, consumers that already did are skipped.
+// Events still failing after outboxMaxAttempts are marked dead.
+type EventRelay struct {
+	repos     *repository.Repositories
+	consumers []EventConsumer
+	clock     Clock
+	interval  time.Duration
+}
+
+// NewEventRelay creates the relay. The clock defaults to the system clock when nil.
+func NewEventRelay(repos *repository.Repositories, consumers []EventConsumer, clock Clock, interval time.Duration) *EventRelay {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &EventRelay{
+		repos:     repos,
+		consumers: consumers,
+		clock:     clock,
+		interval:  interval,
+	}
+}
+
+// Run relays pending events every interval until the context is cancelled
+func (r *EventRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Relay(ctx); err != nil {
+				log.Printf("Failed to relay domain events: %v", err)
+			}
+		}
+	}
+}
+
+// Relay publishes the events that are due now and returns the number of events handled by every consumer
+func (r *EventRelay) Relay(ctx context.Context) (int, error) {
+	events, err := r.repos.Event.ClaimPending(ctx, r.clock.Now(), outboxClaimLease, outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim domain events: %w", err)
+	}
+
+	published := 0
+	for i := range events {
+		event := &events[i]
+
+		err := r.publish(ctx, event)
+		if err == nil {
+			if err := r.repos.Event.MarkPublished(ctx, event.ID, r.clock.Now()); err != nil {
+				log.Printf("Failed to mark domain event %d published: %v", event.ID, err)
+			}
+			published++
+			continue
+		}
+
+		if event.Attempts >= outboxMaxAttempts {
+			log.Printf("Domain event %d (%s) failed %d times and is dead-lettered: %v", event.ID, event.EventType, event.Attempts, err)
+			if err := r.repos.Event.MarkDead(ctx, event.ID, err.Error()); err != nil {
+				log.Printf("Failed to mark domain event %d dead: %v", event.ID, err)
+			}
+			continue
+		}
+
+		if err := r.repos.Event.Retry(ctx, event.ID, r.clock.Now().Add(backoff(event.Attempts, outboxRetryBase, outboxRetryMax)), err.Error()); err != nil {
+			log.Printf("Failed to schedule retry of domain event %d: %v", event.ID, err)
+		}
+	}
+
+	return published, nil
+}
+
+// publish delivers the event to the consumers that have not handled it yet
+func (r *EventRelay) publish(ctx context.Context, event *entity.DomainEvent) error {
+	delivered := make(map[string]bool, len(event.DeliveredTo))
+	for _, name := range event.DeliveredTo {
+		delivered[name] = true
+	}
+
+	var errs []error
+	for _, consumer := range r.consumers {
+		if delivered[consumer.Name()] {
+			continue
+		}
+
+		if err := consumer.Consume(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", consumer.Name(), err))
+			continue
+		}
+
+		// Failing to record the delivery only risks delivering the event to the consumer again
+		if err := r.repos.Event.MarkDelivered(ctx, event.ID, consumer.Name()); err != nil {
+			log.Printf("Failed to mark domain event %d delivered to %s: %v", event.ID, consumer.Name(), err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backoff returns the delay before the next attempt after the given number of attempts,
+// starting at base and doubling on every attempt up to limit
+func backoff(attempts int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// AnalyticsConsumer writes every domain event as a JSON line, to be shipped to the analytics pipeline
+type AnalyticsConsumer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewAnalyticsConsumer(w io.Writer) *AnalyticsConsumer {
+	return &AnalyticsConsumer{w: w}
+}
+
+func (c *AnalyticsConsumer) Name() string {
+	return "analytics"
+}
+
+type analyticsRecord struct {
+	EventID       int             `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	BusinessID    int             `json:"business_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func (c *AnalyticsConsumer) Consume(_ context.Context, event *entity.DomainEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return json.NewEncoder(c.w).Encode(analyticsRecord{
+		EventID:       event.ID,
+		EventType:     event.EventType,
+		BusinessID:    event.BusinessID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt,
+		Payload:       event.Payload,
+	})
+}
@@ -0,0 +1,269 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+// fakeConsumer records the events it consumed, failing for the events in failFor
+type fakeConsumer struct {
+	name     string
+	consumed []int
+	failFor  map[int]bool
+}
+
+func (c *fakeConsumer) Name() string {
+	return c.name
+}
+
+func (c *fakeConsumer) Consume(_ context.Context, event *entity.DomainEvent) error {
+	if c.failFor[event.ID] {
+		return fmt.Errorf("consumer unavailable")
+	}
+	c.consumed = append(c.consumed, event.ID)
+	return nil
+}
+
+func TestEventRelay_Relay(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err       error
+		published int
+		webhooks  []int
+		analytics []int
+	}
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	lease := 5 * time.Minute
+	eventWith := func(id, attempts int, deliveredTo ...string) entity.DomainEvent {
+		return entity.DomainEvent{
+			ID:          id,
+			EventType:   entity.EventAppointmentCreated,
+			Status:      entity.DomainEventStatusPending,
+			Attempts:    attempts,
+			DeliveredTo: deliveredTo,
+		}
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name        string
+		webhookFail map[int]bool
+		mock        func(m *mocks.EventRepository)
+		expected    expected
+	}{
+		{
+			name: "positive: events delivered to every consumer and published",
+			mock: func(m *mocks.EventRepository) {
+				m.On("ClaimPending", ctx, now, lease, 100).Return([]entity.DomainEvent{eventWith(1, 1), eventWith(2, 1)}, nil)
+				for _, id := range []int{1, 2} {
+					m.On("MarkDelivered", ctx, id, "webhooks").Return(nil)
+					m.On("MarkDelivered", ctx, id, "analytics").Return(nil)
+					m.On("MarkPublished", ctx, id, now).Return(nil)
+				}
+			},
+			expected: expected{
+				published: 2,
+				webhooks:  []int{1, 2},
+				analytics: []int{1, 2},
+			},
+		},
+		{
+			name:        "positive: failed consumer retried with backoff, the other one recorded",
+			webhookFail: map[int]bool{1: true},
+			mock: func(m *mocks.EventRepository) {
+				m.On("ClaimPending", ctx, now, lease, 100).Return([]entity.DomainEvent{eventWith(1, 3)}, nil)
+				m.On("MarkDelivered", ctx, 1, "analytics").Return(nil)
+				m.On("Retry", ctx, 1, now.Add(2*time.Minute), "webhooks: consumer unavailable").Return(nil)
+			},
+			expected: expected{
+				analytics: []int{1},
+			},
+		},
+		{
+			name: "positive: retry skips consumers that already handled the event",
+			mock: func(m *mocks.EventRepository) {
+				m.On("ClaimPending", ctx, now, lease, 100).Return([]entity.DomainEvent{eventWith(1, 2, "analytics")}, nil)
+				m.On("MarkDelivered", ctx, 1, "webhooks").Return(nil)
+				m.On("MarkPublished", ctx, 1, now).Return(nil)
+			},
+			expected: expected{
+				published: 1,
+				webhooks:  []int{1},
+			},
+		},
+		{
+			name:        "positive: event dead-lettered after the last attempt",
+			webhookFail: map[int]bool{1: true},
+			mock: func(m *mocks.EventRepository) {
+				m.On("ClaimPending", ctx, now, lease, 100).Return([]entity.DomainEvent{eventWith(1, 10, "analytics")}, nil)
+				m.On("MarkDead", ctx, 1, "webhooks: consumer unavailable").Return(nil)
+			},
+		},
+		{
+			name: "negative: claim fails",
+			mock: func(m *mocks.EventRepository) {
+				m.On("ClaimPending", ctx, now, lease, 100).Return(nil, fmt.Errorf("db error"))
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to claim domain events: %w", fmt.Errorf("db error")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			eventRepoMock := mocks.NewEventRepository(t)
+			webhooks := &fakeConsumer{name: "webhooks", failFor: tc.webhookFail}
+			analytics := &fakeConsumer{name: "analytics"}
+
+			// Setup mocks
+			tc.mock(eventRepoMock)
+
+			// Init relay
+			relay := services.NewEventRelay(&repository.Repositories{
+				Event: eventRepoMock,
+			}, []services.EventConsumer{webhooks, analytics}, &fakeClock{now: now}, time.Second)
+
+			// Execute
+			published, err := relay.Relay(ctx)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected.published, published)
+				assert.Equal(t, tc.expected.webhooks, webhooks.consumed)
+				assert.Equal(t, tc.expected.analytics, analytics.consumed)
+			}
+		})
+	}
+}
+
+func TestNotificationConsumer_Consume(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err    error
+		sent   []int
+		events []string
+	}
+
+	appointment := &entity.Appointment{ID: 7, BusinessID: 1, Status: entity.AppointmentStatusCancelled}
+	payload, err := json.Marshal(appointment)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		event    *entity.DomainEvent
+		mock     func(m *mocks.BusinessRepository)
+		expected expected
+	}{
+		{
+			name:  "positive: cancellation notified",
+			event: &entity.DomainEvent{ID: 1, BusinessID: 1, EventType: entity.EventAppointmentCancelled, Payload: payload},
+			mock: func(m *mocks.BusinessRepository) {
+				m.On("Get", ctx, 1).Return(&entity.Business{ID: 1, Timezone: "UTC"}, nil)
+			},
+			expected: expected{
+				sent:   []int{7},
+				events: []string{entity.EventAppointmentCancelled},
+			},
+		},
+		{
+			name:  "positive: other events ignored",
+			event: &entity.DomainEvent{ID: 1, BusinessID: 1, EventType: entity.EventEmployeeUpdated, Payload: []byte(`{}`)},
+			mock:  func(m *mocks.BusinessRepository) {},
+		},
+		{
+			name:  "negative: business not found",
+			event: &entity.DomainEvent{ID: 1, BusinessID: 1, EventType: entity.EventAppointmentCreated, Payload: payload},
+			mock: func(m *mocks.BusinessRepository) {
+				m.On("Get", ctx, 1).Return(nil, repository.ErrNotFound)
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to get business: %w", repository.ErrNotFound),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			notifier := &inMemoryNotifier{}
+
+			// Setup mocks
+			tc.mock(businessRepoMock)
+
+			// Init consumer
+			consumer := services.NewNotificationConsumer(&repository.Repositories{
+				Business: businessRepoMock,
+			}, notifier)
+
+			// Execute
+			err := consumer.Consume(ctx, tc.event)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected.sent, notifier.sent)
+			assert.Equal(t, tc.expected.events, notifier.events)
+		})
+	}
+}
+
+func TestAnalyticsConsumer_Consume(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	consumer := services.NewAnalyticsConsumer(&out)
+
+	createdAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	err := consumer.Consume(context.Background(), &entity.DomainEvent{
+		ID:            3,
+		BusinessID:    1,
+		AggregateType: entity.AggregateEmployee,
+		AggregateID:   5,
+		EventType:     entity.EventEmployeeCreated,
+		Payload:       []byte(`{"id":5}`),
+		CreatedAt:     createdAt,
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"event_id": 3,
+		"event_type": "employee.created",
+		"business_id": 1,
+		"aggregate_type": "employee",
+		"aggregate_id": 5,
+		"occurred_at": "2025-03-10T08:00:00Z",
+		"payload": {"id": 5}
+	}`, out.String())
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// reminderBatchSize is the number of reminders claimed in a single pass
+	reminderBatchSize = 100
+	// reminderClaimLease is how long a claimed reminder is reserved for the dispatcher that claimed it.
+	// A dispatcher that stops before sending leaves the reminder to others once the lease passes.
+	reminderClaimLease = 5 * time.Minute
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ReminderDispatcher sends the reminders of scheduled appointments once their reminder time has come.
+// Reminders are claimed before sending and marked as sent afterwards, so several dispatchers
+// and restarts do not send a reminder twice.
+type ReminderDispatcher struct {
+	repos    *repository.Repositories
+	notifier Notifier
+	clock    Clock
+	interval time.Duration
+}
+
+// NewReminderDispatcher creates the dispatcher. The clock defaults to the system clock when nil.
+func NewReminderDispatcher(repos *repository.Repositories, notifier Notifier, clock Clock, interval time.Duration) *ReminderDispatcher {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &ReminderDispatcher{
+		repos:    repos,
+		notifier: notifier,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// Run dispatches due reminders every interval until the context is cancelled
+func (d *ReminderDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := d.Dispatch(ctx)
+			if err != nil {
+				log.Printf("Failed to dispatch reminders: %v", err)
+			}
+			if sent > 0 {
+				log.Printf("Sent %d reminders", sent)
+			}
+		}
+	}
+}
+
+// Dispatch sends the reminders that are due now and returns the number of sent reminders.
+// A reminder that fails to send is released and retried on a later pass.
+func (d *ReminderDispatcher) Dispatch(ctx context.Context) (int, error) {
+	now := d.clock.Now()
+	ids, err := d.repos.Appointment.ClaimDueReminders(ctx, now, reminderClaimLease, reminderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+
+	sent := 0
+	for _, id := range ids {
+		if err := d.send(ctx, id); err != nil {
+			log.Printf("Failed to send reminder for appointment %d: %v", id, err)
+			if err := d.repos.Appointment.ReleaseReminderClaim(ctx, id); err != nil {
+				log.Printf("Failed to release reminder claim for appointment %d: %v", id, err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (d *ReminderDispatcher) send(ctx context.Context, id int) error {
+	appointment, err := d.repos.Appointment.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get appointment: %w", err)
+	}
+
+	business, loc, err := appointmentBusiness(ctx, d.repos, appointment)
+	if err != nil {
+		return err
+	}
+
+	if err := d.notifier.Notify(ctx, entity.EventAppointmentReminder, appointment, business, loc); err != nil {
+		return err
+	}
+
+	// The reminder went out, failing to record it only risks sending it again after the lease
+	if err := d.repos.Appointment.MarkReminderSent(ctx, id, d.clock.Now()); err != nil {
+		log.Printf("Failed to mark reminder sent for appointment %d: %v", id, err)
+	}
+
+	return nil
+}
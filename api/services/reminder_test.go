@@ -0,0 +1,192 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+// inMemoryNotifier records the appointments and events it was asked to notify about,
+// failing for the appointments in failFor
+type inMemoryNotifier struct {
+	mu      sync.Mutex
+	sent    []int
+	events  []string
+	failFor map[int]bool
+}
+
+func (n *inMemoryNotifier) Notify(_ context.Context, event string, appointment *entity.Appointment, _ *entity.Business, _ *time.Location) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.failFor[appointment.ID] {
+		return fmt.Errorf("gateway unavailable")
+	}
+	n.sent = append(n.sent, appointment.ID)
+	n.events = append(n.events, event)
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestReminderDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err  error
+		sent []int
+	}
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	lease := 5 * time.Minute
+	appointmentWith := func(id int) *entity.Appointment {
+		reminder := 60
+		return &entity.Appointment{ID: id, BusinessID: 1, StartTime: now.Add(30 * time.Minute), ReminderTime: &reminder, Status: entity.AppointmentStatusScheduled}
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		failFor  map[int]bool
+		mock     func(m *mocks.AppointmentRepository)
+		expected expected
+	}{
+		{
+			name: "positive: due reminders sent and marked",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("ClaimDueReminders", ctx, now, lease, 100).Return([]int{1, 2}, nil)
+				m.On("Get", ctx, 1).Return(appointmentWith(1), nil)
+				m.On("Get", ctx, 2).Return(appointmentWith(2), nil)
+				m.On("MarkReminderSent", ctx, 1, now).Return(nil)
+				m.On("MarkReminderSent", ctx, 2, now).Return(nil)
+			},
+			expected: expected{
+				sent: []int{1, 2},
+			},
+		},
+		{
+			name:    "positive: failed reminder released for a later pass",
+			failFor: map[int]bool{1: true},
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("ClaimDueReminders", ctx, now, lease, 100).Return([]int{1, 2}, nil)
+				m.On("Get", ctx, 1).Return(appointmentWith(1), nil)
+				m.On("Get", ctx, 2).Return(appointmentWith(2), nil)
+				m.On("ReleaseReminderClaim", ctx, 1).Return(nil)
+				m.On("MarkReminderSent", ctx, 2, now).Return(nil)
+			},
+			expected: expected{
+				sent: []int{2},
+			},
+		},
+		{
+			name: "positive: deleted appointment released",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("ClaimDueReminders", ctx, now, lease, 100).Return([]int{1}, nil)
+				m.On("Get", ctx, 1).Return(nil, repository.ErrNotFound)
+				m.On("ReleaseReminderClaim", ctx, 1).Return(nil)
+			},
+		},
+		{
+			name: "positive: nothing due",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("ClaimDueReminders", ctx, now, lease, 100).Return(nil, nil)
+			},
+		},
+		{
+			name: "negative: claim fails",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("ClaimDueReminders", ctx, now, lease, 100).Return(nil, fmt.Errorf("db error"))
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to claim reminders: %w", fmt.Errorf("db error")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			notifier := &inMemoryNotifier{failFor: tc.failFor}
+
+			// Setup mocks
+			tc.mock(appointmentRepoMock)
+			businessRepoMock.On("Get", ctx, 1).Return(&entity.Business{ID: 1, Timezone: "UTC"}, nil).Maybe()
+
+			// Init dispatcher
+			dispatcher := services.NewReminderDispatcher(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+			}, notifier, &fakeClock{now: now}, time.Minute)
+
+			// Execute
+			sent, err := dispatcher.Dispatch(ctx)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, len(tc.expected.sent), sent)
+				assert.Equal(t, tc.expected.sent, notifier.sent)
+				for _, event := range notifier.events {
+					assert.Equal(t, entity.EventAppointmentReminder, event)
+				}
+			}
+		})
+	}
+}
+
+func TestReminderDispatcher_SendsOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)}
+	reminder := 60
+
+	// Init mocks. Once sent, the reminder is no longer claimed on later passes.
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	appointmentRepoMock.On("ClaimDueReminders", ctx, clock.now, 5*time.Minute, 100).Return([]int{1}, nil).Once()
+	appointmentRepoMock.On("Get", ctx, 1).Return(&entity.Appointment{ID: 1, BusinessID: 1, StartTime: clock.now.Add(time.Hour), ReminderTime: &reminder}, nil)
+	appointmentRepoMock.On("MarkReminderSent", ctx, 1, clock.now).Return(nil)
+	appointmentRepoMock.On("ClaimDueReminders", ctx, clock.now.Add(time.Minute), 5*time.Minute, 100).Return(nil, nil).Once()
+
+	businessRepoMock := mocks.NewBusinessRepository(t)
+	businessRepoMock.On("Get", ctx, 1).Return(&entity.Business{ID: 1, Timezone: "UTC"}, nil)
+
+	notifier := &inMemoryNotifier{}
+	dispatcher := services.NewReminderDispatcher(&repository.Repositories{
+		Appointment: appointmentRepoMock,
+		Business:    businessRepoMock,
+	}, notifier, clock, time.Minute)
+
+	// Execute two passes a minute apart
+	_, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	clock.now = clock.now.Add(time.Minute)
+	_, err = dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, []int{1}, notifier.sent)
+}
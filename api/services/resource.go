@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// maxResourceTypeLength is the longest resource type that can be stored
+const maxResourceTypeLength = 50
+
+// ErrInvalidResource is returned when a resource has an invalid name or type
+var ErrInvalidResource = errors.New("invalid resource")
+
+type resourceService struct {
+	repos *repository.Repositories
+}
+
+func NewResourceService(repos *repository.Repositories) ResourceService {
+	return &resourceService{
+		repos: repos,
+	}
+}
+
+func (s *resourceService) Create(ctx context.Context, resource *entity.Resource) error {
+	// Validate business existence
+	if _, err := s.repos.Business.Get(ctx, resource.BusinessID); err != nil {
+		return fmt.Errorf("invalid business: %w", err)
+	}
+
+	if err := validateResource(resource); err != nil {
+		return err
+	}
+	if resource.LocationID != nil {
+		if _, err := getBusinessLocation(ctx, s.repos, resource.BusinessID, *resource.LocationID); err != nil {
+			return err
+		}
+	}
+	resource.IsActive = true
+
+	if err := s.repos.Resource.Create(ctx, resource); err != nil {
+		return fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	return nil
+}
+
+func (s *resourceService) Get(ctx context.Context, id int) (*entity.Resource, error) {
+	resource, err := s.repos.Resource.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	return resource, nil
+}
+
+func (s *resourceService) List(ctx context.Context, businessID int) ([]entity.Resource, error) {
+	resources, err := s.repos.Resource.List(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	return resources, nil
+}
+
+func (s *resourceService) Update(ctx context.Context, resource *entity.Resource) error {
+	existing, err := s.repos.Resource.Get(ctx, resource.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	if err := validateResource(resource); err != nil {
+		return err
+	}
+	if resource.LocationID != nil && !sameLocation(existing.LocationID, *resource.LocationID) {
+		if _, err := getBusinessLocation(ctx, s.repos, existing.BusinessID, *resource.LocationID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repos.Resource.Update(ctx, resource); err != nil {
+		return fmt.Errorf("failed to update resource: %w", err)
+	}
+
+	return nil
+}
+
+func (s *resourceService) Delete(ctx context.Context, id int) error {
+	if err := s.repos.Resource.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete resource: %w", err)
+	}
+
+	return nil
+}
+
+// validateResource requires a name and a type, the type is stored in lower case
+func validateResource(resource *entity.Resource) error {
+	resource.Name = strings.TrimSpace(resource.Name)
+	if resource.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidResource)
+	}
+
+	resourceType, err := normalizeResourceType(resource.Type)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResource, err)
+	}
+	resource.Type = resourceType
+
+	return nil
+}
+
+// normalizeResourceTypes returns the resource types of a service in lower case without duplicates
+func normalizeResourceTypes(types []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool)
+	for _, t := range types {
+		resourceType, err := normalizeResourceType(t)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[resourceType] {
+			seen[resourceType] = true
+			result = append(result, resourceType)
+		}
+	}
+	return result, nil
+}
+
+func normalizeResourceType(resourceType string) (string, error) {
+	resourceType = strings.ToLower(strings.TrimSpace(resourceType))
+	if resourceType == "" {
+		return "", fmt.Errorf("resource type is required")
+	}
+	if len(resourceType) > maxResourceTypeLength {
+		return "", fmt.Errorf("resource type cannot be longer than %d characters", maxResourceTypeLength)
+	}
+	return resourceType, nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+)
+
+// errNoFreeResource is returned when every resource the service can be booked with at the location is taken
+var errNoFreeResource = fmt.Errorf("%w: no required resource is free", ErrSlotUnavailable)
+
+// assignResource assigns a free resource of the types the service needs to the appointment, taking the buffers
+// of the service and of the other bookings into account. A rescheduled appointment keeps its resource while it
+// is free, and the seats of a class session share the resource of the session. Only shared resources and those of
+// the appointment's branch are assigned. Services without resource types get no resource.
+func (s *appointmentService) assignResource(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService) error {
+	return s.assignResourceWith(ctx, appointment, service, func(resourceID int, startTime, endTime time.Time) (bool, error) {
+		return s.repos.Appointment.IsResourceAvailable(ctx, resourceID, startTime, endTime, appointment.ID)
+	})
+}
+
+// resourceCheck reports whether the resource is free for the period including the buffers
+type resourceCheck func(resourceID int, startTime, endTime time.Time) (bool, error)
+
+// assignResourceWith assigns a resource like assignResource, with isFree deciding which bookings block a resource
+func (s *appointmentService) assignResourceWith(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService, isFree resourceCheck) error {
+	if len(service.ResourceTypes) == 0 {
+		appointment.ResourceID = nil
+		return nil
+	}
+
+	if isGroupService(service) {
+		resourceID, err := s.sessionResource(ctx, appointment, service)
+		if err != nil {
+			return err
+		}
+		if resourceID != nil {
+			appointment.ResourceID = resourceID
+			return nil
+		}
+	}
+
+	resources, err := s.repos.Resource.ListActiveByTypes(ctx, service.BusinessID, service.ResourceTypes)
+	if err != nil {
+		return fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	// The current resource of a rescheduled appointment is tried first
+	candidates := make([]entity.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if !servesLocation(resource, appointment.LocationID) {
+			continue
+		}
+		if appointment.ResourceID != nil && resource.ID == *appointment.ResourceID {
+			candidates = append([]entity.Resource{resource}, candidates...)
+			continue
+		}
+		candidates = append(candidates, resource)
+	}
+
+	blockedStart, blockedEnd := withBuffers(service, appointment.StartTime, appointment.EndTime)
+	for _, resource := range candidates {
+		available, err := isFree(resource.ID, blockedStart, blockedEnd)
+		if err != nil {
+			return fmt.Errorf("failed to check resource availability: %w", err)
+		}
+		if available {
+			resourceID := resource.ID
+			appointment.ResourceID = &resourceID
+			return nil
+		}
+	}
+
+	return errNoFreeResource
+}
+
+// sessionResource returns the resource of the class session the seat joins, or nil when the session
+// has not been booked with a resource yet
+func (s *appointmentService) sessionResource(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService) (*int, error) {
+	seats, err := s.repos.Appointment.ListByEmployee(ctx, appointment.EmployeeID, appointment.StartTime, appointment.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get class session: %w", err)
+	}
+
+	for _, seat := range seats {
+		if seat.Status == entity.AppointmentStatusScheduled && seat.ResourceID != nil && isSessionSeat(seat, service, appointment.StartTime) {
+			return seat.ResourceID, nil
+		}
+	}
+	return nil, nil
+}
+
+// resourcePool holds the resources a service can be booked with and their bookings over a period,
+// so the slots of several days and employees are checked without loading them again
+type resourcePool struct {
+	resources []entity.Resource
+	bookings  map[int][]entity.Appointment
+}
+
+// loadResourcePool loads the resources of the service types and their bookings overlapping the period.
+// Nil is returned for services that need no resource.
+func (s *appointmentService) loadResourcePool(ctx context.Context, service *entity.BusinessService, startTime, endTime time.Time) (*resourcePool, error) {
+	if len(service.ResourceTypes) == 0 {
+		return nil, nil
+	}
+
+	resources, err := s.repos.Resource.ListActiveByTypes(ctx, service.BusinessID, service.ResourceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	pool := &resourcePool{
+		resources: resources,
+		bookings:  make(map[int][]entity.Appointment),
+	}
+	if len(resources) == 0 {
+		return pool, nil
+	}
+
+	ids := make([]int, len(resources))
+	for i, resource := range resources {
+		ids[i] = resource.ID
+	}
+
+	// Bookings just outside the period can still block its slots with their buffers
+	appointments, err := s.repos.Appointment.ListByResources(ctx, ids, startTime.AddDate(0, 0, -1), endTime.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource bookings: %w", err)
+	}
+
+	for _, appointment := range withoutExpiredHolds(appointments, time.Now()) {
+		if appointment.ResourceID != nil {
+			pool.bookings[*appointment.ResourceID] = append(pool.bookings[*appointment.ResourceID], appointment)
+		}
+	}
+
+	return pool, nil
+}
+
+// filter keeps the slots of the employee for which a resource is free. A nil pool keeps every slot.
+func (p *resourcePool) filter(employee entity.Employee, service *entity.BusinessService, slots []TimeSlot) []TimeSlot {
+	if p == nil {
+		return slots
+	}
+
+	result := []TimeSlot{}
+	for _, slot := range slots {
+		if p.hasFree(employee, service, slot.StartTime, slot.EndTime) {
+			result = append(result, slot)
+		}
+	}
+	return result
+}
+
+// hasFree reports whether any resource the employee can use is free for the period, with the buffers of the service
+// and of the bookings. The resource of the employee's class session starting at startTime stays free for the other
+// seats of the session.
+func (p *resourcePool) hasFree(employee entity.Employee, service *entity.BusinessService, startTime, endTime time.Time) bool {
+	blockedStart, blockedEnd := withBuffers(service, startTime, endTime)
+	for _, resource := range p.resources {
+		if !servesLocation(resource, employee.LocationID) {
+			continue
+		}
+
+		free := true
+		for _, booking := range p.bookings[resource.ID] {
+			if booking.EmployeeID == employee.ID && isSessionSeat(booking, service, startTime) {
+				continue
+			}
+			bookingStart, bookingEnd := withBuffers(booking.Service, booking.StartTime, booking.EndTime)
+			if bookingStart.Before(blockedEnd) && bookingEnd.After(blockedStart) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return true
+		}
+	}
+	return false
+}
+
+// servesLocation reports whether the resource can be booked for appointments at the branch.
+// Resources without a branch are shared by all of them and by employees without one.
+func servesLocation(resource entity.Resource, locationID *int) bool {
+	return resource.LocationID == nil || (locationID != nil && *resource.LocationID == *locationID)
+}
@@ -0,0 +1,207 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestResourceService_Create(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		resource *entity.Resource
+		mock     func(r *mocks.ResourceRepository)
+		expected *entity.Resource
+		err      error
+	}{
+		{
+			name:     "positive: type stored in lower case",
+			resource: &entity.Resource{BusinessID: businessID, Name: " Room 1 ", Type: "Room"},
+			mock: func(r *mocks.ResourceRepository) {
+				r.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			expected: &entity.Resource{BusinessID: businessID, Name: "Room 1", Type: "room", IsActive: true},
+		},
+		{
+			name:     "negative: empty name",
+			resource: &entity.Resource{BusinessID: businessID, Name: " ", Type: "room"},
+			mock:     func(r *mocks.ResourceRepository) {},
+			err:      fmt.Errorf("%w: name is required", services.ErrInvalidResource),
+		},
+		{
+			name:     "negative: empty type",
+			resource: &entity.Resource{BusinessID: businessID, Name: "Room 1"},
+			mock:     func(r *mocks.ResourceRepository) {},
+			err:      fmt.Errorf("%w: resource type is required", services.ErrInvalidResource),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			resourceRepoMock := mocks.NewResourceRepository(t)
+
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			tc.mock(resourceRepoMock)
+
+			resourceService := services.NewResourceService(&repository.Repositories{
+				Business: businessRepoMock,
+				Resource: resourceRepoMock,
+			})
+
+			err := resourceService.Create(ctx, tc.resource)
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.resource)
+		})
+	}
+}
+
+func TestAppointmentService_CreateWithResource(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		resourceRepo    *mocks.ResourceRepository
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	employeeID := 1
+	firstRoomID := 5
+	secondRoomID := 6
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, IsActive: true, ResourceTypes: []string{"room"}}
+	employee := &entity.Employee{ID: employeeID, BusinessID: businessID, IsActive: true}
+	client := &entity.User{ID: clientID, BusinessID: businessID, Role: entity.RoleClient}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+	rooms := []entity.Resource{
+		{ID: firstRoomID, BusinessID: businessID, Type: "room", IsActive: true},
+		{ID: secondRoomID, BusinessID: businessID, Type: "room", IsActive: true},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		mock       func(m mocksForExecution)
+		resourceID *int
+		err        error
+	}{
+		{
+			name: "positive: first free resource assigned",
+			mock: func(m mocksForExecution) {
+				m.resourceRepo.On("ListActiveByTypes", ctx, businessID, []string{"room"}).Return(rooms, nil)
+				m.appointmentRepo.On("IsResourceAvailable", ctx, firstRoomID, start, end, 0).Return(false, nil)
+				m.appointmentRepo.On("IsResourceAvailable", ctx, secondRoomID, start, end, 0).Return(true, nil)
+				m.appointmentRepo.On("Create", ctx, mock.MatchedBy(func(a *entity.Appointment) bool {
+					return a.ResourceID != nil && *a.ResourceID == secondRoomID
+				})).Return(nil)
+			},
+			resourceID: &secondRoomID,
+		},
+		{
+			name: "negative: every resource taken",
+			mock: func(m mocksForExecution) {
+				m.resourceRepo.On("ListActiveByTypes", ctx, businessID, []string{"room"}).Return(rooms, nil)
+				m.appointmentRepo.On("IsResourceAvailable", ctx, firstRoomID, start, end, 0).Return(false, nil)
+				m.appointmentRepo.On("IsResourceAvailable", ctx, secondRoomID, start, end, 0).Return(false, nil)
+			},
+			err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("%w: no required resource is free", services.ErrSlotUnavailable)),
+		},
+		{
+			name: "negative: no resource of the type",
+			mock: func(m mocksForExecution) {
+				m.resourceRepo.On("ListActiveByTypes", ctx, businessID, []string{"room"}).Return(nil, nil)
+			},
+			err: fmt.Errorf("invalid appointment time: %w", fmt.Errorf("%w: no required resource is free", services.ErrSlotUnavailable)),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			userRepoMock := mocks.NewUserRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+			resourceRepoMock := mocks.NewResourceRepository(t)
+
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			userRepoMock.On("Get", ctx, clientID).Return(client, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+			scheduleRepoMock.On("GetEmployeeSchedule", ctx, employeeID, start).Return(schedule, nil)
+			scheduleRepoMock.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			appointmentRepoMock.On("IsEmployeeAvailable", ctx, employeeID, start, end, 0).Return(true, nil)
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				resourceRepo:    resourceRepoMock,
+			})
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				User:        userRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+				Resource:    resourceRepoMock,
+			}, services.NewFirstFreeStrategy(), nil, nil)
+
+			// Execute
+			appointment := &entity.Appointment{BusinessID: businessID, ClientID: clientID, EmployeeID: employeeID, ServiceID: serviceID, StartTime: start}
+			err := appointmentService.Create(ctx, appointment)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.resourceID, appointment.ResourceID)
+		})
+	}
+}
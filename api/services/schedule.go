@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,9 @@ import (
 	"github.com/vadimpk/ppc-project/repository"
 )
 
+// maxRotationWeeks limits the cycle length of rotating templates
+const maxRotationWeeks = 8
+
 type scheduleService struct {
 	repos *repository.Repositories
 }
@@ -30,6 +34,7 @@ func (s *scheduleService) CreateTemplate(ctx context.Context, template *entity.S
 	}
 
 	// Validate template data
+	normalizeTemplate(template)
 	if err := validateTemplateData(template); err != nil {
 		return err
 	}
@@ -41,10 +46,7 @@ func (s *scheduleService) CreateTemplate(ctx context.Context, template *entity.S
 	}
 
 	for _, t := range templates {
-		if t.DayOfWeek == template.DayOfWeek && isTimeOverlap(
-			t.StartTime, t.EndTime,
-			template.StartTime, template.EndTime,
-		) {
+		if templatesOverlap(t, *template) {
 			return fmt.Errorf("template overlaps with existing schedule")
 		}
 	}
@@ -75,17 +77,16 @@ func (s *scheduleService) UpdateTemplate(ctx context.Context, template *entity.S
 		return fmt.Errorf("template not found")
 	}
 
-	// Validate template data
+	// Validate template data, the day of week cannot be changed
+	template.DayOfWeek = existing.DayOfWeek
+	normalizeTemplate(template)
 	if err := validateTemplateData(template); err != nil {
 		return err
 	}
 
 	// Check for overlapping templates (excluding current template)
 	for _, t := range existingTemplates {
-		if t.ID != template.ID && t.DayOfWeek == template.DayOfWeek && isTimeOverlap(
-			t.StartTime, t.EndTime,
-			template.StartTime, template.EndTime,
-		) {
+		if t.ID != template.ID && templatesOverlap(t, *template) {
 			return fmt.Errorf("template overlaps with existing schedule")
 		}
 	}
@@ -121,12 +122,18 @@ func (s *scheduleService) ListTemplates(ctx context.Context, employeeID int) ([]
 
 func (s *scheduleService) CreateOverride(ctx context.Context, override *entity.ScheduleOverride) error {
 	// Validate employee existence and business context
-	if _, err := s.repos.Employee.Get(ctx, override.EmployeeID); err != nil {
+	employee, err := s.repos.Employee.Get(ctx, override.EmployeeID)
+	if err != nil {
 		return fmt.Errorf("invalid employee: %w", err)
 	}
 
+	today, err := s.employeeToday(ctx, employee)
+	if err != nil {
+		return err
+	}
+
 	// Validate override data
-	if err := validateOverrideData(override); err != nil {
+	if err := validateOverrideData(override, today); err != nil {
 		return err
 	}
 
@@ -138,7 +145,7 @@ func (s *scheduleService) CreateOverride(ctx context.Context, override *entity.S
 
 	if override.IsWorkingDay && override.StartTime != nil && override.EndTime != nil {
 		for _, o := range existingOverrides {
-			if o.StartTime != nil && o.EndTime != nil && isTimeOverlap(
+			if o.IsBreak == override.IsBreak && o.StartTime != nil && o.EndTime != nil && isTimeOverlap(
 				*o.StartTime, *o.EndTime,
 				*override.StartTime, *override.EndTime,
 			) {
@@ -157,10 +164,16 @@ func (s *scheduleService) CreateOverride(ctx context.Context, override *entity.S
 
 func (s *scheduleService) UpdateOverride(ctx context.Context, override *entity.ScheduleOverride) error {
 	// Validate employee existence and business context
-	if _, err := s.repos.Employee.Get(ctx, override.EmployeeID); err != nil {
+	employee, err := s.repos.Employee.Get(ctx, override.EmployeeID)
+	if err != nil {
 		return fmt.Errorf("invalid employee: %w", err)
 	}
 
+	today, err := s.employeeToday(ctx, employee)
+	if err != nil {
+		return err
+	}
+
 	// Verify override exists and get original data
 	existingOverrides, err := s.repos.Schedule.ListOverrides(ctx, override.EmployeeID, override.OverrideDate, override.OverrideDate)
 	if err != nil {
@@ -182,14 +195,14 @@ func (s *scheduleService) UpdateOverride(ctx context.Context, override *entity.S
 	override.OverrideDate = existing.OverrideDate
 
 	// Validate override data
-	if err := validateOverrideData(override); err != nil {
+	if err := validateOverrideData(override, today); err != nil {
 		return err
 	}
 
 	// Check for overlapping overrides (excluding current override)
 	if override.IsWorkingDay && override.StartTime != nil && override.EndTime != nil {
 		for _, o := range existingOverrides {
-			if o.ID != override.ID && o.IsWorkingDay && o.StartTime != nil && o.EndTime != nil {
+			if o.ID != override.ID && o.IsWorkingDay && o.IsBreak == override.IsBreak && o.StartTime != nil && o.EndTime != nil {
 				if isTimeOverlap(
 					*o.StartTime, *o.EndTime,
 					*override.StartTime, *override.EndTime,
@@ -221,7 +234,15 @@ func (s *scheduleService) DeleteOverride(ctx context.Context, id int) error {
 			exists = true
 
 			// Check if trying to delete past override
-			if o.OverrideDate.Before(time.Now().Truncate(24 * time.Hour)) {
+			employee, err := s.repos.Employee.Get(ctx, o.EmployeeID)
+			if err != nil {
+				return fmt.Errorf("invalid employee: %w", err)
+			}
+			today, err := s.employeeToday(ctx, employee)
+			if err != nil {
+				return err
+			}
+			if o.OverrideDate.Before(today) {
 				return fmt.Errorf("cannot delete past overrides")
 			}
 			break
@@ -278,14 +299,105 @@ func validateTemplateData(template *entity.ScheduleTemplate) error {
 		return fmt.Errorf("end time must be after start time")
 	}
 
+	if template.ValidFrom != nil && template.ValidUntil != nil && template.ValidUntil.Before(*template.ValidFrom) {
+		return fmt.Errorf("valid until must not be before valid from")
+	}
+
+	if template.RotationWeeks < 1 || template.RotationWeeks > maxRotationWeeks {
+		return fmt.Errorf("rotation must be between 1 and %d weeks", maxRotationWeeks)
+	}
+	if template.RotationWeeks > 1 {
+		if template.RotationAnchor == nil {
+			return fmt.Errorf("rotation anchor is required for rotating templates")
+		}
+		if template.RotationWeek < 1 || template.RotationWeek > template.RotationWeeks {
+			return fmt.Errorf("rotation week must be between 1 and %d", template.RotationWeeks)
+		}
+	}
+
 	return nil
 }
 
-func validateOverrideData(override *entity.ScheduleOverride) error {
-	if override.OverrideDate.Before(time.Now().Truncate(24 * time.Hour)) {
-		return fmt.Errorf("cannot create override for past dates")
+// normalizeTemplate keeps the calendar dates of the template as UTC dates, the way they are stored,
+// and defaults to a template applying every week
+func normalizeTemplate(template *entity.ScheduleTemplate) {
+	for _, date := range []*time.Time{template.ValidFrom, template.ValidUntil, template.RotationAnchor} {
+		if date != nil {
+			*date = dateIn(*date, time.UTC)
+		}
+	}
+
+	if template.RotationWeeks == 0 {
+		template.RotationWeeks = 1
 	}
+	if template.RotationWeeks == 1 {
+		template.RotationWeek = 1
+		template.RotationAnchor = nil
+	}
+}
 
+// templatesOverlap reports whether two templates would take the same time. Breaks are expected to sit inside
+// working blocks, so only blocks of the same kind may not overlap. Templates of different dates or rotation weeks
+// never apply together.
+func templatesOverlap(a, b entity.ScheduleTemplate) bool {
+	return a.DayOfWeek == b.DayOfWeek && a.IsBreak == b.IsBreak &&
+		isTimeOverlap(a.StartTime, a.EndTime, b.StartTime, b.EndTime) && templatesCoincide(a, b)
+}
+
+// templatesCoincide reports whether two templates of the same weekday apply on a common date,
+// by their validity dates and rotation weeks
+func templatesCoincide(a, b entity.ScheduleTemplate) bool {
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, validFrom := range []*time.Time{a.ValidFrom, b.ValidFrom} {
+		if validFrom != nil && validFrom.After(from) {
+			from = *validFrom
+		}
+	}
+
+	// The rotations of both templates repeat together after the least common multiple of their lengths
+	weeks := lcm(max(a.RotationWeeks, 1), max(b.RotationWeeks, 1))
+	date := from.AddDate(0, 0, (a.DayOfWeek-int(from.Weekday())+7)%7)
+	for i := 0; i < weeks; i++ {
+		if templateAppliesOn(a, date) && templateAppliesOn(b, date) {
+			return true
+		}
+		date = date.AddDate(0, 0, 7)
+	}
+
+	return false
+}
+
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
+
+// employeeToday returns the current date at the employee's branch as a UTC date, the way override dates are stored
+func (s *scheduleService) employeeToday(ctx context.Context, employee *entity.Employee) (time.Time, error) {
+	loc, err := scheduleLocation(ctx, s.repos, employee.BusinessID, employee.LocationID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return dateIn(time.Now().In(loc), time.UTC), nil
+}
+
+// errPastOverride is returned for overrides of dates that already passed
+var errPastOverride = errors.New("cannot create override for past dates")
+
+func validateOverrideData(override *entity.ScheduleOverride, today time.Time) error {
+	if override.OverrideDate.Before(today) {
+		return errPastOverride
+	}
+
+	return validateOverrideHours(override)
+}
+
+// validateOverrideHours checks the working hours of a working day override
+func validateOverrideHours(override *entity.ScheduleOverride) error {
 	if override.IsWorkingDay {
 		if (override.StartTime == nil) != (override.EndTime == nil) {
 			return fmt.Errorf("both start time and end time must be provided for working days")
@@ -312,6 +424,57 @@ func isTimeOverlap(start1, end1, start2, end2 time.Time) bool {
 	return start1Mins < end2Mins && end1Mins > start2Mins
 }
 
-func (s *scheduleService) IsAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time) (bool, error) {
-	panic("not implemented")
+func (s *scheduleService) IsAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time) (*Availability, error) {
+	if !endTime.After(startTime) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+
+	// Validate employee existence and active status
+	employee, err := s.repos.Employee.Get(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid employee: %w", err)
+	}
+	if !employee.IsActive {
+		return nil, fmt.Errorf("employee is not active")
+	}
+
+	loc, err := scheduleLocation(ctx, s.repos, employee.BusinessID, employee.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := getWorkingDay(ctx, s.repos, employee.BusinessID, employeeID, startTime.In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.Closed {
+		return &Availability{Reason: UnavailableClosed}, nil
+	}
+	if schedule.DayOff || len(schedule.Hours) == 0 {
+		return &Availability{Reason: UnavailableDayOff}, nil
+	}
+
+	if !isWithinIntervals(startTime, endTime, schedule.Intervals) {
+		// Within working hours but not bookable means a break is in the way
+		if isWithinIntervals(startTime, endTime, schedule.Hours) {
+			return &Availability{Reason: UnavailableBreak}, nil
+		}
+		return &Availability{Reason: UnavailableOutsideHours}, nil
+	}
+
+	if overlapsAny(startTime, endTime, schedule.Busy) {
+		return &Availability{Reason: UnavailableBusy}, nil
+	}
+
+	// Check for overlapping appointments
+	isAvailable, err := s.repos.Appointment.IsEmployeeAvailable(ctx, employeeID, startTime, endTime, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check employee availability: %w", err)
+	}
+	if !isAvailable {
+		return &Availability{Reason: UnavailableConflict}, nil
+	}
+
+	return &Availability{Available: true}, nil
 }
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// maxBulkOverrideDays limits the date range a single override is applied to
+	maxBulkOverrideDays = 31
+	// maxBulkOverrides limits the overrides created by a single bulk change
+	maxBulkOverrides = 1000
+)
+
+// ErrInvalidBulkSchedule is returned when a bulk schedule change has invalid entries.
+// Nothing is changed then, the problems are listed in the result.
+var ErrInvalidBulkSchedule = errors.New("bulk schedule change is invalid")
+
+func (s *scheduleService) ReplaceTemplates(ctx context.Context, businessID, employeeID int, templates []entity.ScheduleTemplate, from *time.Time) (*BulkScheduleResult, error) {
+	result := &BulkScheduleResult{Errors: []BulkScheduleError{}}
+	from = bulkStartDate(from)
+
+	if _, err := s.checkBulkEmployee(ctx, businessID, employeeID, result); err != nil {
+		return nil, err
+	}
+
+	var batch repository.TemplateBatch
+	if len(result.Errors) == 0 {
+		if err := s.planTemplates(ctx, &batch, employeeID, templates, from, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.applyTemplateBatch(ctx, batch, result)
+}
+
+func (s *scheduleService) CopyTemplates(ctx context.Context, businessID, sourceEmployeeID int, targetEmployeeIDs []int, from *time.Time) (*BulkScheduleResult, error) {
+	result := &BulkScheduleResult{Errors: []BulkScheduleError{}}
+	from = bulkStartDate(from)
+
+	if _, err := s.checkBulkEmployee(ctx, businessID, sourceEmployeeID, result); err != nil {
+		return nil, err
+	}
+	if len(targetEmployeeIDs) == 0 {
+		result.Errors = append(result.Errors, BulkScheduleError{Message: "no employees to copy the schedule to"})
+	}
+
+	seen := make(map[int]bool)
+	for _, employeeID := range targetEmployeeIDs {
+		switch {
+		case employeeID == sourceEmployeeID:
+			result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Message: "cannot copy the schedule to the same employee"})
+		case seen[employeeID]:
+			result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Message: "employee is listed more than once"})
+		default:
+			if _, err := s.checkBulkEmployee(ctx, businessID, employeeID, result); err != nil {
+				return nil, err
+			}
+		}
+		seen[employeeID] = true
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidBulkSchedule
+	}
+
+	source, err := s.repos.Schedule.ListTemplates(ctx, sourceEmployeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	// With a start date only the templates in effect from it on are copied
+	var templates []entity.ScheduleTemplate
+	for _, t := range source {
+		if from == nil || t.ValidUntil == nil || !t.ValidUntil.Before(*from) {
+			templates = append(templates, t)
+		}
+	}
+
+	var batch repository.TemplateBatch
+	for _, employeeID := range targetEmployeeIDs {
+		if err := s.planTemplates(ctx, &batch, employeeID, templates, from, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.applyTemplateBatch(ctx, batch, result)
+}
+
+func (s *scheduleService) ApplyOverride(ctx context.Context, businessID int, employeeIDs []int, startDate, endDate time.Time, override entity.ScheduleOverride) (*BulkScheduleResult, error) {
+	result := &BulkScheduleResult{Errors: []BulkScheduleError{}}
+	startDate, endDate = dateIn(startDate, time.UTC), dateIn(endDate, time.UTC)
+
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	switch {
+	case endDate.Before(startDate):
+		result.Errors = append(result.Errors, BulkScheduleError{Message: "end date must not be before start date"})
+	case days > maxBulkOverrideDays:
+		result.Errors = append(result.Errors, BulkScheduleError{Message: fmt.Sprintf("date range cannot exceed %d days", maxBulkOverrideDays)})
+	case len(employeeIDs) == 0:
+		result.Errors = append(result.Errors, BulkScheduleError{Message: "no employees to apply the override to"})
+	case days*len(employeeIDs) > maxBulkOverrides:
+		result.Errors = append(result.Errors, BulkScheduleError{Message: fmt.Sprintf("cannot create more than %d overrides at once", maxBulkOverrides)})
+	}
+
+	// Only the date differs between the overrides, so validating the hours once covers all of them.
+	// Past dates are checked per employee, at the date of their branch.
+	if err := validateOverrideHours(&override); err != nil {
+		result.Errors = append(result.Errors, BulkScheduleError{Message: err.Error()})
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidBulkSchedule
+	}
+
+	var overrides []*entity.ScheduleOverride
+	seen := make(map[int]bool)
+	for _, employeeID := range employeeIDs {
+		if seen[employeeID] {
+			result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Message: "employee is listed more than once"})
+			continue
+		}
+		seen[employeeID] = true
+
+		employee, err := s.checkBulkEmployee(ctx, businessID, employeeID, result)
+		if err != nil {
+			return nil, err
+		}
+		if employee == nil {
+			continue
+		}
+
+		today, err := s.employeeToday(ctx, employee)
+		if err != nil {
+			return nil, err
+		}
+		if startDate.Before(today) {
+			result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Message: errPastOverride.Error()})
+			continue
+		}
+
+		existing, err := s.repos.Schedule.ListOverrides(ctx, employeeID, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing overrides: %w", err)
+		}
+
+		for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+			o := override
+			o.EmployeeID = employeeID
+			o.OverrideDate = date
+
+			if overrideOverlaps(o, existing) {
+				result.Errors = append(result.Errors, BulkScheduleError{
+					EmployeeID: employeeID,
+					Date:       date.Format("2006-01-02"),
+					Message:    "override overlaps with existing schedule",
+				})
+				continue
+			}
+			overrides = append(overrides, &o)
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidBulkSchedule
+	}
+
+	if err := s.repos.Schedule.CreateOverrides(ctx, overrides); err != nil {
+		return nil, fmt.Errorf("failed to create overrides: %w", err)
+	}
+
+	result.Overrides = overrides
+	return result, nil
+}
+
+// checkBulkEmployee returns the employee, or adds a problem to the result and returns nil
+// when the employee is not an active employee of the business
+func (s *scheduleService) checkBulkEmployee(ctx context.Context, businessID, employeeID int, result *BulkScheduleResult) (*entity.Employee, error) {
+	employee, err := s.repos.Employee.Get(ctx, employeeID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	switch {
+	case err != nil || employee.BusinessID != businessID:
+		result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Message: "employee not found"})
+	case !employee.IsActive:
+		result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Message: "employee is not active"})
+	default:
+		return employee, nil
+	}
+
+	return nil, nil
+}
+
+// planTemplates adds the changes replacing the weekly schedule of the employee with the templates to the batch.
+// Without a start date every template of the employee is replaced. With a start date the templates ending before
+// it are kept, the templates in effect on it are ended the day before, and the new templates start on it.
+// Problems with the templates are added to the result by their index.
+func (s *scheduleService) planTemplates(ctx context.Context, batch *repository.TemplateBatch, employeeID int, templates []entity.ScheduleTemplate, from *time.Time, result *BulkScheduleResult) error {
+	existing, err := s.repos.Schedule.ListTemplates(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	var kept []entity.ScheduleTemplate
+	for _, t := range existing {
+		switch {
+		case from == nil || (t.ValidFrom != nil && !t.ValidFrom.Before(*from)):
+			batch.Delete = append(batch.Delete, t.ID)
+		case t.ValidUntil != nil && t.ValidUntil.Before(*from):
+			kept = append(kept, t)
+		default:
+			ended := t
+			validUntil := from.AddDate(0, 0, -1)
+			ended.ValidUntil = &validUntil
+			batch.Update = append(batch.Update, &ended)
+			kept = append(kept, ended)
+		}
+	}
+
+	var planned []*entity.ScheduleTemplate
+	for i, t := range templates {
+		index := i
+		template := t
+		template.ID = 0
+		template.EmployeeID = employeeID
+		normalizeTemplate(&template)
+		if from != nil && (template.ValidFrom == nil || template.ValidFrom.Before(*from)) {
+			validFrom := *from
+			template.ValidFrom = &validFrom
+		}
+
+		if err := validateTemplateData(&template); err != nil {
+			result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Index: &index, Message: err.Error()})
+			continue
+		}
+
+		overlaps := false
+		for _, other := range kept {
+			overlaps = overlaps || templatesOverlap(other, template)
+		}
+		for _, other := range planned {
+			overlaps = overlaps || templatesOverlap(*other, template)
+		}
+		if overlaps {
+			result.Errors = append(result.Errors, BulkScheduleError{EmployeeID: employeeID, Index: &index, Message: "template overlaps with another template"})
+			continue
+		}
+
+		planned = append(planned, &template)
+	}
+
+	batch.Create = append(batch.Create, planned...)
+	return nil
+}
+
+// applyTemplateBatch saves the batch when no problems were found and returns the created templates
+func (s *scheduleService) applyTemplateBatch(ctx context.Context, batch repository.TemplateBatch, result *BulkScheduleResult) (*BulkScheduleResult, error) {
+	if len(result.Errors) > 0 {
+		return result, ErrInvalidBulkSchedule
+	}
+
+	if err := s.repos.Schedule.ApplyTemplateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to save templates: %w", err)
+	}
+
+	result.Templates = batch.Create
+	return result, nil
+}
+
+// bulkStartDate keeps the start date of a bulk change as a UTC date, the way template dates are stored
+func bulkStartDate(from *time.Time) *time.Time {
+	if from == nil {
+		return nil
+	}
+
+	date := dateIn(*from, time.UTC)
+	return &date
+}
+
+// overrideOverlaps reports whether working hours of the override overlap existing working hours of the same kind
+// on its date, the same way single overrides are checked
+func overrideOverlaps(override entity.ScheduleOverride, existing []entity.ScheduleOverride) bool {
+	if !override.IsWorkingDay || override.StartTime == nil || override.EndTime == nil {
+		return false
+	}
+
+	for _, o := range existing {
+		if o.OverrideDate.Equal(override.OverrideDate) && o.IsBreak == override.IsBreak && o.StartTime != nil && o.EndTime != nil &&
+			isTimeOverlap(*o.StartTime, *o.EndTime, *override.StartTime, *override.EndTime) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestScheduleService_ReplaceTemplates(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	employeeID := 2
+	employee := &entity.Employee{ID: employeeID, BusinessID: businessID, IsActive: true}
+	date := func(month time.Month, day int) *time.Time {
+		d := time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	index := func(i int) *int {
+		return &i
+	}
+	monday, tuesday := int(time.Monday), int(time.Tuesday)
+
+	existing := []entity.ScheduleTemplate{
+		{ID: 1, EmployeeID: employeeID, DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), ValidUntil: date(2, 28)},
+		{ID: 2, EmployeeID: employeeID, DayOfWeek: monday, StartTime: clock(8), EndTime: clock(16), ValidFrom: date(3, 1)},
+		{ID: 3, EmployeeID: employeeID, DayOfWeek: tuesday, StartTime: clock(8), EndTime: clock(16), ValidFrom: date(5, 1)},
+	}
+	week := []entity.ScheduleTemplate{
+		{DayOfWeek: monday, StartTime: clock(10), EndTime: clock(18)},
+		{DayOfWeek: monday, StartTime: clock(13), EndTime: clock(14), IsBreak: true},
+		{DayOfWeek: tuesday, StartTime: clock(10), EndTime: clock(18)},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name      string
+		employee  *entity.Employee
+		templates []entity.ScheduleTemplate
+		from      *time.Time
+		mock      func(s *mocks.ScheduleRepository)
+		errors    []services.BulkScheduleError
+	}{
+		{
+			name:      "positive: whole schedule replaced",
+			employee:  employee,
+			templates: week,
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ApplyTemplateBatch", ctx, mock.MatchedBy(func(b repository.TemplateBatch) bool {
+					return assert.ObjectsAreEqual([]int{1, 2, 3}, b.Delete) && len(b.Update) == 0 && len(b.Create) == 3 &&
+						b.Create[0].EmployeeID == employeeID && b.Create[0].ValidFrom == nil && b.Create[0].RotationWeeks == 1
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "positive: schedule replaced from next month",
+			employee:  employee,
+			templates: week,
+			from:      date(4, 1),
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ApplyTemplateBatch", ctx, mock.MatchedBy(func(b repository.TemplateBatch) bool {
+					// The past schedule is kept, the current one ends with March and the planned one is replaced
+					return assert.ObjectsAreEqual([]int{3}, b.Delete) &&
+						len(b.Update) == 1 && b.Update[0].ID == 2 && b.Update[0].ValidUntil.Equal(*date(3, 31)) &&
+						len(b.Create) == 3 && b.Create[2].ValidFrom.Equal(*date(4, 1))
+				})).Return(nil)
+			},
+		},
+		{
+			name:     "negative: every invalid template reported",
+			employee: employee,
+			templates: []entity.ScheduleTemplate{
+				{DayOfWeek: monday, StartTime: clock(10), EndTime: clock(18)},
+				{DayOfWeek: monday, StartTime: clock(17), EndTime: clock(20)},
+				{DayOfWeek: 7, StartTime: clock(10), EndTime: clock(18)},
+				{DayOfWeek: tuesday, StartTime: clock(18), EndTime: clock(10)},
+			},
+			mock: func(s *mocks.ScheduleRepository) {},
+			errors: []services.BulkScheduleError{
+				{EmployeeID: employeeID, Index: index(1), Message: "template overlaps with another template"},
+				{EmployeeID: employeeID, Index: index(2), Message: "invalid day of week"},
+				{EmployeeID: employeeID, Index: index(3), Message: "end time must be after start time"},
+			},
+		},
+		{
+			name:      "negative: employee of another business",
+			employee:  &entity.Employee{ID: employeeID, BusinessID: 5, IsActive: true},
+			templates: week,
+			mock:      func(s *mocks.ScheduleRepository) {},
+			errors: []services.BulkScheduleError{
+				{EmployeeID: employeeID, Message: "employee not found"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			employeeRepoMock.On("Get", ctx, employeeID).Return(tc.employee, nil)
+			scheduleRepoMock.On("ListTemplates", ctx, employeeID).Return(existing, nil).Maybe()
+			tc.mock(scheduleRepoMock)
+
+			// Init service
+			scheduleService := services.NewScheduleService(&repository.Repositories{
+				Employee: employeeRepoMock,
+				Schedule: scheduleRepoMock,
+			})
+
+			// Execute
+			result, err := scheduleService.ReplaceTemplates(ctx, businessID, employeeID, tc.templates, tc.from)
+
+			// Assert
+			if tc.errors != nil {
+				assert.ErrorIs(t, err, services.ErrInvalidBulkSchedule)
+				require.NotNil(t, result)
+				assert.Equal(t, tc.errors, result.Errors)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, result.Templates, len(tc.templates))
+		})
+	}
+}
+
+func TestScheduleService_CopyTemplates(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	source := []entity.ScheduleTemplate{
+		{ID: 1, EmployeeID: 1, DayOfWeek: int(time.Monday), StartTime: clock(9), EndTime: clock(17), RotationWeeks: 1, RotationWeek: 1},
+		{ID: 2, EmployeeID: 1, DayOfWeek: int(time.Friday), StartTime: clock(9), EndTime: clock(13), RotationWeeks: 1, RotationWeek: 1},
+	}
+	employees := map[int]*entity.Employee{
+		1: {ID: 1, BusinessID: businessID, IsActive: true},
+		2: {ID: 2, BusinessID: businessID, IsActive: true},
+		3: {ID: 3, BusinessID: businessID, IsActive: true},
+		4: {ID: 4, BusinessID: businessID},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		targets []int
+		mock    func(s *mocks.ScheduleRepository)
+		errors  []services.BulkScheduleError
+	}{
+		{
+			name:    "positive: copied to two employees in one batch",
+			targets: []int{2, 3},
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ListTemplates", ctx, 1).Return(source, nil)
+				s.On("ListTemplates", ctx, 2).Return([]entity.ScheduleTemplate{{ID: 10, EmployeeID: 2}}, nil)
+				s.On("ListTemplates", ctx, 3).Return(nil, nil)
+				s.On("ApplyTemplateBatch", ctx, mock.MatchedBy(func(b repository.TemplateBatch) bool {
+					return assert.ObjectsAreEqual([]int{10}, b.Delete) && len(b.Create) == 4 &&
+						b.Create[0].EmployeeID == 2 && b.Create[0].ID == 0 && b.Create[3].EmployeeID == 3
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "negative: every invalid employee reported",
+			targets: []int{1, 2, 2, 4},
+			mock:    func(s *mocks.ScheduleRepository) {},
+			errors: []services.BulkScheduleError{
+				{EmployeeID: 1, Message: "cannot copy the schedule to the same employee"},
+				{EmployeeID: 2, Message: "employee is listed more than once"},
+				{EmployeeID: 4, Message: "employee is not active"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			for id, employee := range employees {
+				employeeRepoMock.On("Get", ctx, id).Return(employee, nil).Maybe()
+			}
+			tc.mock(scheduleRepoMock)
+
+			// Init service
+			scheduleService := services.NewScheduleService(&repository.Repositories{
+				Employee: employeeRepoMock,
+				Schedule: scheduleRepoMock,
+			})
+
+			// Execute
+			result, err := scheduleService.CopyTemplates(ctx, businessID, 1, tc.targets, nil)
+
+			// Assert
+			if tc.errors != nil {
+				assert.ErrorIs(t, err, services.ErrInvalidBulkSchedule)
+				require.NotNil(t, result)
+				assert.Equal(t, tc.errors, result.Errors)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, result.Templates, 4)
+		})
+	}
+}
+
+func TestScheduleService_ApplyOverride(t *testing.T) {
+	t.Parallel()
+
+	business := &entity.Business{ID: 1, Timezone: "UTC"}
+	now := time.Now().UTC()
+	startDate := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 0, 1)
+	clock := func(hour int) *time.Time {
+		c := time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+		return &c
+	}
+	employees := map[int]*entity.Employee{
+		1: {ID: 1, BusinessID: business.ID, IsActive: true},
+		2: {ID: 2, BusinessID: business.ID, IsActive: true},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name      string
+		override  entity.ScheduleOverride
+		startDate time.Time
+		endDate   time.Time
+		mock      func(s *mocks.ScheduleRepository)
+		created   int
+		errors    []services.BulkScheduleError
+	}{
+		{
+			name:      "positive: closed for inventory",
+			override:  entity.ScheduleOverride{IsWorkingDay: false},
+			startDate: startDate,
+			endDate:   endDate,
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ListOverrides", ctx, mock.Anything, startDate, endDate).Return(nil, nil)
+				s.On("CreateOverrides", ctx, mock.MatchedBy(func(overrides []*entity.ScheduleOverride) bool {
+					return len(overrides) == 4 && overrides[1].EmployeeID == 1 && overrides[1].OverrideDate.Equal(endDate) &&
+						overrides[2].EmployeeID == 2 && overrides[2].OverrideDate.Equal(startDate)
+				})).Return(nil)
+			},
+			created: 4,
+		},
+		{
+			name:      "negative: overlaps existing working hours",
+			override:  entity.ScheduleOverride{IsWorkingDay: true, StartTime: clock(9), EndTime: clock(12)},
+			startDate: startDate,
+			endDate:   endDate,
+			mock: func(s *mocks.ScheduleRepository) {
+				s.On("ListOverrides", ctx, 1, startDate, endDate).Return(nil, nil)
+				s.On("ListOverrides", ctx, 2, startDate, endDate).Return([]entity.ScheduleOverride{
+					{EmployeeID: 2, OverrideDate: endDate, IsWorkingDay: true, StartTime: clock(11), EndTime: clock(15)},
+				}, nil)
+			},
+			errors: []services.BulkScheduleError{
+				{EmployeeID: 2, Date: endDate.Format("2006-01-02"), Message: "override overlaps with existing schedule"},
+			},
+		},
+		{
+			name:      "negative: start date passed",
+			override:  entity.ScheduleOverride{IsWorkingDay: false},
+			startDate: startDate.AddDate(0, 0, -4),
+			endDate:   endDate,
+			mock:      func(s *mocks.ScheduleRepository) {},
+			errors: []services.BulkScheduleError{
+				{EmployeeID: 1, Message: "cannot create override for past dates"},
+				{EmployeeID: 2, Message: "cannot create override for past dates"},
+			},
+		},
+		{
+			name:      "negative: range too long",
+			override:  entity.ScheduleOverride{IsWorkingDay: false},
+			startDate: startDate,
+			endDate:   startDate.AddDate(0, 0, 31),
+			mock:      func(s *mocks.ScheduleRepository) {},
+			errors: []services.BulkScheduleError{
+				{Message: "date range cannot exceed 31 days"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil).Maybe()
+			for id, employee := range employees {
+				employeeRepoMock.On("Get", ctx, id).Return(employee, nil).Maybe()
+			}
+			tc.mock(scheduleRepoMock)
+
+			// Init service
+			scheduleService := services.NewScheduleService(&repository.Repositories{
+				Business: businessRepoMock,
+				Employee: employeeRepoMock,
+				Schedule: scheduleRepoMock,
+			})
+
+			// Execute
+			result, err := scheduleService.ApplyOverride(ctx, business.ID, []int{1, 2}, tc.startDate, tc.endDate, tc.override)
+
+			// Assert
+			if tc.errors != nil {
+				assert.ErrorIs(t, err, services.ErrInvalidBulkSchedule)
+				require.NotNil(t, result)
+				assert.Equal(t, tc.errors, result.Errors)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, result.Overrides, tc.created)
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestScheduleService_IsAvailable(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		businessRepo    *mocks.BusinessRepository
+		employeeRepo    *mocks.EmployeeRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type args struct {
+		start time.Time
+		end   time.Time
+	}
+
+	type expected struct {
+		err          error
+		availability *services.Availability
+	}
+
+	employeeID := 1
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	clock := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	business := &entity.Business{ID: 1, Timezone: "UTC"}
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1, IsActive: true}
+	templates := []entity.ScheduleTemplate{
+		{EmployeeID: employeeID, StartTime: clock(9, 0), EndTime: clock(17, 0)},
+		{EmployeeID: employeeID, StartTime: clock(12, 0), EndTime: clock(13, 0), IsBreak: true},
+	}
+
+	// A business in Kyiv on the day clocks move forward, when the offset changes from +2 to +3
+	kyiv := loadLocation(t, "Europe/Kyiv")
+	kyivBusiness := &entity.Business{ID: 2, Timezone: "Europe/Kyiv"}
+	kyivEmployee := &entity.Employee{ID: employeeID, BusinessID: kyivBusiness.ID, IsActive: true}
+	dstDay := nextClockChange(kyiv, time.Now(), true)
+	kyivAt := func(hour, minute int) time.Time {
+		return time.Date(dstDay.Year(), dstDay.Month(), dstDay.Day(), hour, minute, 0, 0, kyiv)
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: interval is available",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, at(10, 0), at(11, 0), 0).Return(true, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Available: true},
+			},
+		},
+		{
+			name: "positive: conflict with an appointment",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, at(10, 0), at(11, 0), 0).Return(false, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableConflict},
+			},
+		},
+		{
+			name: "positive: busy with an imported event",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, date, date.AddDate(0, 0, 1)).Return([]entity.BusyTime{
+					{EmployeeID: employeeID, StartTime: at(10, 30), EndTime: at(11, 30)},
+				}, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableBusy},
+			},
+		},
+		{
+			name: "positive: business closed for a yearly holiday",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, business.ID).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, business.ID, date, date).Return([]entity.BusinessClosure{
+					{BusinessID: business.ID, Name: "Holiday", StartDate: date.AddDate(-4, 0, 0), EndDate: date.AddDate(-4, 0, 0), IsYearly: true},
+				}, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableClosed},
+			},
+		},
+		{
+			name: "positive: employee works before the business opens",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, business.ID).Return([]entity.BusinessHours{
+					{BusinessID: business.ID, DayOfWeek: int(date.Weekday()), OpenTime: clock(10, 30), CloseTime: clock(20, 0)},
+				}, nil)
+				m.scheduleRepo.On("ListClosures", ctx, business.ID, date, date).Return(nil, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableOutsideHours},
+			},
+		},
+		{
+			name: "positive: business without opening hours on the weekday",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, business.ID).Return([]entity.BusinessHours{
+					{BusinessID: business.ID, DayOfWeek: int(date.AddDate(0, 0, 1).Weekday()), OpenTime: clock(9, 0), CloseTime: clock(17, 0)},
+				}, nil)
+				m.scheduleRepo.On("ListClosures", ctx, business.ID, date, date).Return(nil, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableClosed},
+			},
+		},
+		{
+			name: "positive: overlaps a break",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(11, 30)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{start: at(11, 30), end: at(12, 30)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableBreak},
+			},
+		},
+		{
+			name: "positive: outside working hours",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(16, 30)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{start: at(16, 30), end: at(17, 30)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableOutsideHours},
+			},
+		},
+		{
+			name: "positive: day off override",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return([]entity.ScheduleOverride{
+					{EmployeeID: employeeID, OverrideDate: date, IsWorkingDay: false},
+				}, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableDayOff},
+			},
+		},
+		{
+			name: "positive: no working hours on the weekday",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(employee, nil)
+				m.businessRepo.On("Get", ctx, employee.BusinessID).Return(business, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, at(10, 0)).Return([]entity.ScheduleTemplate{}, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, date, date).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableDayOff},
+			},
+		},
+		{
+			name: "positive: working hours in business timezone after clocks move forward",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(kyivEmployee, nil)
+				m.businessRepo.On("Get", ctx, kyivBusiness.ID).Return(kyivBusiness, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, sameInstant(kyivAt(9, 0))).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, sameInstant(kyivAt(0, 0)), sameInstant(kyivAt(0, 0))).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, sameInstant(kyivAt(9, 0)), sameInstant(kyivAt(10, 0)), 0).Return(true, nil)
+			},
+			// 06:00 UTC is 09:00 in Kyiv once the offset is +3
+			args: args{start: kyivAt(9, 0).UTC(), end: kyivAt(10, 0).UTC()},
+			expected: expected{
+				availability: &services.Availability{Available: true},
+			},
+		},
+		{
+			name: "positive: end of working hours in business timezone after clocks move forward",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(kyivEmployee, nil)
+				m.businessRepo.On("Get", ctx, kyivBusiness.ID).Return(kyivBusiness, nil)
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, sameInstant(kyivAt(16, 30))).Return(templates, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, sameInstant(kyivAt(0, 0)), sameInstant(kyivAt(0, 0))).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			// 13:30 UTC is 16:30 in Kyiv, so the hour runs past the end of the working day
+			args: args{start: kyivAt(16, 30).UTC(), end: kyivAt(17, 30).UTC()},
+			expected: expected{
+				availability: &services.Availability{Reason: services.UnavailableOutsideHours},
+			},
+		},
+		{
+			name: "negative: end before start",
+			mock: func(m mocksForExecution) {},
+			args: args{start: at(11, 0), end: at(10, 0)},
+			expected: expected{
+				err: fmt.Errorf("end time must be after start time"),
+			},
+		},
+		{
+			name: "negative: employee not found",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(nil, repository.ErrNotFound)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				err: fmt.Errorf("invalid employee: %w", repository.ErrNotFound),
+			},
+		},
+		{
+			name: "negative: employee is not active",
+			mock: func(m mocksForExecution) {
+				m.employeeRepo.On("Get", ctx, employeeID).Return(&entity.Employee{ID: employeeID, IsActive: false}, nil)
+			},
+			args: args{start: at(10, 0), end: at(11, 0)},
+			expected: expected{
+				err: fmt.Errorf("employee is not active"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				businessRepo:    businessRepoMock,
+				employeeRepo:    employeeRepoMock,
+				scheduleRepo:    scheduleRepoMock,
+			})
+
+			// Init service
+			scheduleService := services.NewScheduleService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Employee:    employeeRepoMock,
+				Schedule:    scheduleRepoMock,
+			})
+
+			// Execute
+			availability, err := scheduleService.IsAvailable(ctx, employeeID, tc.args.start, tc.args.end)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected.availability, availability)
+			}
+		})
+	}
+}
+
+func TestScheduleService_CreateTemplate(t *testing.T) {
+	t.Parallel()
+
+	employeeID := 1
+	employee := &entity.Employee{ID: employeeID, BusinessID: 1, IsActive: true}
+	date := func(month time.Month, day int) *time.Time {
+		d := time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	clock := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	monday := int(time.Monday)
+	// March 3, 2025 is a Monday and starts the first week of the rotations
+	anchor := date(3, 3)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		existing []entity.ScheduleTemplate
+		template *entity.ScheduleTemplate
+		err      error
+	}{
+		{
+			name: "positive: next month planned after the current schedule ends",
+			existing: []entity.ScheduleTemplate{
+				{ID: 1, DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), ValidUntil: date(3, 31)},
+			},
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(10), EndTime: clock(18), ValidFrom: date(4, 1)},
+		},
+		{
+			name: "positive: alternating weeks",
+			existing: []entity.ScheduleTemplate{
+				{ID: 1, DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), RotationWeeks: 2, RotationWeek: 1, RotationAnchor: anchor},
+			},
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(12), EndTime: clock(20), RotationWeeks: 2, RotationWeek: 2, RotationAnchor: anchor},
+		},
+		{
+			name: "negative: overlaps the current schedule without an end",
+			existing: []entity.ScheduleTemplate{
+				{ID: 1, DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17)},
+			},
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(10), EndTime: clock(18), ValidFrom: date(4, 1)},
+			err:      fmt.Errorf("template overlaps with existing schedule"),
+		},
+		{
+			name: "negative: rotations sharing a week",
+			existing: []entity.ScheduleTemplate{
+				{ID: 1, DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), RotationWeeks: 2, RotationWeek: 1, RotationAnchor: anchor},
+			},
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(12), EndTime: clock(20), RotationWeeks: 4, RotationWeek: 3, RotationAnchor: anchor},
+			err:      fmt.Errorf("template overlaps with existing schedule"),
+		},
+		{
+			name:     "negative: rotation without an anchor",
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), RotationWeeks: 2, RotationWeek: 1},
+			err:      fmt.Errorf("rotation anchor is required for rotating templates"),
+		},
+		{
+			name:     "negative: rotation week outside the cycle",
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), RotationWeeks: 2, RotationWeek: 3, RotationAnchor: anchor},
+			err:      fmt.Errorf("rotation week must be between 1 and 2"),
+		},
+		{
+			name:     "negative: valid until before valid from",
+			template: &entity.ScheduleTemplate{DayOfWeek: monday, StartTime: clock(9), EndTime: clock(17), ValidFrom: date(4, 1), ValidUntil: date(3, 31)},
+			err:      fmt.Errorf("valid until must not be before valid from"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			// Setup mocks
+			tc.template.EmployeeID = employeeID
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			scheduleRepoMock.On("ListTemplates", ctx, employeeID).Return(tc.existing, nil).Maybe()
+			if tc.err == nil {
+				scheduleRepoMock.On("CreateTemplate", ctx, tc.template).Return(nil)
+			}
+
+			// Init service
+			scheduleService := services.NewScheduleService(&repository.Repositories{
+				Employee: employeeRepoMock,
+				Schedule: scheduleRepoMock,
+			})
+
+			// Execute
+			err := scheduleService.CreateTemplate(ctx, tc.template)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %s: %v", name, err)
+	}
+	return loc
+}
+
+// nextClockChange returns the start of the first day after from on which the UTC offset of the location
+// increases (forward) or decreases (backward)
+func nextClockChange(loc *time.Location, from time.Time, forward bool) time.Time {
+	offset := func(day time.Time) int {
+		_, seconds := day.AddDate(0, 0, 1).Add(-time.Second).Zone()
+		return seconds
+	}
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	for i := 0; i < 400; i++ {
+		day = day.AddDate(0, 0, 1)
+		before, after := offset(day.AddDate(0, 0, -1)), offset(day)
+		if (forward && after > before) || (!forward && after < before) {
+			return day
+		}
+	}
+	panic("location has no clock changes")
+}
+
+// sameInstant matches a time argument by instant regardless of its location
+func sameInstant(expected time.Time) interface{} {
+	return mock.MatchedBy(func(actual time.Time) bool {
+		return actual.Equal(expected)
+	})
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/rrule"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// ErrSeriesUnavailable is returned when occurrences of a series cannot be booked and the
+// series is booked all-or-nothing, or when none of its occurrences can be booked
+var ErrSeriesUnavailable = errors.New("occurrences of the series are not available")
+
+// maxSeriesOccurrences limits the number of appointments a single series can book
+const maxSeriesOccurrences = 52
+
+func (s *appointmentService) CreateSeries(ctx context.Context, series *entity.AppointmentSeries, allOrNothing bool) (*SeriesResult, error) {
+	// Validate business existence
+	business, err := s.repos.Business.Get(ctx, series.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid business: %w", err)
+	}
+	loc := loadLocation(business.Timezone)
+
+	// Validate client existence
+	client, err := s.repos.User.Get(ctx, series.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client: %w", err)
+	}
+	if client.Role != entity.RoleClient {
+		return nil, fmt.Errorf("user is not a client")
+	}
+
+	// Validate service existence and availability
+	service, err := s.repos.Service.Get(ctx, series.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service: %w", err)
+	}
+	if !service.IsActive {
+		return nil, fmt.Errorf("service is not active")
+	}
+	if isGroupService(service) {
+		return nil, fmt.Errorf("series cannot be booked for group services")
+	}
+
+	// A series is always booked with the same employee
+	if series.EmployeeID == 0 {
+		return nil, fmt.Errorf("employee is required for a series")
+	}
+	employee, err := s.repos.Employee.Get(ctx, series.EmployeeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid employee: %w", err)
+	}
+	if !employee.IsActive {
+		return nil, fmt.Errorf("employee is not active")
+	}
+	if err := s.validateServiceAssignment(ctx, series.EmployeeID, series.ServiceID); err != nil {
+		return nil, err
+	}
+
+	// Occurrences take place at the branch of the employee and follow its timezone
+	if employee.LocationID != nil {
+		loc, err = scheduleLocation(ctx, s.repos, series.BusinessID, employee.LocationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Expand the rule in the local timezone, so occurrences keep their local time
+	rule, err := rrule.Parse(series.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+	starts := rule.Occurrences(series.StartTime.In(loc), maxSeriesOccurrences+1)
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("recurrence rule has no occurrences")
+	}
+	if len(starts) > maxSeriesOccurrences {
+		return nil, fmt.Errorf("series cannot have more than %d occurrences", maxSeriesOccurrences)
+	}
+
+	series.RRule = rule.String()
+	series.StartTime = starts[0].UTC()
+
+	result := &SeriesResult{
+		Series:       series,
+		Appointments: []*entity.Appointment{},
+		Failed:       []FailedOccurrence{},
+	}
+	for _, start := range starts {
+		appointment := &entity.Appointment{
+			BusinessID:   series.BusinessID,
+			ClientID:     series.ClientID,
+			EmployeeID:   series.EmployeeID,
+			ServiceID:    series.ServiceID,
+			StartTime:    start.UTC(),
+			EndTime:      start.UTC().Add(time.Duration(service.Duration) * time.Minute),
+			Status:       entity.AppointmentStatusScheduled,
+			ReminderTime: series.ReminderTime,
+			LocationID:   employee.LocationID,
+		}
+
+		reason, err := s.checkOccurrence(ctx, appointment, service, loc)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			result.Failed = append(result.Failed, FailedOccurrence{StartTime: appointment.StartTime, Reason: reason})
+			continue
+		}
+		result.Appointments = append(result.Appointments, appointment)
+	}
+
+	if len(result.Failed) > 0 && (allOrNothing || len(result.Appointments) == 0) {
+		result.Appointments = []*entity.Appointment{}
+		return result, ErrSeriesUnavailable
+	}
+
+	// The series and its appointments are stored together, a concurrent booking rejects the whole series
+	if err := s.repos.Appointment.CreateSeries(ctx, series, result.Appointments); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrSlotUnavailable
+		}
+		return nil, fmt.Errorf("failed to create appointment series: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *appointmentService) CancelFollowing(ctx context.Context, appointmentID int, reason string) ([]int, error) {
+	appointment, err := s.getSeriesAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateStatusTransition(appointment.Status, entity.AppointmentStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	// Cannot cancel past appointments
+	if appointment.StartTime.Before(time.Now()) {
+		return nil, fmt.Errorf("cannot cancel past appointments")
+	}
+
+	ids, err := s.repos.Appointment.CancelSeries(ctx, *appointment.SeriesID, appointment.StartTime, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel series appointments: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (s *appointmentService) RescheduleFollowing(ctx context.Context, appointmentID int, startTime time.Time, allOrNothing bool) (*SeriesResult, error) {
+	appointment, err := s.getSeriesAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only allow updates for scheduled appointments
+	if appointment.Status != entity.AppointmentStatusScheduled {
+		return nil, fmt.Errorf("can only update scheduled appointments")
+	}
+
+	// Cannot update past appointments
+	if appointment.StartTime.Before(time.Now()) {
+		return nil, fmt.Errorf("cannot update past appointments")
+	}
+
+	service, err := s.repos.Service.Get(ctx, appointment.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service details: %w", err)
+	}
+
+	loc, err := scheduleLocation(ctx, s.repos, appointment.BusinessID, appointment.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := appointment.StartTime
+	following, err := s.repos.Appointment.ListBySeries(ctx, *appointment.SeriesID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series appointments: %w", err)
+	}
+
+	// Every occurrence moves by the same number of local days and takes the new local start time
+	oldStart, newStart := from.In(loc), startTime.In(loc)
+	dayShift := int(dateIn(newStart, time.UTC).Sub(dateIn(oldStart, time.UTC)).Hours() / 24)
+	hour, minute, second := newStart.Clock()
+
+	result := &SeriesResult{
+		Appointments: []*entity.Appointment{},
+		Failed:       []FailedOccurrence{},
+	}
+	// Occurrences that cannot be moved keep their old time
+	previous := make(map[int]entity.Appointment, len(following))
+	var staying []entity.Appointment
+	for i := range following {
+		occurrence := &following[i]
+		if occurrence.Status != entity.AppointmentStatusScheduled {
+			continue
+		}
+
+		previous[occurrence.ID] = *occurrence
+		local := occurrence.StartTime.In(loc)
+		occurrence.StartTime = time.Date(local.Year(), local.Month(), local.Day()+dayShift, hour, minute, second, 0, loc).UTC()
+		occurrence.EndTime = occurrence.StartTime.Add(time.Duration(service.Duration) * time.Minute)
+
+		reason, err := s.checkMovedOccurrence(ctx, occurrence, service, loc, from)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			result.Failed = append(result.Failed, FailedOccurrence{StartTime: occurrence.StartTime, Reason: reason})
+			staying = append(staying, previous[occurrence.ID])
+			continue
+		}
+		result.Appointments = append(result.Appointments, occurrence)
+	}
+
+	// The moved occurrences were checked without the series, so those landing on an occurrence
+	// that keeps its time stay as well, which can block the next ones in turn
+	for blocked := len(staying) > 0; blocked; {
+		blocked = false
+		moved := result.Appointments[:0]
+		for _, occurrence := range result.Appointments {
+			if !overlapsOccurrences(occurrence, service, staying) {
+				moved = append(moved, occurrence)
+				continue
+			}
+
+			result.Failed = append(result.Failed, FailedOccurrence{StartTime: occurrence.StartTime, Reason: ErrSlotUnavailable.Error()})
+			staying = append(staying, previous[occurrence.ID])
+			blocked = true
+		}
+		result.Appointments = moved
+	}
+
+	if len(result.Failed) > 0 && (allOrNothing || len(result.Appointments) == 0) {
+		result.Appointments = []*entity.Appointment{}
+		return result, ErrSeriesUnavailable
+	}
+
+	if err := s.repos.Appointment.UpdateMany(ctx, result.Appointments); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrSlotUnavailable
+		}
+		return nil, fmt.Errorf("failed to update series appointments: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *appointmentService) getSeriesAppointment(ctx context.Context, appointmentID int) (*entity.Appointment, error) {
+	appointment, err := s.repos.Appointment.Get(ctx, appointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid appointment: %w", err)
+	}
+	if appointment.SeriesID == nil {
+		return nil, fmt.Errorf("appointment is not part of a series")
+	}
+
+	return appointment, nil
+}
+
+// checkMovedOccurrence returns the reason an occurrence of a series cannot be moved to its new time like
+// checkOccurrence does. The occurrences of the series starting at or after from are moved together,
+// so their old times do not block it.
+func (s *appointmentService) checkMovedOccurrence(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService, loc *time.Location, from time.Time) (string, error) {
+	if err := validateAppointmentPeriod(appointment, service, time.Now()); err != nil {
+		return err.Error(), nil
+	}
+
+	seriesID := *appointment.SeriesID
+	err := s.checkWorkingTime(ctx, appointment.EmployeeID, service, appointment.StartTime, appointment.EndTime, loc)
+	if err == nil {
+		blockedStart, blockedEnd := withBuffers(service, appointment.StartTime, appointment.EndTime)
+		var available bool
+		available, err = s.repos.Appointment.IsEmployeeAvailableForSeries(ctx, appointment.EmployeeID, seriesID, from, blockedStart, blockedEnd)
+		if err == nil && !available {
+			err = ErrSlotUnavailable
+		}
+	}
+	if err == nil {
+		err = s.assignResourceWith(ctx, appointment, service, func(resourceID int, startTime, endTime time.Time) (bool, error) {
+			return s.repos.Appointment.IsResourceAvailableForSeries(ctx, resourceID, seriesID, from, startTime, endTime)
+		})
+	}
+	if errors.Is(err, errOutsideWorkingHours) || errors.Is(err, ErrSlotUnavailable) {
+		return err.Error(), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check occurrence: %w", err)
+	}
+
+	return "", nil
+}
+
+// overlapsOccurrences reports whether the appointment overlaps any of the occurrences with the buffers of the service
+func overlapsOccurrences(appointment *entity.Appointment, service *entity.BusinessService, occurrences []entity.Appointment) bool {
+	blockedStart, blockedEnd := withBuffers(service, appointment.StartTime, appointment.EndTime)
+	for _, occurrence := range occurrences {
+		occurrenceStart, occurrenceEnd := withBuffers(service, occurrence.StartTime, occurrence.EndTime)
+		if occurrenceStart.Before(blockedEnd) && occurrenceEnd.After(blockedStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOccurrence returns the reason an occurrence of a series cannot be booked,
+// or an empty reason when it can. Errors are returned only when the check itself fails.
+func (s *appointmentService) checkOccurrence(ctx context.Context, appointment *entity.Appointment, service *entity.BusinessService, loc *time.Location) (string, error) {
+	if err := validateAppointmentPeriod(appointment, service, time.Now()); err != nil {
+		return err.Error(), nil
+	}
+
+	err := s.checkEmployeeTime(ctx, appointment.EmployeeID, service, appointment.StartTime, appointment.EndTime, appointment.ID, loc)
+	if err == nil {
+		err = s.assignResource(ctx, appointment, service)
+	}
+	if errors.Is(err, errOutsideWorkingHours) || errors.Is(err, ErrSlotUnavailable) {
+		return err.Error(), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check occurrence: %w", err)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,509 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestAppointmentService_CreateSeries(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		scheduleRepo    *mocks.ScheduleRepository
+	}
+
+	type args struct {
+		rrule        string
+		allOrNothing bool
+	}
+
+	type expected struct {
+		err    error
+		booked []time.Time
+		failed []time.Time
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	employeeID := 1
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time {
+		return start.AddDate(0, 0, n)
+	}
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, MinNotice: 15, MaxAdvanceDays: 30, IsActive: true}
+	client := &entity.User{ID: clientID, BusinessID: businessID, Role: entity.RoleClient}
+	employee := &entity.Employee{ID: employeeID, BusinessID: businessID, IsActive: true}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ctx := context.Background()
+
+	// mockFree sets up the availability of the employee for the occurrence starting at the given time
+	mockFree := func(m mocksForExecution, at time.Time, free bool) {
+		m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, at, at.Add(30*time.Minute), 0).Return(free, nil)
+	}
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: every occurrence booked",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				mockFree(m, day(0), true)
+				mockFree(m, day(7), true)
+				m.appointmentRepo.On("CreateSeries", ctx, mock.Anything, mock.Anything).Return(nil)
+			},
+			args: args{rrule: "FREQ=WEEKLY;COUNT=2"},
+			expected: expected{
+				booked: []time.Time{day(0), day(7)},
+			},
+		},
+		{
+			name: "positive: unavailable occurrence skipped",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				mockFree(m, day(0), true)
+				mockFree(m, day(1), false)
+				mockFree(m, day(2), true)
+				m.appointmentRepo.On("CreateSeries", ctx, mock.Anything, mock.MatchedBy(func(a []*entity.Appointment) bool {
+					return len(a) == 2
+				})).Return(nil)
+			},
+			args: args{rrule: "FREQ=DAILY;COUNT=3"},
+			expected: expected{
+				booked: []time.Time{day(0), day(2)},
+				failed: []time.Time{day(1)},
+			},
+		},
+		{
+			name: "negative: all or nothing with an unavailable occurrence",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				mockFree(m, day(0), true)
+				mockFree(m, day(1), false)
+			},
+			args: args{rrule: "FREQ=DAILY;COUNT=2", allOrNothing: true},
+			expected: expected{
+				err:    services.ErrSeriesUnavailable,
+				failed: []time.Time{day(1)},
+			},
+		},
+		{
+			name: "negative: occurrence beyond the advance window",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				mockFree(m, day(0), true)
+			},
+			args: args{rrule: "FREQ=WEEKLY;INTERVAL=5;COUNT=2", allOrNothing: true},
+			expected: expected{
+				err:    services.ErrSeriesUnavailable,
+				failed: []time.Time{day(35)},
+			},
+		},
+		{
+			name: "negative: concurrent booking",
+			mock: func(m mocksForExecution) {
+				m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+				m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+				m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				mockFree(m, day(0), true)
+				m.appointmentRepo.On("CreateSeries", ctx, mock.Anything, mock.Anything).Return(fmt.Errorf("failed to create appointment: %w", repository.ErrConflict))
+			},
+			args: args{rrule: "FREQ=DAILY;COUNT=1"},
+			expected: expected{
+				err: services.ErrSlotUnavailable,
+			},
+		},
+		{
+			name: "negative: invalid rule",
+			mock: func(m mocksForExecution) {},
+			args: args{rrule: "FREQ=HOURLY"},
+			expected: expected{
+				err: fmt.Errorf("invalid recurrence rule: %w", fmt.Errorf("unsupported frequency %q", "HOURLY")),
+			},
+		},
+		{
+			name: "negative: too many occurrences",
+			mock: func(m mocksForExecution) {},
+			args: args{rrule: "FREQ=DAILY"},
+			expected: expected{
+				err: fmt.Errorf("series cannot have more than 52 occurrences"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			userRepoMock := mocks.NewUserRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			userRepoMock.On("Get", ctx, clientID).Return(client, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+			employeeRepoMock.On("Get", ctx, employeeID).Return(employee, nil)
+			employeeRepoMock.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+
+			// Setup mocks
+			tc.mock(mocksForExecution{
+				appointmentRepo: appointmentRepoMock,
+				scheduleRepo:    scheduleRepoMock,
+			})
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				User:        userRepoMock,
+				Employee:    employeeRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			series := &entity.AppointmentSeries{
+				BusinessID: businessID,
+				ClientID:   clientID,
+				EmployeeID: employeeID,
+				ServiceID:  serviceID,
+				RRule:      tc.args.rrule,
+				StartTime:  start,
+			}
+			result, err := appointmentService.CreateSeries(ctx, series, tc.args.allOrNothing)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.expected.booked == nil && tc.expected.failed == nil {
+				return
+			}
+
+			require.NotNil(t, result)
+			booked := make([]time.Time, 0, len(result.Appointments))
+			for _, appointment := range result.Appointments {
+				assert.Equal(t, entity.AppointmentStatusScheduled, appointment.Status)
+				booked = append(booked, appointment.StartTime)
+			}
+			failed := make([]time.Time, 0, len(result.Failed))
+			for _, occurrence := range result.Failed {
+				assert.NotEmpty(t, occurrence.Reason)
+				failed = append(failed, occurrence.StartTime)
+			}
+
+			if tc.expected.booked == nil {
+				tc.expected.booked = []time.Time{}
+			}
+			if tc.expected.failed == nil {
+				tc.expected.failed = []time.Time{}
+			}
+			assert.Equal(t, tc.expected.booked, booked)
+			assert.Equal(t, tc.expected.failed, failed)
+		})
+	}
+}
+
+func TestAppointmentService_CancelFollowing(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		ids []int
+		err error
+	}
+
+	appointmentID := 1
+	seriesID := 5
+	reason := "moving away"
+	now := time.Now().UTC()
+	start := now.Add(24 * time.Hour)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m *mocks.AppointmentRepository)
+		expected expected
+	}{
+		{
+			name: "positive: following appointments cancelled",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(&entity.Appointment{ID: appointmentID, SeriesID: &seriesID, StartTime: start, Status: entity.AppointmentStatusScheduled}, nil)
+				m.On("CancelSeries", ctx, seriesID, start, reason).Return([]int{1, 2, 3}, nil)
+			},
+			expected: expected{
+				ids: []int{1, 2, 3},
+			},
+		},
+		{
+			name: "negative: appointment is not part of a series",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(&entity.Appointment{ID: appointmentID, StartTime: start, Status: entity.AppointmentStatusScheduled}, nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("appointment is not part of a series"),
+			},
+		},
+		{
+			name: "negative: appointment already cancelled",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(&entity.Appointment{ID: appointmentID, SeriesID: &seriesID, StartTime: start, Status: entity.AppointmentStatusCancelled}, nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: cancelled -> cancelled", services.ErrInvalidStatusTransition),
+			},
+		},
+		{
+			name: "negative: past appointment",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("Get", ctx, appointmentID).Return(&entity.Appointment{ID: appointmentID, SeriesID: &seriesID, StartTime: now.Add(-time.Hour), Status: entity.AppointmentStatusScheduled}, nil)
+			},
+			expected: expected{
+				err: fmt.Errorf("cannot cancel past appointments"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+
+			// Setup mocks
+			tc.mock(appointmentRepoMock)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			ids, err := appointmentService.CancelFollowing(ctx, appointmentID, reason)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected.ids, ids)
+			}
+		})
+	}
+}
+
+func TestAppointmentService_RescheduleFollowing(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		startTime    time.Time
+		allOrNothing bool
+	}
+
+	type expected struct {
+		err    error
+		booked []time.Time
+	}
+
+	businessID := 1
+	serviceID := 1
+	employeeID := 1
+	seriesID := 5
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	day := func(n, hour int) time.Time {
+		return time.Date(start.Year(), start.Month(), start.Day()+n, hour, 0, 0, 0, time.UTC)
+	}
+
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, IsActive: true}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+	series := func() []entity.Appointment {
+		appointments := make([]entity.Appointment, 0, 3)
+		for i := 0; i < 3; i++ {
+			appointments = append(appointments, entity.Appointment{
+				ID:         i + 1,
+				BusinessID: businessID,
+				EmployeeID: employeeID,
+				ServiceID:  serviceID,
+				SeriesID:   &seriesID,
+				StartTime:  day(7*i, 10),
+				EndTime:    day(7*i, 10).Add(30 * time.Minute),
+				Status:     entity.AppointmentStatusScheduled,
+			})
+		}
+		appointments[2].Status = entity.AppointmentStatusCancelled
+		return appointments
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m *mocks.AppointmentRepository)
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive: scheduled occurrences moved to the next day",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(1, 14), day(1, 14).Add(30*time.Minute)).Return(true, nil)
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(8, 14), day(8, 14).Add(30*time.Minute)).Return(true, nil)
+				m.On("UpdateMany", ctx, mock.Anything).Return(nil)
+			},
+			args: args{startTime: day(1, 14)},
+			expected: expected{
+				booked: []time.Time{day(1, 14), day(8, 14)},
+			},
+		},
+		{
+			name: "positive: occurrences moved by a week onto the old times of the series",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(7, 10), day(7, 10).Add(30*time.Minute)).Return(true, nil)
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(14, 10), day(14, 10).Add(30*time.Minute)).Return(true, nil)
+				m.On("UpdateMany", ctx, mock.Anything).Return(nil)
+			},
+			args: args{startTime: day(7, 10)},
+			expected: expected{
+				booked: []time.Time{day(7, 10), day(14, 10)},
+			},
+		},
+		{
+			name: "negative: occurrence moved onto an occurrence that keeps its time",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(7, 10), day(7, 10).Add(30*time.Minute)).Return(true, nil)
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(14, 10), day(14, 10).Add(30*time.Minute)).Return(false, nil)
+			},
+			args: args{startTime: day(7, 10)},
+			expected: expected{
+				err: services.ErrSeriesUnavailable,
+			},
+		},
+		{
+			name: "negative: all or nothing with an unavailable occurrence",
+			mock: func(m *mocks.AppointmentRepository) {
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(1, 14), day(1, 14).Add(30*time.Minute)).Return(true, nil)
+				m.On("IsEmployeeAvailableForSeries", ctx, employeeID, seriesID, day(0, 10), day(8, 14), day(8, 14).Add(30*time.Minute)).Return(false, nil)
+			},
+			args: args{startTime: day(1, 14), allOrNothing: true},
+			expected: expected{
+				err: services.ErrSeriesUnavailable,
+			},
+		},
+		{
+			name: "negative: outside working hours",
+			mock: func(m *mocks.AppointmentRepository) {},
+			args: args{startTime: day(0, 20)},
+			expected: expected{
+				err: services.ErrSeriesUnavailable,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+			scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+			appointments := series()
+			appointmentRepoMock.On("Get", ctx, 1).Return(&appointments[0], nil)
+			appointmentRepoMock.On("ListBySeries", ctx, seriesID, appointments[0].StartTime).Return(appointments, nil)
+			businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID}, nil)
+			serviceRepoMock.On("Get", ctx, serviceID).Return(service, nil)
+			scheduleRepoMock.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+			scheduleRepoMock.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+			scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+			// Setup mocks
+			tc.mock(appointmentRepoMock)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Service:     serviceRepoMock,
+				Schedule:    scheduleRepoMock,
+			}, nil, nil, nil)
+
+			// Execute
+			result, err := appointmentService.RescheduleFollowing(ctx, 1, tc.args.startTime, tc.args.allOrNothing)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			booked := make([]time.Time, 0, len(result.Appointments))
+			for _, appointment := range result.Appointments {
+				booked = append(booked, appointment.StartTime)
+			}
+			assert.Equal(t, tc.expected.booked, booked)
+		})
+	}
+}
@@ -5,26 +5,47 @@ import (
 	"time"
 
 	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/webhook"
 	"github.com/vadimpk/ppc-project/repository"
 )
 
 type Services struct {
-	Business    BusinessService
-	User        UserService
-	Employee    EmployeeService
-	Schedule    ScheduleService
-	Service     BusinessServiceService // renamed to avoid confusion
-	Appointment AppointmentService
+	Business       BusinessService
+	User           UserService
+	Employee       EmployeeService
+	Schedule       ScheduleService
+	BusinessHours  BusinessHoursService
+	TimeOff        TimeOffService
+	Service        BusinessServiceService // renamed to avoid confusion
+	Resource       ResourceService
+	Location       LocationService
+	Appointment    AppointmentService
+	Waitlist       WaitlistService
+	Webhook        WebhookService
+	Calendar       CalendarService
+	CalendarImport CalendarImportService
 }
 
-func NewServices(repos *repository.Repositories) *Services {
+// NewServices creates the services. The notifier informs waitlisted clients about offered slots and may be nil.
+// The sender sends webhook test and replayed deliveries.
+func NewServices(repos *repository.Repositories, notifier Notifier, sender *webhook.Sender) *Services {
+	appointments := NewAppointmentService(repos, NewLeastBookedStrategy(repos), notifier, nil)
+
 	return &Services{
-		Business:    NewBusinessService(repos),
-		User:        NewUserService(repos),
-		Employee:    NewEmployeeService(repos),
-		Schedule:    NewScheduleService(repos),
-		Service:     NewBusinessServiceService(repos),
-		Appointment: NewAppointmentService(repos),
+		Business:       NewBusinessService(repos),
+		User:           NewUserService(repos),
+		Employee:       NewEmployeeService(repos),
+		Schedule:       NewScheduleService(repos),
+		BusinessHours:  NewBusinessHoursService(repos, nil),
+		TimeOff:        NewTimeOffService(repos, nil),
+		Service:        NewBusinessServiceService(repos),
+		Resource:       NewResourceService(repos),
+		Location:       NewLocationService(repos),
+		Appointment:    appointments,
+		Waitlist:       NewWaitlistService(repos, appointments),
+		Webhook:        NewWebhookService(repos, sender, nil),
+		Calendar:       NewCalendarService(repos, nil),
+		CalendarImport: NewCalendarImportService(repos, nil, nil),
 	}
 }
 
@@ -34,8 +55,12 @@ type BusinessService interface {
 	Get(ctx context.Context, id int) (*entity.Business, error)
 	Update(ctx context.Context, business *entity.Business) error
 	UpdateAppearance(ctx context.Context, id int, logoURL string, colorScheme map[string]interface{}) error
-	ListBySearch(ctx context.Context, search string) ([]entity.Business, error)
-	ListServicesBySearch(ctx context.Context, search string) ([]entity.BusinessService, error)
+	// ListBySearch returns the businesses matching the search. A non-empty location limits them
+	// to businesses with an active branch whose name or address contains it.
+	ListBySearch(ctx context.Context, search, location string) ([]entity.Business, error)
+	// ListServicesBySearch returns the services matching the search. A non-empty location limits them
+	// to services provided at an active branch whose name or address contains it.
+	ListServicesBySearch(ctx context.Context, search, location string) ([]entity.BusinessService, error)
 }
 
 // UserService handles user management and authentication
@@ -70,9 +95,32 @@ type BusinessServiceService interface {
 	Delete(ctx context.Context, id int) error
 	List(ctx context.Context, businessID int) ([]entity.BusinessService, error)
 	ListActive(ctx context.Context, businessID int) ([]entity.BusinessService, error)
+	// ListByLocation returns the active services provided by the active employees of the branch
+	ListByLocation(ctx context.Context, businessID, locationID int) ([]entity.BusinessService, error)
 	ListEmployee(ctx context.Context, employeeID int) ([]entity.Employee, error)
 }
 
+// ResourceService manages the rooms, chairs and equipment services are booked with
+type ResourceService interface {
+	Create(ctx context.Context, resource *entity.Resource) error
+	Get(ctx context.Context, id int) (*entity.Resource, error)
+	List(ctx context.Context, businessID int) ([]entity.Resource, error)
+	Update(ctx context.Context, resource *entity.Resource) error
+	// Delete deactivates the resource, it is no longer booked but stays on its appointments
+	Delete(ctx context.Context, id int) error
+}
+
+// LocationService manages the branches of a business
+type LocationService interface {
+	// Create stores the branch, in the business timezone unless another one is given
+	Create(ctx context.Context, location *entity.Location) error
+	Get(ctx context.Context, id int) (*entity.Location, error)
+	List(ctx context.Context, businessID int) ([]entity.Location, error)
+	Update(ctx context.Context, location *entity.Location) error
+	// Delete deactivates the branch, its employees and appointments keep it
+	Delete(ctx context.Context, id int) error
+}
+
 // ScheduleService handles employee scheduling
 type ScheduleService interface {
 	// Regular schedule templates
@@ -88,7 +136,52 @@ type ScheduleService interface {
 	ListOverrides(ctx context.Context, employeeID int, startDate, endDate time.Time) ([]entity.ScheduleOverride, error)
 
 	// Availability checking
-	IsAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time) (bool, error)
+	IsAvailable(ctx context.Context, employeeID int, startTime, endTime time.Time) (*Availability, error)
+
+	// Bulk editing. Every change is validated before anything is saved and all changes are saved in a single
+	// transaction. Invalid changes return every problem found in the result together with ErrInvalidBulkSchedule.
+
+	// ReplaceTemplates replaces the weekly schedule of an employee of the business. With a from date the
+	// schedule is replaced from that date on, and the templates in effect before it are kept for the past.
+	ReplaceTemplates(ctx context.Context, businessID, employeeID int, templates []entity.ScheduleTemplate, from *time.Time) (*BulkScheduleResult, error)
+	// CopyTemplates replaces the weekly schedules of the target employees with the schedule of the source employee,
+	// from the given date on like ReplaceTemplates
+	CopyTemplates(ctx context.Context, businessID, sourceEmployeeID int, targetEmployeeIDs []int, from *time.Time) (*BulkScheduleResult, error)
+	// ApplyOverride creates the override for every employee on every date from startDate to endDate inclusive
+	ApplyOverride(ctx context.Context, businessID int, employeeIDs []int, startDate, endDate time.Time, override entity.ScheduleOverride) (*BulkScheduleResult, error)
+}
+
+// BusinessHoursService manages the opening hours and closures of businesses, such as public holidays.
+// They limit the working hours of all employees: employees are only bookable while their business is open.
+type BusinessHoursService interface {
+	GetHours(ctx context.Context, businessID int) ([]entity.BusinessHours, error)
+	// SetHours replaces the opening hours of the business, an empty list keeps the business open whenever employees work
+	SetHours(ctx context.Context, businessID int, hours []entity.BusinessHours) error
+
+	CreateClosure(ctx context.Context, closure *entity.BusinessClosure) error
+	GetClosure(ctx context.Context, id int) (*entity.BusinessClosure, error)
+	// ListClosures returns the closures overlapping the dates, including every yearly closure started by endDate
+	ListClosures(ctx context.Context, businessID int, startDate, endDate time.Time) ([]entity.BusinessClosure, error)
+	DeleteClosure(ctx context.Context, id int) error
+	// ImportClosures creates closures from an iCalendar holiday list and returns the number of new closures.
+	// Holidays that already exist with the same name and start date are skipped, so a list can be imported again.
+	ImportClosures(ctx context.Context, businessID int, data []byte) (int, error)
+}
+
+// TimeOffService handles time off requested by employees and decided by admins.
+// Approving a request creates non-working overrides, scheduled appointments during the time off
+// are kept and returned as conflicts to reassign or cancel.
+type TimeOffService interface {
+	// Request submits a pending request for the employee
+	Request(ctx context.Context, request *entity.TimeOffRequest) error
+	Get(ctx context.Context, id int) (*entity.TimeOffRequest, error)
+	ListByEmployee(ctx context.Context, employeeID int) ([]entity.TimeOffRequest, error)
+	// ListByBusiness returns the requests of the business with the status, or all of them when status is empty
+	ListByBusiness(ctx context.Context, businessID int, status string) ([]entity.TimeOffRequest, error)
+	Approve(ctx context.Context, id int, adminID int, note string) (*TimeOffDecision, error)
+	Reject(ctx context.Context, id int, adminID int, note string) (*entity.TimeOffRequest, error)
+	// Conflicts returns the scheduled appointments during the time off of an approved or pending request
+	Conflicts(ctx context.Context, id int) ([]entity.Appointment, error)
 }
 
 // AppointmentService handles appointment management
@@ -96,15 +189,174 @@ type AppointmentService interface {
 	Create(ctx context.Context, appointment *entity.Appointment) error
 	Get(ctx context.Context, id int) (*entity.Appointment, error)
 	Update(ctx context.Context, appointment *entity.Appointment) error
-	Cancel(ctx context.Context, id int) error
+	Cancel(ctx context.Context, id int, reason string) error
+	Complete(ctx context.Context, id int) error
+	MarkNoShow(ctx context.Context, id int) error
 	ListByBusiness(ctx context.Context, businessID int, startTime, endTime time.Time) ([]entity.Appointment, error)
 	ListByEmployee(ctx context.Context, employeeID int, startTime, endTime time.Time) ([]entity.Appointment, error)
 	ListByClient(ctx context.Context, clientID int, startTime, endTime time.Time) ([]entity.Appointment, error)
-	GetAvailableSlots(ctx context.Context, employeeID int, serviceID int, date time.Time) ([]TimeSlot, error)
+	GetAvailableSlots(ctx context.Context, employeeID int, serviceID int, locationID int, date time.Time) ([]TimeSlot, error)
+	// GetServiceSlots combines the free slots of every active employee providing the service
+	GetServiceSlots(ctx context.Context, serviceID int, locationID int, date time.Time) ([]TimeSlot, error)
+	// GetAvailability returns the free slots per day for the date range.
+	// Dates are calendar days in the timezone of the requested branch, of the requested employee's branch,
+	// or of the business. With employeeID 0 the slots of every active employee providing the service are combined.
+	// A locationID other than 0 limits the slots to the employees of the branch.
+	GetAvailability(ctx context.Context, employeeID int, serviceID int, locationID int, startDate, endDate time.Time) ([]DayAvailability, error)
+	// GetNextAvailableSlot returns the first free slot starting from the given date, or from today when it is zero
+	GetNextAvailableSlot(ctx context.Context, employeeID int, serviceID int, locationID int, from time.Time) (*TimeSlot, error)
+
+	// CreateSeries books every occurrence of a recurring series with the same employee.
+	// Occurrences that cannot be booked are reported in the result, with allOrNothing nothing is booked then.
+	CreateSeries(ctx context.Context, series *entity.AppointmentSeries, allOrNothing bool) (*SeriesResult, error)
+	// CancelFollowing cancels the appointment and the following appointments of its series and returns their IDs
+	CancelFollowing(ctx context.Context, appointmentID int, reason string) ([]int, error)
+	// RescheduleFollowing moves the appointment and the following appointments of its series
+	// by the same number of days to the new local start time
+	RescheduleFollowing(ctx context.Context, appointmentID int, startTime time.Time, allOrNothing bool) (*SeriesResult, error)
+
+	// Hold reserves a free slot for the client for a few minutes. The interval is not offered to others
+	// until the hold expires, and Create books it when given the hold token.
+	Hold(ctx context.Context, appointment *entity.Appointment) (*SlotHold, error)
+	// ReleaseHold frees a held slot before the hold expires
+	ReleaseHold(ctx context.Context, token string) error
+	// DeleteExpiredHolds removes expired holds and returns their number
+	DeleteExpiredHolds(ctx context.Context) (int, error)
+}
+
+// WaitlistService handles waitlists for fully booked days.
+// Slots freed by cancellations are offered to the oldest matching entry.
+type WaitlistService interface {
+	// Join adds the client to the waitlist. A window without an end covers the local day of its start.
+	Join(ctx context.Context, entry *entity.WaitlistEntry) error
+	Get(ctx context.Context, id int) (*entity.WaitlistEntry, error)
+	ListByClient(ctx context.Context, clientID int) ([]entity.WaitlistEntry, error)
+	Leave(ctx context.Context, id int) error
+	// AcceptOffer confirms the held appointment of an offered entry
+	AcceptOffer(ctx context.Context, id int) (*entity.Appointment, error)
+	// DeclineOffer gives the held slot to the next matching entry
+	DeclineOffer(ctx context.Context, id int) error
+	// ExpireOffers releases the offers whose hold has expired, offering their slots to the next entries,
+	// and returns the number of released offers
+	ExpireOffers(ctx context.Context) (int, error)
+}
+
+// WebhookService manages the webhook endpoints of businesses and their delivery log
+type WebhookService interface {
+	// Create validates the webhook and generates its signing secret
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	Get(ctx context.Context, id int) (*entity.Webhook, error)
+	List(ctx context.Context, businessID int) ([]entity.Webhook, error)
+	Update(ctx context.Context, webhook *entity.Webhook) error
+	Delete(ctx context.Context, id int) error
+	// ListDeliveries returns the latest deliveries of the webhook, newest first
+	ListDeliveries(ctx context.Context, webhookID int) ([]entity.WebhookDelivery, error)
+	// Test sends a webhook.test delivery right away and returns its outcome
+	Test(ctx context.Context, webhookID int) (*entity.WebhookDelivery, error)
+	// Replay sends a delivery of the webhook again right away and returns its outcome
+	Replay(ctx context.Context, webhookID, deliveryID int) (*entity.WebhookDelivery, error)
+}
+
+// CalendarService serves read-only iCalendar feeds of appointments. Feeds are authenticated by a token
+// in their URL, issuing a new token revokes the previous one.
+type CalendarService interface {
+	CreateEmployeeFeed(ctx context.Context, employeeID int) (*entity.CalendarFeed, error)
+	CreateClientFeed(ctx context.Context, clientID int) (*entity.CalendarFeed, error)
+	RevokeEmployeeFeed(ctx context.Context, employeeID int) error
+	RevokeClientFeed(ctx context.Context, clientID int) error
+	// Feed renders the recent and upcoming appointments of the owner of the token
+	Feed(ctx context.Context, token string) ([]byte, error)
+	// AppointmentCalendar renders a single appointment for its client
+	AppointmentCalendar(ctx context.Context, appointmentID int) ([]byte, error)
+}
+
+// CalendarImportService imports external iCalendar calendars of employees. The occurrences of their
+// events are busy time: slots overlapping them are not offered and cannot be booked.
+// Importing a calendar under the name of a previous import replaces the busy time of that import.
+type CalendarImportService interface {
+	// ImportFile imports an uploaded calendar
+	ImportFile(ctx context.Context, employeeID int, name string, data []byte) (*entity.CalendarImport, error)
+	// Subscribe imports the calendar at the URL. Subscribed calendars are imported again by Refresh and RefreshAll.
+	Subscribe(ctx context.Context, employeeID int, name, url string) (*entity.CalendarImport, error)
+	// Refresh imports a subscribed calendar again. A failed import is recorded and keeps the previous busy time.
+	Refresh(ctx context.Context, id int) (*entity.CalendarImport, error)
+	// RefreshAll refreshes every subscribed calendar and returns the number of failed refreshes
+	RefreshAll(ctx context.Context) (int, error)
+	Get(ctx context.Context, id int) (*entity.CalendarImport, error)
+	List(ctx context.Context, employeeID int) ([]entity.CalendarImport, error)
+	Delete(ctx context.Context, id int) error
 }
 
 // Supporting types that match our schema
 type TimeSlot struct {
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
+	// RemainingSeats is the number of free seats of a group class session, omitted for one-on-one services
+	RemainingSeats int `json:"remaining_seats,omitempty"`
+}
+
+// SlotHold is a slot reserved for a client during checkout
+type SlotHold struct {
+	Token       string              `json:"token"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+	Appointment *entity.Appointment `json:"appointment"`
+}
+
+// SeriesResult holds the booked appointments of a series and the occurrences that could not be booked
+type SeriesResult struct {
+	Series       *entity.AppointmentSeries `json:"series,omitempty"`
+	Appointments []*entity.Appointment     `json:"appointments"`
+	Failed       []FailedOccurrence        `json:"failed"`
+}
+
+// FailedOccurrence is an occurrence of a series that could not be booked
+type FailedOccurrence struct {
+	StartTime time.Time `json:"start_time"`
+	Reason    string    `json:"reason"`
+}
+
+// BulkScheduleResult holds the templates or overrides created by a bulk schedule change,
+// or every problem found when the change is invalid
+type BulkScheduleResult struct {
+	Templates []*entity.ScheduleTemplate `json:"templates,omitempty"`
+	Overrides []*entity.ScheduleOverride `json:"overrides,omitempty"`
+	Errors    []BulkScheduleError        `json:"errors"`
+}
+
+// BulkScheduleError is a problem found in a bulk schedule change
+type BulkScheduleError struct {
+	EmployeeID int    `json:"employee_id,omitempty"`
+	Index      *int   `json:"index,omitempty"` // position of the template in the request
+	Date       string `json:"date,omitempty"`  // YYYY-MM-DD
+	Message    string `json:"message"`
+}
+
+// TimeOffDecision is an approved time off request with the scheduled appointments during it
+type TimeOffDecision struct {
+	Request   *entity.TimeOffRequest `json:"request"`
+	Conflicts []entity.Appointment   `json:"conflicts"`
+}
+
+// DayAvailability holds the free slots of a single day
+type DayAvailability struct {
+	Date  string     `json:"date"` // YYYY-MM-DD
+	Slots []TimeSlot `json:"slots"`
+}
+
+// Reasons why an employee cannot take an interval
+const (
+	UnavailableOutsideHours = "outside_working_hours"
+	UnavailableBreak        = "break"
+	UnavailableDayOff       = "day_off"
+	UnavailableConflict     = "conflict"
+	// UnavailableBusy is the time of an event of an imported calendar
+	UnavailableBusy = "busy"
+	// UnavailableClosed is a day the business is closed, by a closure or by its opening hours
+	UnavailableClosed = "business_closed"
+)
+
+// Availability is the result of an availability check, with the reason set when unavailable
+type Availability struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
 }
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Sweeper periodically releases expired holds: checkout holds and waitlist offers
+type Sweeper struct {
+	appointments AppointmentService
+	waitlist     WaitlistService
+	interval     time.Duration
+}
+
+func NewSweeper(appointments AppointmentService, waitlist WaitlistService, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		appointments: appointments,
+		waitlist:     waitlist,
+		interval:     interval,
+	}
+}
+
+// Run sweeps every interval until the context is cancelled
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep makes a single pass. Failures are logged and retried on the next pass.
+func (s *Sweeper) Sweep(ctx context.Context) {
+	deleted, err := s.appointments.DeleteExpiredHolds(ctx)
+	if err != nil {
+		log.Printf("Failed to delete expired holds: %v", err)
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d expired holds", deleted)
+	}
+
+	expired, err := s.waitlist.ExpireOffers(ctx)
+	if err != nil {
+		log.Printf("Failed to expire waitlist offers: %v", err)
+	}
+	if expired > 0 {
+		log.Printf("Expired %d waitlist offers", expired)
+	}
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+// maxTimeOffDays limits the number of days of a single time off request
+const maxTimeOffDays = 90
+
+var (
+	// ErrInvalidTimeOff is returned for time off requests with invalid dates or times
+	ErrInvalidTimeOff = errors.New("invalid time off request")
+	// ErrTimeOffDecided is returned when deciding a request that was already approved or rejected
+	ErrTimeOffDecided = errors.New("time off request was already decided")
+)
+
+type timeOffService struct {
+	repos *repository.Repositories
+	clock Clock
+}
+
+// NewTimeOffService creates the service. The clock defaults to the system clock when nil.
+func NewTimeOffService(repos *repository.Repositories, clock Clock) TimeOffService {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &timeOffService{
+		repos: repos,
+		clock: clock,
+	}
+}
+
+func (s *timeOffService) Request(ctx context.Context, request *entity.TimeOffRequest) error {
+	// Validate employee existence and business context
+	employee, err := s.repos.Employee.Get(ctx, request.EmployeeID)
+	if err != nil {
+		return fmt.Errorf("invalid employee: %w", err)
+	}
+	if !employee.IsActive {
+		return fmt.Errorf("employee is not active")
+	}
+
+	loc, err := scheduleLocation(ctx, s.repos, employee.BusinessID, employee.LocationID)
+	if err != nil {
+		return err
+	}
+	today := dateIn(s.clock.Now().In(loc), time.UTC)
+
+	request.StartDate = dateIn(request.StartDate, time.UTC)
+	request.EndDate = dateIn(request.EndDate, time.UTC)
+	if err := validateTimeOffRequest(request, today); err != nil {
+		return err
+	}
+
+	// The repository rejects requests overlapping ones that are still pending or already approved
+	request.BusinessID = employee.BusinessID
+	if err := s.repos.TimeOff.Create(ctx, request); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return fmt.Errorf("%w: overlaps with another time off request", ErrInvalidTimeOff)
+		}
+		return fmt.Errorf("failed to create time off request: %w", err)
+	}
+
+	return nil
+}
+
+func (s *timeOffService) Get(ctx context.Context, id int) (*entity.TimeOffRequest, error) {
+	request, err := s.repos.TimeOff.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off request: %w", err)
+	}
+
+	return request, nil
+}
+
+func (s *timeOffService) ListByEmployee(ctx context.Context, employeeID int) ([]entity.TimeOffRequest, error) {
+	requests, err := s.repos.TimeOff.ListByEmployee(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+func (s *timeOffService) ListByBusiness(ctx context.Context, businessID int, status string) ([]entity.TimeOffRequest, error) {
+	switch status {
+	case "", entity.TimeOffStatusPending, entity.TimeOffStatusApproved, entity.TimeOffStatusRejected:
+	default:
+		return nil, fmt.Errorf("%w: invalid status", ErrInvalidTimeOff)
+	}
+
+	requests, err := s.repos.TimeOff.ListByBusiness(ctx, businessID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// Approve takes the employee off for the dates of the request with non-working overrides
+func (s *timeOffService) Approve(ctx context.Context, id int, adminID int, note string) (*TimeOffDecision, error) {
+	request, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != entity.TimeOffStatusPending {
+		return nil, ErrTimeOffDecided
+	}
+
+	s.decide(request, adminID, note)
+	if err := s.repos.TimeOff.Approve(ctx, request, timeOffOverrides(request)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTimeOffDecided
+		}
+		return nil, fmt.Errorf("failed to approve time off request: %w", err)
+	}
+
+	conflicts, err := s.conflicts(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TimeOffDecision{
+		Request:   request,
+		Conflicts: conflicts,
+	}, nil
+}
+
+func (s *timeOffService) Reject(ctx context.Context, id int, adminID int, note string) (*entity.TimeOffRequest, error) {
+	request, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != entity.TimeOffStatusPending {
+		return nil, ErrTimeOffDecided
+	}
+
+	s.decide(request, adminID, note)
+	if err := s.repos.TimeOff.Reject(ctx, request); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTimeOffDecided
+		}
+		return nil, fmt.Errorf("failed to reject time off request: %w", err)
+	}
+
+	return request, nil
+}
+
+func (s *timeOffService) Conflicts(ctx context.Context, id int) ([]entity.Appointment, error) {
+	request, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status == entity.TimeOffStatusRejected {
+		return []entity.Appointment{}, nil
+	}
+
+	return s.conflicts(ctx, request)
+}
+
+// decide sets the decision fields of the request
+func (s *timeOffService) decide(request *entity.TimeOffRequest, adminID int, note string) {
+	now := s.clock.Now()
+	request.DecidedBy = &adminID
+	request.DecidedAt = &now
+	request.DecisionNote = nil
+	if note != "" {
+		request.DecisionNote = &note
+	}
+}
+
+// conflicts returns the scheduled appointments of the employee overlapping the time off,
+// whose dates are in the timezone of the employee's branch
+func (s *timeOffService) conflicts(ctx context.Context, request *entity.TimeOffRequest) ([]entity.Appointment, error) {
+	employee, err := s.repos.Employee.Get(ctx, request.EmployeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+	loc, err := scheduleLocation(ctx, s.repos, request.BusinessID, employee.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := timeOffInterval(request, loc)
+	appointments, err := s.repos.Appointment.ListByEmployee(ctx, request.EmployeeID, interval.StartTime, interval.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointments: %w", err)
+	}
+
+	conflicts := []entity.Appointment{}
+	for _, a := range appointments {
+		if a.Status == entity.AppointmentStatusScheduled && overlapsAny(a.StartTime, a.EndTime, []TimeSlot{interval}) {
+			conflicts = append(conflicts, a)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// timeOffOverrides returns a non-working override for every date of the request.
+// Partial days get an override for their hours only, which is subtracted from the working hours like a break.
+func timeOffOverrides(request *entity.TimeOffRequest) []*entity.ScheduleOverride {
+	var overrides []*entity.ScheduleOverride
+	for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+		overrides = append(overrides, &entity.ScheduleOverride{
+			EmployeeID:   request.EmployeeID,
+			OverrideDate: date,
+			IsWorkingDay: false,
+			StartTime:    request.StartTime,
+			EndTime:      request.EndTime,
+		})
+	}
+	return overrides
+}
+
+// timeOffInterval returns the time the request takes off in the business location
+func timeOffInterval(request *entity.TimeOffRequest, loc *time.Location) TimeSlot {
+	start := dateIn(request.StartDate, loc)
+	if request.StartTime != nil && request.EndTime != nil {
+		return timeOfDayInterval(start, *request.StartTime, *request.EndTime)
+	}
+
+	return TimeSlot{
+		StartTime: start,
+		EndTime:   dateIn(request.EndDate, loc).AddDate(0, 0, 1),
+	}
+}
+
+func validateTimeOffRequest(request *entity.TimeOffRequest, today time.Time) error {
+	if request.StartDate.Before(today) {
+		return fmt.Errorf("%w: cannot request time off for past dates", ErrInvalidTimeOff)
+	}
+	if request.EndDate.Before(request.StartDate) {
+		return fmt.Errorf("%w: end date must not be before start date", ErrInvalidTimeOff)
+	}
+	if request.EndDate.Sub(request.StartDate).Hours()/24 >= maxTimeOffDays {
+		return fmt.Errorf("%w: time off cannot exceed %d days", ErrInvalidTimeOff, maxTimeOffDays)
+	}
+	if request.Reason != nil && len(*request.Reason) > 1000 {
+		return fmt.Errorf("%w: reason must be at most 1000 characters", ErrInvalidTimeOff)
+	}
+
+	if (request.StartTime == nil) != (request.EndTime == nil) {
+		return fmt.Errorf("%w: both start time and end time must be provided for part of a day", ErrInvalidTimeOff)
+	}
+	if request.StartTime != nil {
+		if !request.EndDate.Equal(request.StartDate) {
+			return fmt.Errorf("%w: part of a day can only be requested for a single date", ErrInvalidTimeOff)
+		}
+		if request.StartTime.Hour()*60+request.StartTime.Minute() >= request.EndTime.Hour()*60+request.EndTime.Minute() {
+			return fmt.Errorf("%w: end time must be after start time", ErrInvalidTimeOff)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestTimeOffService_Request(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 10, 23, 30, 0, 0, time.UTC)
+	employee := &entity.Employee{ID: 2, BusinessID: 1, IsActive: true}
+	business := &entity.Business{ID: 1, Timezone: "Europe/Kyiv"}
+	date := func(day int) time.Time {
+		return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)
+	}
+	clock := func(hour int) *time.Time {
+		t := time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		request *entity.TimeOffRequest
+		mock    func(s *mocks.TimeOffRepository)
+		err     error
+	}{
+		{
+			name:    "positive: days off starting today in the business timezone",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(11), EndDate: date(14)},
+			mock: func(s *mocks.TimeOffRepository) {
+				s.On("Create", ctx, &entity.TimeOffRequest{
+					BusinessID: business.ID, EmployeeID: employee.ID, StartDate: date(11), EndDate: date(14),
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "positive: part of a day",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(12), EndDate: date(12), StartTime: clock(14), EndTime: clock(16)},
+			mock: func(s *mocks.TimeOffRepository) {
+				s.On("Create", ctx, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:    "negative: overlaps with another request",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(12), EndDate: date(12), StartTime: clock(9), EndTime: clock(12)},
+			mock: func(s *mocks.TimeOffRepository) {
+				s.On("Create", ctx, mock.Anything).Return(repository.ErrConflict)
+			},
+			err: fmt.Errorf("%w: overlaps with another time off request", services.ErrInvalidTimeOff),
+		},
+		{
+			name:    "negative: in the past",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(10), EndDate: date(12)},
+			mock:    func(s *mocks.TimeOffRepository) {},
+			err:     fmt.Errorf("%w: cannot request time off for past dates", services.ErrInvalidTimeOff),
+		},
+		{
+			name:    "negative: ending before it starts",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(14), EndDate: date(12)},
+			mock:    func(s *mocks.TimeOffRepository) {},
+			err:     fmt.Errorf("%w: end date must not be before start date", services.ErrInvalidTimeOff),
+		},
+		{
+			name:    "negative: too long",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(11), EndDate: date(11).AddDate(0, 0, 90)},
+			mock:    func(s *mocks.TimeOffRepository) {},
+			err:     fmt.Errorf("%w: time off cannot exceed 90 days", services.ErrInvalidTimeOff),
+		},
+		{
+			name:    "negative: part of several days",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(11), EndDate: date(12), StartTime: clock(9), EndTime: clock(12)},
+			mock:    func(s *mocks.TimeOffRepository) {},
+			err:     fmt.Errorf("%w: part of a day can only be requested for a single date", services.ErrInvalidTimeOff),
+		},
+		{
+			name:    "negative: part of a day ending before it starts",
+			request: &entity.TimeOffRequest{EmployeeID: employee.ID, StartDate: date(11), EndDate: date(11), StartTime: clock(12), EndTime: clock(9)},
+			mock:    func(s *mocks.TimeOffRepository) {},
+			err:     fmt.Errorf("%w: end time must be after start time", services.ErrInvalidTimeOff),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			timeOffRepoMock := mocks.NewTimeOffRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+
+			// Setup mocks
+			employeeRepoMock.On("Get", ctx, employee.ID).Return(employee, nil)
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil)
+			tc.mock(timeOffRepoMock)
+
+			// Init service
+			timeOffService := services.NewTimeOffService(&repository.Repositories{
+				TimeOff:  timeOffRepoMock,
+				Employee: employeeRepoMock,
+				Business: businessRepoMock,
+			}, &fakeClock{now: now})
+
+			// Execute
+			err := timeOffService.Request(ctx, tc.request)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTimeOffService_Approve(t *testing.T) {
+	t.Parallel()
+
+	kyiv := loadLocation(t, "Europe/Kyiv")
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	adminID := 7
+	business := &entity.Business{ID: 1, Timezone: "Europe/Kyiv"}
+	date := func(day int) time.Time {
+		return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)
+	}
+	kyivAt := func(day, hour int) time.Time {
+		return time.Date(2025, 3, day, hour, 0, 0, 0, kyiv)
+	}
+	clock := func(hour int) *time.Time {
+		t := time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	scheduled := func(id, day, hour int) entity.Appointment {
+		return entity.Appointment{ID: id, StartTime: kyivAt(day, hour), EndTime: kyivAt(day, hour+1), Status: entity.AppointmentStatusScheduled}
+	}
+	london := loadLocation(t, "Europe/London")
+	branch := &entity.Location{ID: 5, BusinessID: business.ID, Timezone: "Europe/London"}
+	employees := map[int]*entity.Employee{
+		2: {ID: 2, BusinessID: business.ID, IsActive: true},
+		4: {ID: 4, BusinessID: business.ID, LocationID: &branch.ID, IsActive: true},
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name      string
+		request   *entity.TimeOffRequest
+		mock      func(s *mocks.TimeOffRepository, a *mocks.AppointmentRepository)
+		conflicts []int
+		err       error
+	}{
+		{
+			name: "positive: days off with scheduled appointments",
+			request: &entity.TimeOffRequest{
+				ID: 3, BusinessID: business.ID, EmployeeID: 2, StartDate: date(10), EndDate: date(11), Status: entity.TimeOffStatusPending,
+			},
+			mock: func(s *mocks.TimeOffRepository, a *mocks.AppointmentRepository) {
+				s.On("Approve", ctx, mock.MatchedBy(func(r *entity.TimeOffRequest) bool {
+					return *r.DecidedBy == adminID && r.DecidedAt.Equal(now) && r.DecisionNote == nil
+				}), []*entity.ScheduleOverride{
+					{EmployeeID: 2, OverrideDate: date(10)},
+					{EmployeeID: 2, OverrideDate: date(11)},
+				}).Return(nil)
+				a.On("ListByEmployee", ctx, 2, kyivAt(10, 0), kyivAt(12, 0)).Return([]entity.Appointment{
+					scheduled(1, 10, 9),
+					{ID: 2, StartTime: kyivAt(10, 11), EndTime: kyivAt(10, 12), Status: entity.AppointmentStatusCancelled},
+					scheduled(3, 11, 18),
+				}, nil)
+			},
+			conflicts: []int{1, 3},
+		},
+		{
+			name: "positive: part of a day",
+			request: &entity.TimeOffRequest{
+				ID: 3, BusinessID: business.ID, EmployeeID: 2, StartDate: date(10), EndDate: date(10), StartTime: clock(9), EndTime: clock(12), Status: entity.TimeOffStatusPending,
+			},
+			mock: func(s *mocks.TimeOffRepository, a *mocks.AppointmentRepository) {
+				s.On("Approve", ctx, mock.Anything, []*entity.ScheduleOverride{
+					{EmployeeID: 2, OverrideDate: date(10), StartTime: clock(9), EndTime: clock(12)},
+				}).Return(nil)
+				a.On("ListByEmployee", ctx, 2, kyivAt(10, 9), kyivAt(10, 12)).Return([]entity.Appointment{
+					scheduled(1, 10, 11),
+				}, nil)
+			},
+			conflicts: []int{1},
+		},
+		{
+			name: "positive: days off at a branch in another timezone",
+			request: &entity.TimeOffRequest{
+				ID: 3, BusinessID: business.ID, EmployeeID: 4, StartDate: date(10), EndDate: date(10), Status: entity.TimeOffStatusPending,
+			},
+			mock: func(s *mocks.TimeOffRepository, a *mocks.AppointmentRepository) {
+				s.On("Approve", ctx, mock.Anything, mock.Anything).Return(nil)
+				a.On("ListByEmployee", ctx, 4, time.Date(2025, 3, 10, 0, 0, 0, 0, london), time.Date(2025, 3, 11, 0, 0, 0, 0, london)).Return([]entity.Appointment{
+					// 23:30 in Kyiv is 21:30 in London, still the day off
+					{ID: 1, StartTime: kyivAt(10, 23), EndTime: kyivAt(10, 23).Add(30 * time.Minute), Status: entity.AppointmentStatusScheduled},
+				}, nil)
+			},
+			conflicts: []int{1},
+		},
+		{
+			name: "negative: already decided",
+			request: &entity.TimeOffRequest{
+				ID: 3, BusinessID: business.ID, EmployeeID: 2, StartDate: date(10), EndDate: date(10), Status: entity.TimeOffStatusRejected,
+			},
+			mock: func(s *mocks.TimeOffRepository, a *mocks.AppointmentRepository) {},
+			err:  services.ErrTimeOffDecided,
+		},
+		{
+			name: "negative: decided meanwhile",
+			request: &entity.TimeOffRequest{
+				ID: 3, BusinessID: business.ID, EmployeeID: 2, StartDate: date(10), EndDate: date(10), Status: entity.TimeOffStatusPending,
+			},
+			mock: func(s *mocks.TimeOffRepository, a *mocks.AppointmentRepository) {
+				s.On("Approve", ctx, mock.Anything, mock.Anything).Return(repository.ErrNotFound)
+			},
+			err: services.ErrTimeOffDecided,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			timeOffRepoMock := mocks.NewTimeOffRepository(t)
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			employeeRepoMock := mocks.NewEmployeeRepository(t)
+			locationRepoMock := mocks.NewLocationRepository(t)
+
+			// Setup mocks
+			timeOffRepoMock.On("Get", ctx, tc.request.ID).Return(tc.request, nil)
+			businessRepoMock.On("Get", ctx, business.ID).Return(business, nil).Maybe()
+			employeeRepoMock.On("Get", ctx, tc.request.EmployeeID).Return(employees[tc.request.EmployeeID], nil).Maybe()
+			locationRepoMock.On("Get", ctx, branch.ID).Return(branch, nil).Maybe()
+			tc.mock(timeOffRepoMock, appointmentRepoMock)
+
+			// Init service
+			timeOffService := services.NewTimeOffService(&repository.Repositories{
+				TimeOff:     timeOffRepoMock,
+				Appointment: appointmentRepoMock,
+				Business:    businessRepoMock,
+				Employee:    employeeRepoMock,
+				Location:    locationRepoMock,
+			}, &fakeClock{now: now})
+
+			// Execute
+			decision, err := timeOffService.Approve(ctx, tc.request.ID, adminID, "")
+
+			// Assert
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			ids := make([]int, len(decision.Conflicts))
+			for i, a := range decision.Conflicts {
+				ids[i] = a.ID
+			}
+			assert.Equal(t, tc.conflicts, ids)
+		})
+	}
+}
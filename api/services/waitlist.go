@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+var (
+	ErrNoWaitlistOffer = errors.New("waitlist entry has no open offer")
+	ErrOfferExpired    = errors.New("waitlist offer has expired")
+)
+
+// waitlistOfferHold is how long a freed slot is held for a waitlist entry before it moves to the next one
+const waitlistOfferHold = 15 * time.Minute
+
+type waitlistService struct {
+	repos        *repository.Repositories
+	appointments *appointmentService
+}
+
+// NewWaitlistService creates the waitlist service. Freed slots are offered through the appointments service,
+// which must be created by NewAppointmentService, so offers are checked and booked like any other appointment.
+func NewWaitlistService(repos *repository.Repositories, appointments AppointmentService) WaitlistService {
+	return &waitlistService{
+		repos:        repos,
+		appointments: appointments.(*appointmentService),
+	}
+}
+
+func (s *waitlistService) Join(ctx context.Context, entry *entity.WaitlistEntry) error {
+	// Validate client existence
+	client, err := s.repos.User.Get(ctx, entry.ClientID)
+	if err != nil {
+		return fmt.Errorf("invalid client: %w", err)
+	}
+	if client.Role != entity.RoleClient {
+		return fmt.Errorf("user is not a client")
+	}
+
+	// Validate service existence and availability
+	service, err := s.repos.Service.Get(ctx, entry.ServiceID)
+	if err != nil {
+		return fmt.Errorf("invalid service: %w", err)
+	}
+	if !service.IsActive {
+		return fmt.Errorf("service is not active")
+	}
+	if service.BusinessID != entry.BusinessID {
+		return fmt.Errorf("service does not belong to business")
+	}
+	if isGroupService(service) {
+		return fmt.Errorf("waitlist is not available for group services")
+	}
+
+	// Without an employee any employee providing the service can take the slot
+	var locationID *int
+	if entry.EmployeeID != nil {
+		employee, err := s.repos.Employee.Get(ctx, *entry.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("invalid employee: %w", err)
+		}
+		if !employee.IsActive {
+			return fmt.Errorf("employee is not active")
+		}
+		if err := s.appointments.validateServiceAssignment(ctx, *entry.EmployeeID, entry.ServiceID); err != nil {
+			return err
+		}
+		locationID = employee.LocationID
+	}
+
+	// A window without an end covers the whole local day of its start, at the employee's branch when chosen
+	if entry.WindowEnd.IsZero() {
+		loc, err := scheduleLocation(ctx, s.repos, entry.BusinessID, locationID)
+		if err != nil {
+			return err
+		}
+		day := dateIn(entry.WindowStart.In(loc), loc)
+		entry.WindowStart = day.UTC()
+		entry.WindowEnd = day.AddDate(0, 0, 1).UTC()
+	}
+
+	if err := validateWaitlistWindow(entry.WindowStart, entry.WindowEnd, time.Now()); err != nil {
+		return err
+	}
+
+	entry.Status = entity.WaitlistStatusWaiting
+	if err := s.repos.Waitlist.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to join waitlist: %w", err)
+	}
+
+	return nil
+}
+
+func (s *waitlistService) Get(ctx context.Context, id int) (*entity.WaitlistEntry, error) {
+	entry, err := s.repos.Waitlist.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (s *waitlistService) ListByClient(ctx context.Context, clientID int) ([]entity.WaitlistEntry, error) {
+	entries, err := s.repos.Waitlist.ListByClient(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *waitlistService) Leave(ctx context.Context, id int) error {
+	entry, err := s.repos.Waitlist.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("invalid waitlist entry: %w", err)
+	}
+
+	switch entry.Status {
+	case entity.WaitlistStatusWaiting:
+		if err := s.repos.Waitlist.UpdateStatus(ctx, id, entity.WaitlistStatusWaiting, entity.WaitlistStatusCancelled); err != nil {
+			return fmt.Errorf("failed to leave waitlist: %w", err)
+		}
+		return nil
+	case entity.WaitlistStatusOffered:
+		// Leaving with an open offer gives the held slot to the next entry
+		return s.releaseOffer(ctx, entry, entity.WaitlistStatusCancelled)
+	default:
+		return fmt.Errorf("waitlist entry is already %s", entry.Status)
+	}
+}
+
+func (s *waitlistService) AcceptOffer(ctx context.Context, id int) (*entity.Appointment, error) {
+	entry, err := s.repos.Waitlist.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid waitlist entry: %w", err)
+	}
+	if entry.Status != entity.WaitlistStatusOffered {
+		return nil, ErrNoWaitlistOffer
+	}
+
+	if err := s.repos.Waitlist.Accept(ctx, entry); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrOfferExpired
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrSlotUnavailable
+		}
+		return nil, fmt.Errorf("failed to accept offer: %w", err)
+	}
+
+	appointment, err := s.repos.Appointment.Get(ctx, *entry.AppointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+
+	return appointment, nil
+}
+
+func (s *waitlistService) DeclineOffer(ctx context.Context, id int) error {
+	entry, err := s.repos.Waitlist.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("invalid waitlist entry: %w", err)
+	}
+	if entry.Status != entity.WaitlistStatusOffered {
+		return ErrNoWaitlistOffer
+	}
+
+	return s.releaseOffer(ctx, entry, entity.WaitlistStatusCancelled)
+}
+
+func (s *waitlistService) ExpireOffers(ctx context.Context) (int, error) {
+	entries, err := s.repos.Waitlist.ListExpiredOffers(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired offers: %w", err)
+	}
+
+	expired := 0
+	for i := range entries {
+		err := s.releaseOffer(ctx, &entries[i], entity.WaitlistStatusExpired)
+		// The offer was accepted or released in the meantime
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// releaseOffer drops the held appointment of an offered entry, moves the entry to the status
+// and offers the freed slot to the next matching entry
+func (s *waitlistService) releaseOffer(ctx context.Context, entry *entity.WaitlistEntry, status string) error {
+	if entry.AppointmentID == nil {
+		return fmt.Errorf("waitlist entry has no held appointment")
+	}
+	held, err := s.repos.Appointment.Get(ctx, *entry.AppointmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get held appointment: %w", err)
+	}
+
+	if err := s.repos.Waitlist.Release(ctx, entry, status); err != nil {
+		return fmt.Errorf("failed to release offer: %w", err)
+	}
+
+	if err := s.appointments.offerToWaitlist(ctx, held); err != nil {
+		return fmt.Errorf("failed to offer slot to waitlist: %w", err)
+	}
+
+	return nil
+}
+
+// offerToWaitlist gives the time of a freed appointment to the oldest matching waitlist entry.
+// Entries with auto-booking get a scheduled appointment, others a hold they have to accept before it expires.
+// Seats of class sessions and past appointments are not offered.
+func (s *appointmentService) offerToWaitlist(ctx context.Context, freed *entity.Appointment) error {
+	if freed.SessionID != nil || freed.StartTime.Before(time.Now()) {
+		return nil
+	}
+
+	entries, err := s.repos.Waitlist.ListMatching(ctx, freed.ServiceID, freed.EmployeeID, freed.StartTime, freed.EndTime)
+	if err != nil {
+		return fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	service, err := s.repos.Service.Get(ctx, freed.ServiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service details: %w", err)
+	}
+	if !service.IsActive {
+		return nil
+	}
+
+	loc, err := scheduleLocation(ctx, s.repos, freed.BusinessID, freed.LocationID)
+	if err != nil {
+		return err
+	}
+
+	slot := &entity.Appointment{
+		BusinessID: freed.BusinessID,
+		EmployeeID: freed.EmployeeID,
+		ServiceID:  freed.ServiceID,
+		LocationID: freed.LocationID,
+		StartTime:  freed.StartTime,
+		EndTime:    freed.StartTime.Add(time.Duration(service.Duration) * time.Minute),
+	}
+	// The slot is the same for every entry, so it is checked once.
+	// A slot that can no longer be booked is simply not offered.
+	if err := validateAppointmentPeriod(slot, service, time.Now()); err != nil {
+		return nil
+	}
+	err = s.checkEmployeeTime(ctx, slot.EmployeeID, service, slot.StartTime, slot.EndTime, 0, loc)
+	if err == nil {
+		err = s.assignResource(ctx, slot, service)
+	}
+	if errors.Is(err, errOutsideWorkingHours) || errors.Is(err, ErrSlotUnavailable) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		appointment := *slot
+		appointment.ClientID = entry.ClientID
+		if entry.AutoBook {
+			appointment.Status = entity.AppointmentStatusScheduled
+		} else {
+			expiresAt := time.Now().Add(waitlistOfferHold).UTC()
+			appointment.Status = entity.AppointmentStatusHeld
+			appointment.HoldExpiresAt = &expiresAt
+		}
+
+		err := s.repos.Waitlist.Offer(ctx, entry, &appointment)
+		// The entry was cancelled or offered another slot in the meantime
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		// The slot was booked in the meantime
+		if errors.Is(err, repository.ErrConflict) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to offer slot: %w", err)
+		}
+
+		// Auto-booked appointments are announced by their appointment.created event
+		if !entry.AutoBook {
+			s.notify(ctx, entity.EventAppointmentOffered, appointment.ID)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func validateWaitlistWindow(start, end, now time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("window end must be after window start")
+	}
+	if !end.After(now) {
+		return fmt.Errorf("window must be in the future")
+	}
+	if end.Sub(start) > maxRangeDays*24*time.Hour {
+		return fmt.Errorf("window cannot exceed %d days", maxRangeDays)
+	}
+
+	return nil
+}
+
+// withoutExpiredHolds drops held appointments whose hold has expired, they no longer block their time
+func withoutExpiredHolds(appointments []entity.Appointment, now time.Time) []entity.Appointment {
+	result := make([]entity.Appointment, 0, len(appointments))
+	for _, appointment := range appointments {
+		if appointment.Status == entity.AppointmentStatusHeld &&
+			appointment.HoldExpiresAt != nil && !appointment.HoldExpiresAt.After(now) {
+			continue
+		}
+		result = append(result, appointment)
+	}
+	return result
+}
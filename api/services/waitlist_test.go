@@ -0,0 +1,494 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+func TestWaitlistService_Join(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		userRepo     *mocks.UserRepository
+		serviceRepo  *mocks.BusinessServiceRepository
+		employeeRepo *mocks.EmployeeRepository
+		businessRepo *mocks.BusinessRepository
+		waitlistRepo *mocks.WaitlistRepository
+	}
+
+	type expected struct {
+		err         error
+		windowStart time.Time
+		windowEnd   time.Time
+	}
+
+	businessID := 1
+	clientID := 10
+	serviceID := 1
+	employeeID := 1
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.UTC)
+
+	client := &entity.User{ID: clientID, BusinessID: businessID, Role: entity.RoleClient}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, Capacity: 1, IsActive: true}
+	business := &entity.Business{ID: businessID, Timezone: "UTC"}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(m mocksForExecution)
+		entry    *entity.WaitlistEntry
+		expected expected
+	}{
+		{
+			name: "positive: whole day of the business for any employee",
+			mock: func(m mocksForExecution) {
+				m.userRepo.On("Get", ctx, clientID).Return(client, nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+				m.waitlistRepo.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			entry: &entity.WaitlistEntry{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, WindowStart: date.Add(15 * time.Hour)},
+			expected: expected{
+				windowStart: date,
+				windowEnd:   date.AddDate(0, 0, 1),
+			},
+		},
+		{
+			name: "positive: time window with an employee",
+			mock: func(m mocksForExecution) {
+				m.userRepo.On("Get", ctx, clientID).Return(client, nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+				m.employeeRepo.On("Get", ctx, employeeID).Return(&entity.Employee{ID: employeeID, IsActive: true}, nil)
+				m.employeeRepo.On("GetServices", ctx, employeeID).Return([]entity.BusinessService{*service}, nil)
+				m.waitlistRepo.On("Create", ctx, mock.Anything).Return(nil)
+			},
+			entry: &entity.WaitlistEntry{
+				BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, EmployeeID: &employeeID,
+				WindowStart: date.Add(9 * time.Hour), WindowEnd: date.Add(12 * time.Hour),
+			},
+			expected: expected{
+				windowStart: date.Add(9 * time.Hour),
+				windowEnd:   date.Add(12 * time.Hour),
+			},
+		},
+		{
+			name: "negative: user is not a client",
+			mock: func(m mocksForExecution) {
+				m.userRepo.On("Get", ctx, clientID).Return(&entity.User{ID: clientID, Role: entity.RoleEmployee}, nil)
+			},
+			entry: &entity.WaitlistEntry{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, WindowStart: date},
+			expected: expected{
+				err: fmt.Errorf("user is not a client"),
+			},
+		},
+		{
+			name: "negative: group service",
+			mock: func(m mocksForExecution) {
+				m.userRepo.On("Get", ctx, clientID).Return(client, nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(&entity.BusinessService{ID: serviceID, BusinessID: businessID, Capacity: 5, IsActive: true}, nil)
+			},
+			entry: &entity.WaitlistEntry{BusinessID: businessID, ClientID: clientID, ServiceID: serviceID, WindowStart: date},
+			expected: expected{
+				err: fmt.Errorf("waitlist is not available for group services"),
+			},
+		},
+		{
+			name: "negative: window in the past",
+			mock: func(m mocksForExecution) {
+				m.userRepo.On("Get", ctx, clientID).Return(client, nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+			},
+			entry: &entity.WaitlistEntry{
+				BusinessID: businessID, ClientID: clientID, ServiceID: serviceID,
+				WindowStart: now.Add(-3 * time.Hour), WindowEnd: now.Add(-time.Hour),
+			},
+			expected: expected{
+				err: fmt.Errorf("window must be in the future"),
+			},
+		},
+		{
+			name: "negative: window end before start",
+			mock: func(m mocksForExecution) {
+				m.userRepo.On("Get", ctx, clientID).Return(client, nil)
+				m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+			},
+			entry: &entity.WaitlistEntry{
+				BusinessID: businessID, ClientID: clientID, ServiceID: serviceID,
+				WindowStart: date.Add(12 * time.Hour), WindowEnd: date.Add(9 * time.Hour),
+			},
+			expected: expected{
+				err: fmt.Errorf("window end must be after window start"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			m := mocksForExecution{
+				userRepo:     mocks.NewUserRepository(t),
+				serviceRepo:  mocks.NewBusinessServiceRepository(t),
+				employeeRepo: mocks.NewEmployeeRepository(t),
+				businessRepo: mocks.NewBusinessRepository(t),
+				waitlistRepo: mocks.NewWaitlistRepository(t),
+			}
+
+			// Setup mocks
+			tc.mock(m)
+
+			// Init service
+			repos := &repository.Repositories{
+				User:     m.userRepo,
+				Service:  m.serviceRepo,
+				Employee: m.employeeRepo,
+				Business: m.businessRepo,
+				Waitlist: m.waitlistRepo,
+			}
+			waitlistService := services.NewWaitlistService(repos, services.NewAppointmentService(repos, nil, nil, nil))
+
+			// Execute
+			err := waitlistService.Join(ctx, tc.entry)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, entity.WaitlistStatusWaiting, tc.entry.Status)
+				assert.True(t, tc.expected.windowStart.Equal(tc.entry.WindowStart))
+				assert.True(t, tc.expected.windowEnd.Equal(tc.entry.WindowEnd))
+			}
+		})
+	}
+}
+
+func TestAppointmentService_CancelOffersToWaitlist(t *testing.T) {
+	t.Parallel()
+
+	type mocksForExecution struct {
+		appointmentRepo *mocks.AppointmentRepository
+		serviceRepo     *mocks.BusinessServiceRepository
+		businessRepo    *mocks.BusinessRepository
+		scheduleRepo    *mocks.ScheduleRepository
+		waitlistRepo    *mocks.WaitlistRepository
+	}
+
+	businessID := 1
+	appointmentID := 1
+	serviceID := 1
+	employeeID := 1
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	cancelled := &entity.Appointment{
+		ID:         appointmentID,
+		BusinessID: businessID,
+		ClientID:   10,
+		EmployeeID: employeeID,
+		ServiceID:  serviceID,
+		StartTime:  start,
+		EndTime:    end,
+		Status:     entity.AppointmentStatusScheduled,
+	}
+	service := &entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, Capacity: 1, IsActive: true}
+	business := &entity.Business{ID: businessID, Timezone: "UTC"}
+	schedule := []entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+	first := entity.WaitlistEntry{ID: 1, BusinessID: businessID, ClientID: 20, ServiceID: serviceID, Status: entity.WaitlistStatusWaiting}
+	second := entity.WaitlistEntry{ID: 2, BusinessID: businessID, ClientID: 30, ServiceID: serviceID, Status: entity.WaitlistStatusWaiting, AutoBook: true}
+
+	ctx := context.Background()
+
+	// mockFreedSlot sets up the cancellation and the check of the freed slot
+	mockFreedSlot := func(m mocksForExecution, entries []entity.WaitlistEntry) {
+		m.appointmentRepo.On("Get", ctx, appointmentID).Return(cancelled, nil)
+		m.appointmentRepo.On("Cancel", ctx, appointmentID, "").Return(nil)
+		m.waitlistRepo.On("ListMatching", ctx, serviceID, employeeID, start, end).Return(entries, nil)
+		m.serviceRepo.On("Get", ctx, serviceID).Return(service, nil)
+		m.businessRepo.On("Get", ctx, businessID).Return(business, nil)
+		m.scheduleRepo.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return(schedule, nil)
+		m.scheduleRepo.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+		m.scheduleRepo.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		m.appointmentRepo.On("IsEmployeeAvailable", ctx, employeeID, start, end, 0).Return(true, nil)
+	}
+
+	isOffer := func(clientID int, status string) interface{} {
+		return mock.MatchedBy(func(a *entity.Appointment) bool {
+			held := status == entity.AppointmentStatusHeld
+			return a.ClientID == clientID && a.Status == status && a.StartTime.Equal(start) &&
+				a.EmployeeID == employeeID && (a.HoldExpiresAt != nil) == held
+		})
+	}
+
+	testCases := []struct {
+		name string
+		mock func(m mocksForExecution)
+	}{
+		{
+			name: "positive: slot held for the first entry",
+			mock: func(m mocksForExecution) {
+				mockFreedSlot(m, []entity.WaitlistEntry{first, second})
+				m.waitlistRepo.On("Offer", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == first.ID }),
+					isOffer(first.ClientID, entity.AppointmentStatusHeld)).Return(nil)
+			},
+		},
+		{
+			name: "positive: entry left in the meantime, next entry auto-booked",
+			mock: func(m mocksForExecution) {
+				mockFreedSlot(m, []entity.WaitlistEntry{first, second})
+				m.waitlistRepo.On("Offer", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == first.ID }),
+					mock.Anything).Return(repository.ErrNotFound)
+				m.waitlistRepo.On("Offer", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == second.ID }),
+					isOffer(second.ClientID, entity.AppointmentStatusScheduled)).Return(nil)
+			},
+		},
+		{
+			name: "positive: slot booked in the meantime is not offered further",
+			mock: func(m mocksForExecution) {
+				mockFreedSlot(m, []entity.WaitlistEntry{first, second})
+				m.waitlistRepo.On("Offer", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == first.ID }),
+					mock.Anything).Return(repository.ErrConflict)
+			},
+		},
+		{
+			name: "positive: offering fails without failing the cancellation",
+			mock: func(m mocksForExecution) {
+				m.appointmentRepo.On("Get", ctx, appointmentID).Return(cancelled, nil)
+				m.appointmentRepo.On("Cancel", ctx, appointmentID, "").Return(nil)
+				m.waitlistRepo.On("ListMatching", ctx, serviceID, employeeID, start, end).Return(nil, fmt.Errorf("connection lost"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			m := mocksForExecution{
+				appointmentRepo: mocks.NewAppointmentRepository(t),
+				serviceRepo:     mocks.NewBusinessServiceRepository(t),
+				businessRepo:    mocks.NewBusinessRepository(t),
+				scheduleRepo:    mocks.NewScheduleRepository(t),
+				waitlistRepo:    mocks.NewWaitlistRepository(t),
+			}
+
+			// Setup mocks
+			tc.mock(m)
+
+			// Init service
+			appointmentService := services.NewAppointmentService(&repository.Repositories{
+				Appointment: m.appointmentRepo,
+				Service:     m.serviceRepo,
+				Business:    m.businessRepo,
+				Schedule:    m.scheduleRepo,
+				Waitlist:    m.waitlistRepo,
+			}, nil, nil, nil)
+
+			// Execute
+			err := appointmentService.Cancel(ctx, appointmentID, "")
+
+			// Assert
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWaitlistService_AcceptOffer(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err         error
+		appointment *entity.Appointment
+	}
+
+	entryID := 1
+	appointmentID := 5
+	held := &entity.Appointment{ID: appointmentID, Status: entity.AppointmentStatusScheduled}
+
+	offered := func() *entity.WaitlistEntry {
+		return &entity.WaitlistEntry{ID: entryID, Status: entity.WaitlistStatusOffered, AppointmentID: &appointmentID}
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		mock     func(waitlistRepo *mocks.WaitlistRepository, appointmentRepo *mocks.AppointmentRepository)
+		expected expected
+	}{
+		{
+			name: "positive: held appointment confirmed",
+			mock: func(waitlistRepo *mocks.WaitlistRepository, appointmentRepo *mocks.AppointmentRepository) {
+				waitlistRepo.On("Get", ctx, entryID).Return(offered(), nil)
+				waitlistRepo.On("Accept", ctx, mock.Anything).Return(nil)
+				appointmentRepo.On("Get", ctx, appointmentID).Return(held, nil)
+			},
+			expected: expected{
+				appointment: held,
+			},
+		},
+		{
+			name: "negative: hold expired",
+			mock: func(waitlistRepo *mocks.WaitlistRepository, appointmentRepo *mocks.AppointmentRepository) {
+				waitlistRepo.On("Get", ctx, entryID).Return(offered(), nil)
+				waitlistRepo.On("Accept", ctx, mock.Anything).Return(repository.ErrNotFound)
+			},
+			expected: expected{
+				err: services.ErrOfferExpired,
+			},
+		},
+		{
+			name: "negative: entry without an offer",
+			mock: func(waitlistRepo *mocks.WaitlistRepository, appointmentRepo *mocks.AppointmentRepository) {
+				waitlistRepo.On("Get", ctx, entryID).Return(&entity.WaitlistEntry{ID: entryID, Status: entity.WaitlistStatusWaiting}, nil)
+			},
+			expected: expected{
+				err: services.ErrNoWaitlistOffer,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			waitlistRepoMock := mocks.NewWaitlistRepository(t)
+			appointmentRepoMock := mocks.NewAppointmentRepository(t)
+
+			// Setup mocks
+			tc.mock(waitlistRepoMock, appointmentRepoMock)
+
+			// Init service
+			repos := &repository.Repositories{
+				Waitlist:    waitlistRepoMock,
+				Appointment: appointmentRepoMock,
+			}
+			waitlistService := services.NewWaitlistService(repos, services.NewAppointmentService(repos, nil, nil, nil))
+
+			// Execute
+			appointment, err := waitlistService.AcceptOffer(ctx, entryID)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.ErrorIs(t, err, tc.expected.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected.appointment, appointment)
+			}
+		})
+	}
+}
+
+func TestWaitlistService_ExpireOffers(t *testing.T) {
+	t.Parallel()
+
+	businessID := 1
+	serviceID := 1
+	employeeID := 1
+	heldID := 5
+	offeredID := 9
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day()+2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	held := &entity.Appointment{
+		ID:         heldID,
+		BusinessID: businessID,
+		ClientID:   20,
+		EmployeeID: employeeID,
+		ServiceID:  serviceID,
+		StartTime:  start,
+		EndTime:    end,
+		Status:     entity.AppointmentStatusHeld,
+	}
+	expiredEntry := entity.WaitlistEntry{ID: 1, ClientID: 20, ServiceID: serviceID, Status: entity.WaitlistStatusOffered, AppointmentID: &heldID}
+	acceptedEntry := entity.WaitlistEntry{ID: 3, ClientID: 40, ServiceID: serviceID, Status: entity.WaitlistStatusOffered, AppointmentID: &heldID}
+	next := entity.WaitlistEntry{ID: 2, ClientID: 30, ServiceID: serviceID, Status: entity.WaitlistStatusWaiting}
+
+	ctx := context.Background()
+
+	// Init mocks
+	waitlistRepoMock := mocks.NewWaitlistRepository(t)
+	appointmentRepoMock := mocks.NewAppointmentRepository(t)
+	serviceRepoMock := mocks.NewBusinessServiceRepository(t)
+	businessRepoMock := mocks.NewBusinessRepository(t)
+	scheduleRepoMock := mocks.NewScheduleRepository(t)
+
+	// Setup mocks. The second offer was accepted before it could be released.
+	waitlistRepoMock.On("ListExpiredOffers", ctx, mock.Anything).Return([]entity.WaitlistEntry{expiredEntry, acceptedEntry}, nil)
+	appointmentRepoMock.On("Get", ctx, heldID).Return(held, nil)
+	waitlistRepoMock.On("Release", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == expiredEntry.ID }),
+		entity.WaitlistStatusExpired).Return(nil)
+	waitlistRepoMock.On("Release", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == acceptedEntry.ID }),
+		entity.WaitlistStatusExpired).Return(repository.ErrNotFound)
+	waitlistRepoMock.On("ListMatching", ctx, serviceID, employeeID, start, end).Return([]entity.WaitlistEntry{next}, nil)
+	serviceRepoMock.On("Get", ctx, serviceID).Return(&entity.BusinessService{ID: serviceID, BusinessID: businessID, Duration: 30, Capacity: 1, IsActive: true}, nil)
+	businessRepoMock.On("Get", ctx, businessID).Return(&entity.Business{ID: businessID, Timezone: "UTC"}, nil)
+	scheduleRepoMock.On("GetEmployeeSchedule", ctx, employeeID, mock.Anything).Return([]entity.ScheduleTemplate{
+		{
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}, nil)
+	scheduleRepoMock.On("ListOverrides", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListBusyTime", ctx, employeeID, mock.Anything, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListBusinessHours", ctx, mock.Anything).Return(nil, nil)
+	scheduleRepoMock.On("ListClosures", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	appointmentRepoMock.On("IsEmployeeAvailable", ctx, employeeID, start, end, 0).Return(true, nil)
+	waitlistRepoMock.On("Offer", ctx, mock.MatchedBy(func(e *entity.WaitlistEntry) bool { return e.ID == next.ID }),
+		mock.MatchedBy(func(a *entity.Appointment) bool {
+			return a.ClientID == next.ClientID && a.Status == entity.AppointmentStatusHeld && a.HoldExpiresAt != nil
+		})).Run(func(args mock.Arguments) {
+		args.Get(2).(*entity.Appointment).ID = offeredID
+	}).Return(nil)
+	appointmentRepoMock.On("Get", ctx, offeredID).Return(&entity.Appointment{ID: offeredID, BusinessID: businessID, ClientID: next.ClientID}, nil)
+
+	// Init service
+	repos := &repository.Repositories{
+		Waitlist:    waitlistRepoMock,
+		Appointment: appointmentRepoMock,
+		Service:     serviceRepoMock,
+		Business:    businessRepoMock,
+		Schedule:    scheduleRepoMock,
+	}
+	notifier := &inMemoryNotifier{}
+	waitlistService := services.NewWaitlistService(repos, services.NewAppointmentService(repos, nil, notifier, nil))
+
+	// Execute
+	expired, err := waitlistService.ExpireOffers(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	// The offer is announced by the notifier of the appointment service
+	assert.Equal(t, []string{entity.EventAppointmentOffered}, notifier.events)
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/netguard"
+	"github.com/vadimpk/ppc-project/pkg/webhook"
+	"github.com/vadimpk/ppc-project/repository"
+)
+
+const (
+	// webhookBatchSize is the number of deliveries claimed in a single pass
+	webhookBatchSize = 50
+	// webhookClaimLease is how long a claimed delivery is reserved for the dispatcher that claimed it,
+	// well above the timeout of a single request
+	webhookClaimLease = 2 * time.Minute
+	// webhookMaxAttempts is the number of attempts after which a delivery fails for good
+	webhookMaxAttempts = 8
+	// webhookRetryBase is the delay before the first retry, doubled on every following one up to webhookRetryMax.
+	// Eight attempts span about four hours.
+	webhookRetryBase = time.Minute
+	webhookRetryMax  = 2 * time.Hour
+	// webhookDeliveryLogSize is the number of latest deliveries listed per webhook
+	webhookDeliveryLogSize = 100
+)
+
+// ErrInvalidWebhook is returned when a webhook has an invalid URL or event types
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+type webhookService struct {
+	repos  *repository.Repositories
+	sender *webhook.Sender
+	clock  Clock
+}
+
+// NewWebhookService creates the service. The clock defaults to the system clock when nil.
+func NewWebhookService(repos *repository.Repositories, sender *webhook.Sender, clock Clock) WebhookService {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &webhookService{
+		repos:  repos,
+		sender: sender,
+		clock:  clock,
+	}
+}
+
+func (s *webhookService) Create(ctx context.Context, hook *entity.Webhook) error {
+	// Validate business existence
+	if _, err := s.repos.Business.Get(ctx, hook.BusinessID); err != nil {
+		return fmt.Errorf("invalid business: %w", err)
+	}
+
+	if err := validateWebhook(hook); err != nil {
+		return err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return err
+	}
+	hook.Secret = secret
+	hook.IsActive = true
+
+	if err := s.repos.Webhook.Create(ctx, hook); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (s *webhookService) Get(ctx context.Context, id int) (*entity.Webhook, error) {
+	hook, err := s.repos.Webhook.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+func (s *webhookService) List(ctx context.Context, businessID int) ([]entity.Webhook, error) {
+	hooks, err := s.repos.Webhook.ListByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, hook *entity.Webhook) error {
+	if _, err := s.repos.Webhook.Get(ctx, hook.ID); err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	if err := validateWebhook(hook); err != nil {
+		return err
+	}
+
+	if err := s.repos.Webhook.Update(ctx, hook); err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id int) error {
+	if err := s.repos.Webhook.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID int) ([]entity.WebhookDelivery, error) {
+	deliveries, err := s.repos.Webhook.ListDeliveries(ctx, webhookID, webhookDeliveryLogSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *webhookService) Test(ctx context.Context, webhookID int) (*entity.WebhookDelivery, error) {
+	hook, err := s.repos.Webhook.Get(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	now := s.clock.Now()
+	payload, err := json.Marshal(webhookPayload{
+		Type:       entity.WebhookEventTest,
+		BusinessID: hook.BusinessID,
+		CreatedAt:  now,
+		Data:       json.RawMessage(fmt.Sprintf(`{"webhook_id":%d}`, hook.ID)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode test payload: %w", err)
+	}
+
+	delivery := &entity.WebhookDelivery{
+		WebhookID: hook.ID,
+		EventType: entity.WebhookEventTest,
+		Payload:   payload,
+	}
+	if err := s.repos.Webhook.CreateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create test delivery: %w", err)
+	}
+
+	// Test deliveries are sent once and not retried
+	if err := attemptWebhookDelivery(ctx, s.repos, s.sender, s.clock, hook, delivery, 1); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (s *webhookService) Replay(ctx context.Context, webhookID, deliveryID int) (*entity.WebhookDelivery, error) {
+	delivery, err := s.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", repository.ErrNotFound)
+	}
+
+	hook, err := s.repos.Webhook.Get(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	// A replay is one more attempt of the same body. A failing replay of a delivery
+	// that has retries left is retried as usual.
+	if err := attemptWebhookDelivery(ctx, s.repos, s.sender, s.clock, hook, delivery, webhookMaxAttempts); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func validateWebhook(hook *entity.Webhook) error {
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	// Hosts are checked again when the sender connects, after they are resolved
+	if !isPublicHost(u.Hostname()) {
+		return fmt.Errorf("%w: url must point to a public address", ErrInvalidWebhook)
+	}
+
+	if len(hook.EventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	for _, eventType := range hook.EventTypes {
+		if !slices.Contains(entity.WebhookEventTypes, eventType) {
+			return fmt.Errorf("%w: unsupported event type %q", ErrInvalidWebhook, eventType)
+		}
+	}
+
+	slices.Sort(hook.EventTypes)
+	hook.EventTypes = slices.Compact(hook.EventTypes)
+	return nil
+}
+
+// isPublicHost rejects local host names and addresses netguard.PublicAddresses does not allow
+func isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	addr, err := netip.ParseAddr(host)
+	return err != nil || netguard.PublicAddresses(addr)
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// webhookPayload is the body of webhook deliveries
+type webhookPayload struct {
+	EventID    int             `json:"event_id,omitempty"` // omitted for test deliveries
+	Type       string          `json:"type"`
+	BusinessID int             `json:"business_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"` // the aggregate after the change
+}
+
+// attemptWebhookDelivery sends the delivery and stores the outcome. A failed delivery is retried
+// with exponential backoff until it has been attempted maxAttempts times.
+// Only failing to store the outcome is returned as an error.
+func attemptWebhookDelivery(ctx context.Context, repos *repository.Repositories, sender *webhook.Sender, clock Clock, hook *entity.Webhook, delivery *entity.WebhookDelivery, maxAttempts int) error {
+	now := clock.Now()
+	status, err := sender.Send(ctx, webhook.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		DeliveryID: delivery.ID,
+		EventType:  delivery.EventType,
+		Body:       delivery.Payload,
+	}, now)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	recordWebhookOutcome(delivery, err, now, maxAttempts)
+
+	if err := repos.Webhook.UpdateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// recordWebhookOutcome moves an attempted delivery to its next status
+func recordWebhookOutcome(delivery *entity.WebhookDelivery, err error, now time.Time, maxAttempts int) {
+	if err == nil {
+		delivery.Status = entity.WebhookDeliveryStatusSucceeded
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	lastError := err.Error()
+	delivery.LastError = &lastError
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = entity.WebhookDeliveryStatusFailed
+		return
+	}
+	delivery.Status = entity.WebhookDeliveryStatusPending
+	delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts, webhookRetryBase, webhookRetryMax))
+}
+
+// WebhookConsumer queues a delivery of every event to the active webhooks subscribed to it.
+// The deliveries are sent by the WebhookDispatcher.
+type WebhookConsumer struct {
+	repos *repository.Repositories
+}
+
+func NewWebhookConsumer(repos *repository.Repositories) *WebhookConsumer {
+	return &WebhookConsumer{
+		repos: repos,
+	}
+}
+
+func (c *WebhookConsumer) Name() string {
+	return "webhooks"
+}
+
+func (c *WebhookConsumer) Consume(ctx context.Context, event *entity.DomainEvent) error {
+	if !slices.Contains(entity.WebhookEventTypes, event.EventType) {
+		return nil
+	}
+
+	hooks, err := c.repos.Webhook.ListSubscribed(ctx, event.BusinessID, event.EventType)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		EventID:    event.ID,
+		Type:       event.EventType,
+		BusinessID: event.BusinessID,
+		CreatedAt:  event.CreatedAt,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		eventID := event.ID
+		err := c.repos.Webhook.CreateDelivery(ctx, &entity.WebhookDelivery{
+			WebhookID: hook.ID,
+			EventID:   &eventID,
+			EventType: event.EventType,
+			Payload:   payload,
+		})
+		// The event is relayed again when another consumer failed, its deliveries are kept
+		if err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+			errs = append(errs, fmt.Errorf("webhook %d: %w", hook.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WebhookDispatcher sends the pending webhook deliveries that are due. Deliveries are claimed
+// before sending, so several dispatchers do not send a delivery at the same time.
+type WebhookDispatcher struct {
+	repos    *repository.Repositories
+	sender   *webhook.Sender
+	clock    Clock
+	interval time.Duration
+}
+
+// NewWebhookDispatcher creates the dispatcher. The clock defaults to the system clock when nil.
+func NewWebhookDispatcher(repos *repository.Repositories, sender *webhook.Sender, clock Clock, interval time.Duration) *WebhookDispatcher {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &WebhookDispatcher{
+		repos:    repos,
+		sender:   sender,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// Run dispatches due deliveries every interval until the context is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Dispatch(ctx); err != nil {
+				log.Printf("Failed to dispatch webhook deliveries: %v", err)
+			}
+		}
+	}
+}
+
+// Dispatch sends the deliveries that are due now and returns the number of successful ones
+func (d *WebhookDispatcher) Dispatch(ctx context.Context) (int, error) {
+	deliveries, err := d.repos.Webhook.ClaimDue(ctx, d.clock.Now(), webhookClaimLease, webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	hooks := make(map[int]*entity.Webhook)
+	succeeded := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		hook, ok := hooks[delivery.WebhookID]
+		if !ok {
+			hook, err = d.repos.Webhook.Get(ctx, delivery.WebhookID)
+			if err != nil {
+				// The claim expires and the delivery is tried again
+				log.Printf("Failed to get webhook %d of delivery %d: %v", delivery.WebhookID, delivery.ID, err)
+				continue
+			}
+			hooks[hook.ID] = hook
+		}
+
+		// Deliveries of disabled webhooks are not sent, they can still be replayed
+		if !hook.IsActive {
+			recordWebhookOutcome(delivery, fmt.Errorf("webhook is disabled"), d.clock.Now(), 0)
+			if err := d.repos.Webhook.UpdateDelivery(ctx, delivery); err != nil {
+				log.Printf("Failed to store webhook delivery %d: %v", delivery.ID, err)
+			}
+			continue
+		}
+
+		if err := attemptWebhookDelivery(ctx, d.repos, d.sender, d.clock, hook, delivery, webhookMaxAttempts); err != nil {
+			log.Printf("Failed to deliver webhook delivery %d: %v", delivery.ID, err)
+			continue
+		}
+		if delivery.Status == entity.WebhookDeliveryStatusSucceeded {
+			succeeded++
+		} else {
+			log.Printf("Webhook delivery %d (%s) to webhook %d failed on attempt %d: %s",
+				delivery.ID, delivery.EventType, hook.ID, delivery.Attempts, *delivery.LastError)
+		}
+	}
+
+	return succeeded, nil
+}
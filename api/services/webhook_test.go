@@ -0,0 +1,405 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimpk/ppc-project/entity"
+	"github.com/vadimpk/ppc-project/pkg/webhook"
+	"github.com/vadimpk/ppc-project/repository"
+	"github.com/vadimpk/ppc-project/repository/mocks"
+	"github.com/vadimpk/ppc-project/services"
+)
+
+// webhookEndpoint is a local endpoint verifying the signature of every delivery it receives.
+// Requests to /fail are answered with 500.
+type webhookEndpoint struct {
+	*httptest.Server
+	mu       sync.Mutex
+	received []string // bodies of the deliveries with a valid signature
+}
+
+func newWebhookEndpoint(t *testing.T, secret string) *webhookEndpoint {
+	e := &webhookEndpoint{}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header, body, time.Now(), 0); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/fail" {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+			return
+		}
+
+		e.mu.Lock()
+		e.received = append(e.received, string(body))
+		e.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+func TestWebhookDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		err       error
+		succeeded int
+		received  []string
+		updated   []entity.WebhookDelivery
+	}
+
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	lease := 2 * time.Minute
+	ctx := context.Background()
+
+	endpoint := newWebhookEndpoint(t, "secret")
+	hooks := map[int]*entity.Webhook{
+		1: {ID: 1, URL: endpoint.URL + "/hook", Secret: "secret", IsActive: true},
+		2: {ID: 2, URL: endpoint.URL + "/fail", Secret: "secret", IsActive: true},
+		3: {ID: 3, URL: endpoint.URL + "/hook", Secret: "other", IsActive: true},
+		4: {ID: 4, URL: endpoint.URL + "/hook", Secret: "secret", IsActive: false},
+	}
+	deliveryWith := func(id, webhookID, attempts int) entity.WebhookDelivery {
+		return entity.WebhookDelivery{
+			ID:        id,
+			WebhookID: webhookID,
+			EventType: entity.EventAppointmentCreated,
+			Payload:   []byte(fmt.Sprintf(`{"delivery":%d}`, id)),
+			Status:    entity.WebhookDeliveryStatusPending,
+			Attempts:  attempts,
+		}
+	}
+	status := func(code int) *int {
+		return &code
+	}
+	message := func(s string) *string {
+		return &s
+	}
+
+	testCases := []struct {
+		name       string
+		deliveries []entity.WebhookDelivery
+		claimErr   error
+		expected   expected
+	}{
+		{
+			name:       "positive: signed delivery succeeds",
+			deliveries: []entity.WebhookDelivery{deliveryWith(1, 1, 0)},
+			expected: expected{
+				succeeded: 1,
+				received:  []string{`{"delivery":1}`},
+				updated: []entity.WebhookDelivery{{
+					ID: 1, WebhookID: 1, EventType: entity.EventAppointmentCreated, Payload: []byte(`{"delivery":1}`),
+					Status: entity.WebhookDeliveryStatusSucceeded, Attempts: 1, ResponseStatus: status(200), DeliveredAt: &now,
+				}},
+			},
+		},
+		{
+			name:       "positive: failed delivery retried with exponential backoff",
+			deliveries: []entity.WebhookDelivery{deliveryWith(1, 2, 2)},
+			expected: expected{
+				updated: []entity.WebhookDelivery{{
+					ID: 1, WebhookID: 2, EventType: entity.EventAppointmentCreated, Payload: []byte(`{"delivery":1}`),
+					Status: entity.WebhookDeliveryStatusPending, Attempts: 3, ResponseStatus: status(500),
+					LastError: message("endpoint responded with 500: unavailable"), NextAttemptAt: now.Add(4 * time.Minute),
+				}},
+			},
+		},
+		{
+			name:       "positive: delivery rejected by the signature check fails after the last attempt",
+			deliveries: []entity.WebhookDelivery{deliveryWith(1, 3, 7)},
+			expected: expected{
+				updated: []entity.WebhookDelivery{{
+					ID: 1, WebhookID: 3, EventType: entity.EventAppointmentCreated, Payload: []byte(`{"delivery":1}`),
+					Status: entity.WebhookDeliveryStatusFailed, Attempts: 8, ResponseStatus: status(401),
+					LastError: message("endpoint responded with 401: signature mismatch"),
+				}},
+			},
+		},
+		{
+			name:       "positive: delivery of a disabled webhook not sent",
+			deliveries: []entity.WebhookDelivery{deliveryWith(1, 4, 0)},
+			expected: expected{
+				updated: []entity.WebhookDelivery{{
+					ID: 1, WebhookID: 4, EventType: entity.EventAppointmentCreated, Payload: []byte(`{"delivery":1}`),
+					Status: entity.WebhookDeliveryStatusFailed, LastError: message("webhook is disabled"),
+				}},
+			},
+		},
+		{
+			name:     "negative: claim fails",
+			claimErr: fmt.Errorf("db error"),
+			expected: expected{
+				err: fmt.Errorf("failed to claim webhook deliveries: %w", fmt.Errorf("db error")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// Init mocks
+			webhookRepoMock := mocks.NewWebhookRepository(t)
+
+			// Setup mocks
+			webhookRepoMock.On("ClaimDue", ctx, now, lease, 50).Return(tc.deliveries, tc.claimErr)
+			var updated []entity.WebhookDelivery
+			for _, d := range tc.deliveries {
+				webhookRepoMock.On("Get", ctx, d.WebhookID).Return(hooks[d.WebhookID], nil).Once()
+				webhookRepoMock.On("UpdateDelivery", ctx, mock.AnythingOfType("*entity.WebhookDelivery")).
+					Run(func(args mock.Arguments) {
+						updated = append(updated, *args.Get(1).(*entity.WebhookDelivery))
+					}).Return(nil).Once()
+			}
+
+			endpoint.mu.Lock()
+			endpoint.received = nil
+			endpoint.mu.Unlock()
+
+			// Init dispatcher
+			dispatcher := services.NewWebhookDispatcher(&repository.Repositories{
+				Webhook: webhookRepoMock,
+			}, webhook.NewSender(endpoint.Client()), &fakeClock{now: now}, time.Second)
+
+			// Execute
+			succeeded, err := dispatcher.Dispatch(ctx)
+
+			// Assert
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected.succeeded, succeeded)
+				assert.Equal(t, tc.expected.received, endpoint.received)
+				assert.Equal(t, tc.expected.updated, updated)
+			}
+		})
+	}
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		webhook  *entity.Webhook
+		mock     func(b *mocks.BusinessRepository, w *mocks.WebhookRepository)
+		expected *entity.Webhook
+		err      error
+	}{
+		{
+			name: "positive: secret generated and event types deduplicated",
+			webhook: &entity.Webhook{
+				BusinessID: 1,
+				URL:        "https://crm.example.com/hooks",
+				EventTypes: []string{entity.EventAppointmentCreated, entity.EventEmployeeUpdated, entity.EventAppointmentCreated},
+			},
+			mock: func(b *mocks.BusinessRepository, w *mocks.WebhookRepository) {
+				b.On("Get", ctx, 1).Return(&entity.Business{ID: 1}, nil)
+				w.On("Create", ctx, mock.AnythingOfType("*entity.Webhook")).Return(nil)
+			},
+			expected: &entity.Webhook{
+				BusinessID: 1,
+				URL:        "https://crm.example.com/hooks",
+				EventTypes: []string{entity.EventAppointmentCreated, entity.EventEmployeeUpdated},
+				IsActive:   true,
+			},
+		},
+		{
+			name:    "negative: relative url",
+			webhook: &entity.Webhook{BusinessID: 1, URL: "/hooks", EventTypes: []string{entity.EventAppointmentCreated}},
+			mock: func(b *mocks.BusinessRepository, w *mocks.WebhookRepository) {
+				b.On("Get", ctx, 1).Return(&entity.Business{ID: 1}, nil)
+			},
+			err: fmt.Errorf("%w: url must be an absolute http or https URL", services.ErrInvalidWebhook),
+		},
+		{
+			name:    "negative: loopback url",
+			webhook: &entity.Webhook{BusinessID: 1, URL: "http://127.0.0.1:8080/hooks", EventTypes: []string{entity.EventAppointmentCreated}},
+			mock: func(b *mocks.BusinessRepository, w *mocks.WebhookRepository) {
+				b.On("Get", ctx, 1).Return(&entity.Business{ID: 1}, nil)
+			},
+			err: fmt.Errorf("%w: url must point to a public address", services.ErrInvalidWebhook),
+		},
+		{
+			name:    "negative: unsupported event type",
+			webhook: &entity.Webhook{BusinessID: 1, URL: "https://crm.example.com", EventTypes: []string{"appointment.deleted"}},
+			mock: func(b *mocks.BusinessRepository, w *mocks.WebhookRepository) {
+				b.On("Get", ctx, 1).Return(&entity.Business{ID: 1}, nil)
+			},
+			err: fmt.Errorf("%w: unsupported event type %q", services.ErrInvalidWebhook, "appointment.deleted"),
+		},
+		{
+			name:    "negative: no event types",
+			webhook: &entity.Webhook{BusinessID: 1, URL: "https://crm.example.com"},
+			mock: func(b *mocks.BusinessRepository, w *mocks.WebhookRepository) {
+				b.On("Get", ctx, 1).Return(&entity.Business{ID: 1}, nil)
+			},
+			err: fmt.Errorf("%w: at least one event type is required", services.ErrInvalidWebhook),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			businessRepoMock := mocks.NewBusinessRepository(t)
+			webhookRepoMock := mocks.NewWebhookRepository(t)
+
+			// Setup mocks
+			tc.mock(businessRepoMock, webhookRepoMock)
+
+			// Init service
+			service := services.NewWebhookService(&repository.Repositories{
+				Business: businessRepoMock,
+				Webhook:  webhookRepoMock,
+			}, webhook.NewSender(nil), nil)
+
+			// Execute
+			err := service.Create(ctx, tc.webhook)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+				assert.True(t, errors.Is(err, services.ErrInvalidWebhook))
+			} else {
+				require.NoError(t, err)
+				assert.Regexp(t, `^whsec_[0-9a-f]{48}$`, tc.webhook.Secret)
+				tc.expected.Secret = tc.webhook.Secret
+				assert.Equal(t, tc.expected, tc.webhook)
+			}
+		})
+	}
+}
+
+func TestWebhookService_Test(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Truncate(time.Second).UTC()
+	ctx := context.Background()
+	endpoint := newWebhookEndpoint(t, "secret")
+
+	// Init mocks
+	webhookRepoMock := mocks.NewWebhookRepository(t)
+
+	// Setup mocks
+	hook := &entity.Webhook{ID: 3, BusinessID: 1, URL: endpoint.URL, Secret: "secret", IsActive: true}
+	webhookRepoMock.On("Get", ctx, 3).Return(hook, nil)
+	webhookRepoMock.On("CreateDelivery", ctx, mock.AnythingOfType("*entity.WebhookDelivery")).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*entity.WebhookDelivery).ID = 10
+		}).Return(nil)
+	webhookRepoMock.On("UpdateDelivery", ctx, mock.AnythingOfType("*entity.WebhookDelivery")).Return(nil)
+
+	// Init service
+	service := services.NewWebhookService(&repository.Repositories{
+		Webhook: webhookRepoMock,
+	}, webhook.NewSender(endpoint.Client()), &fakeClock{now: now})
+
+	// Execute
+	delivery, err := service.Test(ctx, 3)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entity.WebhookDeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	expectedBody := fmt.Sprintf(`{"type":"webhook.test","business_id":1,"created_at":%q,"data":{"webhook_id":3}}`, now.Format(time.RFC3339))
+	assert.Equal(t, []string{expectedBody}, endpoint.received)
+}
+
+func TestWebhookConsumer_Consume(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	event := &entity.DomainEvent{
+		ID:         5,
+		BusinessID: 1,
+		EventType:  entity.EventAppointmentCancelled,
+		Payload:    []byte(`{"id":7}`),
+		CreatedAt:  createdAt,
+	}
+	payload := `{"event_id":5,"type":"appointment.cancelled","business_id":1,"created_at":"2025-03-10T08:00:00Z","data":{"id":7}}`
+	deliveryTo := func(webhookID int) interface{} {
+		return mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.WebhookID == webhookID && *d.EventID == 5 && d.EventType == event.EventType && string(d.Payload) == payload
+		})
+	}
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name  string
+		event *entity.DomainEvent
+		mock  func(m *mocks.WebhookRepository)
+		err   error
+	}{
+		{
+			name:  "positive: delivery queued for every subscribed webhook",
+			event: event,
+			mock: func(m *mocks.WebhookRepository) {
+				m.On("ListSubscribed", ctx, 1, entity.EventAppointmentCancelled).Return([]entity.Webhook{{ID: 1}, {ID: 2}}, nil)
+				m.On("CreateDelivery", ctx, deliveryTo(1)).Return(nil)
+				m.On("CreateDelivery", ctx, deliveryTo(2)).Return(fmt.Errorf("failed to create webhook delivery: %w", repository.ErrAlreadyExists))
+			},
+		},
+		{
+			name:  "positive: events webhooks cannot subscribe to ignored",
+			event: &entity.DomainEvent{ID: 6, BusinessID: 1, EventType: entity.EventAppointmentReminder},
+			mock:  func(m *mocks.WebhookRepository) {},
+		},
+		{
+			name:  "negative: delivery not stored",
+			event: event,
+			mock: func(m *mocks.WebhookRepository) {
+				m.On("ListSubscribed", ctx, 1, entity.EventAppointmentCancelled).Return([]entity.Webhook{{ID: 1}}, nil)
+				m.On("CreateDelivery", ctx, deliveryTo(1)).Return(fmt.Errorf("db error"))
+			},
+			err: fmt.Errorf("webhook 1: db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Init mocks
+			webhookRepoMock := mocks.NewWebhookRepository(t)
+
+			// Setup mocks
+			tc.mock(webhookRepoMock)
+
+			// Init consumer
+			consumer := services.NewWebhookConsumer(&repository.Repositories{
+				Webhook: webhookRepoMock,
+			})
+
+			// Execute
+			err := consumer.Consume(ctx, tc.event)
+
+			// Assert
+			if tc.err != nil {
+				assert.EqualError(t, err, tc.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}